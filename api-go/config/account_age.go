@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// Hesap yaşı sınırına tabi eylemler
+const (
+	AccountAgeActionBatchFollow = "follow_batch"
+	AccountAgeActionComment     = "comment"
+	AccountAgeActionReport      = "report"
+	// AccountAgeActionReferral davet bonusunu geciktirir; davet eden ve edilen hesaplara uygulanır
+	AccountAgeActionReferral = "referral"
+)
+
+// DefaultMinAccountAge kısıtlı eylemler için varsayılan en düşük hesap yaşı
+const DefaultMinAccountAge = 24 * time.Hour
+
+var defaultAccountAgeActions = []string{AccountAgeActionBatchFollow, AccountAgeActionComment, AccountAgeActionReport, AccountAgeActionReferral}
+
+// GetMinAccountAge returns how old an account must be before it can take the
+// gated actions, overridable with MIN_ACCOUNT_AGE (e.g. "72h"; "0" disables).
+func GetMinAccountAge() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("MIN_ACCOUNT_AGE")); err == nil && value >= 0 {
+		return value
+	}
+	return DefaultMinAccountAge
+}
+
+// GetAccountAgeActions returns the actions gated by the minimum account age,
+// overridable with a comma-separated MIN_ACCOUNT_AGE_ACTIONS. "none" gates nothing.
+func GetAccountAgeActions() []string {
+	value := strings.TrimSpace(os.Getenv("MIN_ACCOUNT_AGE_ACTIONS"))
+	if value == "" {
+		return defaultAccountAgeActions
+	}
+
+	actions := []string{}
+	for _, action := range strings.Split(value, ",") {
+		if action = strings.ToLower(strings.TrimSpace(action)); action != "" && action != "none" {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// IsAccountAgeGated reports whether action requires the minimum account age
+func IsAccountAgeGated(action string) bool {
+	for _, gated := range GetAccountAgeActions() {
+		if gated == action {
+			return true
+		}
+	}
+	return false
+}
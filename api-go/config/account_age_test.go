@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAccountAgePolicy(t *testing.T) {
+	t.Setenv("MIN_ACCOUNT_AGE", "")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", "")
+	if got := GetMinAccountAge(); got != DefaultMinAccountAge {
+		t.Errorf("default age = %s, want %s", got, DefaultMinAccountAge)
+	}
+	if !IsAccountAgeGated(AccountAgeActionComment) || !IsAccountAgeGated(AccountAgeActionReport) || !IsAccountAgeGated(AccountAgeActionBatchFollow) || !IsAccountAgeGated(AccountAgeActionReferral) {
+		t.Errorf("default actions = %v, want follow_batch, comment, report and referral", GetAccountAgeActions())
+	}
+
+	t.Setenv("MIN_ACCOUNT_AGE", "72h")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", " Report, ,comment ")
+	if got := GetMinAccountAge(); got != 72*time.Hour {
+		t.Errorf("age = %s, want 72h", got)
+	}
+	if got := GetAccountAgeActions(); !reflect.DeepEqual(got, []string{"report", "comment"}) {
+		t.Errorf("actions = %v, want [report comment]", got)
+	}
+	if IsAccountAgeGated(AccountAgeActionBatchFollow) {
+		t.Error("follow_batch gated although not listed")
+	}
+
+	t.Setenv("MIN_ACCOUNT_AGE", "0")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", "none")
+	if got := GetMinAccountAge(); got != 0 {
+		t.Errorf("disabled age = %s, want 0", got)
+	}
+	if got := GetAccountAgeActions(); len(got) != 0 {
+		t.Errorf("actions with none = %v, want empty", got)
+	}
+
+	t.Setenv("MIN_ACCOUNT_AGE", "-1h")
+	if got := GetMinAccountAge(); got != DefaultMinAccountAge {
+		t.Errorf("negative age = %s, want the default", got)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Profil sayaçlarını gerçek sayımlarla uzlaştıran işin varsayılan aralığı
+const DefaultCounterReconcileInterval = 6 * time.Hour
+
+// GetCounterReconcileInterval returns how often cached follower, following and
+// post counts are recomputed, set with COUNTER_RECONCILE_INTERVAL (e.g. "1h").
+// "0" disables the job.
+func GetCounterReconcileInterval() time.Duration {
+	value, ok := os.LookupEnv("COUNTER_RECONCILE_INTERVAL")
+	if !ok {
+		return DefaultCounterReconcileInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return DefaultCounterReconcileInterval
+	}
+	if interval < 0 {
+		return 0
+	}
+	return interval
+}
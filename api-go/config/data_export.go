@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultDataExportSyncLimit bu kadar kayda kadar olan hesapların dışa aktarımı istekte hazırlanır
+const DefaultDataExportSyncLimit = 1000
+
+// DefaultDataExportTTL hazırlanan arşivin indirilebileceği varsayılan süre
+const DefaultDataExportTTL = 7 * 24 * time.Hour
+
+// DefaultDataExportTimeout bu süre ilerlemeyen arka plan dışa aktarımı başarısız sayılır
+const DefaultDataExportTimeout = time.Hour
+
+// GetDataExportSyncLimit returns how many posts, comments, likes and activity
+// entries an account may have for its data export to be returned directly;
+// larger accounts are exported in the background. Overridable with
+// DATA_EXPORT_SYNC_LIMIT; "0" makes every export asynchronous.
+func GetDataExportSyncLimit() int64 {
+	if value, err := strconv.ParseInt(os.Getenv("DATA_EXPORT_SYNC_LIMIT"), 10, 64); err == nil && value >= 0 {
+		return value
+	}
+	return DefaultDataExportSyncLimit
+}
+
+// GetDataExportTTL returns how long a background export stays downloadable,
+// overridable with DATA_EXPORT_TTL (e.g. "48h").
+func GetDataExportTTL() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("DATA_EXPORT_TTL")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultDataExportTTL
+}
+
+// GetDataExportTimeout returns how long a pending or processing export may go
+// without progress before it counts as failed, overridable with
+// DATA_EXPORT_TIMEOUT (e.g. "30m"). Exports whose job died with a restart or
+// crash never finish on their own.
+func GetDataExportTimeout() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("DATA_EXPORT_TIMEOUT")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultDataExportTimeout
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDataExportSyncLimit(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"", DefaultDataExportSyncLimit},
+		{"250", 250},
+		{"0", 0},
+		{"-1", DefaultDataExportSyncLimit},
+		{"bogus", DefaultDataExportSyncLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DATA_EXPORT_SYNC_LIMIT", tt.value)
+			if got := GetDataExportSyncLimit(); got != tt.want {
+				t.Errorf("GetDataExportSyncLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataExportTTL(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultDataExportTTL},
+		{"48h", 48 * time.Hour},
+		{"0", DefaultDataExportTTL},
+		{"bogus", DefaultDataExportTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DATA_EXPORT_TTL", tt.value)
+			if got := GetDataExportTTL(); got != tt.want {
+				t.Errorf("GetDataExportTTL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDataExportTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultDataExportTimeout},
+		{"30m", 30 * time.Minute},
+		{"-5m", DefaultDataExportTimeout},
+		{"bogus", DefaultDataExportTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DATA_EXPORT_TIMEOUT", tt.value)
+			if got := GetDataExportTimeout(); got != tt.want {
+				t.Errorf("GetDataExportTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
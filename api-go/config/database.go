@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/snap-point/api-go/metrics"
 	"github.com/snap-point/api-go/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -18,6 +19,8 @@ type R2Config struct {
 	BucketName      string
 	PublicURL       string
 	Region          string
+	// PrivateMedia kova herkese açık değilse medya okunurken imzalı GET URL'leri üretilir
+	PrivateMedia bool
 }
 
 func GetR2Config() *R2Config {
@@ -28,6 +31,7 @@ func GetR2Config() *R2Config {
 		BucketName:      os.Getenv("CLOUDFLARE_BUCKET_NAME"),
 		PublicURL:       os.Getenv("CLOUDFLARE_PUBLIC_URL"),
 		Region:          "auto",
+		PrivateMedia:    os.Getenv("CLOUDFLARE_PRIVATE_MEDIA") == "true",
 	}
 }
 
@@ -42,7 +46,7 @@ func ConnectDatabase() (*gorm.DB, error) {
 		dsn = "host=localhost user=youruser dbname=yourdb port=5432 sslmode=disable TimeZone=Asia/Shanghai"
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: NewDBLogger(nil)})
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +54,60 @@ func ConnectDatabase() (*gorm.DB, error) {
 	return db, nil
 }
 
+// Migrate creates or updates the tables for all models
+func Migrate(db *gorm.DB) error {
+	// follows tablosu Follow modelidir; aksi halde GORM many2many için iki sütunlu,
+	// birleşik anahtarlı bir tablo varsayar ve Preload silinmiş takipleri de getirir
+	for _, field := range []string{"Followers", "Following"} {
+		if err := db.SetupJoinTable(&models.User{}, field, &models.Follow{}); err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.Post{}, &models.Comment{}, &models.Like{}, &models.Follow{}, &models.Place{}, &models.ActivityLog{}, &models.Role{}, &models.PostMedia{}, &models.UsernameChange{}, &models.Block{}, &models.LoginAttempt{}, &models.SearchHistory{}, &models.Mute{}, &models.FeedPreference{}, &models.PostDraft{}, &models.Notification{}, &models.NotificationPreference{}, &models.DeviceToken{}, &models.AdminAuditLog{}, &models.IdempotencyKey{}, &models.Report{}, &models.MediaUpload{}, &models.FavoritePlace{}, &models.ReservedUsername{}, &models.PlaceLocationSuggestion{}, &models.Referral{}, &models.DataExport{}); err != nil {
+		return err
+	}
+
+	// Takip gibi aktiviteler mekân ve gönderi yerine 0 yazar; eski şemalardaki FK'ler bu satırları reddeder
+	for _, name := range []string{"fk_activity_logs_place", "fk_activity_logs_post"} {
+		if db.Migrator().HasConstraint(&models.ActivityLog{}, name) {
+			if err := db.Migrator().DropConstraint(&models.ActivityLog{}, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return createCaseInsensitiveUserIndexes(db)
+}
+
+// createCaseInsensitiveUserIndexes adds unique indexes on LOWER(email) and
+// LOWER(username). Rows that already differ only by case would make the index
+// fail, so they are logged and reported as an error instead.
+func createCaseInsensitiveUserIndexes(db *gorm.DB) error {
+	for _, column := range []string{"email", "username"} {
+		var conflicts []struct {
+			Value string
+			IDs   string
+		}
+		if err := db.Raw(fmt.Sprintf(`SELECT LOWER(%[1]s) AS value, STRING_AGG(id::text, ', ' ORDER BY id) AS ids
+			FROM users GROUP BY LOWER(%[1]s) HAVING COUNT(*) > 1`, column)).Scan(&conflicts).Error; err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			for _, conflict := range conflicts {
+				log.Printf("users with case-insensitive duplicate %s %q: ids %s", column, conflict.Value, conflict.IDs)
+			}
+			return fmt.Errorf("%d case-insensitive duplicate %s values in users; merge or rename them before starting", len(conflicts), column)
+		}
+
+		// E-posta ve kullanıcı adı için büyük/küçük harf duyarsız benzersizlik
+		if err := db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_%[1]s_lower ON users (LOWER(%[1]s))", column)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func InitDB() *gorm.DB {
 	dbHost := os.Getenv("DB_HOST")
 	dbUser := os.Getenv("DB_USER")
@@ -60,13 +118,19 @@ func InitDB() *gorm.DB {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 		dbHost, dbUser, dbPassword, dbName, dbPort)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: NewDBLogger(nil)})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	if err := metrics.InstrumentDB(db); err != nil {
+		log.Fatal("Failed to instrument database:", err)
+	}
+
 	// Auto Migrate models
-	db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.Post{}, &models.Comment{}, &models.Like{}, &models.Follow{}, &models.Place{}, &models.ActivityLog{}, &models.Role{}, &models.PostMedia{})
+	if err := Migrate(db); err != nil {
+		log.Fatal("Failed to migrate models:", err)
+	}
 
 	return db
 }
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// DefaultSlowQueryThreshold bu süreyi aşan sorgular yavaş sorgu olarak günlüğe yazılır
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+var dbLogLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// isProduction reports whether the server runs in gin's release mode
+func isProduction() bool {
+	return os.Getenv("GIN_MODE") == "release"
+}
+
+// GetDBLogLevel returns the GORM log level set with DB_LOG_LEVEL: "silent",
+// "error", "warn" (slow queries and errors) or "info" (every query). It
+// defaults to "warn" when GIN_MODE=release and "info" otherwise.
+func GetDBLogLevel() logger.LogLevel {
+	if level, ok := dbLogLevels[strings.ToLower(strings.TrimSpace(os.Getenv("DB_LOG_LEVEL")))]; ok {
+		return level
+	}
+	if isProduction() {
+		return logger.Warn
+	}
+	return logger.Info
+}
+
+// GetSlowQueryThreshold returns the duration above which a query is logged as
+// slow, overridable with DB_SLOW_QUERY_THRESHOLD (e.g. "500ms").
+func GetSlowQueryThreshold() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("DB_SLOW_QUERY_THRESHOLD")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultSlowQueryThreshold
+}
+
+// NewDBLogger returns the GORM logger writing to out, or to the application
+// log when out is nil. Missing records are not logged as errors since handlers
+// expect them, and in production query values are left out of the log.
+func NewDBLogger(out logger.Writer) logger.Interface {
+	if out == nil {
+		out = log.Default()
+	}
+	return logger.New(out, logger.Config{
+		SlowThreshold:             GetSlowQueryThreshold(),
+		LogLevel:                  GetDBLogLevel(),
+		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      isProduction(),
+		Colorful:                  false,
+	})
+}
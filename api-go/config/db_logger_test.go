@@ -0,0 +1,70 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// bufferWriter günlük satırlarını testte okumak için toplar
+type bufferWriter struct {
+	lines []string
+}
+
+func (w *bufferWriter) Printf(format string, args ...interface{}) {
+	w.lines = append(w.lines, fmt.Sprintf(format, args...))
+}
+
+func TestDBLogLevel(t *testing.T) {
+	t.Setenv("DB_LOG_LEVEL", "")
+	t.Setenv("GIN_MODE", "")
+	if got := GetDBLogLevel(); got != logger.Info {
+		t.Errorf("development level = %v, want info", got)
+	}
+	t.Setenv("GIN_MODE", "release")
+	if got := GetDBLogLevel(); got != logger.Warn {
+		t.Errorf("production level = %v, want warn", got)
+	}
+	t.Setenv("DB_LOG_LEVEL", " Error ")
+	if got := GetDBLogLevel(); got != logger.Error {
+		t.Errorf("DB_LOG_LEVEL=error: level = %v, want error", got)
+	}
+	t.Setenv("DB_LOG_LEVEL", "verbose")
+	if got := GetDBLogLevel(); got != logger.Warn {
+		t.Errorf("unknown level = %v, want the production default", got)
+	}
+}
+
+func TestDBLoggerLogsSlowQueries(t *testing.T) {
+	t.Setenv("GIN_MODE", "release")
+	t.Setenv("DB_LOG_LEVEL", "")
+	t.Setenv("DB_SLOW_QUERY_THRESHOLD", "50ms")
+
+	out := &bufferWriter{}
+	dbLogger := NewDBLogger(out)
+	query := func(sql string, took time.Duration) {
+		dbLogger.Trace(context.Background(), time.Now().Add(-took), func() (string, int64) { return sql, 1 }, nil)
+	}
+
+	query("SELECT fast", time.Millisecond)
+	if len(out.lines) != 0 {
+		t.Fatalf("fast query in production logged: %q", out.lines)
+	}
+	query("SELECT slow", 120*time.Millisecond)
+	if len(out.lines) != 1 || !strings.Contains(out.lines[0], "SLOW SQL >= 50ms") || !strings.Contains(out.lines[0], "SELECT slow") {
+		t.Errorf("slow query log = %q, want one SLOW SQL line", out.lines)
+	}
+
+	// Geliştirmede tüm sorgular yazılır
+	t.Setenv("GIN_MODE", "")
+	out = &bufferWriter{}
+	dbLogger = NewDBLogger(out)
+	query("SELECT fast", time.Millisecond)
+	if len(out.lines) != 1 || !strings.Contains(out.lines[0], "SELECT fast") {
+		t.Errorf("development log = %q, want the fast query", out.lines)
+	}
+}
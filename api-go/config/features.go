@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Gönderi ve check-in için konum yarıçapı politikaları
+const (
+	PostRadiusEnforce = "enforce"
+	PostRadiusOff     = "off"
+)
+
+// FeatureFlags are the server-controlled behaviours the client reads from
+// GET /config/features; the server gates the same behaviours on them.
+type FeatureFlags struct {
+	VideoEnabled     bool   `json:"videoEnabled"`
+	MaxMediaItems    int    `json:"maxMediaItems"`
+	MaxCaptionLength int    `json:"maxCaptionLength"`
+	PostRadiusPolicy string `json:"postRadiusPolicy"`
+}
+
+// GetFeatureFlags returns the current feature flags.
+func GetFeatureFlags() FeatureFlags {
+	return FeatureFlags{
+		VideoEnabled:     IsVideoEnabled(),
+		MaxMediaItems:    GetMaxMediaItemsPerPost(),
+		MaxCaptionLength: GetMaxCaptionLength(),
+		PostRadiusPolicy: GetPostRadiusPolicy(),
+	}
+}
+
+// IsVideoEnabled reports whether video uploads and posts are accepted,
+// switched off with FEATURE_VIDEO_ENABLED=false. On by default.
+func IsVideoEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("FEATURE_VIDEO_ENABLED"))
+	return err != nil || enabled
+}
+
+// GetPostRadiusPolicy returns whether posts and check-ins must be made within
+// the place's radius, set with POST_RADIUS_POLICY: "enforce" (default) or
+// "off", meant for development and testing.
+func GetPostRadiusPolicy() string {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("POST_RADIUS_POLICY"))) == PostRadiusOff {
+		return PostRadiusOff
+	}
+	return PostRadiusEnforce
+}
@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestGetFeatureFlags(t *testing.T) {
+	t.Setenv("FEATURE_VIDEO_ENABLED", "")
+	t.Setenv("MAX_MEDIA_ITEMS_PER_POST", "")
+	t.Setenv("MAX_CAPTION_LENGTH", "")
+	t.Setenv("POST_RADIUS_POLICY", "")
+	want := FeatureFlags{
+		VideoEnabled:     true,
+		MaxMediaItems:    DefaultMaxMediaItemsPerPost,
+		MaxCaptionLength: DefaultMaxCaptionLength,
+		PostRadiusPolicy: PostRadiusEnforce,
+	}
+	if got := GetFeatureFlags(); got != want {
+		t.Errorf("defaults = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("FEATURE_VIDEO_ENABLED", "false")
+	t.Setenv("MAX_MEDIA_ITEMS_PER_POST", "4")
+	t.Setenv("MAX_CAPTION_LENGTH", "500")
+	t.Setenv("POST_RADIUS_POLICY", " OFF ")
+	want = FeatureFlags{VideoEnabled: false, MaxMediaItems: 4, MaxCaptionLength: 500, PostRadiusPolicy: PostRadiusOff}
+	if got := GetFeatureFlags(); got != want {
+		t.Errorf("overrides = %+v, want %+v", got, want)
+	}
+
+	// Tanınmayan değerler varsayılana düşer
+	t.Setenv("FEATURE_VIDEO_ENABLED", "maybe")
+	t.Setenv("POST_RADIUS_POLICY", "lenient")
+	if got := GetFeatureFlags(); !got.VideoEnabled || got.PostRadiusPolicy != PostRadiusEnforce {
+		t.Errorf("invalid values = %+v, want video on and radius enforced", got)
+	}
+}
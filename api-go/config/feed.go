@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultFeedCandidateLimit akışın sıralandığı, takip edilenlerin en yeni gönderi sayısı
+const DefaultFeedCandidateLimit = 1000
+
+// GetFeedCandidateLimit returns how many of the newest posts from followed
+// users the feed ranks, overridable with FEED_CANDIDATE_LIMIT.
+func GetFeedCandidateLimit() int {
+	if value, err := strconv.Atoi(os.Getenv("FEED_CANDIDATE_LIMIT")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultFeedCandidateLimit
+}
+
+// Trend sıralamasının varsayılan ağırlıkları ve süreleri
+const (
+	DefaultTrendingLikeWeight       = 3.0
+	DefaultTrendingCommentWeight    = 2.0
+	DefaultTrendingBaseScore        = 1.0
+	DefaultTrendingHalfLife         = 6 * time.Hour
+	DefaultTrendingEngagementWindow = 24 * time.Hour
+)
+
+// TrendingParams tune the trending feed sort. A post scores
+// (LikeWeight*likes + CommentWeight*comments + BaseScore) * 0.5^(age/HalfLife),
+// counting only likes and comments made within EngagementWindow.
+type TrendingParams struct {
+	LikeWeight       float64
+	CommentWeight    float64
+	BaseScore        float64 // etkileşimi olmayan gönderiler de yaşlarına göre sıralanır
+	HalfLife         time.Duration
+	EngagementWindow time.Duration
+}
+
+// GetTrendingParams returns the trending parameters, overridable with
+// TRENDING_LIKE_WEIGHT, TRENDING_COMMENT_WEIGHT, TRENDING_BASE_SCORE (non-negative
+// numbers), TRENDING_HALF_LIFE and TRENDING_ENGAGEMENT_WINDOW (durations, e.g. "12h").
+func GetTrendingParams() TrendingParams {
+	return TrendingParams{
+		LikeWeight:       trendingWeight("TRENDING_LIKE_WEIGHT", DefaultTrendingLikeWeight),
+		CommentWeight:    trendingWeight("TRENDING_COMMENT_WEIGHT", DefaultTrendingCommentWeight),
+		BaseScore:        trendingWeight("TRENDING_BASE_SCORE", DefaultTrendingBaseScore),
+		HalfLife:         trendingDuration("TRENDING_HALF_LIFE", DefaultTrendingHalfLife),
+		EngagementWindow: trendingDuration("TRENDING_ENGAGEMENT_WINDOW", DefaultTrendingEngagementWindow),
+	}
+}
+
+func trendingWeight(key string, fallback float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+func trendingDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
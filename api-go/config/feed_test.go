@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTrendingParams(t *testing.T) {
+	keys := []string{"TRENDING_LIKE_WEIGHT", "TRENDING_COMMENT_WEIGHT", "TRENDING_BASE_SCORE", "TRENDING_HALF_LIFE", "TRENDING_ENGAGEMENT_WINDOW"}
+	tests := []struct {
+		name   string
+		values []string
+		want   TrendingParams
+	}{
+		{"defaults", []string{"", "", "", "", ""}, TrendingParams{
+			DefaultTrendingLikeWeight, DefaultTrendingCommentWeight, DefaultTrendingBaseScore,
+			DefaultTrendingHalfLife, DefaultTrendingEngagementWindow,
+		}},
+		{"overrides", []string{"1.5", "0", "0.5", "90m", "48h"}, TrendingParams{1.5, 0, 0.5, 90 * time.Minute, 48 * time.Hour}},
+		{"invalid", []string{"-1", "many", "", "0", "-1h"}, TrendingParams{
+			DefaultTrendingLikeWeight, DefaultTrendingCommentWeight, DefaultTrendingBaseScore,
+			DefaultTrendingHalfLife, DefaultTrendingEngagementWindow,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, key := range keys {
+				t.Setenv(key, tt.values[i])
+			}
+			if got := GetTrendingParams(); got != tt.want {
+				t.Errorf("GetTrendingParams() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
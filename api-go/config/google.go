@@ -3,19 +3,41 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
+var (
+	// ErrGoogleInvalidToken Google token'ı/kodu reddetti (istemci hatası)
+	ErrGoogleInvalidToken = errors.New("invalid google token")
+	// ErrGoogleUnavailable Google'a ulaşılamadı veya beklenmeyen yanıt döndü
+	ErrGoogleUnavailable = errors.New("google unavailable")
+)
+
+var googleHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+const (
+	DefaultGoogleTokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+	DefaultGoogleUserInfoURL  = "https://www.googleapis.com/oauth2/v2/userinfo"
+)
+
 type GoogleConfig struct {
 	ClientID     string
 	ClientSecret string
 	RedirectURL  string
 	Config       *oauth2.Config
+	// ID token'larında kabul edilen istemciler: web istemcisi ve GOOGLE_CLIENT_IDS (iOS, Android)
+	AllowedClientIDs []string
+	// Google uç noktaları; testlerde sahte sunucuya yönlendirilebilir
+	TokenInfoURL string
+	UserInfoURL  string
 }
 
 type GoogleUserInfo struct {
@@ -39,9 +61,12 @@ func NewGoogleConfig() *GoogleConfig {
 	}
 
 	return &GoogleConfig{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  redirectURL,
+		ClientID:         clientID,
+		ClientSecret:     clientSecret,
+		RedirectURL:      redirectURL,
+		AllowedClientIDs: googleClientIDs(clientID, os.Getenv("GOOGLE_CLIENT_IDS")),
+		TokenInfoURL:     DefaultGoogleTokenInfoURL,
+		UserInfoURL:      DefaultGoogleUserInfoURL,
 		Config: &oauth2.Config{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
@@ -55,48 +80,124 @@ func NewGoogleConfig() *GoogleConfig {
 	}
 }
 
+// googleClientIDs returns the web client ID followed by the comma-separated
+// extra client IDs (e.g. the iOS and Android clients), without blanks or repeats.
+func googleClientIDs(webClientID, extra string) []string {
+	ids := []string{webClientID}
+	for _, id := range strings.Split(extra, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || id == webClientID {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// acceptsAudience reports whether an ID token issued for aud may sign in
+func (g *GoogleConfig) acceptsAudience(aud string) bool {
+	if aud == "" {
+		return false
+	}
+	for _, id := range g.AllowedClientIDs {
+		if aud == id {
+			return true
+		}
+	}
+	return aud == g.ClientID
+}
+
+// googleTokenInfo is the tokeninfo endpoint's response; it uses different
+// field names than the userinfo endpoint (sub, string-typed email_verified).
+type googleTokenInfo struct {
+	Sub           string `json:"sub"`
+	Aud           string `json:"aud"`
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Picture       string `json:"picture"`
+	Locale        string `json:"locale"`
+}
+
 func (g *GoogleConfig) VerifyIDToken(idToken string) (*GoogleUserInfo, error) {
-	url := fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?id_token=%s", idToken)
-	
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify token: %v", err)
+	url := fmt.Sprintf("%s?id_token=%s", g.TokenInfoURL, idToken)
+
+	var tokenInfo googleTokenInfo
+	if err := getGoogleJSON(url, &tokenInfo); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("invalid token")
+	// Token başka bir uygulama için verilmişse reddet
+	if !g.acceptsAudience(tokenInfo.Aud) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrGoogleInvalidToken)
+	}
+
+	userInfo := &GoogleUserInfo{
+		ID:            tokenInfo.Sub,
+		Email:         tokenInfo.Email,
+		VerifiedEmail: tokenInfo.EmailVerified == "true",
+		Name:          tokenInfo.Name,
+		GivenName:     tokenInfo.GivenName,
+		FamilyName:    tokenInfo.FamilyName,
+		Picture:       tokenInfo.Picture,
+		Locale:        tokenInfo.Locale,
 	}
+	if userInfo.ID == "" || userInfo.Email == "" {
+		return nil, fmt.Errorf("%w: token has no subject or email", ErrGoogleInvalidToken)
+	}
+
+	return userInfo, nil
+}
+
+func (g *GoogleConfig) GetUserInfo(accessToken string) (*GoogleUserInfo, error) {
+	url := fmt.Sprintf("%s?access_token=%s", g.UserInfoURL, accessToken)
 
 	var userInfo GoogleUserInfo
-	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode user info: %v", err)
+	if err := getGoogleJSON(url, &userInfo); err != nil {
+		return nil, err
+	}
+
+	if userInfo.ID == "" || userInfo.Email == "" {
+		return nil, fmt.Errorf("%w: user info has no id or email", ErrGoogleInvalidToken)
 	}
 
 	return &userInfo, nil
 }
 
-func (g *GoogleConfig) GetUserInfo(accessToken string) (*GoogleUserInfo, error) {
-	url := fmt.Sprintf("https://www.googleapis.com/oauth2/v2/userinfo?access_token=%s", accessToken)
-	
-	resp, err := http.Get(url)
+func (g *GoogleConfig) ExchangeCode(ctx context.Context, code string) (*oauth2.Token, error) {
+	token, err := g.Config.Exchange(ctx, code)
+	if err != nil {
+		// RetrieveError: Google yanıt verdi ama kodu reddetti
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.Response != nil && retrieveErr.Response.StatusCode < 500 {
+			return nil, fmt.Errorf("%w: %v", ErrGoogleInvalidToken, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrGoogleUnavailable, err)
+	}
+	return token, nil
+}
+
+// getGoogleJSON fetches a Google endpoint and decodes the JSON body, mapping
+// 4xx responses to ErrGoogleInvalidToken and network/5xx failures to ErrGoogleUnavailable.
+func getGoogleJSON(url string, out interface{}) error {
+	resp, err := googleHTTPClient.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %v", err)
+		return fmt.Errorf("%w: %v", ErrGoogleUnavailable, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%w: status %d", ErrGoogleUnavailable, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get user info")
+		return fmt.Errorf("%w: status %d", ErrGoogleInvalidToken, resp.StatusCode)
 	}
 
-	var userInfo GoogleUserInfo
-	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode user info: %v", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: failed to decode response: %v", ErrGoogleUnavailable, err)
 	}
 
-	return &userInfo, nil
-}
-
-func (g *GoogleConfig) ExchangeCode(ctx context.Context, code string) (*oauth2.Token, error) {
-	return g.Config.Exchange(ctx, code)
+	return nil
 } 
\ No newline at end of file
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGoogleClientIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		extra string
+		want  []string
+	}{
+		{"web client only", "", []string{"web"}},
+		{"mobile clients", "ios,android", []string{"web", "ios", "android"}},
+		{"blanks and spaces ignored", " ios , ,android ", []string{"web", "ios", "android"}},
+		{"web client not repeated", "web,ios", []string{"web", "ios"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := googleClientIDs("web", tt.extra); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("googleClientIDs(%q) = %v, want %v", tt.extra, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoogleConfigAcceptsAudience(t *testing.T) {
+	t.Setenv("GOOGLE_CLIENT_ID", "web.apps.googleusercontent.com")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+	t.Setenv("GOOGLE_CLIENT_IDS", "ios.apps.googleusercontent.com,android.apps.googleusercontent.com")
+	g := NewGoogleConfig()
+
+	tests := []struct {
+		aud  string
+		want bool
+	}{
+		{"web.apps.googleusercontent.com", true},
+		{"ios.apps.googleusercontent.com", true},
+		{"android.apps.googleusercontent.com", true},
+		{"other.apps.googleusercontent.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := g.acceptsAudience(tt.aud); got != tt.want {
+			t.Errorf("acceptsAudience(%q) = %v, want %v", tt.aud, got, tt.want)
+		}
+	}
+}
+
+func TestGetUserInfoFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    error
+	}{
+		{"rejected token", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}, ErrGoogleInvalidToken},
+		{"server error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, ErrGoogleUnavailable},
+		{"undecodable body", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>"))
+		}, ErrGoogleUnavailable},
+		{"missing id", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"email":"a@example.com"}`))
+		}, ErrGoogleInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			g := &GoogleConfig{UserInfoURL: server.URL}
+			info, err := g.GetUserInfo("token")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetUserInfo error = %v, want %v", err, tt.want)
+			}
+			if info != nil {
+				t.Errorf("GetUserInfo returned user info alongside error: %+v", info)
+			}
+		})
+	}
+}
+
+func TestGetUserInfoUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	g := &GoogleConfig{UserInfoURL: url}
+	if _, err := g.GetUserInfo("token"); !errors.Is(err, ErrGoogleUnavailable) {
+		t.Fatalf("GetUserInfo error = %v, want %v", err, ErrGoogleUnavailable)
+	}
+}
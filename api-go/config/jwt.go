@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// JWTKey imzalama anahtarı ve token header'ındaki kid değeri
+type JWTKey struct {
+	ID     string
+	Secret []byte
+}
+
+// JWTKeySet holds the current signing key and, during a rotation, the
+// previous key that is still accepted for verification.
+type JWTKeySet struct {
+	Current            JWTKey
+	Previous           *JWTKey
+	PreviousValidUntil *time.Time
+}
+
+// GetJWTKeySet reads the key set from the environment:
+//
+//	JWT_SECRET / JWT_KID                    current signing key
+//	JWT_PREVIOUS_SECRET / JWT_PREVIOUS_KID  previous key, verification only
+//	JWT_PREVIOUS_VALID_UNTIL                optional RFC3339 end of the grace period
+//
+// Tokens issued before kid support carry no kid; leave JWT_PREVIOUS_KID empty
+// when rotating away from such a secret so they keep validating.
+func GetJWTKeySet() *JWTKeySet {
+	keySet := &JWTKeySet{
+		Current: JWTKey{
+			ID:     os.Getenv("JWT_KID"),
+			Secret: []byte(os.Getenv("JWT_SECRET")),
+		},
+	}
+
+	if previousSecret := os.Getenv("JWT_PREVIOUS_SECRET"); previousSecret != "" {
+		keySet.Previous = &JWTKey{
+			ID:     os.Getenv("JWT_PREVIOUS_KID"),
+			Secret: []byte(previousSecret),
+		}
+
+		if validUntil, err := time.Parse(time.RFC3339, os.Getenv("JWT_PREVIOUS_VALID_UNTIL")); err == nil {
+			keySet.PreviousValidUntil = &validUntil
+		}
+	}
+
+	return keySet
+}
+
+// Lookup returns the secret for the given kid, or false if no active key matches
+func (ks *JWTKeySet) Lookup(kid string) ([]byte, bool) {
+	if kid == ks.Current.ID {
+		return ks.Current.Secret, true
+	}
+
+	if ks.Previous != nil && kid == ks.Previous.ID {
+		if ks.PreviousValidUntil != nil && time.Now().After(*ks.PreviousValidUntil) {
+			return nil, false
+		}
+		return ks.Previous.Secret, true
+	}
+
+	return nil, false
+}
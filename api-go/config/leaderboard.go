@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultLeaderboardWeekStart haftalık sıralamanın sıfırlandığı varsayılan gün (pazar)
+const DefaultLeaderboardWeekStart = time.Sunday
+
+// GetLeaderboardWeekStart returns the weekday the weekly leaderboard resets
+// on, overridable with LEADERBOARD_WEEK_START ("sunday" or "monday").
+func GetLeaderboardWeekStart() time.Weekday {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("LEADERBOARD_WEEK_START"))) {
+	case "monday":
+		return time.Monday
+	case "sunday":
+		return time.Sunday
+	}
+	return DefaultLeaderboardWeekStart
+}
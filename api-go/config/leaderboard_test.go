@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetLeaderboardWeekStart(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Weekday
+	}{
+		{"", time.Sunday},
+		{"sunday", time.Sunday},
+		{"Monday", time.Monday},
+		{" monday ", time.Monday},
+		{"friday", DefaultLeaderboardWeekStart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("LEADERBOARD_WEEK_START", tt.value)
+			if got := GetLeaderboardWeekStart(); got != tt.want {
+				t.Errorf("GetLeaderboardWeekStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
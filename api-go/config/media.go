@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxMediaItemsPerPost bir gönderiye eklenebilecek varsayılan en fazla medya sayısı
+const DefaultMaxMediaItemsPerPost = 10
+
+// GetMaxMediaItemsPerPost returns the per-post media limit, overridable with
+// MAX_MEDIA_ITEMS_PER_POST. Both post creation and bulk presigning use it.
+func GetMaxMediaItemsPerPost() int {
+	if value, err := strconv.Atoi(os.Getenv("MAX_MEDIA_ITEMS_PER_POST")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultMaxMediaItemsPerPost
+}
+
+// DefaultMaxUploadBytesPerPost bir gönderinin toplu yüklemesi için varsayılan toplam boyut bütçesi (300MB)
+const DefaultMaxUploadBytesPerPost int64 = 300 * 1024 * 1024
+
+// GetMaxUploadBytesPerPost returns the total declared size allowed for one
+// bulk presign batch, overridable with MAX_UPLOAD_BYTES_PER_POST (bytes).
+func GetMaxUploadBytesPerPost() int64 {
+	if value, err := strconv.ParseInt(os.Getenv("MAX_UPLOAD_BYTES_PER_POST"), 10, 64); err == nil && value > 0 {
+		return value
+	}
+	return DefaultMaxUploadBytesPerPost
+}
+
+// ShouldVerifyMediaUploads enables an R2 HEAD request per media URL on post
+// creation (VERIFY_MEDIA_UPLOADS=true). Off by default to keep posting fast.
+func ShouldVerifyMediaUploads() bool {
+	verify, _ := strconv.ParseBool(os.Getenv("VERIFY_MEDIA_UPLOADS"))
+	return verify
+}
+
+// DefaultMediaURLExpiry özel medya için üretilen imzalı okuma URL'lerinin varsayılan geçerlilik süresi
+const DefaultMediaURLExpiry = 15 * time.Minute
+
+// GetMediaURLExpiry returns how long presigned media GET URLs stay valid,
+// overridable with MEDIA_URL_EXPIRY (e.g. 5m). Only used when
+// CLOUDFLARE_PRIVATE_MEDIA is enabled.
+func GetMediaURLExpiry() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("MEDIA_URL_EXPIRY")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultMediaURLExpiry
+}
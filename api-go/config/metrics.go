@@ -0,0 +1,10 @@
+package config
+
+import "os"
+
+// GetMetricsToken returns the bearer token required to scrape /metrics, set
+// with METRICS_TOKEN. When it is empty only loopback and private network
+// clients may scrape.
+func GetMetricsToken() string {
+	return os.Getenv("METRICS_TOKEN")
+}
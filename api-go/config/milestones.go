@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultPointMilestones profil zaman akışında kutlanan varsayılan toplam puan eşikleri
+var defaultPointMilestones = []int64{100, 250, 500, 1000, 2500, 5000, 10000}
+
+// GetPointMilestones returns the total point thresholds shown as milestones on
+// profile timelines in ascending order, overridable with a comma-separated
+// POINT_MILESTONES. Invalid and non-positive entries are ignored.
+func GetPointMilestones() []int64 {
+	value := os.Getenv("POINT_MILESTONES")
+	if value == "" {
+		return defaultPointMilestones
+	}
+
+	milestones := []int64{}
+	seen := map[int64]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		points, err := strconv.ParseInt(strings.TrimSpace(entry), 10, 64)
+		if err != nil || points <= 0 || seen[points] {
+			continue
+		}
+		seen[points] = true
+		milestones = append(milestones, points)
+	}
+	sort.Slice(milestones, func(i, j int) bool { return milestones[i] < milestones[j] })
+	return milestones
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetPointMilestones(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []int64
+	}{
+		{"", defaultPointMilestones},
+		{"50", []int64{50}},
+		{"500, 100,bogus,-5,100,0", []int64{100, 500}},
+		{"nothing valid", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("POINT_MILESTONES", tt.value)
+			if got := GetPointMilestones(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPointMilestones() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
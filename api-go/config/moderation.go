@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultBannedWords otomatik içe aktarılan mekan isimlerinde işaretlenen varsayılan kelimeler
+var defaultBannedWords = []string{
+	"fuck", "shit", "porn", "nazi",
+	"amk", "siktir", "orospu", "piç",
+}
+
+// GetBannedWords returns the lower-cased banned word list, overridable with a
+// comma-separated BANNED_WORDS. Entries may contain spaces to match phrases.
+func GetBannedWords() []string {
+	value := os.Getenv("BANNED_WORDS")
+	if value == "" {
+		return defaultBannedWords
+	}
+
+	words := []string{}
+	for _, word := range strings.Split(value, ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// Yinelenen gönderi politikaları
+const (
+	DuplicatePostReject = "reject"
+	DuplicatePostFlag   = "flag"
+	DuplicatePostOff    = "off"
+)
+
+// DefaultDuplicatePostWindow aynı medyanın aynı mekanda tekrar paylaşımının arandığı varsayılan süre
+const DefaultDuplicatePostWindow = 24 * time.Hour
+
+// GetDuplicatePostPolicy returns what happens to a post repeating the user's
+// recent media at the same place, set with DUPLICATE_POST_POLICY: "reject"
+// (default), "flag" to publish it marked for review, or "off".
+func GetDuplicatePostPolicy() string {
+	switch policy := strings.ToLower(strings.TrimSpace(os.Getenv("DUPLICATE_POST_POLICY"))); policy {
+	case DuplicatePostFlag, DuplicatePostOff:
+		return policy
+	}
+	return DuplicatePostReject
+}
+
+// GetDuplicatePostWindow returns how far back duplicates are looked for,
+// overridable with DUPLICATE_POST_WINDOW (e.g. "6h").
+func GetDuplicatePostWindow() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("DUPLICATE_POST_WINDOW")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultDuplicatePostWindow
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultMaxPageSize sayfalı endpointlerde tek sayfada dönebilecek varsayılan en fazla kayıt
+const DefaultMaxPageSize = 50
+
+// GetMaxPageSize returns the upper bound for page sizes read from query
+// strings, overridable with MAX_PAGE_SIZE.
+func GetMaxPageSize() int {
+	if value, err := strconv.Atoi(os.Getenv("MAX_PAGE_SIZE")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultMaxPageSize
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Puanı olmayan mekanların minRating süzgecinde nasıl ele alınacağı
+const (
+	NullRatingInclude = "include"
+	NullRatingExclude = "exclude"
+)
+
+// GetNullRatingPolicy returns whether places without a rating pass a
+// minRating filter, set with NULL_RATING_POLICY (include or exclude).
+// Defaults to include so user-created places, which never have a Google
+// rating, are not hidden.
+func GetNullRatingPolicy() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("NULL_RATING_POLICY")), NullRatingExclude) {
+		return NullRatingExclude
+	}
+	return NullRatingInclude
+}
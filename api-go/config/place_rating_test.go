@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestGetNullRatingPolicy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", NullRatingInclude},
+		{"include", NullRatingInclude},
+		{"EXCLUDE", NullRatingExclude},
+		{" exclude ", NullRatingExclude},
+		{"sometimes", NullRatingInclude},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("NULL_RATING_POLICY", tt.value)
+			if got := GetNullRatingPolicy(); got != tt.want {
+				t.Errorf("GetNullRatingPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
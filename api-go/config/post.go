@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxCaptionLength gönderi açıklaması için varsayılan en fazla karakter (rune) sayısı
+const DefaultMaxCaptionLength = 2200
+
+// GetMaxCaptionLength returns the caption limit in characters, overridable
+// with MAX_CAPTION_LENGTH.
+func GetMaxCaptionLength() int {
+	if value, err := strconv.Atoi(os.Getenv("MAX_CAPTION_LENGTH")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultMaxCaptionLength
+}
+
+// DefaultIdempotencyKeyTTL bir Idempotency-Key'in tekrar eden isteklere aynı yanıtı döndürdüğü varsayılan süre
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+// GetIdempotencyKeyTTL returns how long a post creation Idempotency-Key is
+// remembered, overridable with IDEMPOTENCY_KEY_TTL (e.g. "12h").
+func GetIdempotencyKeyTTL() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL")); err == nil && value > 0 {
+		return value
+	}
+	return DefaultIdempotencyKeyTTL
+}
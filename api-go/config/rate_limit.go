@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit bir endpoint için pencere başına izin verilen istek sayısı
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// GetRateLimit returns the limit named by RATE_LIMIT_<NAME> in the form
+// "requests/duration" (e.g. RATE_LIMIT_LOGIN=5/1m), or the given default.
+func GetRateLimit(name string, defaultRequests int, defaultWindow time.Duration) RateLimit {
+	limit := RateLimit{Requests: defaultRequests, Window: defaultWindow}
+
+	value := os.Getenv("RATE_LIMIT_" + strings.ToUpper(name))
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return limit
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests < 1 {
+		return limit
+	}
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return limit
+	}
+
+	return RateLimit{Requests: requests, Window: window}
+}
+
+// GetTrustedProxies returns the proxy addresses or CIDRs from TRUSTED_PROXIES
+// (comma-separated) whose X-Forwarded-For header is honoured. It is nil when
+// unset, so the client IP used for rate limiting is the TCP peer and cannot be
+// spoofed with a header.
+func GetTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Davet bonusunun varsayılan puanları
+const (
+	DefaultReferrerBonusPoints = 20
+	DefaultReferredBonusPoints = 10
+)
+
+// GetReferralBonusPoints returns the points awarded to the referrer and to the
+// referred user when a referral completes, overridable with
+// REFERRAL_REFERRER_POINTS and REFERRAL_REFERRED_POINTS. Negative or invalid
+// values fall back to the defaults; "0" disables that side's bonus.
+func GetReferralBonusPoints() (referrer, referred int) {
+	return referralPoints("REFERRAL_REFERRER_POINTS", DefaultReferrerBonusPoints),
+		referralPoints("REFERRAL_REFERRED_POINTS", DefaultReferredBonusPoints)
+}
+
+func referralPoints(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestGetReferralBonusPoints(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantReferrer int
+		wantReferred int
+	}{
+		{"", DefaultReferrerBonusPoints, DefaultReferredBonusPoints},
+		{"50", 50, 50},
+		{"0", 0, 0},
+		{"-5", DefaultReferrerBonusPoints, DefaultReferredBonusPoints},
+		{"bogus", DefaultReferrerBonusPoints, DefaultReferredBonusPoints},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("REFERRAL_REFERRER_POINTS", tt.value)
+			t.Setenv("REFERRAL_REFERRED_POINTS", tt.value)
+			referrer, referred := GetReferralBonusPoints()
+			if referrer != tt.wantReferrer || referred != tt.wantReferred {
+				t.Errorf("GetReferralBonusPoints() = %d, %d, want %d, %d", referrer, referred, tt.wantReferrer, tt.wantReferred)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Silinmiş (soft-delete) kayıtların kalıcı olarak temizlenmeden önce tutulduğu varsayılan gün sayıları
+var defaultRetentionDays = map[string]int{
+	"users":  30,
+	"posts":  30,
+	"places": 90,
+}
+
+// GetRetentionDays returns how many days soft-deleted rows of entity (users,
+// posts, places) are kept before purging, overridable with RETENTION_DAYS_<ENTITY>.
+func GetRetentionDays(entity string) int {
+	if value, err := strconv.Atoi(os.Getenv("RETENTION_DAYS_" + strings.ToUpper(entity))); err == nil && value > 0 {
+		return value
+	}
+	return defaultRetentionDays[entity]
+}
+
+// GetPurgeInterval returns how often the purge job runs, set with PURGE_INTERVAL
+// (e.g. "24h"). Zero, the default, disables the scheduled job; POST /admin/purge still works.
+func GetPurgeInterval() time.Duration {
+	if value, err := time.ParseDuration(os.Getenv("PURGE_INTERVAL")); err == nil && value > 0 {
+		return value
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultReservedUsernames kimsenin alamayacağı varsayılan kullanıcı adları
+var DefaultReservedUsernames = []string{
+	"admin", "root", "api", "www", "mail", "ftp", "test", "demo", "user", "guest", "null", "undefined",
+	"support", "help", "moderator", "system", "official", "staff", "snappoint",
+}
+
+// GetExtraReservedUsernames returns the lower-cased usernames added with a
+// comma-separated RESERVED_USERNAMES, e.g. brand names and abuse terms.
+func GetExtraReservedUsernames() []string {
+	words := []string{}
+	for _, word := range strings.Split(os.Getenv("RESERVED_USERNAMES"), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// GetReservedUsernames returns the default reserved usernames merged with
+// RESERVED_USERNAMES, without duplicates.
+func GetReservedUsernames() []string {
+	words := append([]string{}, DefaultReservedUsernames...)
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		seen[word] = true
+	}
+	for _, word := range GetExtraReservedUsernames() {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// requireAccountAge responds with 403 and reports false when the user's
+// account is younger than the configured minimum age for action. Verified
+// and admin accounts are exempt.
+func requireAccountAge(c *gin.Context, db *gorm.DB, user *utils.UserClaims, action string) bool {
+	minAge := config.GetMinAccountAge()
+	if minAge == 0 || user.IsAdmin() || !config.IsAccountAgeGated(action) {
+		return true
+	}
+
+	account, err := utils.GetCurrentUser(c, db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check account age"})
+		return false
+	}
+	if account.IsVerified {
+		return true
+	}
+
+	remaining := account.CreatedAt.Add(minAge).Sub(time.Now())
+	if remaining <= 0 {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(remaining.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":      "Your account is too new for this action, please wait",
+		"action":     action,
+		"retryAfter": retryAfter,
+		"allowedAt":  account.CreatedAt.Add(minAge),
+	})
+	return false
+}
+
+// accountAgeAllows reports whether account may take action without an HTTP
+// request to answer, e.g. when awarding a bonus. Verified accounts are exempt.
+func accountAgeAllows(account models.User, action string) bool {
+	minAge := config.GetMinAccountAge()
+	if minAge == 0 || account.IsVerified || !config.IsAccountAgeGated(action) {
+		return true
+	}
+	return !time.Now().Before(account.CreatedAt.Add(minAge))
+}
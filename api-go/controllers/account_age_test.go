@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestNewAccountsCannotTakeGatedActions(t *testing.T) {
+	t.Setenv("MIN_ACCOUNT_AGE", "24h")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", "")
+	db := openTestDB(t)
+	fresh := createTestUser(t, db, "agefresh")
+	target := createTestUser(t, db, "agetarget")
+	if err := db.Model(&fresh).Updates(map[string]interface{}{
+		"created_at": time.Now().Add(-2 * time.Hour), "is_verified": false,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ic := NewInteractionController(db)
+	uc := NewUserController(db, nil)
+	follow := func() *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"userIds":[%d]}`, target.ID)
+		return callHandler(ic.BatchFollowUsers, http.MethodPost, "/users/follow/batch", strings.NewReader(body), fresh.ID)
+	}
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(target.ID))}
+	report := func() int {
+		return callHandler(uc.ReportUser, http.MethodPost, "/users/"+param.Value+"/report", strings.NewReader(`{"reason":"spam"}`), fresh.ID, param).Code
+	}
+
+	w := follow()
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("batch follow from a new account: status = %d, want 403", w.Code)
+	}
+	var resp struct {
+		RetryAfter int `json:"retryAfter"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// Hesap 2 saatlik: yaklaşık 22 saat beklemesi gerekir
+	if resp.RetryAfter < 21*3600 || resp.RetryAfter > 22*3600 || w.Header().Get("Retry-After") != strconv.Itoa(resp.RetryAfter) {
+		t.Errorf("retryAfter = %d, header %q; want about 22h and matching", resp.RetryAfter, w.Header().Get("Retry-After"))
+	}
+	if got := report(); got != http.StatusForbidden {
+		t.Errorf("report from a new account: status = %d, want 403", got)
+	}
+	var reports int64
+	db.Model(&models.Report{}).Where("reporter_user_id = ?", fresh.ID).Count(&reports)
+	if reports != 0 {
+		t.Errorf("reports = %d, want 0", reports)
+	}
+
+	// Kısıtlanmayan eylemler serbesttir
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", "follow_batch")
+	if got := report(); got != http.StatusOK {
+		t.Errorf("report when not gated: status = %d, want 200", got)
+	}
+
+	// Doğrulanmış hesaplar muaftır
+	if err := db.Model(&fresh).Update("is_verified", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := follow().Code; got != http.StatusOK {
+		t.Errorf("batch follow from a verified account: status = %d, want 200", got)
+	}
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+// MarkActivitySeenRequest; boş alanlar süzgeç uygulamaz
+type MarkActivitySeenRequest struct {
+	IDs      []uint `json:"ids" binding:"omitempty,max=100"`
+	BeforeID uint   `json:"beforeId"`
+}
+
+// unseenActivityCount counts the user's activity not yet seen on the activity tab
+func (uc *UserController) unseenActivityCount(userID uint) (int64, error) {
+	var unseen int64
+	err := uc.DB.Model(&models.ActivityLog{}).Where("user_id = ? AND is_seen = false", userID).Count(&unseen).Error
+	return unseen, err
+}
+
+// GetUnseenActivityCount godoc
+// @Summary Get the number of unseen activity items
+// @Tags users
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /activity/unseen-count [get]
+func (uc *UserController) GetUnseenActivityCount(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	unseen, err := uc.unseenActivityCount(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"unseenCount": unseen},
+	})
+}
+
+// MarkActivitySeen godoc
+// @Summary Mark activity items as seen
+// @Description Marks the current user's unseen activity as seen: the given ids (at most 100), those with an ID lower than beforeId, or all of them when the body is empty. Returns the number marked and the remaining unseen count.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body MarkActivitySeenRequest false "Filters"
+// @Success 200 {object} StandardResponse
+// @Router /activity/seen [post]
+func (uc *UserController) MarkActivitySeen(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var req MarkActivitySeenRequest
+	// Gövdesiz istek tüm görülmemişleri işaretler
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+			return
+		}
+	}
+
+	query := uc.DB.Model(&models.ActivityLog{}).Where("user_id = ? AND is_seen = false", user.UserID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	if req.BeforeID > 0 {
+		query = query.Where("id < ?", req.BeforeID)
+	}
+
+	result := query.Updates(map[string]interface{}{"is_seen": true, "seen_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update activity"})
+		return
+	}
+
+	unseen, err := uc.unseenActivityCount(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"updated": result.RowsAffected, "unseenCount": unseen},
+	})
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestMarkActivitySeen(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "seenowner")
+	other := createTestUser(t, db, "seenother")
+	place := createTestPlace(t, db, "seenplace")
+	post := createTestPost(t, db, user, place, "seen", true)
+
+	logActivity := func(owner models.User) uint {
+		t.Helper()
+		activity := models.ActivityLog{
+			UserID: owner.ID, PlaceID: place.ID, PostID: post.ID, Activity: "post_created",
+			Latitude: place.Latitude, Longitude: place.Longitude,
+		}
+		if err := db.Create(&activity).Error; err != nil {
+			t.Fatal(err)
+		}
+		return activity.ID
+	}
+	ids := make([]uint, 4)
+	for i := range ids {
+		ids[i] = logActivity(user)
+	}
+	otherID := logActivity(other)
+
+	uc := NewUserController(db, nil)
+	unseenCount := func() int64 {
+		t.Helper()
+		w := callHandler(uc.GetUnseenActivityCount, http.MethodGet, "/activity/unseen-count", nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unseen count: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				UnseenCount int64 `json:"unseenCount"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.UnseenCount
+	}
+	markSeen := func(body string) (updated, unseen int64) {
+		t.Helper()
+		w := callHandler(uc.MarkActivitySeen, http.MethodPost, "/activity/seen", strings.NewReader(body), user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("mark %s: status = %d, body = %s", body, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Updated     int64 `json:"updated"`
+				UnseenCount int64 `json:"unseenCount"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.Updated, resp.Data.UnseenCount
+	}
+
+	if got := unseenCount(); got != 4 {
+		t.Fatalf("unseen count = %d, want 4", got)
+	}
+
+	// Başka kullanıcının aktivitesi kimliği verilse de işaretlenmez
+	body := `{"ids":[` + strconv.Itoa(int(ids[0])) + `,` + strconv.Itoa(int(otherID)) + `]}`
+	if updated, unseen := markSeen(body); updated != 1 || unseen != 3 {
+		t.Errorf("mark by ids: updated %d, unseen %d; want 1 and 3", updated, unseen)
+	}
+	if updated, unseen := markSeen(`{"beforeId":` + strconv.Itoa(int(ids[2])) + `}`); updated != 1 || unseen != 2 {
+		t.Errorf("mark before: updated %d, unseen %d; want 1 and 2", updated, unseen)
+	}
+	if got := unseenCount(); got != 2 {
+		t.Errorf("unseen count = %d, want 2", got)
+	}
+
+	// Görülme durumu aktivite listesinde de döner
+	w := callHandler(uc.GetUserActivity, http.MethodGet, "/users/me/activity", nil, user.ID,
+		gin.Param{Key: "userId", Value: strconv.Itoa(int(user.ID))})
+	var list struct {
+		Data []ActivityItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[uint]bool{}
+	for _, item := range list.Data {
+		if item.IsSeen != (item.SeenAt != nil) {
+			t.Errorf("activity %d: isSeen %v with seenAt %v", item.ID, item.IsSeen, item.SeenAt)
+		}
+		seen[item.ID] = item.IsSeen
+	}
+	if len(seen) != 4 || !seen[ids[0]] || !seen[ids[1]] || seen[ids[2]] || seen[ids[3]] {
+		t.Errorf("seen state = %v, want only %d and %d seen", seen, ids[0], ids[1])
+	}
+
+	if updated, unseen := markSeen(""); updated != 2 || unseen != 0 {
+		t.Errorf("mark all: updated %d, unseen %d; want 2 and 0", updated, unseen)
+	}
+	var otherSeen bool
+	db.Model(&models.ActivityLog{}).Where("id = ?", otherID).Pluck("is_seen", &otherSeen)
+	if otherSeen {
+		t.Errorf("another user's activity was marked seen")
+	}
+}
@@ -0,0 +1,405 @@
+package controllers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+type AdminController struct {
+	DB               *gorm.DB
+	UploadController *UploadController
+}
+
+// AdminStatsQuery; Days "yeni" sayımların penceresidir
+type AdminStatsQuery struct {
+	Days int `form:"days,default=7" binding:"min=1,max=90"`
+}
+
+// AdminCount is a table total with the rows created in the stats window
+type AdminCount struct {
+	Total  int64 `json:"total" gorm:"column:total"`
+	Recent int64 `json:"recent" gorm:"column:recent"`
+}
+
+// AdminPostCount; NeedsReview yinelenen gönderi olarak işaretlenip incelenmeyi bekleyenlerdir
+type AdminPostCount struct {
+	AdminCount
+	NeedsReview int64 `json:"needsReview" gorm:"column:needs_review"`
+}
+
+type AdminPlaceCount struct {
+	AdminCount
+	GoogleImported int64 `json:"googleImported" gorm:"column:google_imported"`
+	NeedsReview    int64 `json:"needsReview" gorm:"column:needs_review"`
+}
+
+// AdminActiveUsers counts distinct users with any logged activity or successful login
+type AdminActiveUsers struct {
+	Daily  int64 `json:"daily" gorm:"column:daily"`
+	Window int64 `json:"window" gorm:"column:window_active"`
+}
+
+type AdminStatsResponse struct {
+	WindowDays  int              `json:"windowDays"`
+	Users       AdminCount       `json:"users"`
+	Posts       AdminPostCount   `json:"posts"`
+	Places      AdminPlaceCount  `json:"places"`
+	Likes       AdminCount       `json:"likes"`
+	Comments    AdminCount       `json:"comments"`
+	Reports     map[string]int64 `json:"reports"`
+	ActiveUsers AdminActiveUsers `json:"activeUsers"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+}
+
+// AuditLogQuery; From/To RFC3339 veya YYYY-MM-DD kabul eder
+type AuditLogQuery struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"pageSize,default=20" binding:"min=1,max=50"`
+	ActorID  uint   `form:"actorId"`
+	Action   string `form:"action"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+}
+
+type AuditLogItem struct {
+	ID         uint            `json:"id"`
+	Actor      PostUser        `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   uint            `json:"targetId"`
+	Detail     json.RawMessage `json:"detail,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func NewAdminController(db *gorm.DB, uploadController *UploadController) *AdminController {
+	return &AdminController{
+		DB:               db,
+		UploadController: uploadController,
+	}
+}
+
+// GetStats godoc
+// @Summary Get dashboard totals (admin)
+// @Description Returns totals and counts for the last `days` days of users, posts, places, likes, comments, reports by status and active users
+// @Tags admin
+// @Produce json
+// @Param days query integer false "Window for recent counts in days (default: 7, max: 90)"
+// @Success 200 {object} StandardResponse
+// @Router /admin/stats [get]
+func (ac *AdminController) GetStats(c *gin.Context) {
+	var query AdminStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -query.Days)
+	dayAgo := now.Add(-24 * time.Hour)
+
+	stats := AdminStatsResponse{
+		WindowDays:  query.Days,
+		Reports:     map[string]int64{},
+		GeneratedAt: now,
+	}
+
+	// Her tablo için toplam ve pencere içi sayım tek sorguda
+	counts := []struct {
+		sql  string
+		dest interface{}
+	}{
+		{`SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= ?) AS recent FROM users WHERE deleted_at IS NULL`, &stats.Users},
+		{`SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= ?) AS recent, COUNT(*) FILTER (WHERE needs_review) AS needs_review FROM posts WHERE deleted_at IS NULL`, &stats.Posts},
+		{`SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= ?) AS recent FROM likes`, &stats.Likes},
+		{`SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= ?) AS recent FROM comments WHERE deleted_at IS NULL`, &stats.Comments},
+	}
+	for _, count := range counts {
+		if err := ac.DB.Raw(count.sql, since).Scan(count.dest).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error computing stats"})
+			return
+		}
+	}
+
+	if err := ac.DB.Raw(`SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE created_at >= ?) AS recent,
+			COUNT(*) FILTER (WHERE place_type = 'google_place') AS google_imported,
+			COUNT(*) FILTER (WHERE needs_review) AS needs_review
+		FROM places WHERE deleted_at IS NULL`, since).Scan(&stats.Places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error computing stats"})
+		return
+	}
+
+	var reports []struct {
+		Status string
+		Count  int64
+	}
+	if err := ac.DB.Raw(`SELECT status, COUNT(*) AS count FROM reports WHERE deleted_at IS NULL GROUP BY status`).
+		Scan(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error computing stats"})
+		return
+	}
+	for _, report := range reports {
+		stats.Reports[report.Status] = report.Count
+	}
+
+	// Aktif kullanıcı için yaklaşık ölçü: aktivite kaydı veya başarılı giriş
+	if err := ac.DB.Raw(`SELECT COUNT(DISTINCT user_id) FILTER (WHERE created_at >= ?) AS daily,
+			COUNT(DISTINCT user_id) AS window_active
+		FROM (
+			SELECT user_id, created_at FROM activity_logs WHERE created_at >= ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT user_id, created_at FROM login_attempts WHERE created_at >= ? AND success AND user_id IS NOT NULL
+		) active`, dayAgo, since, since).Scan(&stats.ActiveUsers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error computing stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
+// recordAdminAction writes an audit entry for an admin mutation. It must be
+// called with the transaction of the action itself so both commit or neither does.
+func recordAdminAction(tx *gorm.DB, adminID uint, action, targetType string, targetID uint, detail interface{}) error {
+	entry := models.AdminAuditLog{
+		AdminUserID: adminID,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+	}
+	if detail != nil {
+		raw, err := json.Marshal(detail)
+		if err != nil {
+			return err
+		}
+		value := string(raw)
+		entry.Detail = &value
+	}
+	return tx.Create(&entry).Error
+}
+
+// GetAuditLog godoc
+// @Summary List admin actions (admin)
+// @Description Returns audit entries newest first, optionally filtered by acting admin, action and date range
+// @Tags admin
+// @Produce json
+// @Param actorId query integer false "Acting admin user ID"
+// @Param action query string false "Action name, e.g. place_review_approve"
+// @Param from query string false "Start date (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End date, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20, max: 50)"
+// @Success 200 {object} StandardResponse
+// @Router /admin/audit-log [get]
+func (ac *AdminController) GetAuditLog(c *gin.Context) {
+	var query AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	db := ac.DB.Model(&models.AdminAuditLog{})
+	if query.ActorID != 0 {
+		db = db.Where("admin_user_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.From != "" {
+		from, err := parseActivityDate(query.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use RFC3339 or YYYY-MM-DD", "field": "from"})
+			return
+		}
+		db = db.Where("created_at >= ?", from)
+	}
+	if query.To != "" {
+		to, err := parseActivityDate(query.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use RFC3339 or YYYY-MM-DD", "field": "to"})
+			return
+		}
+		// Sadece tarih verildiyse o günün tamamını dahil et
+		if len(query.To) == len("2006-01-02") {
+			to = to.AddDate(0, 0, 1)
+		}
+		db = db.Where("created_at < ?", to)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch audit log"})
+		return
+	}
+
+	var entries []models.AdminAuditLog
+	if err := db.Preload("AdminUser").
+		Order("created_at DESC, id DESC").
+		Offset((query.Page - 1) * query.PageSize).
+		Limit(query.PageSize).
+		Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch audit log"})
+		return
+	}
+
+	items := make([]AuditLogItem, len(entries))
+	for i, entry := range entries {
+		items[i] = AuditLogItem{
+			ID: entry.ID,
+			Actor: PostUser{
+				ID:        entry.AdminUser.ID,
+				Username:  entry.AdminUser.Username,
+				FirstName: entry.AdminUser.FirstName,
+				LastName:  entry.AdminUser.LastName,
+				Avatar:    entry.AdminUser.Avatar,
+			},
+			Action:     entry.Action,
+			TargetType: entry.TargetType,
+			TargetID:   entry.TargetID,
+			CreatedAt:  entry.CreatedAt,
+		}
+		if entry.Detail != nil {
+			items[i].Detail = json.RawMessage(*entry.Detail)
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    items,
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		},
+	})
+}
+
+// DeleteComment godoc
+// @Summary Remove a comment (admin)
+// @Description Soft-deletes the comment so it no longer appears in listings or counts, and notifies its author
+// @Tags admin
+// @Produce json
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} StandardResponse
+// @Router /admin/comments/{commentId} [delete]
+func (ac *AdminController) DeleteComment(c *gin.Context) {
+	adminID := utils.GetUser(c).UserID
+
+	var comment models.Comment
+	if err := ac.DB.First(&comment, "comment_id = ?", c.Param("commentId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Comment not found"})
+		return
+	}
+
+	tx := ac.DB.Begin()
+	if err := tx.Delete(&comment).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to remove comment"})
+		return
+	}
+
+	if err := recordAdminAction(tx, adminID, "comment_remove", "comment", comment.CommentID, gin.H{
+		"postId": comment.PostID,
+		"userId": comment.UserID,
+	}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to remove comment"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to remove comment"})
+		return
+	}
+
+	// Kaldıran yönetici yazara gösterilmez, bildirim sistemden gelir
+	notifyUser(ac.DB, comment.UserID, 0, "comment_removed", &comment.PostID)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"commentId": comment.CommentID,
+			"postId":    comment.PostID,
+		},
+		Message: "Comment removed",
+	})
+}
+
+// RestoreComment godoc
+// @Summary Restore a removed comment (admin)
+// @Tags admin
+// @Produce json
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} StandardResponse
+// @Router /admin/comments/{commentId}/restore [post]
+func (ac *AdminController) RestoreComment(c *gin.Context) {
+	var comment models.Comment
+	if err := ac.DB.Unscoped().First(&comment, "comment_id = ?", c.Param("commentId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Comment not found"})
+		return
+	}
+	if !comment.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, StandardResponse{Success: false, Message: "Comment is not removed"})
+		return
+	}
+
+	tx := ac.DB.Begin()
+	if err := tx.Unscoped().Model(&comment).Update("deleted_at", nil).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to restore comment"})
+		return
+	}
+
+	if err := recordAdminAction(tx, utils.GetUser(c).UserID, "comment_restore", "comment", comment.CommentID, gin.H{
+		"postId": comment.PostID,
+		"userId": comment.UserID,
+	}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to restore comment"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to restore comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"commentId": comment.CommentID,
+			"postId":    comment.PostID,
+		},
+		Message: "Comment restored",
+	})
+}
+
+// PurgeDeleted godoc
+// @Summary Permanently remove old soft-deleted records (admin)
+// @Description Hard-deletes users, posts and places soft-deleted longer than their retention window (RETENTION_DAYS_USERS/POSTS/PLACES), with dependent rows and stored media. Runs at most 500 records per entity; call again for more.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /admin/purge [post]
+func (ac *AdminController) PurgeDeleted(c *gin.Context) {
+	result, err := purgeSoftDeleted(ac.DB, ac.UploadController, time.Now(), utils.GetUser(c).UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to purge deleted records"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    result,
+		Message: "Purge completed",
+	})
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetStatsReflectsSeededData(t *testing.T) {
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "statsadmin")
+	reporter := createTestUser(t, db, "statsreporter")
+	reported := createTestUser(t, db, "statsreported")
+	// createTestUser bir ay önce kayıt olmuş kullanıcı oluşturur
+	if err := db.Model(&reported).Update("created_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	place := createTestPlace(t, db, "statsplace")
+	imported := createTestPlace(t, db, "statsimported")
+	flagged := createTestPlace(t, db, "statsflagged")
+	if err := db.Model(&imported).Update("place_type", "google_place").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&flagged).Updates(map[string]interface{}{"place_type": "google_place", "needs_review": true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	post := createTestPost(t, db, reporter, place, "", true)
+	old := createTestPost(t, db, reporter, place, "", true)
+	if err := db.Model(&old).Update("created_at", time.Now().AddDate(0, 0, -30)).Error; err != nil {
+		t.Fatal(err)
+	}
+	deleted := createTestPost(t, db, reporter, place, "", true)
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+	seed := []interface{}{
+		&models.Like{PostID: post.ID, UserID: admin.ID},
+		&models.Comment{PostID: post.ID, UserID: admin.ID, TextContent: "nice"},
+		&[]models.Report{
+			{ReporterUserID: reporter.ID, ReportedUserID: reported.ID, Reason: "spam", Status: "pending"},
+			{ReporterUserID: reporter.ID, ReportedUserID: reported.ID, Reason: "abuse", Status: "pending"},
+			{ReporterUserID: admin.ID, ReportedUserID: reported.ID, Reason: "spam", Status: "resolved"},
+		},
+		// Bugün ve üç gün önce aktif olan iki kullanıcı
+		&models.ActivityLog{UserID: reporter.ID, PlaceID: place.ID, Activity: "post_created", CreatedAt: time.Now()},
+		&models.LoginAttempt{Email: admin.Email, UserID: &admin.ID, Success: true, CreatedAt: time.Now().AddDate(0, 0, -3)},
+		&models.LoginAttempt{Email: reported.Email, UserID: &reported.ID, Success: false},
+	}
+	for _, row := range seed {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := callHandler(NewAdminController(db, nil).GetStats, http.MethodGet, "/admin/stats?days=7", nil, admin.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data AdminStatsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := resp.Data
+
+	checks := []struct {
+		name      string
+		got, want int64
+	}{
+		{"users.total", got.Users.Total, 3},
+		{"users.recent", got.Users.Recent, 1},
+		{"posts.total", got.Posts.Total, 2},
+		{"posts.recent", got.Posts.Recent, 1},
+		{"places.total", got.Places.Total, 3},
+		{"places.googleImported", got.Places.GoogleImported, 2},
+		{"places.needsReview", got.Places.NeedsReview, 1},
+		{"likes.total", got.Likes.Total, 1},
+		{"comments.total", got.Comments.Total, 1},
+		{"reports.pending", got.Reports["pending"], 2},
+		{"reports.resolved", got.Reports["resolved"], 1},
+		{"activeUsers.daily", got.ActiveUsers.Daily, 1},
+		{"activeUsers.window", got.ActiveUsers.Window, 2},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	if w := callHandler(NewAdminController(db, nil).GetStats, http.MethodGet, "/admin/stats?days=365", nil, admin.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("days above max: status = %d, want 400", w.Code)
+	}
+}
+
+func TestAdminActionsWriteAuditLog(t *testing.T) {
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "auditadmin")
+	other := createTestUser(t, db, "auditother")
+	place := createTestPlace(t, db, "auditplace")
+	approved := createTestPlace(t, db, "auditapproved")
+	rejected := createTestPlace(t, db, "auditrejected")
+	if err := db.Model(&models.Place{}).Where("id IN ?", []uint{approved.ID, rejected.ID}).Update("needs_review", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	pc := NewPlaceController(db, nil)
+
+	placeParam := func(p models.Place) gin.Param {
+		return gin.Param{Key: "placeId", Value: strconv.Itoa(int(p.ID))}
+	}
+	actions := []struct {
+		handler gin.HandlerFunc
+		place   models.Place
+		body    string
+		userID  uint
+	}{
+		{pc.SetPostRadiusOverride, place, `{"radius":250}`, admin.ID},
+		{pc.ReviewPlace, approved, `{"action":"approve"}`, admin.ID},
+		{pc.ReviewPlace, rejected, `{"action":"reject"}`, other.ID},
+	}
+	for _, a := range actions {
+		if w := callHandler(a.handler, http.MethodPost, "/admin", strings.NewReader(a.body), a.userID, placeParam(a.place)); w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", a.body, w.Code, w.Body.String())
+		}
+	}
+	// Başarısız işlem kayıt bırakmaz
+	if w := callHandler(pc.ReviewPlace, http.MethodPost, "/admin", strings.NewReader(`{"action":"approve"}`), admin.ID, placeParam(place)); w.Code != http.StatusNotFound {
+		t.Fatalf("review of unflagged place: status = %d, want 404", w.Code)
+	}
+
+	auditLog := func(query string) []AuditLogItem {
+		t.Helper()
+		w := callHandler(NewAdminController(db, nil).GetAuditLog, http.MethodGet, "/admin/audit-log"+query, nil, admin.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("audit log %s: status = %d, body = %s", query, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []AuditLogItem `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+
+	entries := auditLog("")
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, want 3: %+v", len(entries), entries)
+	}
+	want := []struct {
+		action string
+		actor  uint
+		target uint
+	}{
+		{"place_review_reject", other.ID, rejected.ID},
+		{"place_review_approve", admin.ID, approved.ID},
+		{"place_post_radius_set", admin.ID, place.ID},
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Action != w.action || e.Actor.ID != w.actor || e.TargetType != "place" || e.TargetID != w.target {
+			t.Errorf("entry %d = %s by %d on %s %d, want %s by %d on place %d", i, e.Action, e.Actor.ID, e.TargetType, e.TargetID, w.action, w.actor, w.target)
+		}
+	}
+	if !strings.Contains(string(entries[2].Detail), `"radius":250`) {
+		t.Errorf("radius detail = %s", entries[2].Detail)
+	}
+
+	if got := auditLog("?actorId=" + strconv.Itoa(int(other.ID))); len(got) != 1 || got[0].Action != "place_review_reject" {
+		t.Errorf("filter by actor = %+v", got)
+	}
+	if got := auditLog("?action=place_review_approve"); len(got) != 1 || got[0].TargetID != approved.ID {
+		t.Errorf("filter by action = %+v", got)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if got := auditLog("?from=" + today + "&to=" + today); len(got) != 3 {
+		t.Errorf("filter by today = %d entries, want 3", len(got))
+	}
+	if got := auditLog("?to=2000-01-01"); len(got) != 0 {
+		t.Errorf("filter before any action = %d entries, want 0", len(got))
+	}
+}
+
+func TestAdminRemoveAndRestoreComment(t *testing.T) {
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "commentadmin")
+	author := createTestUser(t, db, "commentauthor")
+	post := createTestPost(t, db, admin, createTestPlace(t, db, "commentplace"), "", true)
+	kept := models.Comment{PostID: post.ID, UserID: admin.ID, TextContent: "kept"}
+	removed := models.Comment{PostID: post.ID, UserID: author.ID, TextContent: "removed"}
+	for _, comment := range []*models.Comment{&kept, &removed} {
+		if err := db.Create(comment).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	ac := NewAdminController(db, nil)
+	commentParam := gin.Param{Key: "commentId", Value: strconv.Itoa(int(removed.CommentID))}
+
+	postComments := func() (int64, []uint) {
+		t.Helper()
+		param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+		w := callHandler(NewPostController(db, nil).GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, admin.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("post detail: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Interaction struct {
+					CommentsCount int64 `json:"commentsCount"`
+				} `json:"interaction"`
+				RecentComments []struct {
+					ID uint `json:"id"`
+				} `json:"recentComments"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		var ids []uint
+		for _, comment := range resp.Data.RecentComments {
+			ids = append(ids, comment.ID)
+		}
+		return resp.Data.Interaction.CommentsCount, ids
+	}
+	if count, ids := postComments(); count != 2 || len(ids) != 2 {
+		t.Fatalf("before removal: count = %d, comments = %v", count, ids)
+	}
+
+	if w := callHandler(ac.RestoreComment, http.MethodPost, "/admin/comments/"+commentParam.Value+"/restore", nil, admin.ID, commentParam); w.Code != http.StatusConflict {
+		t.Errorf("restore of a live comment: status = %d, want 409", w.Code)
+	}
+	if w := callHandler(ac.DeleteComment, http.MethodDelete, "/admin/comments/"+commentParam.Value, nil, admin.ID, commentParam); w.Code != http.StatusOK {
+		t.Fatalf("remove: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if count, ids := postComments(); count != 1 || len(ids) != 1 || ids[0] != kept.CommentID {
+		t.Errorf("after removal: count = %d, comments = %v; want 1, [%d]", count, ids, kept.CommentID)
+	}
+
+	// Yazar, yönetici adı olmadan sistem bildirimi alır
+	var notification models.Notification
+	if err := db.Where("user_id = ? AND type = ?", author.ID, "comment_removed").First(&notification).Error; err != nil {
+		t.Fatalf("author notification: %v", err)
+	}
+	if notification.ActorUserID != nil || notification.PostID == nil || *notification.PostID != post.ID {
+		t.Errorf("notification actor = %v, post = %v; want no actor and post %d", notification.ActorUserID, notification.PostID, post.ID)
+	}
+
+	if w := callHandler(ac.RestoreComment, http.MethodPost, "/admin/comments/"+commentParam.Value+"/restore", nil, admin.ID, commentParam); w.Code != http.StatusOK {
+		t.Fatalf("restore: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if count, ids := postComments(); count != 2 || len(ids) != 2 {
+		t.Errorf("after restore: count = %d, comments = %v", count, ids)
+	}
+
+	var actions []string
+	if err := db.Model(&models.AdminAuditLog{}).Where("target_type = ? AND target_id = ?", "comment", removed.CommentID).
+		Order("id").Pluck("action", &actions).Error; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"comment_remove", "comment_restore"}; strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Errorf("audit actions = %v, want %v", actions, want)
+	}
+}
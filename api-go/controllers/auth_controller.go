@@ -1,16 +1,20 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
 	"github.com/snap-point/api-go/utils"
@@ -51,17 +55,121 @@ func validateUsernamePattern(username string) error {
 		return fmt.Errorf("username can only contain letters, numbers, and underscores")
 	}
 	
-	// Check for reserved usernames
-	reserved := []string{"admin", "root", "api", "www", "mail", "ftp", "test", "demo", "user", "guest", "null", "undefined"}
-	for _, reservedWord := range reserved {
-		if strings.ToLower(trimmedUsername) == reservedWord {
-			return fmt.Errorf("this username is reserved and cannot be used")
-		}
+	// Check for reserved usernames (yöneticilerin eklediği kelimeler isUsernameBlocked ile)
+	if isConfigReservedUsername(trimmedUsername) {
+		return errUsernameReserved
 	}
 	
 	return nil
 }
 
+const (
+	// Ad/soyad ve biyografi için en fazla karakter (rune) sayısı
+	maxNameLength = 50
+	maxBioLength  = 150
+)
+
+// stripControlChars removes control characters such as NUL, tabs or
+// terminal escapes. Newlines survive only when keepNewlines is set.
+func stripControlChars(value string, keepNewlines bool) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' && keepNewlines {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// sanitizeName cleans a first or last name; Unicode letters are allowed but
+// the result must be non-blank and at most maxNameLength characters.
+func sanitizeName(field, value string) (string, error) {
+	name := strings.TrimSpace(stripControlChars(value, false))
+	if name == "" {
+		return "", fmt.Errorf("%s cannot be empty", field)
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return "", fmt.Errorf("%s must be no more than %d characters long", field, maxNameLength)
+	}
+	return name, nil
+}
+
+// sanitizeBio cleans a bio, keeping line breaks. An empty bio is allowed.
+func sanitizeBio(value string) (string, error) {
+	bio := strings.TrimSpace(stripControlChars(value, true))
+	if utf8.RuneCountInString(bio) > maxBioLength {
+		return "", fmt.Errorf("bio must be no more than %d characters long", maxBioLength)
+	}
+	return bio, nil
+}
+
+const (
+	// Kullanıcı adı en fazla bu aralıkla bir kez değiştirilebilir
+	usernameChangeInterval = 30 * 24 * time.Hour
+	// Eski kullanıcı adı bu süre boyunca başka kullanıcılar tarafından alınamaz
+	usernameReservationPeriod = 30 * 24 * time.Hour
+)
+
+const (
+	// Bu pencere içinde maxFailedLogins ardışık hatalı denemeden sonra hesap kilitlenir
+	maxFailedLogins    = 5
+	loginLockoutWindow = 15 * time.Minute
+)
+
+// isLoginLocked reports whether the email has reached maxFailedLogins failed
+// attempts within the lockout window since its last successful login.
+func (ac *AuthController) isLoginLocked(email string) (bool, error) {
+	since := time.Now().Add(-loginLockoutWindow)
+
+	var lastSuccess models.LoginAttempt
+	err := ac.DB.Where("email = ? AND success = ?", email, true).Order("created_at DESC").First(&lastSuccess).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return false, err
+	}
+	if err == nil && lastSuccess.CreatedAt.After(since) {
+		since = lastSuccess.CreatedAt
+	}
+
+	var failures int64
+	if err := ac.DB.Model(&models.LoginAttempt{}).
+		Where("email = ? AND success = ? AND created_at > ?", email, false, since).
+		Count(&failures).Error; err != nil {
+		return false, err
+	}
+
+	return failures >= maxFailedLogins, nil
+}
+
+// recordLoginAttempt stores a login attempt; failures here must not block login
+func (ac *AuthController) recordLoginAttempt(c *gin.Context, email string, userID *uint, success bool) {
+	attempt := models.LoginAttempt{
+		Email:     email,
+		UserID:    userID,
+		IPAddress: c.ClientIP(),
+		Success:   success,
+	}
+	if err := ac.DB.Create(&attempt).Error; err != nil {
+		log.Printf("Failed to record login attempt for %s: %v", email, err)
+	}
+}
+
+// normalizeEmail lowercases and trims an email so lookups and uniqueness are case-insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// isUsernameReserved reports whether the username was recently released by
+// another user and is still inside its reservation window.
+func isUsernameReserved(db *gorm.DB, username string, exceptUserID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.UsernameChange{}).
+		Where("LOWER(old_username) = LOWER(?) AND reserved_until > ? AND user_id <> ?", strings.TrimSpace(username), time.Now(), exceptUserID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func NewAuthController(db *gorm.DB, uploadController *UploadController) *AuthController {
 	return &AuthController{
 		DB:               db,
@@ -82,6 +190,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 		Phone        string `json:"phone"`
 		Avatar       string `json:"avatar"`
 		AvatarTempKey string `json:"avatarTempKey"`
+		ReferralCode string `json:"referralCode"`
 	}
 
 	
@@ -95,7 +204,46 @@ func (ac *AuthController) Register(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false})
 		return
 	}
+	if blocked, err := isUsernameBlocked(ac.DB, input.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username", "success": false})
+		return
+	} else if blocked {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errUsernameReserved.Error(), "success": false})
+		return
+	}
+
+	firstName, err := sanitizeName("firstName", input.FirstName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "firstName", "success": false})
+		return
+	}
+	lastName, err := sanitizeName("lastName", input.LastName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "lastName", "success": false})
+		return
+	}
+
+	if reserved, err := isUsernameReserved(ac.DB, input.Username, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username", "success": false})
+		return
+	} else if reserved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username is temporarily reserved", "success": false})
+		return
+	}
 	
+	// Davet kodu isteğe bağlıdır; verilmişse geçerli olmalıdır
+	var referrer *models.User
+	if strings.TrimSpace(input.ReferralCode) != "" {
+		referrer, err = findReferrer(ac.DB, input.ReferralCode, input.Email)
+		if errors.Is(err, errInvalidReferralCode) || errors.Is(err, errSelfReferral) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "referralCode", "success": false})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify referral code", "success": false})
+			return
+		}
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -121,10 +269,10 @@ func (ac *AuthController) Register(c *gin.Context) {
 	
 	user := models.User{
 		Username:    input.Username,
-		Email:       input.Email,
+		Email:       normalizeEmail(input.Email),
 		Password:    &hashedPasswordStr,
-		FirstName:   input.FirstName,
-		LastName:    input.LastName,
+		FirstName:   firstName,
+		LastName:    lastName,
 		Gender:      input.Gender,
 		Birthday:    birthday,
 		Phone:       phone,
@@ -132,6 +280,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 		GoogleID:    nil, // Explicitly set to nil for email registration
 		RoleID:      1, // Default role
 		Provider:    "email",
+		LinkedProviders: pq.StringArray{"email"},
 		TotalPoints: 0,
 		IsVerified:  false,
 		EmailVerified: false,
@@ -143,6 +292,14 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
+	if referrer != nil {
+		referral := models.Referral{ReferrerUserID: referrer.ID, ReferredUserID: user.ID, Status: models.ReferralPending}
+		if err := ac.DB.Create(&referral).Error; err != nil {
+			// Kayıt tamamlanmıştır; davet kaydı olmadan devam edilir
+			log.Printf("Failed to record referral of user %d by user %d: %v", user.ID, referrer.ID, err)
+		}
+	}
+
 	var finalAvatarURL string
 	if input.AvatarTempKey != "" {
 		finalAvatarURL = ac.confirmAvatarUpload(input.AvatarTempKey, user.ID)
@@ -184,7 +341,7 @@ func (ac *AuthController) VerifyEmail(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := ac.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := ac.DB.Where("LOWER(email) = ?", normalizeEmail(input.Email)).First(&user).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Email not found", "success": false})
 		return
 	}
@@ -207,7 +364,7 @@ func (ac *AuthController) RegisterEmailCheck(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := ac.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := ac.DB.Where("LOWER(email) = ?", normalizeEmail(input.Email)).First(&user).Error; err != nil {
 		// Email not found - good for registration
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
@@ -244,9 +401,32 @@ func (ac *AuthController) RegisterUsernameCheck(c *gin.Context) {
 		})
 		return
 	}
+	if blocked, err := isUsernameBlocked(ac.DB, input.Username); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Could not verify username"})
+		return
+	} else if blocked {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":   false,
+			"error":     errUsernameReserved.Error(),
+			"available": false,
+		})
+		return
+	}
+
+	if reserved, err := isUsernameReserved(ac.DB, input.Username, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Could not verify username"})
+		return
+	} else if reserved {
+		c.JSON(http.StatusConflict, gin.H{
+			"success":   false,
+			"error":     "Username is temporarily reserved",
+			"available": false,
+		})
+		return
+	}
 
 	var user models.User
-	if err := ac.DB.Where("username = ?", input.Username).First(&user).Error; err != nil {
+	if err := ac.DB.Where("LOWER(username) = LOWER(?)", strings.TrimSpace(input.Username)).First(&user).Error; err != nil {
 		// Username not found - good for registration
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
@@ -275,22 +455,41 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	email := normalizeEmail(input.Email)
+
+	// Kilitli hesaplar için de aynı genel hata döner; kilit durumu yalnızca loglanır
+	locked, err := ac.isLoginLocked(email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not process login"})
+		return
+	}
+	if locked {
+		log.Printf("Login blocked for %s from %s: too many failed attempts", email, c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
 	var user models.User
-	if err := ac.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
+	if err := ac.DB.Where("LOWER(email) = ?", email).First(&user).Error; err != nil {
+		ac.recordLoginAttempt(c, email, nil, false)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	if user.Password == nil {
+		ac.recordLoginAttempt(c, email, &user.ID, false)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(input.Password)); err != nil {
+		ac.recordLoginAttempt(c, email, &user.ID, false)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
+	ac.recordLoginAttempt(c, email, &user.ID, true)
+
 	// Get user role
 	var role models.Role
 	if err := ac.DB.First(&role, user.RoleID).Error; err != nil {
@@ -299,19 +498,24 @@ func (ac *AuthController) Login(c *gin.Context) {
 	}
 
 	// Generate JWT token
-	access_token_base := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	access_token, err := utils.SignToken(jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    role.Name,
 		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(), // Token expires in 7 days
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token", "success": false})
+		return
+	}
 
-	refresh_token_base := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	refresh_token, err := utils.SignToken(jwt.MapClaims{
 		"user_id": user.ID,
 		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(), // Refresh token expires in 30 days
 	})
-
-	access_token, err := access_token_base.SignedString([]byte(os.Getenv("JWT_SECRET")))
-	refresh_token, err := refresh_token_base.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token", "success": false})
+		return
+	}
 
 	ac.DB.Create(&models.RefreshToken{
 		UserID:         user.ID,
@@ -319,11 +523,6 @@ func (ac *AuthController) Login(c *gin.Context) {
 		ExpirationDate: time.Now().Add(time.Hour * 24 * 30), // Refresh token expires in 30 days
 	})
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token", "success": false})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"token_type":    "Bearer",
 		"access_token":  access_token,
@@ -373,25 +572,21 @@ func (ac *AuthController) RefreshToken(c *gin.Context) {
 	}
 
 	// Generate new access token
-	accessTokenBase := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	accessToken, err := utils.SignToken(jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    role.Name,
 		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(), // Access token expires in 7 days
 	})
-
-	accessToken, err := accessTokenBase.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate access token", "success": false})
 		return
 	}
 
 	// Generate new refresh token
-	refreshTokenBase := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	newRefreshToken, err := utils.SignToken(jwt.MapClaims{
 		"user_id": user.ID,
 		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(), // Refresh token expires in 30 days
 	})
-
-	newRefreshToken, err := refreshTokenBase.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate refresh token", "success": false})
 		return
@@ -418,36 +613,72 @@ func (ac *AuthController) GetProfile(c *gin.Context) {
 		return
 	}
 
-	var dbUser models.User
-	if err := ac.DB.First(&dbUser, user.UserID).Error; err != nil {
+	dbUser, err := utils.GetCurrentUser(c, ac.DB)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
+	// Ana ekran için kalan sayaçları tek sorguda topla; takip/gönderi sayıları kullanıcı satırında tutulur
+	var stats struct {
+		PendingFollowRequests int64 `gorm:"column:pending_follow_requests"`
+		UnreadNotifications   int64 `gorm:"column:unread_notifications"`
+		GlobalRank            int64 `gorm:"column:global_rank"`
+	}
+	if err := ac.DB.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM follows WHERE follows.following_user_id = ? AND follows.status = 'pending' AND follows.deleted_at IS NULL) as pending_follow_requests,
+			(SELECT COUNT(*) FROM notifications WHERE notifications.user_id = ? AND notifications.is_read = false AND `+notificationActorNotBlocked+`) as unread_notifications,
+			(SELECT COUNT(*) + 1 FROM users WHERE users.total_points > ? AND users.deleted_at IS NULL) as global_rank
+	`, dbUser.ID, dbUser.ID, dbUser.TotalPoints).Scan(&stats).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch profile stats"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"user": gin.H{
-			"id":        dbUser.ID,
-			"username":  dbUser.Username,
-			"email":     dbUser.Email,
-			"firstName": dbUser.FirstName,
-			"lastName":  dbUser.LastName,
-			"phone":     dbUser.Phone,
-			"bio":       dbUser.Bio,
-			"avatar":    dbUser.Avatar,
-			"createdAt": dbUser.CreatedAt,
-			"role":      user.Role,
+			"id":            dbUser.ID,
+			"username":      dbUser.Username,
+			"email":         dbUser.Email,
+			"firstName":     dbUser.FirstName,
+			"lastName":      dbUser.LastName,
+			"phone":         dbUser.Phone,
+			"bio":           dbUser.Bio,
+			"avatar":        dbUser.Avatar,
+			"isPrivate":     dbUser.IsPrivate,
+			"shareLocation": dbUser.ShareLocation,
+			"providers":     linkedProviders(*dbUser),
+			"createdAt":     dbUser.CreatedAt,
+			"role":          user.Role,
+		},
+		"stats": gin.H{
+			"postsCount":            dbUser.PostsCount,
+			"followersCount":        dbUser.FollowersCount,
+			"followingCount":        dbUser.FollowingCount,
+			"totalPoints":           dbUser.TotalPoints,
+			"globalRank":            stats.GlobalRank,
+			"pendingFollowRequests": stats.PendingFollowRequests,
+			"unreadNotifications":   stats.UnreadNotifications,
 		},
 	})
 }
 
 func (ac *AuthController) UpdateProfile(c *gin.Context) {
-	userID, _ := c.Get("user_id")
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
 
 	var input struct {
-		FullName string `json:"full_name"`
-		Bio      string `json:"bio"`
-		Avatar   string `json:"avatar"`
+		Username      *string `json:"username"`
+		FirstName     *string `json:"firstName"`
+		LastName      *string `json:"lastName"`
+		Bio           *string `json:"bio"`
+		Avatar        *string `json:"avatar"`
+		IsPrivate     *bool   `json:"isPrivate"`
+		ShareLocation *bool   `json:"shareLocation"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -456,34 +687,150 @@ func (ac *AuthController) UpdateProfile(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := ac.DB.First(&user, userID).Error; err != nil {
+	if err := ac.DB.First(&user, currentUser.UserID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	updates := map[string]interface{}{
-		"full_name": input.FullName,
-		"bio":       input.Bio,
-		"avatar":    input.Avatar,
+	updates := map[string]interface{}{}
+	if input.FirstName != nil {
+		firstName, err := sanitizeName("firstName", *input.FirstName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "firstName"})
+			return
+		}
+		updates["first_name"] = firstName
+	}
+	if input.LastName != nil {
+		lastName, err := sanitizeName("lastName", *input.LastName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "lastName"})
+			return
+		}
+		updates["last_name"] = lastName
+	}
+	if input.Bio != nil {
+		bio, err := sanitizeBio(*input.Bio)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "bio"})
+			return
+		}
+		updates["bio"] = bio
+	}
+	if input.Avatar != nil {
+		updates["avatar"] = *input.Avatar
+	}
+	if input.IsPrivate != nil {
+		updates["is_private"] = *input.IsPrivate
+	}
+	if input.ShareLocation != nil {
+		updates["share_location"] = *input.ShareLocation
 	}
 
-	if err := ac.DB.Model(&user).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
-		return
+	// Kullanıcı adı değişikliği: format, sıklık limiti, rezervasyon ve benzersizlik kontrolleri
+	var usernameChange *models.UsernameChange
+	if input.Username != nil && strings.TrimSpace(*input.Username) != user.Username {
+		newUsername := strings.TrimSpace(*input.Username)
+
+		if err := validateUsernamePattern(newUsername); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if blocked, err := isUsernameBlocked(ac.DB, newUsername); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username"})
+			return
+		} else if blocked {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errUsernameReserved.Error()})
+			return
+		}
+
+		var lastChange models.UsernameChange
+		err := ac.DB.Where("user_id = ?", user.ID).Order("created_at DESC").First(&lastChange).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username history"})
+			return
+		}
+		if err == nil {
+			nextAllowedAt := lastChange.CreatedAt.Add(usernameChangeInterval)
+			if time.Now().Before(nextAllowedAt) {
+				c.JSON(http.StatusTooManyRequests, gin.H{
+					"error":         fmt.Sprintf("Username can only be changed once every %d days", int(usernameChangeInterval.Hours()/24)),
+					"nextAllowedAt": nextAllowedAt,
+				})
+				return
+			}
+		}
+
+		reserved, err := isUsernameReserved(ac.DB, newUsername, user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username"})
+			return
+		}
+		if reserved {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username is temporarily reserved"})
+			return
+		}
+
+		var taken int64
+		if err := ac.DB.Model(&models.User{}).
+			Where("LOWER(username) = LOWER(?) AND id <> ?", newUsername, user.ID).
+			Count(&taken).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify username"})
+			return
+		}
+		if taken > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username already taken"})
+			return
+		}
+
+		updates["username"] = newUsername
+		usernameChange = &models.UsernameChange{
+			UserID:        user.ID,
+			OldUsername:   user.Username,
+			NewUsername:   newUsername,
+			ReservedUntil: time.Now().Add(usernameReservationPeriod),
+		}
+	}
+
+	if len(updates) > 0 {
+		tx := ac.DB.Begin()
+
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+
+		if usernameChange != nil {
+			if err := tx.Create(usernameChange).Error; err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record username change"})
+				return
+			}
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+
+		ac.DB.First(&user, user.ID)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile updated successfully",
 		"user": gin.H{
-			"id":        user.ID,
-			"username":  user.Username,
-			"email":     user.Email,
-			"firstName": user.FirstName,
-			"lastName":  user.LastName,
-			"phone":     user.Phone,
-			"bio":       user.Bio,
-			"avatar":    user.Avatar,
-			"createdAt": user.CreatedAt,
+			"id":            user.ID,
+			"username":      user.Username,
+			"email":         user.Email,
+			"firstName":     user.FirstName,
+			"lastName":      user.LastName,
+			"phone":         user.Phone,
+			"bio":           user.Bio,
+			"avatar":        user.Avatar,
+			"isPrivate":     user.IsPrivate,
+			"shareLocation": user.ShareLocation,
+			"createdAt":     user.CreatedAt,
 		},
 	})
 }
@@ -516,32 +863,48 @@ func (ac *AuthController) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully", "success": true})
 }
 
-func (ac *AuthController) GoogleLogin(c *gin.Context) {
-	var input struct {
-		IDToken      string `json:"id_token"`
-		AccessToken  string `json:"access_token"`
-		Code         string `json:"code"`
-		RedirectURI  string `json:"redirect_uri"`
-	}
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false})
+// respondGoogleError distinguishes a rejected Google credential (401) from
+// Google being unreachable or misbehaving (502).
+func respondGoogleError(c *gin.Context, err error) {
+	if errors.Is(err, config.ErrGoogleUnavailable) {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Google authentication is currently unavailable",
+			"code":    "google_unavailable",
+			"success": false,
+		})
 		return
 	}
 
-	// Verify Google ID token or exchange code
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"error":   "Invalid Google token",
+		"code":    "google_invalid_token",
+		"success": false,
+	})
+}
+
+// GoogleAuthInput carries one of the credentials accepted for Google sign-in
+type GoogleAuthInput struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+	Code        string `json:"code"`
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// resolveGoogleUser verifies the Google credential in input and returns the
+// Google profile. On failure it writes the error response and returns false.
+func (ac *AuthController) resolveGoogleUser(c *gin.Context, input GoogleAuthInput) (*config.GoogleUserInfo, bool) {
 	var userInfo *config.GoogleUserInfo
 	var err error
 
 	if input.Code != "" && input.RedirectURI != "" {
 		// Exchange authorization code for tokens
 		ctx := c.Request.Context()
-		token, err := ac.GoogleConfig.ExchangeCode(ctx, input.Code)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Failed to exchange code for token", "success": false})
-			return
+		token, exchangeErr := ac.GoogleConfig.ExchangeCode(ctx, input.Code)
+		if exchangeErr != nil {
+			respondGoogleError(c, exchangeErr)
+			return nil, false
 		}
-		
+
 		userInfo, err = ac.GoogleConfig.GetUserInfo(token.AccessToken)
 	} else if input.IDToken != "" {
 		userInfo, err = ac.GoogleConfig.VerifyIDToken(input.IDToken)
@@ -549,37 +912,73 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 		userInfo, err = ac.GoogleConfig.GetUserInfo(input.AccessToken)
 	} else {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Either code with redirect_uri, id_token, or access_token is required", "success": false})
-		return
+		return nil, false
 	}
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Google token", "success": false})
+		respondGoogleError(c, err)
+		return nil, false
+	}
+	userInfo.Email = normalizeEmail(userInfo.Email)
+
+	return userInfo, true
+}
+
+// linkedProviders returns the user's sign-in methods, deriving them for
+// accounts created before LinkedProviders existed.
+func linkedProviders(user models.User) []string {
+	if len(user.LinkedProviders) > 0 {
+		return user.LinkedProviders
+	}
+
+	providers := []string{}
+	if user.Password != nil {
+		providers = append(providers, "email")
+	}
+	if user.GoogleID != nil && *user.GoogleID != "" {
+		providers = append(providers, "google")
+	}
+	return providers
+}
+
+func (ac *AuthController) GoogleLogin(c *gin.Context) {
+	var input GoogleAuthInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false})
+		return
+	}
+
+	userInfo, ok := ac.resolveGoogleUser(c, input)
+	if !ok {
 		return
 	}
 
 	// Check if user already exists
 	var user models.User
-	userExists := ac.DB.Where("google_id = ? OR email = ?", userInfo.ID, userInfo.Email).First(&user).Error == nil
-
-	if userExists {
-		// Update existing user's Google info if needed
-		if user.GoogleID == nil || *user.GoogleID == "" {
-			user.GoogleID = &userInfo.ID
-			user.Provider = "google"
-			user.ProviderID = userInfo.ID
-			if user.Avatar == "" && userInfo.Picture != "" {
-				user.Avatar = userInfo.Picture
-			}
-			ac.DB.Save(&user)
+	userExists := ac.DB.Where("google_id = ?", userInfo.ID).First(&user).Error == nil
+
+	if !userExists {
+		// Aynı e-posta ile kayıtlı hesap varsa otomatik bağlama yapılmaz; kullanıcı
+		// e-posta ile giriş yapıp /profile/link-google ile açıkça bağlamalıdır
+		var emailUser models.User
+		if ac.DB.Where("LOWER(email) = ?", userInfo.Email).First(&emailUser).Error == nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "An account with this email already exists. Sign in with your password and link Google from your profile.",
+				"code":    "google_link_required",
+				"success": false,
+			})
+			return
 		}
-	} else {
+	}
+
+	if !userExists {
 		// Create new user
 		// Generate unique username from email
 		username := userInfo.Email
 		counter := 1
 		for {
 			var existingUser models.User
-			if ac.DB.Where("username = ?", username).First(&existingUser).Error != nil {
+			if ac.DB.Where("LOWER(username) = LOWER(?)", username).First(&existingUser).Error != nil {
 				break
 			}
 			username = userInfo.Email + strconv.Itoa(counter)
@@ -594,17 +993,18 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 		}
 
 		user = models.User{
-			Username:      username,
-			Email:         userInfo.Email,
-			FirstName:     userInfo.GivenName,
-			LastName:      userInfo.FamilyName,
-			Avatar:        userInfo.Picture,
-			GoogleID:      &userInfo.ID,
-			Provider:      "google",
-			ProviderID:    userInfo.ID,
-			RoleID:        defaultRole.ID,
-			EmailVerified: userInfo.VerifiedEmail,
-			IsVerified:    userInfo.VerifiedEmail,
+			Username:        username,
+			Email:           userInfo.Email,
+			FirstName:       userInfo.GivenName,
+			LastName:        userInfo.FamilyName,
+			Avatar:          userInfo.Picture,
+			GoogleID:        &userInfo.ID,
+			Provider:        "google",
+			ProviderID:      userInfo.ID,
+			LinkedProviders: pq.StringArray{"google"},
+			RoleID:          defaultRole.ID,
+			EmailVerified:   userInfo.VerifiedEmail,
+			IsVerified:      userInfo.VerifiedEmail,
 		}
 
 		if err := ac.DB.Create(&user).Error; err != nil {
@@ -621,24 +1021,20 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 	}
 
 	// Generate JWT tokens
-	accessTokenBase := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	accessToken, err := utils.SignToken(jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    role.Name,
 		"exp":     time.Now().Add(time.Hour * 24 * 7).Unix(),
 	})
-
-	refreshTokenBase := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(),
-	})
-
-	accessToken, err := accessTokenBase.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate access token", "success": false})
 		return
 	}
 
-	refreshToken, err := refreshTokenBase.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	refreshToken, err := utils.SignToken(jwt.MapClaims{
+		"user_id": user.ID,
+		"exp":     time.Now().Add(time.Hour * 24 * 30).Unix(),
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate refresh token", "success": false})
 		return
@@ -660,6 +1056,137 @@ func (ac *AuthController) GoogleLogin(c *gin.Context) {
 	})
 }
 
+// LinkGoogle links a Google account to the authenticated user. The Google
+// email must match the account email unless the client sends confirm=true.
+func (ac *AuthController) LinkGoogle(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context", "success": false})
+		return
+	}
+
+	var input struct {
+		GoogleAuthInput
+		Confirm bool `json:"confirm"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "success": false})
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.First(&user, currentUser.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found", "success": false})
+		return
+	}
+
+	userInfo, ok := ac.resolveGoogleUser(c, input.GoogleAuthInput)
+	if !ok {
+		return
+	}
+
+	if user.GoogleID != nil && *user.GoogleID != "" {
+		if *user.GoogleID == userInfo.ID {
+			c.JSON(http.StatusOK, gin.H{"success": true, "message": "Google account already linked", "providers": linkedProviders(user)})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "A different Google account is already linked", "code": "google_already_linked", "success": false})
+		return
+	}
+
+	// Bu Google hesabı başka bir kullanıcıya bağlı mı?
+	var otherUser models.User
+	if ac.DB.Where("google_id = ? AND id <> ?", userInfo.ID, user.ID).First(&otherUser).Error == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "This Google account is linked to another user", "code": "google_account_in_use", "success": false})
+		return
+	}
+
+	if userInfo.Email != normalizeEmail(user.Email) && !input.Confirm {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "Google email does not match your account email; resend with confirm=true to link anyway",
+			"code":        "google_email_mismatch",
+			"googleEmail": userInfo.Email,
+			"success":     false,
+		})
+		return
+	}
+
+	providers := linkedProviders(user)
+	providers = append(providers, "google")
+
+	if err := ac.DB.Model(&user).Updates(map[string]interface{}{
+		"google_id":        userInfo.ID,
+		"linked_providers": pq.StringArray(providers),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link Google account", "success": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Google account linked successfully",
+		"providers": providers,
+	})
+}
+
+// UnlinkGoogle removes the Google association from the authenticated user.
+// Accounts without a password are refused since Google is their only sign-in.
+func (ac *AuthController) UnlinkGoogle(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context", "success": false})
+		return
+	}
+
+	var user models.User
+	if err := ac.DB.First(&user, currentUser.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found", "success": false})
+		return
+	}
+
+	if user.GoogleID == nil || *user.GoogleID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No Google account is linked", "code": "google_not_linked", "success": false})
+		return
+	}
+
+	// Şifresi olmayan hesap Google bağlantısı kaldırılırsa kilitlenir
+	if user.Password == nil || *user.Password == "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Set a password before unlinking Google, otherwise you will not be able to sign in",
+			"code":    "password_required",
+			"success": false,
+		})
+		return
+	}
+
+	providers := []string{}
+	for _, provider := range linkedProviders(user) {
+		if provider != "google" {
+			providers = append(providers, provider)
+		}
+	}
+
+	updates := map[string]interface{}{
+		"google_id":        nil,
+		"linked_providers": pq.StringArray(providers),
+	}
+	if user.Provider == "google" {
+		updates["provider"] = "email"
+		updates["provider_id"] = ""
+	}
+
+	if err := ac.DB.Model(&user).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink Google account", "success": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Google account unlinked successfully",
+		"providers": providers,
+	})
+}
+
 func (ac *AuthController) confirmAvatarUpload(tempKey string, userID uint) string {
 	if ac.UploadController == nil {
 		return ""
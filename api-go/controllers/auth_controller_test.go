@@ -0,0 +1,699 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type profileStatsResponse struct {
+	Stats struct {
+		PostsCount            int64 `json:"postsCount"`
+		FollowersCount        int64 `json:"followersCount"`
+		FollowingCount        int64 `json:"followingCount"`
+		TotalPoints           int64 `json:"totalPoints"`
+		GlobalRank            int64 `json:"globalRank"`
+		PendingFollowRequests int64 `json:"pendingFollowRequests"`
+		UnreadNotifications   int64 `json:"unreadNotifications"`
+	} `json:"stats"`
+}
+
+func TestGetProfileStats(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "profileme")
+	place := createTestPlace(t, db, "profileplace")
+
+	createTestPost(t, db, me, place, "first", true)
+	createTestPost(t, db, me, place, "second", false)
+	deleted := createTestPost(t, db, me, place, "deleted", true)
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	follow := func(follower, following models.User, status string) {
+		t.Helper()
+		if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: following.ID, Status: status}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	fan := createTestUser(t, db, "profilefan")
+	friend := createTestUser(t, db, "profilefriend")
+	requester := createTestUser(t, db, "profilerequester")
+	follow(fan, me, "accepted")
+	follow(friend, me, "accepted")
+	follow(me, friend, "accepted")
+	follow(requester, me, "pending")
+
+	notifyUser(db, me.ID, fan.ID, "follow", nil)
+	notifyUser(db, me.ID, requester.ID, "follow_request", nil)
+	if err := db.Model(&models.Notification{}).Where("user_id = ? AND type = ?", me.ID, "follow").Update("is_read", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// me iki kullanıcının gerisinde, birinin önünde
+	points := map[uint]int64{me.ID: 50, fan.ID: 80, friend.ID: 120, requester.ID: 10}
+	for id, p := range points {
+		if err := db.Model(&models.User{}).Where("id = ?", id).Update("total_points", p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Kayıtlar doğrudan yazıldığı için önbellek sayaçları uzlaştırılır
+	if _, err := reconcileUserCounts(db); err != nil {
+		t.Fatal(err)
+	}
+
+	ac := NewAuthController(db, nil)
+	w := callHandler(ac.GetProfile, http.MethodGet, "/profile", nil, me.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp profileStatsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	got := resp.Stats
+	checks := []struct {
+		name      string
+		got, want int64
+	}{
+		{"postsCount", got.PostsCount, 2},
+		{"followersCount", got.FollowersCount, 2},
+		{"followingCount", got.FollowingCount, 1},
+		{"totalPoints", got.TotalPoints, 50},
+		{"globalRank", got.GlobalRank, 3},
+		{"pendingFollowRequests", got.PendingFollowRequests, 1},
+		{"unreadNotifications", got.UnreadNotifications, 1},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestUpdateProfileUsernameCooldown(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "cooldownme")
+	other := createTestUser(t, db, "cooldownother")
+	ac := NewAuthController(db, nil)
+
+	rename := func(userID uint, username string) int {
+		t.Helper()
+		w := callHandler(ac.UpdateProfile, http.MethodPut, "/profile", strings.NewReader(`{"username":"`+username+`"}`), userID)
+		return w.Code
+	}
+
+	if code := rename(me.ID, "cooldownnew"); code != http.StatusOK {
+		t.Fatalf("first change: status = %d, want %d", code, http.StatusOK)
+	}
+
+	// İkinci değişiklik 30 gün dolmadan reddedilir
+	w := callHandler(ac.UpdateProfile, http.MethodPut, "/profile", strings.NewReader(`{"username":"cooldownagain"}`), me.ID)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second change: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	var resp struct {
+		NextAllowedAt time.Time `json:"nextAllowedAt"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if wait := time.Until(resp.NextAllowedAt); wait < 29*24*time.Hour || wait > 30*24*time.Hour {
+		t.Errorf("nextAllowedAt = %v, want about 30 days from now", resp.NextAllowedAt)
+	}
+
+	// Eski kullanıcı adı başkası tarafından alınamaz
+	if code := rename(other.ID, "cooldownme"); code != http.StatusConflict {
+		t.Errorf("other user taking released username: status = %d, want %d", code, http.StatusConflict)
+	}
+
+	// Limit dolduktan sonra değişiklik yeniden serbest
+	if err := db.Model(&models.UsernameChange{}).Where("user_id = ?", me.ID).
+		Update("created_at", time.Now().Add(-usernameChangeInterval-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := rename(me.ID, "cooldownagain"); code != http.StatusOK {
+		t.Errorf("change after interval: status = %d, want %d", code, http.StatusOK)
+	}
+
+	var changes int64
+	db.Model(&models.UsernameChange{}).Where("user_id = ?", me.ID).Count(&changes)
+	if changes != 2 {
+		t.Errorf("username changes = %d, want 2", changes)
+	}
+}
+
+func TestUsernameReservationExpires(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "reserveme")
+	if err := db.Create(&models.UsernameChange{
+		UserID:        me.ID,
+		OldUsername:   "expiredname",
+		NewUsername:   "reserveme",
+		ReservedUntil: time.Now().Add(-time.Minute),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.UsernameChange{
+		UserID:        me.ID,
+		OldUsername:   "heldname",
+		NewUsername:   "reserveme",
+		ReservedUntil: time.Now().Add(time.Hour),
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		username     string
+		exceptUserID uint
+		want         bool
+	}{
+		{"expiredname", 0, false},
+		{"heldname", 0, true},
+		{"HeldName", 0, true},
+		// Kullanıcı kendi eski adına geri dönebilir
+		{"heldname", me.ID, false},
+	}
+	for _, tt := range tests {
+		got, err := isUsernameReserved(db, tt.username, tt.exceptUserID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("isUsernameReserved(%q, %d) = %v, want %v", tt.username, tt.exceptUserID, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterRejectsCaseInsensitiveDuplicates(t *testing.T) {
+	db := openTestDB(t)
+	// Register varsayılan olarak RoleID 1 kullanır
+	createTestUser(t, db, "Existing")
+	ac := NewAuthController(db, nil)
+
+	register := func(username, email string) int {
+		t.Helper()
+		body := `{"username":"` + username + `","email":"` + email + `","password":"secret123","firstName":"A","lastName":"B"}`
+		return callHandler(ac.Register, http.MethodPost, "/auth/register", strings.NewReader(body), 0).Code
+	}
+
+	if code := register("existing", "fresh@example.com"); code != http.StatusBadRequest {
+		t.Errorf("mixed-case username duplicate: status %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := register("Newcomer", "EXISTING@Example.com"); code != http.StatusBadRequest {
+		t.Errorf("mixed-case email duplicate: status %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := register("Newcomer", "Newcomer@Example.com"); code != http.StatusCreated {
+		t.Fatalf("distinct user: status %d, want %d", code, http.StatusCreated)
+	}
+
+	var stored models.User
+	if err := db.Where("username = ?", "Newcomer").First(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Email != "newcomer@example.com" {
+		t.Errorf("stored email = %q, want lowercased", stored.Email)
+	}
+}
+
+func TestRegisterChecksAreCaseInsensitive(t *testing.T) {
+	db := openTestDB(t)
+	createTestUser(t, db, "Taken")
+	ac := NewAuthController(db, nil)
+
+	cases := []struct {
+		name    string
+		handler func(*gin.Context)
+		body    string
+		want    int
+	}{
+		{"email taken", ac.RegisterEmailCheck, `{"email":"TAKEN@EXAMPLE.COM"}`, http.StatusConflict},
+		{"email free", ac.RegisterEmailCheck, `{"email":"free@example.com"}`, http.StatusOK},
+		{"username taken", ac.RegisterUsernameCheck, `{"username":"tAKEN"}`, http.StatusConflict},
+		{"username free", ac.RegisterUsernameCheck, `{"username":"freename"}`, http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := callHandler(tc.handler, http.MethodPost, "/auth/check", strings.NewReader(tc.body), 0)
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
+
+func TestMigrateFailsOnCaseInsensitiveDuplicates(t *testing.T) {
+	db := openTestDB(t)
+	// Eski şemayı taklit et: indeks yokken yalnızca harf büyüklüğü farklı kayıtlar
+	if err := db.Exec("DROP INDEX idx_users_email_lower").Error; err != nil {
+		t.Fatal(err)
+	}
+	createTestUser(t, db, "dupe")
+	other := createTestUser(t, db, "dupe2")
+	if err := db.Model(&other).Update("email", "DUPE@example.com").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	err := config.Migrate(db)
+	if err == nil || !strings.Contains(err.Error(), "email") {
+		t.Fatalf("Migrate error = %v, want duplicate email error", err)
+	}
+	if db.Migrator().HasIndex(&models.User{}, "idx_users_email_lower") {
+		t.Error("index created despite duplicates")
+	}
+
+	if err := db.Model(&other).Update("email", "dupe2@example.com").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Migrate(db); err != nil {
+		t.Fatalf("Migrate after fixing duplicates: %v", err)
+	}
+}
+
+func TestGoogleLoginUserInfoFailure(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"access","token_type":"Bearer"}`))
+	}))
+	defer tokenServer.Close()
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantCode   string
+	}{
+		{"userinfo rejects token", http.StatusUnauthorized, http.StatusUnauthorized, "google_invalid_token"},
+		{"userinfo down", http.StatusServiceUnavailable, http.StatusBadGateway, "google_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userInfoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer userInfoServer.Close()
+
+			ac := &AuthController{GoogleConfig: &config.GoogleConfig{
+				UserInfoURL: userInfoServer.URL,
+				Config: &oauth2.Config{
+					ClientID: "web",
+					Endpoint: oauth2.Endpoint{TokenURL: tokenServer.URL, AuthStyle: oauth2.AuthStyleInParams},
+				},
+			}}
+
+			// Kod değişimi başarılı, ardından userinfo çağrısı başarısız
+			for _, body := range []string{
+				`{"code":"code","redirect_uri":"app://callback"}`,
+				`{"access_token":"access"}`,
+			} {
+				w := callHandler(ac.GoogleLogin, http.MethodPost, "/auth/google", strings.NewReader(body), 0)
+				if w.Code != tt.wantStatus {
+					t.Fatalf("%s: status %d, want %d", body, w.Code, tt.wantStatus)
+				}
+				var resp struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.Code != tt.wantCode {
+					t.Errorf("%s: code %q, want %q", body, resp.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+// stubGoogleIDToken returns a GoogleConfig whose tokeninfo endpoint accepts any
+// ID token as the given Google account.
+func stubGoogleIDToken(t *testing.T, googleID, email string) *config.GoogleConfig {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"sub":            googleID,
+			"aud":            "web",
+			"email":          email,
+			"email_verified": "true",
+		})
+	}))
+	t.Cleanup(server.Close)
+	return &config.GoogleConfig{ClientID: "web", AllowedClientIDs: []string{"web"}, TokenInfoURL: server.URL}
+}
+
+func TestLinkGoogle(t *testing.T) {
+	db := openTestDB(t)
+
+	type linkResponse struct {
+		Code      string   `json:"code"`
+		Providers []string `json:"providers"`
+	}
+	link := func(ac *AuthController, user models.User, body string) (int, linkResponse) {
+		t.Helper()
+		w := callHandler(ac.LinkGoogle, http.MethodPost, "/profile/link-google", strings.NewReader(body), user.ID)
+		var resp linkResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, resp
+	}
+	newEmailUser := func(username string) models.User {
+		t.Helper()
+		user := createTestUser(t, db, username)
+		if err := db.Model(&user).Update("password", "hash").Error; err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+
+	t.Run("matching email", func(t *testing.T) {
+		user := newEmailUser("linkmatch")
+		ac := &AuthController{DB: db, GoogleConfig: stubGoogleIDToken(t, "g-match", "LinkMatch@example.com")}
+
+		code, resp := link(ac, user, `{"id_token":"token"}`)
+		if code != http.StatusOK {
+			t.Fatalf("status %d, want %d", code, http.StatusOK)
+		}
+		if !reflect.DeepEqual(resp.Providers, []string{"email", "google"}) {
+			t.Errorf("providers = %v, want [email google]", resp.Providers)
+		}
+		var stored models.User
+		db.First(&stored, user.ID)
+		if stored.GoogleID == nil || *stored.GoogleID != "g-match" {
+			t.Errorf("google_id = %v, want g-match", stored.GoogleID)
+		}
+
+		// Aynı hesabı tekrar bağlamak zararsızdır
+		if code, _ := link(ac, user, `{"id_token":"token"}`); code != http.StatusOK {
+			t.Errorf("relink: status %d, want %d", code, http.StatusOK)
+		}
+	})
+
+	t.Run("email mismatch needs confirmation", func(t *testing.T) {
+		user := newEmailUser("linkmismatch")
+		ac := &AuthController{DB: db, GoogleConfig: stubGoogleIDToken(t, "g-mismatch", "someone.else@example.com")}
+
+		code, resp := link(ac, user, `{"id_token":"token"}`)
+		if code != http.StatusConflict || resp.Code != "google_email_mismatch" {
+			t.Fatalf("unconfirmed: status %d code %q, want 409 google_email_mismatch", code, resp.Code)
+		}
+		if code, _ := link(ac, user, `{"id_token":"token","confirm":true}`); code != http.StatusOK {
+			t.Errorf("confirmed: status %d, want %d", code, http.StatusOK)
+		}
+	})
+
+	t.Run("google account linked to another user", func(t *testing.T) {
+		owner := newEmailUser("linkowner")
+		if err := db.Model(&owner).Update("google_id", "g-taken").Error; err != nil {
+			t.Fatal(err)
+		}
+		user := newEmailUser("linkintruder")
+		ac := &AuthController{DB: db, GoogleConfig: stubGoogleIDToken(t, "g-taken", "linkintruder@example.com")}
+
+		if code, resp := link(ac, user, `{"id_token":"token"}`); code != http.StatusConflict || resp.Code != "google_account_in_use" {
+			t.Errorf("status %d code %q, want 409 google_account_in_use", code, resp.Code)
+		}
+	})
+
+	t.Run("different google account already linked", func(t *testing.T) {
+		user := newEmailUser("linkswap")
+		if err := db.Model(&user).Update("google_id", "g-first").Error; err != nil {
+			t.Fatal(err)
+		}
+		ac := &AuthController{DB: db, GoogleConfig: stubGoogleIDToken(t, "g-second", "linkswap@example.com")}
+
+		if code, resp := link(ac, user, `{"id_token":"token"}`); code != http.StatusConflict || resp.Code != "google_already_linked" {
+			t.Errorf("status %d code %q, want 409 google_already_linked", code, resp.Code)
+		}
+	})
+}
+
+func TestGoogleLoginDoesNotTakeOverEmailAccount(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "emailonly")
+	ac := &AuthController{DB: db, GoogleConfig: stubGoogleIDToken(t, "g-new", "EmailOnly@example.com")}
+
+	w := callHandler(ac.GoogleLogin, http.MethodPost, "/auth/google", strings.NewReader(`{"id_token":"token"}`), 0)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "google_link_required") {
+		t.Fatalf("status %d body %s, want 409 google_link_required", w.Code, w.Body.String())
+	}
+
+	var stored models.User
+	db.First(&stored, user.ID)
+	if stored.GoogleID != nil {
+		t.Errorf("google_id = %q, want unlinked", *stored.GoogleID)
+	}
+}
+
+func TestUnlinkGoogle(t *testing.T) {
+	db := openTestDB(t)
+	ac := NewAuthController(db, nil)
+
+	googleUser := func(username string, password *string) models.User {
+		t.Helper()
+		user := createTestUser(t, db, username)
+		if err := db.Model(&user).Updates(map[string]interface{}{
+			"google_id":        "g-" + username,
+			"provider":         "google",
+			"provider_id":      "g-" + username,
+			"password":         password,
+			"linked_providers": pq.StringArray{"email", "google"},
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+		return user
+	}
+
+	t.Run("with password", func(t *testing.T) {
+		password := "hash"
+		user := googleUser("unlinkpw", &password)
+
+		w := callHandler(ac.UnlinkGoogle, http.MethodDelete, "/profile/link-google", nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status %d, want %d", w.Code, http.StatusOK)
+		}
+		var stored models.User
+		db.First(&stored, user.ID)
+		if stored.GoogleID != nil {
+			t.Errorf("google_id = %q, want cleared", *stored.GoogleID)
+		}
+		if stored.Provider != "email" || stored.ProviderID != "" {
+			t.Errorf("provider = %q/%q, want email with no provider id", stored.Provider, stored.ProviderID)
+		}
+		if !reflect.DeepEqual([]string(stored.LinkedProviders), []string{"email"}) {
+			t.Errorf("linked_providers = %v, want [email]", stored.LinkedProviders)
+		}
+	})
+
+	t.Run("without password", func(t *testing.T) {
+		user := googleUser("unlinknopw", nil)
+
+		w := callHandler(ac.UnlinkGoogle, http.MethodDelete, "/profile/link-google", nil, user.ID)
+		if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "password_required") {
+			t.Fatalf("status %d body %s, want 409 password_required", w.Code, w.Body.String())
+		}
+		var stored models.User
+		db.First(&stored, user.ID)
+		if stored.GoogleID == nil {
+			t.Error("google_id cleared despite missing password")
+		}
+	})
+
+	t.Run("nothing linked", func(t *testing.T) {
+		user := createTestUser(t, db, "unlinknone")
+		w := callHandler(ac.UnlinkGoogle, http.MethodDelete, "/profile/link-google", nil, user.ID)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+// createPasswordUser stores a test user whose password is "correct-password"
+func createPasswordUser(t *testing.T, db *gorm.DB, username string) models.User {
+	t.Helper()
+	user := createTestUser(t, db, username)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&user).Update("password", string(hash)).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestLoginLockout(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("JWT_SECRET", "test-secret")
+	ac := NewAuthController(db, nil)
+
+	login := func(email, password string) int {
+		t.Helper()
+		body := `{"email":"` + email + `","password":"` + password + `"}`
+		return callHandler(ac.Login, http.MethodPost, "/auth/login", strings.NewReader(body), 0).Code
+	}
+
+	t.Run("locks after repeated failures", func(t *testing.T) {
+		createPasswordUser(t, db, "lockme")
+		for i := 0; i < maxFailedLogins; i++ {
+			if code := login("lockme@example.com", "wrong"); code != http.StatusUnauthorized {
+				t.Fatalf("failure %d: status %d, want %d", i, code, http.StatusUnauthorized)
+			}
+		}
+
+		// Doğru şifre de aynı genel hatayı alır
+		w := callHandler(ac.Login, http.MethodPost, "/auth/login",
+			strings.NewReader(`{"email":"LockMe@example.com","password":"correct-password"}`), 0)
+		if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "Invalid credentials") {
+			t.Fatalf("locked login: status %d body %s, want generic 401", w.Code, w.Body.String())
+		}
+
+		// Pencere geçtikten sonra giriş tekrar mümkün
+		if err := db.Model(&models.LoginAttempt{}).Where("email = ?", "lockme@example.com").
+			Update("created_at", time.Now().Add(-loginLockoutWindow-time.Minute)).Error; err != nil {
+			t.Fatal(err)
+		}
+		if code := login("lockme@example.com", "correct-password"); code != http.StatusOK {
+			t.Errorf("after window: status %d, want %d", code, http.StatusOK)
+		}
+	})
+
+	t.Run("success resets the count", func(t *testing.T) {
+		createPasswordUser(t, db, "resetme")
+		for round := 0; round < 2; round++ {
+			for i := 0; i < maxFailedLogins-1; i++ {
+				login("resetme@example.com", "wrong")
+			}
+			if code := login("resetme@example.com", "correct-password"); code != http.StatusOK {
+				t.Fatalf("round %d: status %d, want %d", round, code, http.StatusOK)
+			}
+		}
+
+		var failures int64
+		db.Model(&models.LoginAttempt{}).Where("email = ? AND success = ?", "resetme@example.com", false).Count(&failures)
+		if failures != 2*(maxFailedLogins-1) {
+			t.Errorf("recorded %d failures, want %d", failures, 2*(maxFailedLogins-1))
+		}
+	})
+
+	t.Run("unknown email is tracked too", func(t *testing.T) {
+		for i := 0; i < maxFailedLogins; i++ {
+			login("nobody@example.com", "wrong")
+		}
+		locked, err := ac.isLoginLocked("nobody@example.com")
+		if err != nil || !locked {
+			t.Errorf("isLoginLocked = %v, %v; want locked", locked, err)
+		}
+	})
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"Ayşe", "Ayşe", false},
+		{"  Çağrı  ", "Çağrı", false},
+		{"Zoë 🙂", "Zoë 🙂", false},
+		{"Jo\x00hn\x1b", "John", false},
+		{"Ali\tVeli", "AliVeli", false},
+		{"   ", "", true},
+		{"\x00\x07\n", "", true},
+		{strings.Repeat("ş", maxNameLength), strings.Repeat("ş", maxNameLength), false},
+		{strings.Repeat("ş", maxNameLength+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := sanitizeName("firstName", tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("sanitizeName(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSanitizeBio(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"line one\nline two", "line one\nline two", false},
+		{"bell\x07 and tab\t", "bell and tab", false},
+		{"  \n  ", "", false},
+		{strings.Repeat("🙂", maxBioLength), strings.Repeat("🙂", maxBioLength), false},
+		{strings.Repeat("🙂", maxBioLength+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := sanitizeBio(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("sanitizeBio(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRegisterAndUpdateProfileValidateNames(t *testing.T) {
+	db := openTestDB(t)
+	createTestUser(t, db, "nameseed")
+	ac := NewAuthController(db, nil)
+
+	register := func(firstName string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(map[string]string{
+			"username": "namecheck", "email": "namecheck@example.com", "password": "secret123",
+			"firstName": firstName, "lastName": "Yılmaz",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(ac.Register, http.MethodPost, "/auth/register", bytes.NewReader(body), 0)
+	}
+	if w := register(" \t "); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"field":"firstName"`) {
+		t.Errorf("blank first name: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := register(strings.Repeat("a", maxNameLength+1)); w.Code != http.StatusBadRequest {
+		t.Errorf("long first name: status = %d, want 400", w.Code)
+	}
+	if w := register("Ay\x00şe "); w.Code != http.StatusCreated {
+		t.Fatalf("register: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var user models.User
+	if err := db.Where("username = ?", "namecheck").First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.FirstName != "Ayşe" {
+		t.Errorf("stored first name = %q, want Ayşe", user.FirstName)
+	}
+
+	update := func(body string) *httptest.ResponseRecorder {
+		t.Helper()
+		return callHandler(ac.UpdateProfile, http.MethodPut, "/profile", strings.NewReader(body), user.ID)
+	}
+	if w := update(`{"lastName":"   "}`); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"field":"lastName"`) {
+		t.Errorf("blank last name: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := update(`{"bio":"` + strings.Repeat("x", maxBioLength+1) + `"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("long bio: status = %d, want 400", w.Code)
+	}
+	if w := update(`{"bio":"merhaba\u0007\ndünya"}`); w.Code != http.StatusOK {
+		t.Fatalf("update bio: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if err := db.First(&user, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Bio != "merhaba\ndünya" {
+		t.Errorf("stored bio = %q, want control character removed", user.Bio)
+	}
+}
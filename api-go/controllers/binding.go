@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/snap-point/api-go/media"
+)
+
+// İstek yapılarındaki `mediatype` etiketi kabul edilen türleri media paketinden okur
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation(media.ValidationTag, func(fl validator.FieldLevel) bool {
+			return media.IsValid(fl.Field().String())
+		})
+	}
+}
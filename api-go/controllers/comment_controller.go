@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Kalıcı bağlantıda yorumun önünde/arkasında gösterilen kardeş ve altında gösterilen yanıt sayısı
+const (
+	commentContextSiblings = 3
+	commentContextReplies  = 5
+)
+
+type CommentController struct {
+	DB               *gorm.DB
+	UploadController *UploadController
+}
+
+type CommentItem struct {
+	ID        uint      `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+	IsEdited  bool      `json:"isEdited"`
+	ParentID  *uint     `json:"parentId"`
+	User      PostUser  `json:"user"`
+}
+
+type CommentPostSummary struct {
+	ID           uint      `json:"id"`
+	Caption      string    `json:"caption"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	MediaType    string    `json:"mediaType"`
+	User         PostUser  `json:"user"`
+	Place        PostPlace `json:"place"`
+}
+
+// CommentContext is a comment with what the client needs to show it in place:
+// its post, its parent when it is a reply, the neighbouring comments in the
+// same thread (oldest first) and its first replies.
+type CommentContext struct {
+	Comment CommentItem        `json:"comment"`
+	Post    CommentPostSummary `json:"post"`
+	Parent  *CommentItem       `json:"parent"`
+	Before  []CommentItem      `json:"before"`
+	After   []CommentItem      `json:"after"`
+	Replies []CommentItem      `json:"replies"`
+}
+
+type commentRow struct {
+	ID              uint      `gorm:"column:comment_id"`
+	PostID          uint      `gorm:"column:post_id"`
+	ParentCommentID *uint     `gorm:"column:parent_comment_id"`
+	Content         string    `gorm:"column:text_content"`
+	CreatedAt       time.Time `gorm:"column:created_at"`
+	IsEdited        bool      `gorm:"column:is_edited"`
+	UserID          uint      `gorm:"column:user_id"`
+	Username        string    `gorm:"column:username"`
+	FirstName       string    `gorm:"column:first_name"`
+	LastName        string    `gorm:"column:last_name"`
+	Avatar          string    `gorm:"column:avatar"`
+}
+
+func NewCommentController(db *gorm.DB, uploadController *UploadController) *CommentController {
+	return &CommentController{DB: db, UploadController: uploadController}
+}
+
+// visibleComments selects undeleted comments whose author has no block with
+// the viewer in either direction.
+func (cc *CommentController) visibleComments(viewerID uint) *gorm.DB {
+	return cc.DB.Table("comments").
+		Select(`comments.comment_id, comments.post_id, comments.parent_comment_id, comments.text_content,
+			comments.created_at, comments.is_edited, users.id as user_id, users.username,
+			users.first_name, users.last_name, users.avatar`).
+		Joins("JOIN users ON users.id = comments.user_id").
+		Where("comments.deleted_at IS NULL").
+		Where(`NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+			((blocks.blocker_user_id = ? AND blocks.blocked_user_id = comments.user_id) OR
+			 (blocks.blocker_user_id = comments.user_id AND blocks.blocked_user_id = ?)))`, viewerID, viewerID)
+}
+
+func (row commentRow) item() CommentItem {
+	return CommentItem{
+		ID:        row.ID,
+		Content:   row.Content,
+		CreatedAt: row.CreatedAt,
+		IsEdited:  row.IsEdited,
+		ParentID:  row.ParentCommentID,
+		User: PostUser{
+			ID:        row.UserID,
+			Username:  row.Username,
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Avatar:    row.Avatar,
+		},
+	}
+}
+
+func commentItems(rows []commentRow) []CommentItem {
+	items := make([]CommentItem, len(rows))
+	for i, row := range rows {
+		items[i] = row.item()
+	}
+	return items
+}
+
+// GetCommentContext godoc
+// @Summary Get a comment with its surrounding context
+// @Description Returns the comment, a summary of its post, its parent when it is a reply, up to 3 comments before and after it in the same thread and its first 5 replies. Used to deep-link to a comment.
+// @Tags comments
+// @Produce json
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} StandardResponse
+// @Router /comments/{commentId} [get]
+func (cc *CommentController) GetCommentContext(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	commentID, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Invalid comment ID"})
+		return
+	}
+
+	var comment commentRow
+	result := cc.visibleComments(user.UserID).Where("comments.comment_id = ?", commentID).Limit(1).Find(&comment)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Comment not found"})
+		return
+	}
+
+	// Gönderiyi göremeyen kullanıcı yorumunu da göremez
+	var post struct {
+		ID            uint      `gorm:"column:id"`
+		Caption       string    `gorm:"column:post_caption"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+		ThumbnailURL  string    `gorm:"column:thumbnail_url"`
+		MediaType     string    `gorm:"column:media_type"`
+		UserID        uint      `gorm:"column:user_id"`
+		Username      string    `gorm:"column:username"`
+		UserFirstName string    `gorm:"column:user_first_name"`
+		UserLastName  string    `gorm:"column:user_last_name"`
+		UserAvatar    string    `gorm:"column:user_avatar"`
+		PlaceID       uint      `gorm:"column:place_id"`
+		PlaceName     string    `gorm:"column:place_name"`
+		PlaceImage    string    `gorm:"column:place_image"`
+	}
+	visibility, visibilityArgs := visiblePostsCondition(user.UserID)
+	result = cc.DB.Model(&models.Post{}).
+		Select(`
+			posts.id,
+			posts.post_caption,
+			posts.created_at,
+			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
+			posts.user_id,
+			users.username,
+			users.first_name as user_first_name,
+			users.last_name as user_last_name,
+			users.avatar as user_avatar,
+			posts.place_id,
+			places.name as place_name,
+			places.place_image as place_image
+		`).
+		Joins("JOIN users ON posts.user_id = users.id").
+		Joins("JOIN places ON posts.place_id = places.id").
+		Where("posts.id = ?", comment.PostID).
+		Where(visibility, visibilityArgs...).
+		Limit(1).
+		Find(&post)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Comment not found"})
+		return
+	}
+
+	response := CommentContext{
+		Comment: comment.item(),
+		Post: CommentPostSummary{
+			ID:           post.ID,
+			Caption:      post.Caption,
+			CreatedAt:    post.CreatedAt,
+			ThumbnailURL: cc.UploadController.mediaReadURL(post.ThumbnailURL),
+			MediaType:    post.MediaType,
+			User: PostUser{
+				ID:        post.UserID,
+				Username:  post.Username,
+				FirstName: post.UserFirstName,
+				LastName:  post.UserLastName,
+				Avatar:    post.UserAvatar,
+			},
+			Place: PostPlace{
+				ID:    post.PlaceID,
+				Name:  post.PlaceName,
+				Image: post.PlaceImage,
+			},
+		},
+	}
+
+	// Silinmiş veya engellenmiş kullanıcıya ait üst yorum gösterilmez
+	if comment.ParentCommentID != nil {
+		var parent []commentRow
+		if err := cc.visibleComments(user.UserID).Where("comments.comment_id = ?", *comment.ParentCommentID).Limit(1).Find(&parent).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+			return
+		}
+		if len(parent) > 0 {
+			item := parent[0].item()
+			response.Parent = &item
+		}
+	}
+
+	// Aynı başlıktaki kardeşler: üst yorumu aynı olan (veya ikisi de üst düzey) yorumlar
+	thread := func() *gorm.DB {
+		query := cc.visibleComments(user.UserID).Where("comments.post_id = ?", comment.PostID)
+		if comment.ParentCommentID != nil {
+			return query.Where("comments.parent_comment_id = ?", *comment.ParentCommentID)
+		}
+		return query.Where("comments.parent_comment_id IS NULL")
+	}
+
+	var before, after, replies []commentRow
+	if err := thread().
+		Where("(comments.created_at, comments.comment_id) < (?, ?)", comment.CreatedAt, comment.ID).
+		Order("comments.created_at DESC, comments.comment_id DESC").
+		Limit(commentContextSiblings).
+		Find(&before).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+		return
+	}
+	if err := thread().
+		Where("(comments.created_at, comments.comment_id) > (?, ?)", comment.CreatedAt, comment.ID).
+		Order("comments.created_at, comments.comment_id").
+		Limit(commentContextSiblings).
+		Find(&after).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+		return
+	}
+	if err := cc.visibleComments(user.UserID).
+		Where("comments.parent_comment_id = ?", comment.ID).
+		Order("comments.created_at, comments.comment_id").
+		Limit(commentContextReplies).
+		Find(&replies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch comment"})
+		return
+	}
+
+	// Öncekiler en yeniden eskiye okunur; istemci sırayla göstersin diye çevrilir
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+	response.Before = commentItems(before)
+	response.After = commentItems(after)
+	response.Replies = commentItems(replies)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    response,
+	})
+}
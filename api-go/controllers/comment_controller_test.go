@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+func createTestComment(t testing.TB, db *gorm.DB, user models.User, post models.Post, parent *models.Comment, text string, createdAt time.Time) models.Comment {
+	t.Helper()
+	comment := models.Comment{PostID: post.ID, UserID: user.ID, TextContent: text, CreatedAt: createdAt}
+	if parent != nil {
+		comment.ParentCommentID = &parent.CommentID
+	}
+	if err := db.Create(&comment).Error; err != nil {
+		t.Fatalf("create comment %q: %v", text, err)
+	}
+	return comment
+}
+
+func TestGetCommentContext(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "ctxauthor")
+	viewer := createTestUser(t, db, "ctxviewer")
+	blocked := createTestUser(t, db, "ctxblocked")
+	place := createTestPlace(t, db, "ctxplace")
+	post := createTestPost(t, db, author, place, "context", true)
+	if err := db.Create(&models.Block{BlockerUserID: viewer.ID, BlockedUserID: blocked.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	var top []models.Comment
+	for i := 0; i < 6; i++ {
+		top = append(top, createTestComment(t, db, author, post, nil, "top "+strconv.Itoa(i), base.Add(time.Duration(i)*time.Minute)))
+	}
+	target := top[4]
+	hidden := createTestComment(t, db, blocked, post, nil, "blocked top", base.Add(270*time.Second))
+	reply1 := createTestComment(t, db, viewer, post, &target, "reply 1", base.Add(10*time.Minute))
+	reply2 := createTestComment(t, db, author, post, &target, "reply 2", base.Add(11*time.Minute))
+	blockedReply := createTestComment(t, db, blocked, post, &target, "blocked reply", base.Add(12*time.Minute))
+	deleted := createTestComment(t, db, author, post, nil, "deleted", base.Add(20*time.Minute))
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cc := NewCommentController(db, nil)
+	get := func(viewerID, commentID uint) (int, CommentContext) {
+		t.Helper()
+		param := gin.Param{Key: "commentId", Value: strconv.Itoa(int(commentID))}
+		w := callHandler(cc.GetCommentContext, http.MethodGet, "/comments/"+param.Value, nil, viewerID, param)
+		var resp struct {
+			Data CommentContext `json:"data"`
+		}
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code, resp.Data
+	}
+	ids := func(items []CommentItem) []uint {
+		out := []uint{}
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+	equal := func(got, want []uint) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("top-level comment", func(t *testing.T) {
+		code, ctx := get(viewer.ID, target.CommentID)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d", code)
+		}
+		if ctx.Comment.ID != target.CommentID || ctx.Post.ID != post.ID || ctx.Parent != nil {
+			t.Errorf("comment = %d, post = %d, parent = %v", ctx.Comment.ID, ctx.Post.ID, ctx.Parent)
+		}
+		// Engellenen kullanıcının yorumu kardeşler arasında görünmez
+		if want := []uint{top[1].CommentID, top[2].CommentID, top[3].CommentID}; !equal(ids(ctx.Before), want) {
+			t.Errorf("before = %v, want %v (without %d)", ids(ctx.Before), want, hidden.CommentID)
+		}
+		if want := []uint{top[5].CommentID}; !equal(ids(ctx.After), want) {
+			t.Errorf("after = %v, want %v (without deleted %d)", ids(ctx.After), want, deleted.CommentID)
+		}
+		if want := []uint{reply1.CommentID, reply2.CommentID}; !equal(ids(ctx.Replies), want) {
+			t.Errorf("replies = %v, want %v (without %d)", ids(ctx.Replies), want, blockedReply.CommentID)
+		}
+	})
+
+	t.Run("reply", func(t *testing.T) {
+		code, ctx := get(viewer.ID, reply2.CommentID)
+		if code != http.StatusOK {
+			t.Fatalf("status = %d", code)
+		}
+		if ctx.Parent == nil || ctx.Parent.ID != target.CommentID {
+			t.Fatalf("parent = %+v, want %d", ctx.Parent, target.CommentID)
+		}
+		if ctx.Comment.ParentID == nil || *ctx.Comment.ParentID != target.CommentID {
+			t.Errorf("comment parent id = %v", ctx.Comment.ParentID)
+		}
+		if want := []uint{reply1.CommentID}; !equal(ids(ctx.Before), want) {
+			t.Errorf("before = %v, want %v", ids(ctx.Before), want)
+		}
+		if len(ctx.After) != 0 || len(ctx.Replies) != 0 {
+			t.Errorf("after = %v, replies = %v, want none", ids(ctx.After), ids(ctx.Replies))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if code, _ := get(viewer.ID, deleted.CommentID); code != http.StatusNotFound {
+			t.Errorf("deleted comment: status = %d, want 404", code)
+		}
+		if code, _ := get(viewer.ID, blockedReply.CommentID); code != http.StatusNotFound {
+			t.Errorf("comment by blocked user: status = %d, want 404", code)
+		}
+	})
+
+	t.Run("hidden post", func(t *testing.T) {
+		if err := db.Model(&post).Update("is_public", false).Error; err != nil {
+			t.Fatal(err)
+		}
+		if code, _ := get(viewer.ID, target.CommentID); code != http.StatusNotFound {
+			t.Errorf("comment on followers-only post: status = %d, want 404", code)
+		}
+		if code, _ := get(author.ID, target.CommentID); code != http.StatusOK {
+			t.Errorf("post owner: status = %d, want 200", code)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+)
+
+// ConfigController serves the scoring, radius and feature configuration to clients
+type ConfigController struct{}
+
+func NewConfigController() *ConfigController {
+	return &ConfigController{}
+}
+
+// CategoriesConfigQuery; Lang verilmezse Accept-Language başlığı kullanılır
+type CategoriesConfigQuery struct {
+	Lang string `form:"lang"`
+}
+
+// CategoryConfigItem is a category with its configured points and post radius
+type CategoryConfigItem struct {
+	Category          string `json:"category"`
+	Points            *int   `json:"points"`            // kategoriye puan tanımlı değilse null
+	Radius            *int   `json:"radius"`            // kategoriye yarıçap tanımlı değilse null, varsayılan yarıçap geçerlidir
+	RadiusType        string `json:"radiusType"`        // geçerli yarıçapın tipi
+	RadiusDescription string `json:"radiusDescription"` // istenen dilde açıklama
+	Excluded          bool   `json:"excluded"`          // bu kategorideki yerler listelenmez
+}
+
+// RadiusTierItem is a radius band with its localized description
+type RadiusTierItem struct {
+	types.RadiusTier
+	Description string `json:"description"`
+}
+
+// radiusLanguage picks the language of radius descriptions from the lang query
+// or the Accept-Language header, falling back to types.DefaultRadiusLanguage.
+func radiusLanguage(c *gin.Context, lang string) string {
+	candidates := []string{lang}
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		// "en-US;q=0.8" -> "en-US"
+		candidates = append(candidates, strings.SplitN(part, ";", 2)[0])
+	}
+	for _, candidate := range candidates {
+		if code, ok := utils.NormalizeLanguageCode(candidate); ok && types.HasRadiusDescriptions(code) {
+			return code
+		}
+	}
+	return types.DefaultRadiusLanguage
+}
+
+// GetCategories godoc
+// @Summary List place categories with their points and radii
+// @Description Returns every category known to the scoring, radius or filtering configuration with its category points, post radius, radius type and whether it is excluded. Radius descriptions are localized by the lang query or the Accept-Language header.
+// @Tags config
+// @Produce json
+// @Param lang query string false "Language of radius descriptions, e.g. tr or en (default: tr)"
+// @Success 200 {object} StandardResponse{data=[]CategoryConfigItem}
+// @Router /config/categories [get]
+func (cc *ConfigController) GetCategories(c *gin.Context) {
+	var query CategoriesConfigQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	language := radiusLanguage(c, query.Lang)
+
+	scoring := types.GetPlaceScoring()
+	radiusConfig := types.GetPlaceRadius()
+	filtering := types.GetPlaceFiltering()
+
+	// Üç yapılandırmadan birinde geçen her kategori bir kez listelenir
+	items := map[string]*CategoryConfigItem{}
+	item := func(category string) *CategoryConfigItem {
+		if existing, ok := items[category]; ok {
+			return existing
+		}
+		items[category] = &CategoryConfigItem{Category: category}
+		return items[category]
+	}
+	for category, points := range scoring.CategoryPoints {
+		points := points
+		item(category).Points = &points
+	}
+	for category, radius := range radiusConfig.CategoryRadius {
+		radius := radius
+		item(category).Radius = &radius
+	}
+	for _, category := range filtering.ExcludedCategories {
+		item(category).Excluded = true
+	}
+
+	categories := make([]CategoryConfigItem, 0, len(items))
+	for _, entry := range items {
+		radius := radiusConfig.DefaultRadius
+		if entry.Radius != nil {
+			radius = *entry.Radius
+		}
+		entry.RadiusType = types.GetRadiusType(radius)
+		entry.RadiusDescription = types.GetRadiusDescription(entry.RadiusType, language)
+		categories = append(categories, *entry)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+
+	tiers := []RadiusTierItem{}
+	for _, tier := range types.GetRadiusTiers() {
+		tiers = append(tiers, RadiusTierItem{RadiusTier: tier, Description: types.GetRadiusDescription(tier.Type, language)})
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    categories,
+		Meta: gin.H{
+			"language":      language,
+			"defaultPoints": types.GetPointsConfig().DefaultPlacePoints,
+			"defaultRadius": radiusConfig.DefaultRadius,
+			"radiusTypes":   tiers,
+		},
+	})
+}
+
+// GetFeatures godoc
+// @Summary Get server-driven feature flags
+// @Description Returns the feature flags the server enforces, such as whether video is accepted, the media and caption limits and the posting radius policy, so the client can match its behaviour.
+// @Tags config
+// @Produce json
+// @Success 200 {object} StandardResponse{data=config.FeatureFlags}
+// @Router /config/features [get]
+func (cc *ConfigController) GetFeatures(c *gin.Context) {
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    config.GetFeatureFlags(),
+	})
+}
+
+// TrendingConfig is the current trending feed scoring, see config.TrendingParams
+type TrendingConfig struct {
+	LikeWeight            float64 `json:"likeWeight"`
+	CommentWeight         float64 `json:"commentWeight"`
+	BaseScore             float64 `json:"baseScore"`
+	HalfLifeHours         float64 `json:"halfLifeHours"`
+	EngagementWindowHours float64 `json:"engagementWindowHours"`
+}
+
+// GetTrending godoc
+// @Summary Get the trending feed scoring parameters
+// @Description Returns the weights and decay currently used by the trending feed sort. A post scores (likeWeight*likes + commentWeight*comments + baseScore) * 0.5^(ageHours/halfLifeHours), counting likes and comments from the last engagementWindowHours.
+// @Tags config
+// @Produce json
+// @Success 200 {object} StandardResponse{data=TrendingConfig}
+// @Router /config/trending [get]
+func (cc *ConfigController) GetTrending(c *gin.Context) {
+	params := config.GetTrendingParams()
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: TrendingConfig{
+			LikeWeight:            params.LikeWeight,
+			CommentWeight:         params.CommentWeight,
+			BaseScore:             params.BaseScore,
+			HalfLifeHours:         params.HalfLife.Hours(),
+			EngagementWindowHours: params.EngagementWindow.Hours(),
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestGetCategoriesConfig(t *testing.T) {
+	cc := NewConfigController()
+	get := func(target, acceptLanguage string) (items []CategoryConfigItem, language string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		c.Request.Header.Set("Accept-Language", acceptLanguage)
+		cc.GetCategories(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []CategoryConfigItem `json:"data"`
+			Meta struct {
+				Language string `json:"language"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data, resp.Meta.Language
+	}
+
+	items, language := get("/config/categories", "")
+	if language != types.DefaultRadiusLanguage {
+		t.Errorf("language = %q, want %q", language, types.DefaultRadiusLanguage)
+	}
+	var withPoints, withRadius, excluded int
+	seen := map[string]bool{}
+	for _, item := range items {
+		if seen[item.Category] {
+			t.Errorf("category %q listed twice", item.Category)
+		}
+		seen[item.Category] = true
+		if item.Points != nil {
+			withPoints++
+		}
+		if item.Radius != nil {
+			withRadius++
+		}
+		if item.Excluded {
+			excluded++
+		}
+	}
+	if want := len(types.GetPlaceScoring().CategoryPoints); withPoints != want {
+		t.Errorf("categories with points = %d, want %d", withPoints, want)
+	}
+	if want := len(types.GetPlaceRadius().CategoryRadius); withRadius != want {
+		t.Errorf("categories with radius = %d, want %d", withRadius, want)
+	}
+	excludedSet := map[string]bool{}
+	for _, category := range types.GetPlaceFiltering().ExcludedCategories {
+		excludedSet[category] = true
+	}
+	if excluded != len(excludedSet) {
+		t.Errorf("excluded categories = %d, want %d", excluded, len(excludedSet))
+	}
+
+	// Açıklama dile göre değişir; tip aynı kalır
+	english, language := get("/config/categories", "de-DE, en-US;q=0.8")
+	if language != "en" {
+		t.Fatalf("language from Accept-Language = %q, want en", language)
+	}
+	for i := range english {
+		if english[i].RadiusType != items[i].RadiusType {
+			t.Errorf("%s: radius type %q, want %q", english[i].Category, english[i].RadiusType, items[i].RadiusType)
+		}
+		if english[i].RadiusDescription != types.GetRadiusDescription(english[i].RadiusType, "en") {
+			t.Errorf("%s: description %q is not English", english[i].Category, english[i].RadiusDescription)
+		}
+	}
+	if _, language := get("/config/categories?lang=tr", "en"); language != "tr" {
+		t.Errorf("lang query = %q, want it to win over the header", language)
+	}
+}
+
+func TestGetFeaturesReflectsConfig(t *testing.T) {
+	t.Setenv("FEATURE_VIDEO_ENABLED", "false")
+	t.Setenv("MAX_MEDIA_ITEMS_PER_POST", "3")
+	t.Setenv("POST_RADIUS_POLICY", "off")
+
+	w := callHandler(NewConfigController().GetFeatures, http.MethodGet, "/config/features", nil, 1)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["videoEnabled"] != false || resp.Data["maxMediaItems"] != float64(3) || resp.Data["postRadiusPolicy"] != "off" {
+		t.Errorf("flags = %v, want video off, 3 media items and radius off", resp.Data)
+	}
+
+	// Sunucu aynı bayrağa uyar: video yükleme adresi verilmez
+	uc := newTestUploadController(t)
+	presign := func(mediaType, contentType string) int {
+		body := `{"fileName":"f","contentType":"` + contentType + `","fileSize":1024,"mediaType":"` + mediaType + `"}`
+		return callHandler(uc.GetPresignedURL, http.MethodPost, "/upload/presigned-url", strings.NewReader(body), 1).Code
+	}
+	if code := presign("video", "video/mp4"); code != http.StatusBadRequest {
+		t.Errorf("video presign with video disabled: status = %d, want 400", code)
+	}
+	if code := presign("photo", "image/jpeg"); code != http.StatusOK {
+		t.Errorf("photo presign: status = %d, want 200", code)
+	}
+	if field, _ := validateMediaTypesEnabled([]string{"photo", "video"}); field != "mediaItems[1].mediaType" {
+		t.Errorf("post media validation field = %q, want mediaItems[1].mediaType", field)
+	}
+}
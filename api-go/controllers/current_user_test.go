@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+func TestGetCurrentUserQueriesOncePerRequest(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("MIN_ACCOUNT_AGE", "24h")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", config.AccountAgeActionReport)
+	me := createTestUser(t, db, "cacheduser")
+	ac := NewAuthController(db, nil)
+	userQueries := countUserQueries(t, db)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/profile", nil)
+	claims := &utils.UserClaims{UserID: me.ID, Role: "user"}
+	c.Set(string(utils.UserContextKey), claims)
+
+	// Aynı istekte hesap yaşı kontrolü, profil ve doğrudan çağrılar satırı paylaşır
+	if !requireAccountAge(c, db, claims, config.AccountAgeActionReport) {
+		t.Fatalf("account age check failed: %s", w.Body.String())
+	}
+	ac.GetProfile(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	user, err := utils.GetCurrentUser(c, db)
+	if err != nil || user.ID != me.ID {
+		t.Fatalf("GetCurrentUser = %+v, %v; want user %d", user, err, me.ID)
+	}
+	if *userQueries != 1 {
+		t.Errorf("users queried %d times in one request, want 1", *userQueries)
+	}
+
+	// Yeni istek satırı yeniden yükler
+	next, _ := gin.CreateTestContext(httptest.NewRecorder())
+	next.Set(string(utils.UserContextKey), claims)
+	if _, err := utils.GetCurrentUser(next, db); err != nil {
+		t.Fatal(err)
+	}
+	if *userQueries != 2 {
+		t.Errorf("users queried %d times after a second request, want 2", *userQueries)
+	}
+}
+
+func TestCreatePostQueriesUserOnce(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "cachedposter")
+	other := createTestUser(t, db, "cachedother")
+	place := createTestPlace(t, db, "cachedplace")
+	// Keşif bildirimi oluşmasın diye yerde zaten bir gönderi vardır
+	createTestPost(t, db, other, place, "earlier post", true)
+	userQueries := countUserQueries(t, db)
+
+	w := createPostAs(t, db, me, place)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Username != me.Username {
+		t.Errorf("username = %q, want %q", resp.Username, me.Username)
+	}
+	if *userQueries != 1 {
+		t.Errorf("users queried %d times in POST /posts, want 1", *userQueries)
+	}
+}
+
+// countUserQueries counts the SELECTs on the users table made through db
+func countUserQueries(t *testing.T, db *gorm.DB) *int {
+	t.Helper()
+	count := 0
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_user_queries", func(tx *gorm.DB) {
+		if tx.Statement.Table == "users" {
+			count++
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return &count
+}
@@ -0,0 +1,511 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// DataExportArchive is everything the user has created or done in the app.
+// Other users appear only by ID and username.
+type DataExportArchive struct {
+	ExportedAt time.Time            `json:"exportedAt"`
+	Profile    DataExportProfile    `json:"profile"`
+	Posts      []DataExportPost     `json:"posts"`
+	Comments   []DataExportComment  `json:"comments"`
+	Likes      []DataExportLike     `json:"likes"`
+	Following  []DataExportFollow   `json:"following"`
+	Followers  []DataExportFollow   `json:"followers"`
+	Activity   []DataExportActivity `json:"activity"`
+}
+
+type DataExportProfile struct {
+	ID              uint       `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"firstName"`
+	LastName        string     `json:"lastName"`
+	Gender          string     `json:"gender"`
+	Birthday        *time.Time `json:"birthday"`
+	Phone           *string    `json:"phone"`
+	Bio             string     `json:"bio"`
+	Avatar          string     `json:"avatar"`
+	Provider        string     `json:"provider"`
+	LinkedProviders []string   `json:"linkedProviders"`
+	IsPrivate       bool       `json:"isPrivate"`
+	ShareLocation   bool       `json:"shareLocation"`
+	IsVerified      bool       `json:"isVerified"`
+	TotalPoints     int64      `json:"totalPoints"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+type DataExportMedia struct {
+	MediaType    string `json:"mediaType"`
+	MediaURL     string `json:"mediaUrl"`
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	AltText      string `json:"altText,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	Duration     int    `json:"duration,omitempty"`
+}
+
+type DataExportPost struct {
+	ID            uint              `json:"id"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Caption       string            `json:"caption"`
+	PlaceID       uint              `json:"placeId"`
+	PlaceName     string            `json:"placeName"`
+	Latitude      float64           `json:"latitude"`
+	Longitude     float64           `json:"longitude"`
+	IsPublic      bool              `json:"isPublic"`
+	IsArchived    bool              `json:"isArchived"`
+	AllowComments bool              `json:"allowComments"`
+	EarnedPoints  int64             `json:"earnedPoints"`
+	Media         []DataExportMedia `json:"media"`
+}
+
+// DataExportComment is a comment the user wrote; the post it is on may belong to someone else
+type DataExportComment struct {
+	ID              uint      `json:"id"`
+	CreatedAt       time.Time `json:"createdAt"`
+	PostID          uint      `json:"postId"`
+	ParentCommentID *uint     `json:"parentCommentId,omitempty"`
+	Text            string    `json:"text"`
+	IsEdited        bool      `json:"isEdited"`
+}
+
+type DataExportLike struct {
+	PostID    uint      `json:"postId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type DataExportFollow struct {
+	UserID    uint      `json:"userId"`
+	Username  string    `json:"username"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type DataExportActivity struct {
+	ID           uint      `json:"id"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Activity     string    `json:"activity"`
+	Points       int       `json:"points"`
+	PlaceID      uint      `json:"placeId,omitempty"`
+	PostID       uint      `json:"postId,omitempty"`
+	TargetUserID *uint     `json:"targetUserId,omitempty"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+}
+
+// DataExportStatus describes a background export; DownloadURL is set once it is ready
+type DataExportStatus struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+}
+
+func newDataExportStatus(export models.DataExport) DataExportStatus {
+	status := DataExportStatus{
+		ID:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == models.DataExportCompleted {
+		status.DownloadURL = fmt.Sprintf("/api/profile/export/%d/download", export.ID)
+	}
+	return status
+}
+
+// startDataExport prepares export exportID in the background. Tests replace
+// it to run the export synchronously.
+var startDataExport = func(db *gorm.DB, exportID uint) {
+	go runDataExport(db, exportID)
+}
+
+// runDataExport builds the archive of export exportID and stores it
+func runDataExport(db *gorm.DB, exportID uint) {
+	var export models.DataExport
+	if err := db.First(&export, exportID).Error; err != nil {
+		log.Printf("Data export %d: %v", exportID, err)
+		return
+	}
+	// Başarısız güncellemede dışa aktarım bekleyen kalır ve zaman aşımıyla başarısız sayılır
+	if err := db.Model(&export).Update("status", models.DataExportProcessing).Error; err != nil {
+		log.Printf("Data export %d: failed to mark as processing: %v", exportID, err)
+		return
+	}
+
+	archive, err := buildDataExportJSON(db, export.UserID)
+	if err != nil {
+		log.Printf("Data export %d for user %d failed: %v", exportID, export.UserID, err)
+		if err := db.Model(&export).Update("status", models.DataExportFailed).Error; err != nil {
+			log.Printf("Data export %d: failed to mark as failed: %v", exportID, err)
+		}
+		return
+	}
+
+	now := time.Now()
+	if err := db.Model(&export).Updates(map[string]interface{}{
+		"status":       models.DataExportCompleted,
+		"completed_at": now,
+		"expires_at":   now.Add(config.GetDataExportTTL()),
+		"archive":      archive,
+	}).Error; err != nil {
+		log.Printf("Data export %d: failed to store archive: %v", exportID, err)
+	}
+}
+
+// failStaleDataExports marks the user's pending or processing exports that
+// made no progress within the export timeout as failed
+func failStaleDataExports(db *gorm.DB, userID uint, now time.Time) error {
+	return db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status IN ? AND updated_at < ?", userID,
+			[]string{models.DataExportPending, models.DataExportProcessing}, now.Add(-config.GetDataExportTimeout())).
+		Update("status", models.DataExportFailed).Error
+}
+
+// dataExportSize counts the rows that make an account's export expensive
+func dataExportSize(db *gorm.DB, userID uint) (int64, error) {
+	var size int64
+	err := db.Raw(`SELECT
+		(SELECT COUNT(*) FROM posts WHERE user_id = ? AND deleted_at IS NULL) +
+		(SELECT COUNT(*) FROM comments WHERE user_id = ? AND deleted_at IS NULL) +
+		(SELECT COUNT(*) FROM likes WHERE user_id = ?) +
+		(SELECT COUNT(*) FROM activity_logs WHERE user_id = ? AND deleted_at IS NULL)`,
+		userID, userID, userID, userID).Scan(&size).Error
+	return size, err
+}
+
+// buildDataExportJSON assembles the archive of userID as indented JSON
+func buildDataExportJSON(db *gorm.DB, userID uint) ([]byte, error) {
+	archive, err := buildDataExport(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(archive, "", "  ")
+}
+
+// buildDataExport assembles the archive of userID
+func buildDataExport(db *gorm.DB, userID uint) (*DataExportArchive, error) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	archive := &DataExportArchive{
+		ExportedAt: time.Now(),
+		Profile: DataExportProfile{
+			ID:              user.ID,
+			Username:        user.Username,
+			Email:           user.Email,
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Gender:          user.Gender,
+			Birthday:        user.Birthday,
+			Phone:           user.Phone,
+			Bio:             user.Bio,
+			Avatar:          user.Avatar,
+			Provider:        user.Provider,
+			LinkedProviders: user.LinkedProviders,
+			IsPrivate:       user.IsPrivate,
+			ShareLocation:   user.ShareLocation,
+			IsVerified:      user.IsVerified,
+			TotalPoints:     user.TotalPoints,
+			CreatedAt:       user.CreatedAt,
+		},
+		Posts:     []DataExportPost{},
+		Comments:  []DataExportComment{},
+		Likes:     []DataExportLike{},
+		Following: []DataExportFollow{},
+		Followers: []DataExportFollow{},
+		Activity:  []DataExportActivity{},
+	}
+
+	var posts []struct {
+		ID            uint      `gorm:"column:id"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+		PostCaption   string    `gorm:"column:post_caption"`
+		PlaceID       uint      `gorm:"column:place_id"`
+		PlaceName     string    `gorm:"column:place_name"`
+		Latitude      float64   `gorm:"column:latitude"`
+		Longitude     float64   `gorm:"column:longitude"`
+		IsPublic      bool      `gorm:"column:is_public"`
+		IsArchived    bool      `gorm:"column:is_archived"`
+		AllowComments bool      `gorm:"column:allow_comments"`
+		EarnedPoints  int64     `gorm:"column:earned_points"`
+	}
+	if err := db.Model(&models.Post{}).
+		Select(`posts.id, posts.created_at, posts.post_caption, posts.place_id, places.name AS place_name,
+			posts.latitude, posts.longitude, posts.is_public, posts.is_archived, posts.allow_comments, posts.earned_points`).
+		Joins("LEFT JOIN places ON places.id = posts.place_id").
+		Where("posts.user_id = ?", userID).
+		Order("posts.created_at, posts.id").
+		Scan(&posts).Error; err != nil {
+		return nil, err
+	}
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+	mediaByPost := make(map[uint][]DataExportMedia)
+	if len(postIDs) > 0 {
+		var media []models.PostMedia
+		if err := db.Where("post_id IN ?", postIDs).Order("post_id, order_index").Find(&media).Error; err != nil {
+			return nil, err
+		}
+		for _, item := range media {
+			mediaByPost[item.PostID] = append(mediaByPost[item.PostID], DataExportMedia{
+				MediaType:    item.MediaType,
+				MediaURL:     item.MediaURL,
+				ThumbnailURL: item.ThumbnailURL,
+				AltText:      item.AltText,
+				Width:        item.Width,
+				Height:       item.Height,
+				Duration:     item.Duration,
+			})
+		}
+	}
+	for _, post := range posts {
+		media := mediaByPost[post.ID]
+		if media == nil {
+			media = []DataExportMedia{}
+		}
+		archive.Posts = append(archive.Posts, DataExportPost{
+			ID:            post.ID,
+			CreatedAt:     post.CreatedAt,
+			Caption:       post.PostCaption,
+			PlaceID:       post.PlaceID,
+			PlaceName:     post.PlaceName,
+			Latitude:      post.Latitude,
+			Longitude:     post.Longitude,
+			IsPublic:      post.IsPublic,
+			IsArchived:    post.IsArchived,
+			AllowComments: post.AllowComments,
+			EarnedPoints:  post.EarnedPoints,
+			Media:         media,
+		})
+	}
+
+	var comments []models.Comment
+	if err := db.Where("user_id = ?", userID).Order("created_at, comment_id").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		archive.Comments = append(archive.Comments, DataExportComment{
+			ID:              comment.CommentID,
+			CreatedAt:       comment.CreatedAt,
+			PostID:          comment.PostID,
+			ParentCommentID: comment.ParentCommentID,
+			Text:            comment.TextContent,
+			IsEdited:        comment.IsEdited,
+		})
+	}
+
+	var likes []models.Like
+	if err := db.Where("user_id = ?", userID).Order("created_at, like_id").Find(&likes).Error; err != nil {
+		return nil, err
+	}
+	for _, like := range likes {
+		archive.Likes = append(archive.Likes, DataExportLike{PostID: like.PostID, CreatedAt: like.CreatedAt})
+	}
+
+	// Karşı taraftan yalnızca kimlik ve kullanıcı adı aktarılır
+	follows := func(userColumn, otherColumn string) ([]DataExportFollow, error) {
+		rows := []DataExportFollow{}
+		err := db.Model(&models.Follow{}).
+			Select("users.id AS user_id, users.username, follows.status, follows.created_at").
+			Joins("JOIN users ON users.id = follows."+otherColumn+" AND users.deleted_at IS NULL").
+			Where("follows."+userColumn+" = ?", userID).
+			Order("follows.created_at, follows.id").
+			Scan(&rows).Error
+		return rows, err
+	}
+	var err error
+	if archive.Following, err = follows("follower_user_id", "following_user_id"); err != nil {
+		return nil, err
+	}
+	if archive.Followers, err = follows("following_user_id", "follower_user_id"); err != nil {
+		return nil, err
+	}
+
+	var activity []models.ActivityLog
+	if err := db.Where("user_id = ?", userID).Order("created_at, id").Find(&activity).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range activity {
+		archive.Activity = append(archive.Activity, DataExportActivity{
+			ID:           entry.ID,
+			CreatedAt:    entry.CreatedAt,
+			Activity:     entry.Activity,
+			Points:       entry.Points,
+			PlaceID:      entry.PlaceID,
+			PostID:       entry.PostID,
+			TargetUserID: entry.TargetUserID,
+			Latitude:     entry.Latitude,
+			Longitude:    entry.Longitude,
+		})
+	}
+
+	return archive, nil
+}
+
+// sendDataExport responds with archive as a JSON file download
+func sendDataExport(c *gin.Context, userID uint, createdAt time.Time, archive []byte) {
+	filename := fmt.Sprintf("snappoint-export-%d-%s.json", userID, createdAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/json", archive)
+}
+
+// ExportData godoc
+// @Summary Export the current user's data
+// @Description Returns the user's profile, posts with media URLs, comments, likes, follows and activity as a JSON file. Accounts with more entries than DATA_EXPORT_SYNC_LIMIT are exported in the background instead: the response is 202 with an export whose status can be polled and which can be downloaded once completed. Only one background export runs at a time; one that makes no progress within DATA_EXPORT_TIMEOUT (default 1h) counts as failed.
+// @Tags auth
+// @Produce json
+// @Success 200 {file} file "JSON archive"
+// @Success 202 {object} StandardResponse{data=DataExportStatus}
+// @Failure 429 {object} map[string]interface{}
+// @Router /profile/export [get]
+func (ac *AuthController) ExportData(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	size, err := dataExportSize(ac.DB, user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to export data"})
+		return
+	}
+	if size <= config.GetDataExportSyncLimit() {
+		archive, err := buildDataExportJSON(ac.DB, user.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to export data"})
+			return
+		}
+		sendDataExport(c, user.UserID, time.Now(), archive)
+		return
+	}
+
+	// Süren bir dışa aktarım varsa yenisi başlatılmaz; takılı kalanlar önce başarısız sayılır
+	if err := failStaleDataExports(ac.DB, user.UserID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to export data"})
+		return
+	}
+	var active models.DataExport
+	err = ac.DB.Omit("archive").
+		Where("user_id = ? AND status IN ?", user.UserID, []string{models.DataExportPending, models.DataExportProcessing}).
+		Order("id DESC").First(&active).Error
+	if err == nil {
+		c.JSON(http.StatusAccepted, StandardResponse{Success: true, Data: newDataExportStatus(active), Message: "Export already in progress"})
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to export data"})
+		return
+	}
+
+	// Süresi dolmuş arşivler saklanmaz
+	if err := ac.DB.Where("user_id = ? AND expires_at < ?", user.UserID, time.Now()).Delete(&models.DataExport{}).Error; err != nil {
+		log.Printf("Failed to delete expired data exports of user %d: %v", user.UserID, err)
+	}
+
+	export := models.DataExport{UserID: user.UserID, Status: models.DataExportPending}
+	if err := ac.DB.Create(&export).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to export data"})
+		return
+	}
+	startDataExport(ac.DB, export.ID)
+
+	c.JSON(http.StatusAccepted, StandardResponse{Success: true, Data: newDataExportStatus(export), Message: "Export started"})
+}
+
+// userDataExport loads the current user's export named by the exportId path parameter
+func (ac *AuthController) userDataExport(c *gin.Context, userID uint, withArchive bool) (models.DataExport, bool) {
+	var export models.DataExport
+	if err := failStaleDataExports(ac.DB, userID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch export"})
+		return export, false
+	}
+	query := ac.DB.Where("id = ? AND user_id = ?", c.Param("exportId"), userID)
+	if !withArchive {
+		query = query.Omit("archive")
+	}
+	if err := query.First(&export).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Export not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch export"})
+		}
+		return export, false
+	}
+	return export, true
+}
+
+// GetDataExport godoc
+// @Summary Get the status of a background data export
+// @Tags auth
+// @Produce json
+// @Param exportId path string true "Export ID"
+// @Success 200 {object} StandardResponse{data=DataExportStatus}
+// @Router /profile/export/{exportId} [get]
+func (ac *AuthController) GetDataExport(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	export, ok := ac.userDataExport(c, user.UserID, false)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Data: newDataExportStatus(export)})
+}
+
+// DownloadDataExport godoc
+// @Summary Download a completed background data export
+// @Description Returns the JSON archive of a completed export. Archives expire after DATA_EXPORT_TTL (default 7 days) and then return 410.
+// @Tags auth
+// @Produce json
+// @Param exportId path string true "Export ID"
+// @Success 200 {file} file "JSON archive"
+// @Failure 409 {object} StandardResponse
+// @Failure 410 {object} StandardResponse
+// @Router /profile/export/{exportId}/download [get]
+func (ac *AuthController) DownloadDataExport(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	export, ok := ac.userDataExport(c, user.UserID, true)
+	if !ok {
+		return
+	}
+	if export.Status != models.DataExportCompleted {
+		c.JSON(http.StatusConflict, StandardResponse{Success: false, Data: newDataExportStatus(export), Message: "Export is not ready"})
+		return
+	}
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		c.JSON(http.StatusGone, StandardResponse{Success: false, Message: "Export has expired, please request a new one"})
+		return
+	}
+
+	sendDataExport(c, user.UserID, export.CreatedAt, export.Archive)
+}
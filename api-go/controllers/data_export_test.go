@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+func TestExportDataContainsOwnDataOnly(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "exporter")
+	other := createTestUser(t, db, "otherexporter")
+	place := createTestPlace(t, db, "exportplace")
+	ac := NewAuthController(db, nil)
+
+	own := createTestPost(t, db, user, place, "my own caption", false)
+	if err := db.Create(&models.PostMedia{PostID: own.ID, MediaType: "photo", MediaURL: "https://cdn.example.com/own.jpg"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	otherPrivate := createTestPost(t, db, other, place, "someone else's private caption", false)
+	otherPublic := createTestPost(t, db, other, place, "someone else's public caption", true)
+	seed := []interface{}{
+		&models.Comment{PostID: own.ID, UserID: other.ID, TextContent: "a stranger's comment"},
+		&models.Comment{PostID: otherPublic.ID, UserID: user.ID, TextContent: "my comment elsewhere"},
+		&models.Like{PostID: otherPublic.ID, UserID: user.ID},
+		&models.Like{PostID: own.ID, UserID: other.ID},
+		&models.Follow{FollowerUserID: user.ID, FollowingUserID: other.ID, Status: "accepted"},
+	}
+	for _, row := range seed {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := callHandler(ac.ExportData, http.MethodGet, "/profile/export", nil, user.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment;") {
+		t.Errorf("Content-Disposition = %q, want an attachment", w.Header().Get("Content-Disposition"))
+	}
+
+	var archive DataExportArchive
+	if err := json.Unmarshal(w.Body.Bytes(), &archive); err != nil {
+		t.Fatal(err)
+	}
+	if archive.Profile.ID != user.ID || archive.Profile.Email != user.Email {
+		t.Errorf("profile = %+v, want the exporting user", archive.Profile)
+	}
+	if len(archive.Posts) != 1 || archive.Posts[0].ID != own.ID {
+		t.Fatalf("posts = %+v, want only the user's post", archive.Posts)
+	}
+	if media := archive.Posts[0].Media; len(media) != 1 || media[0].MediaURL != "https://cdn.example.com/own.jpg" {
+		t.Errorf("media = %+v, want the post's media URL", media)
+	}
+	if len(archive.Comments) != 1 || archive.Comments[0].Text != "my comment elsewhere" {
+		t.Errorf("comments = %+v, want only the user's comment", archive.Comments)
+	}
+	if len(archive.Likes) != 1 || archive.Likes[0].PostID != otherPublic.ID {
+		t.Errorf("likes = %+v, want only the user's like", archive.Likes)
+	}
+	if len(archive.Following) != 1 || archive.Following[0].Username != other.Username {
+		t.Errorf("following = %+v, want %s", archive.Following, other.Username)
+	}
+
+	body := w.Body.String()
+	for _, leaked := range []string{otherPrivate.PostCaption, otherPublic.PostCaption, "a stranger's comment", other.Email} {
+		if strings.Contains(body, leaked) {
+			t.Errorf("export contains another user's data %q", leaked)
+		}
+	}
+}
+
+func TestExportDataRunsInBackgroundForLargeAccounts(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("DATA_EXPORT_SYNC_LIMIT", "0")
+	user := createTestUser(t, db, "bigexporter")
+	other := createTestUser(t, db, "snooper")
+	place := createTestPlace(t, db, "bigexportplace")
+	post := createTestPost(t, db, user, place, "large account post", true)
+	ac := NewAuthController(db, nil)
+
+	// Arka plan işi testte isteğin içinde tamamlanır
+	var started []uint
+	original := startDataExport
+	startDataExport = func(db *gorm.DB, exportID uint) {
+		started = append(started, exportID)
+	}
+	t.Cleanup(func() { startDataExport = original })
+
+	w := callHandler(ac.ExportData, http.MethodGet, "/profile/export", nil, user.ID)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202; body = %s", w.Code, w.Body.String())
+	}
+	if len(started) != 1 {
+		t.Fatalf("started %d exports, want 1", len(started))
+	}
+	exportID := started[0]
+	param := gin.Param{Key: "exportId", Value: strconv.Itoa(int(exportID))}
+
+	// Hazırlanan dışa aktarım bitmeden yenisi başlatılmaz
+	if w := callHandler(ac.ExportData, http.MethodGet, "/profile/export", nil, user.ID); w.Code != http.StatusAccepted || len(started) != 1 {
+		t.Errorf("second request: status = %d, started = %d; want 202 and no new export", w.Code, len(started))
+	}
+	if w := callHandler(ac.DownloadDataExport, http.MethodGet, "/profile/export/"+param.Value+"/download", nil, user.ID, param); w.Code != http.StatusConflict {
+		t.Errorf("download before ready: status = %d, want 409", w.Code)
+	}
+
+	runDataExport(db, exportID)
+
+	w = callHandler(ac.GetDataExport, http.MethodGet, "/profile/export/"+param.Value, nil, user.ID, param)
+	var status struct {
+		Data DataExportStatus `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Data.Status != models.DataExportCompleted || status.Data.DownloadURL == "" {
+		t.Fatalf("status = %+v, want completed with a download URL", status.Data)
+	}
+
+	if w := callHandler(ac.DownloadDataExport, http.MethodGet, "/profile/export/"+param.Value+"/download", nil, other.ID, param); w.Code != http.StatusNotFound {
+		t.Errorf("download by another user: status = %d, want 404", w.Code)
+	}
+
+	w = callHandler(ac.DownloadDataExport, http.MethodGet, "/profile/export/"+param.Value+"/download", nil, user.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("download: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var archive DataExportArchive
+	if err := json.Unmarshal(w.Body.Bytes(), &archive); err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Posts) != 1 || archive.Posts[0].ID != post.ID {
+		t.Errorf("posts = %+v, want the user's post", archive.Posts)
+	}
+}
+
+func TestStaleDataExportsFail(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("DATA_EXPORT_SYNC_LIMIT", "0")
+	t.Setenv("DATA_EXPORT_TIMEOUT", "1h")
+	user := createTestUser(t, db, "staleexporter")
+	ac := NewAuthController(db, nil)
+
+	// Yeniden başlatmada işi kaybolan dışa aktarım "processing" durumunda kalır
+	stale := models.DataExport{UserID: user.ID, Status: models.DataExportProcessing}
+	if err := db.Create(&stale).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&stale).UpdateColumn("updated_at", time.Now().Add(-2*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	param := gin.Param{Key: "exportId", Value: strconv.Itoa(int(stale.ID))}
+	w := callHandler(ac.GetDataExport, http.MethodGet, "/profile/export/"+param.Value, nil, user.ID, param)
+	var status struct {
+		Data DataExportStatus `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Data.Status != models.DataExportFailed {
+		t.Errorf("stale export status = %q, want failed", status.Data.Status)
+	}
+
+	var started []uint
+	original := startDataExport
+	startDataExport = func(db *gorm.DB, exportID uint) {
+		started = append(started, exportID)
+	}
+	t.Cleanup(func() { startDataExport = original })
+
+	w = callHandler(ac.ExportData, http.MethodGet, "/profile/export", nil, user.ID)
+	if w.Code != http.StatusAccepted || len(started) != 1 || started[0] == stale.ID {
+		t.Errorf("new request: status = %d, started = %v; want a new export", w.Code, started)
+	}
+}
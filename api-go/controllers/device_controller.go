@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Bu süre boyunca yeniden kaydedilmeyen token'lar bayat sayılır ve silinir
+	deviceTokenStaleAfter = 60 * 24 * time.Hour
+	// Kullanıcı başına en fazla cihaz; fazlası en eskiden başlanarak silinir
+	maxDevicesPerUser = 10
+)
+
+type DeviceController struct {
+	DB *gorm.DB
+}
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=512"`
+	Platform string `json:"platform" binding:"required,oneof=ios android web"`
+}
+
+func NewDeviceController(db *gorm.DB) *DeviceController {
+	return &DeviceController{DB: db}
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Description Registers or refreshes a push token. Apps should call this on every launch; tokens not seen for 60 days are pruned.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Param request body RegisterDeviceRequest true "Push token and platform"
+// @Success 200 {object} StandardResponse
+// @Router /devices/register [post]
+func (dc *DeviceController) RegisterDevice(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	now := time.Now()
+	device := models.DeviceToken{
+		UserID:   user.UserID,
+		Token:    req.Token,
+		Platform: req.Platform,
+		LastSeen: now,
+	}
+
+	tx := dc.DB.Begin()
+
+	// Aynı token başka hesapta kayıtlıysa cihaz bu kullanıcıya geçer
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "last_seen", "updated_at"}),
+	}).Create(&device).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to register device"})
+		return
+	}
+
+	if err := pruneDeviceTokens(tx, user.UserID, now); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to register device"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to register device"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"token":    req.Token,
+			"platform": req.Platform,
+			"lastSeen": now,
+		},
+		Message: "Device registered",
+	})
+}
+
+// UnregisterDevice godoc
+// @Summary Stop push notifications to a device
+// @Description Removes the token if it belongs to the current user, e.g. on logout
+// @Tags devices
+// @Produce json
+// @Param token path string true "Push token"
+// @Success 200 {object} StandardResponse
+// @Router /devices/{token} [delete]
+func (dc *DeviceController) UnregisterDevice(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	result := dc.DB.Where("user_id = ? AND token = ?", user.UserID, c.Param("token")).Delete(&models.DeviceToken{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to unregister device"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Device not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Message: "Device unregistered",
+	})
+}
+
+// pruneDeviceTokens removes the user's tokens not seen since deviceTokenStaleAfter
+// and keeps only the maxDevicesPerUser most recently seen ones.
+func pruneDeviceTokens(db *gorm.DB, userID uint, now time.Time) error {
+	if err := db.Where("user_id = ? AND last_seen < ?", userID, now.Add(-deviceTokenStaleAfter)).
+		Delete(&models.DeviceToken{}).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`DELETE FROM device_tokens WHERE user_id = ? AND id NOT IN (
+			SELECT id FROM device_tokens WHERE user_id = ? ORDER BY last_seen DESC, id DESC LIMIT ?)`,
+		userID, userID, maxDevicesPerUser).Error
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func registerDevice(t *testing.T, dc *DeviceController, userID uint, token string) {
+	t.Helper()
+	w := callHandler(dc.RegisterDevice, http.MethodPost, "/devices/register",
+		strings.NewReader(fmt.Sprintf(`{"token":%q,"platform":"ios"}`, token)), userID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("register %s: status = %d, body = %s", token, w.Code, w.Body.String())
+	}
+}
+
+func deviceTokens(t *testing.T, dc *DeviceController, userID uint) []string {
+	t.Helper()
+	var tokens []string
+	if err := dc.DB.Model(&models.DeviceToken{}).Where("user_id = ?", userID).Pluck("token", &tokens).Error; err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+func TestRegisterAndUnregisterDevice(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "deviceme")
+	other := createTestUser(t, db, "deviceother")
+	dc := NewDeviceController(db)
+
+	if w := callHandler(dc.RegisterDevice, http.MethodPost, "/devices/register",
+		strings.NewReader(`{"token":"abc","platform":"symbian"}`), me.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid platform: status = %d, want 400", w.Code)
+	}
+
+	registerDevice(t, dc, me.ID, "token-a")
+	registerDevice(t, dc, me.ID, "token-a") // tekrar kayıt kopya oluşturmaz
+	registerDevice(t, dc, other.ID, "token-b")
+	if got := deviceTokens(t, dc, me.ID); !reflect.DeepEqual(got, []string{"token-a"}) {
+		t.Errorf("my tokens = %v, want [token-a]", got)
+	}
+
+	// Aynı cihaz başka hesapla giriş yapınca token o hesaba geçer
+	registerDevice(t, dc, me.ID, "token-b")
+	if got := deviceTokens(t, dc, other.ID); len(got) != 0 {
+		t.Errorf("other's tokens after moving = %v, want none", got)
+	}
+
+	unregister := func(userID uint, token string) int {
+		t.Helper()
+		param := gin.Param{Key: "token", Value: token}
+		return callHandler(dc.UnregisterDevice, http.MethodDelete, "/devices/"+token, nil, userID, param).Code
+	}
+	if code := unregister(other.ID, "token-a"); code != http.StatusNotFound {
+		t.Errorf("unregister another user's token: status = %d, want 404", code)
+	}
+	if code := unregister(me.ID, "token-a"); code != http.StatusOK {
+		t.Errorf("unregister: status = %d, want 200", code)
+	}
+	if got := deviceTokens(t, dc, me.ID); !reflect.DeepEqual(got, []string{"token-b"}) {
+		t.Errorf("my tokens after unregister = %v, want [token-b]", got)
+	}
+}
+
+func TestRegisterDevicePrunesStaleTokens(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "pruneme")
+	dc := NewDeviceController(db)
+
+	registerDevice(t, dc, me.ID, "stale")
+	if err := db.Model(&models.DeviceToken{}).Where("token = ?", "stale").
+		Update("last_seen", time.Now().Add(-deviceTokenStaleAfter-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxDevicesPerUser+2; i++ {
+		registerDevice(t, dc, me.ID, fmt.Sprintf("device-%02d", i))
+	}
+
+	got := deviceTokens(t, dc, me.ID)
+	if len(got) != maxDevicesPerUser {
+		t.Fatalf("kept %d tokens, want %d: %v", len(got), maxDevicesPerUser, got)
+	}
+	// Bayat token ve en eski iki cihaz silinir
+	for _, token := range []string{"stale", "device-00", "device-01"} {
+		for _, kept := range got {
+			if kept == token {
+				t.Errorf("%s was not pruned", token)
+			}
+		}
+	}
+}
+
+func TestPushDeliveryRemovesInvalidTokens(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "pushme")
+	dc := NewDeviceController(db)
+	registerDevice(t, dc, me.ID, "live")
+	registerDevice(t, dc, me.ID, "dead")
+
+	fake := &fakeNotifier{invalidTokens: map[string]bool{"dead": true}}
+	sendOutbound(context.Background(), db, fake, me.ID, NotificationItem{ID: 1, Type: "like"})
+
+	if want := []string{"live like"}; !reflect.DeepEqual(fake.pushes, want) {
+		t.Errorf("pushes = %v, want %v", fake.pushes, want)
+	}
+	if got := deviceTokens(t, dc, me.ID); !reflect.DeepEqual(got, []string{"live"}) {
+		t.Errorf("tokens after delivery = %v, want [live]", got)
+	}
+}
@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// DraftController manages server-side post drafts; publishing goes through PostController
+type DraftController struct {
+	DB             *gorm.DB
+	PostController *PostController
+}
+
+type PostDraftRequest struct {
+	PostCaption   string                `json:"postCaption"`
+	MediaItems    []CreatePostMediaItem `json:"mediaItems"`
+	PlaceID       *uint                 `json:"placeId"`
+	Latitude      *float64              `json:"latitude"`
+	Longitude     *float64              `json:"longitude"`
+	IsPublic      *bool                 `json:"isPublic"`
+	AllowComments *bool                 `json:"allowComments"`
+	Language      string                `json:"language"`
+	Data          json.RawMessage       `json:"data"` // client-side state, stored as is
+}
+
+type PostDraftResponse struct {
+	ID            uint                  `json:"id"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+	PostCaption   string                `json:"postCaption"`
+	MediaItems    []CreatePostMediaItem `json:"mediaItems"`
+	PlaceID       *uint                 `json:"placeId"`
+	Latitude      *float64              `json:"latitude"`
+	Longitude     *float64              `json:"longitude"`
+	IsPublic      bool                  `json:"isPublic"`
+	AllowComments bool                  `json:"allowComments"`
+	Language      string                `json:"language"`
+	Data          json.RawMessage       `json:"data,omitempty"`
+}
+
+func NewDraftController(db *gorm.DB, postController *PostController) *DraftController {
+	return &DraftController{DB: db, PostController: postController}
+}
+
+// CreateDraft godoc
+// @Summary Save a post draft
+// @Description Stores an unpublished post. Drafts are private to their owner and never award points.
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Param draft body PostDraftRequest true "Draft"
+// @Success 201 {object} StandardResponse{data=PostDraftResponse}
+// @Router /posts/drafts [post]
+func (dc *DraftController) CreateDraft(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req PostDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	draft := models.PostDraft{UserID: user.UserID}
+	if msg := applyDraftRequest(&draft, req); msg != "" {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: msg})
+		return
+	}
+
+	if err := dc.DB.Create(&draft).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to save draft"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, StandardResponse{
+		Success: true,
+		Data:    draftResponse(draft),
+	})
+}
+
+// GetDrafts godoc
+// @Summary List the current user's post drafts
+// @Tags drafts
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]PostDraftResponse}
+// @Router /posts/drafts [get]
+func (dc *DraftController) GetDrafts(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var drafts []models.PostDraft
+	if err := dc.DB.Where("user_id = ?", user.UserID).Order("updated_at DESC").Find(&drafts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching drafts"})
+		return
+	}
+
+	items := make([]PostDraftResponse, len(drafts))
+	for i, draft := range drafts {
+		items[i] = draftResponse(draft)
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// UpdateDraft godoc
+// @Summary Replace a post draft
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Param draft body PostDraftRequest true "Draft"
+// @Success 200 {object} StandardResponse{data=PostDraftResponse}
+// @Router /posts/drafts/{id} [put]
+func (dc *DraftController) UpdateDraft(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req PostDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var draft models.PostDraft
+	if err := dc.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.UserID).First(&draft).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Draft not found"})
+		return
+	}
+
+	if msg := applyDraftRequest(&draft, req); msg != "" {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: msg})
+		return
+	}
+
+	if err := dc.DB.Save(&draft).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to save draft"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    draftResponse(draft),
+	})
+}
+
+// PublishDraft godoc
+// @Summary Publish a post draft
+// @Description Runs the normal post creation checks, including the location check, and deletes the draft on success
+// @Tags drafts
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Success 201 {object} map[string]interface{}
+// @Router /posts/drafts/{id}/publish [post]
+func (dc *DraftController) PublishDraft(c *gin.Context) {
+	user, err := utils.GetCurrentUser(c, dc.DB)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	var draft models.PostDraft
+	if err := dc.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&draft).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Draft not found"})
+		return
+	}
+
+	if draft.PlaceID == nil || draft.Latitude == nil || draft.Longitude == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Draft needs a place and coordinates before publishing"})
+		return
+	}
+
+	req := CreatePostRequest{
+		PostCaption:   draft.PostCaption,
+		PlaceID:       *draft.PlaceID,
+		Latitude:      *draft.Latitude,
+		Longitude:     *draft.Longitude,
+		IsPublic:      draft.IsPublic,
+		AllowComments: draft.AllowComments,
+		Language:      draft.Language,
+	}
+	if err := json.Unmarshal([]byte(draft.MediaItems), &req.MediaItems); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Draft media is corrupted"})
+		return
+	}
+
+	// Taslak, CreatePost isteğiyle aynı bağlama kurallarından geçer
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Taslak gönderiyle aynı transaction içinde silinir; biri olmadan diğeri kalmaz
+	post, earnedPoints, ok := dc.PostController.createPost(c, user.ID, req, func(tx *gorm.DB, post models.Post, earnedPoints int64) error {
+		return tx.Delete(&draft).Error
+	})
+	if !ok {
+		return
+	}
+
+	dc.PostController.respondCreatedPost(c, user, post, earnedPoints)
+}
+
+// DeleteDraft godoc
+// @Summary Delete a post draft
+// @Tags drafts
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Success 200 {object} StandardResponse
+// @Router /posts/drafts/{id} [delete]
+func (dc *DraftController) DeleteDraft(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	result := dc.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.UserID).Delete(&models.PostDraft{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to delete draft"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Draft not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Message: "Draft deleted",
+	})
+}
+
+// applyDraftRequest copies req onto draft, returning a validation message on bad input
+func applyDraftRequest(draft *models.PostDraft, req PostDraftRequest) string {
+	if _, msg := validateCaption("postCaption", req.PostCaption); msg != "" {
+		return msg
+	}
+
+	language := ""
+	if req.Language != "" {
+		code, ok := utils.NormalizeLanguageCode(req.Language)
+		if !ok {
+			return "Language must be an ISO 639-1 code"
+		}
+		language = code
+	}
+
+	mediaItems := req.MediaItems
+	if mediaItems == nil {
+		mediaItems = []CreatePostMediaItem{}
+	}
+	mediaJSON, err := json.Marshal(mediaItems)
+	if err != nil {
+		return "Invalid media items"
+	}
+
+	var data *string
+	if len(req.Data) > 0 && string(req.Data) != "null" {
+		s := string(req.Data)
+		data = &s
+	}
+
+	draft.PostCaption = req.PostCaption
+	draft.MediaItems = string(mediaJSON)
+	draft.PlaceID = req.PlaceID
+	draft.Latitude = req.Latitude
+	draft.Longitude = req.Longitude
+	draft.IsPublic = req.IsPublic == nil || *req.IsPublic
+	draft.AllowComments = req.AllowComments == nil || *req.AllowComments
+	draft.Language = language
+	draft.Data = data
+	return ""
+}
+
+func draftResponse(draft models.PostDraft) PostDraftResponse {
+	mediaItems := []CreatePostMediaItem{}
+	if err := json.Unmarshal([]byte(draft.MediaItems), &mediaItems); err != nil {
+		log.Printf("draftResponse - invalid media items on draft %d: %v", draft.ID, err)
+	}
+
+	response := PostDraftResponse{
+		ID:            draft.ID,
+		CreatedAt:     draft.CreatedAt,
+		UpdatedAt:     draft.UpdatedAt,
+		PostCaption:   draft.PostCaption,
+		MediaItems:    mediaItems,
+		PlaceID:       draft.PlaceID,
+		Latitude:      draft.Latitude,
+		Longitude:     draft.Longitude,
+		IsPublic:      draft.IsPublic,
+		AllowComments: draft.AllowComments,
+		Language:      draft.Language,
+	}
+	if draft.Data != nil {
+		response.Data = json.RawMessage(*draft.Data)
+	}
+	return response
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+// saveDraft stores a draft for place at the given coordinates and returns its id
+func saveDraft(t *testing.T, dc *DraftController, user models.User, place models.Place, lat, lng float64) uint {
+	t.Helper()
+	body, err := json.Marshal(gin.H{
+		"postCaption": "draft",
+		"mediaItems":  []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/test.jpg"}},
+		"placeId":     place.ID,
+		"latitude":    lat,
+		"longitude":   lng,
+		"data":        gin.H{"filter": "sepia"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := callHandler(dc.CreateDraft, http.MethodPost, "/posts/drafts", bytes.NewReader(body), user.ID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create draft: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data PostDraftResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.ID
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPublishDraft(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "draftuser")
+	other := createTestUser(t, db, "draftother")
+	place := createTestPlace(t, db, "draftplace")
+	dc := NewDraftController(db, NewPostController(db, nil))
+
+	id := saveDraft(t, dc, user, place, place.Latitude, place.Longitude)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(id))}
+
+	// Taslak gönderi değildir ve puan kazandırmaz
+	if n := countRows(t, db, &models.Post{}, "user_id = ?", user.ID); n != 0 {
+		t.Fatalf("posts before publish = %d, want 0", n)
+	}
+	if got := totalPoints(t, db, user); got != 0 {
+		t.Fatalf("points before publish = %d, want 0", got)
+	}
+
+	w := callHandler(dc.GetDrafts, http.MethodGet, "/posts/drafts", nil, other.ID)
+	var list struct {
+		Data []PostDraftResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 0 {
+		t.Errorf("other user's drafts = %d, want 0", len(list.Data))
+	}
+	if w := callHandler(dc.PublishDraft, http.MethodPost, "/posts/drafts/"+param.Value+"/publish", nil, other.ID, param); w.Code != http.StatusNotFound {
+		t.Errorf("publish by other user: status = %d, want 404", w.Code)
+	}
+
+	w = callHandler(dc.PublishDraft, http.MethodPost, "/posts/drafts/"+param.Value+"/publish", nil, user.ID, param)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("publish: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if n := countRows(t, db, &models.Post{}, "user_id = ? AND post_caption = ?", user.ID, "draft"); n != 1 {
+		t.Errorf("posts after publish = %d, want 1", n)
+	}
+	if n := countRows(t, db, &models.PostDraft{}, "id = ?", id); n != 0 {
+		t.Errorf("draft still stored after publish")
+	}
+	if got := totalPoints(t, db, user); got == 0 {
+		t.Errorf("points after publish = 0, want the post's points")
+	}
+}
+
+func TestPublishDraftFailsLocationCheck(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "draftfar")
+	place := createTestPlace(t, db, "draftfarplace")
+	dc := NewDraftController(db, NewPostController(db, nil))
+
+	// Taslak mekandan ~10 km uzakta kaydedildi
+	id := saveDraft(t, dc, user, place, place.Latitude+0.09, place.Longitude)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(id))}
+
+	w := callHandler(dc.PublishDraft, http.MethodPost, "/posts/drafts/"+param.Value+"/publish", nil, user.ID, param)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("publish: status = %d, want 400, body = %s", w.Code, w.Body.String())
+	}
+	if n := countRows(t, db, &models.Post{}, "user_id = ?", user.ID); n != 0 {
+		t.Errorf("posts after failed publish = %d, want 0", n)
+	}
+	if n := countRows(t, db, &models.PostDraft{}, "id = ?", id); n != 1 {
+		t.Errorf("draft removed after failed publish")
+	}
+}
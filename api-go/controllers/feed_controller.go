@@ -1,13 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
 	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
 )
@@ -26,8 +30,18 @@ type FeedQuery struct {
 	Radius       float64  `form:"radius,default=10" binding:"omitempty,min=0.1,max=100"` // in kilometers
 	Categories   []string `form:"categories" binding:"omitempty"`
 	Hashtags     []string `form:"hashtags" binding:"omitempty"`
+	Languages    []string `form:"languages" binding:"omitempty"`
+	MediaType    string   `form:"mediaType" binding:"omitempty,mediatype"` // yalnızca ilk medyası bu türde olan gönderiler
 	OnlyFriends  bool     `form:"onlyFriends"`
 	NearbyPlaces bool     `form:"nearbyPlaces"`
+	Since        string   `form:"since"` // RFC3339 zaman damgası veya gönderi ID'si
+}
+
+type FeedPreferenceRequest struct {
+	SortBy      string   `json:"sortBy" binding:"omitempty,oneof=newest popular trending friends_activity"`
+	Radius      float64  `json:"radius" binding:"omitempty,min=0.1,max=100"`
+	Categories  []string `json:"categories"`
+	OnlyFriends bool     `json:"onlyFriends"`
 }
 
 func NewFeedController(db *gorm.DB) *FeedController {
@@ -44,13 +58,18 @@ func NewFeedController(db *gorm.DB) *FeedController {
 // @Param pageSize query integer false "Items per page (default: 20, max: 50)"
 // @Param sortBy query string false "Sort by: newest, popular, trending, friends_activity"
 // @Param timeFrame query string false "Time frame: today, this_week, this_month, all_time"
+// @Param timezone query string false "IANA timezone for time frame boundaries (e.g. Europe/Istanbul)"
+// @Param tzOffset query integer false "UTC offset in minutes east of UTC, used when timezone is not given"
 // @Param latitude query number false "User's latitude for location-based feed"
 // @Param longitude query number false "User's longitude for location-based feed"
 // @Param radius query number false "Search radius in kilometers (default: 10, max: 100)"
 // @Param categories query []string false "Filter by place categories"
 // @Param hashtags query []string false "Filter by hashtags"
-// @Param onlyFriends query boolean false "Show only friends' activities"
+// @Param languages query []string false "Filter by post language (ISO 639-1 codes); all languages when omitted"
+// @Param mediaType query string false "Only posts whose first media is of this type, e.g. photo or video"
+// @Param onlyFriends query boolean false "Only posts from users the viewer follows; defaults to the stored preference"
 // @Param nearbyPlaces query boolean false "Show posts from nearby places"
+// @Param since query string false "Only posts newer than this RFC3339 timestamp or post ID (newest and friends_activity sorts); adds newCount"
 // @Success 200 {object} map[string]interface{}
 // @Router /feed [get]
 func (fc *FeedController) GetUserFeed(c *gin.Context) {
@@ -68,19 +87,45 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 		return
 	}
 
+	if err := fc.applyFeedPreferences(c, userID, &query); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading feed preferences"})
+		return
+	}
+
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Base query
 	db := fc.DB.Model(&models.Post{})
 
 	// Join necessary tables
 	db = db.Joins("JOIN users ON posts.user_id = users.id")
-	db = db.Joins("JOIN places ON posts.place_id = places.id")
+	db = db.Joins("JOIN places ON posts.place_id = places.id AND places.needs_review = false")
 
 	// Filter by followed users if not showing only nearby places
+	candidateLimit := config.GetFeedCandidateLimit()
 	if !query.NearbyPlaces {
-		db = db.Joins("JOIN follows ON posts.user_id = follows.following_id").
-			Where("follows.follower_id = ?", userID)
+		db = db.Where("posts.id IN ("+feedCandidatesSQL+")", candidateLimit, userID, candidateLimit)
 	}
 
+	// Yakındaki yerler açıkken de yalnızca takip edilenlerin gönderileri istenebilir
+	if query.OnlyFriends {
+		db = db.Where(`posts.user_id IN (
+			SELECT following_user_id FROM follows
+			WHERE follower_user_id = ? AND status = 'accepted' AND deleted_at IS NULL
+		)`, userID)
+	}
+
+	// İncelemedeki yinelenen gönderiler onaylanana kadar akışta gösterilmez
+	db = db.Where("posts.needs_review = false")
+
+	// Susturulan kullanıcıların gönderileri akışta gösterilmez
+	mutedCond, mutedArgs := notMutedCondition("posts.user_id", userID)
+	db = db.Where(mutedCond, mutedArgs...)
+
 	// Apply location-based filtering if coordinates are provided
 	if query.Latitude != 0 && query.Longitude != 0 {
 		// Haversine formula for distance calculation
@@ -96,7 +141,7 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 
 	// Apply category filtering
 	if len(query.Categories) > 0 {
-		db = db.Where("places.categories && ?", query.Categories)
+		db = db.Where("places.categories && ?", types.NormalizeCategoryFilter(query.Categories))
 	}
 
 	// Apply hashtag filtering
@@ -110,14 +155,40 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 		db = db.Where(strings.Join(hashtagConditions, " OR "), hashtagValues...)
 	}
 
-	// Apply time frame filter
-	switch query.TimeFrame {
-	case "today":
-		db = db.Where("posts.created_at >= CURRENT_DATE")
-	case "this_week":
-		db = db.Where("posts.created_at >= DATE_TRUNC('week', CURRENT_DATE)")
-	case "this_month":
-		db = db.Where("posts.created_at >= DATE_TRUNC('month', CURRENT_DATE)")
+	// Apply language filtering
+	if len(query.Languages) > 0 {
+		languages := make([]string, 0, len(query.Languages))
+		for _, lang := range query.Languages {
+			code, ok := utils.NormalizeLanguageCode(lang)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid language code: " + lang})
+				return
+			}
+			languages = append(languages, code)
+		}
+		db = db.Where("posts.language IN ?", languages)
+	}
+
+	// Apply media type filtering
+	db = filterByMediaType(db, query.MediaType)
+
+	// Apply time frame filter (kullanıcının saat dilimine göre)
+	if start, ok := utils.PeriodStart(query.TimeFrame, time.Now(), loc); ok {
+		db = db.Where("posts.created_at >= ?", start)
+	}
+
+	// Yenileme için yalnızca verilen noktadan yeni gönderiler
+	if query.Since != "" {
+		if query.SortBy == "popular" || query.SortBy == "trending" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since is only supported for newest and friends_activity sorts"})
+			return
+		}
+		sinceCond, sinceArgs, err := fc.feedSinceCondition(query.Since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		db = db.Where(sinceCond, sinceArgs...)
 	}
 
 	// Apply sorting
@@ -125,23 +196,8 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 	case "popular":
 		db = db.Order("(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) DESC")
 	case "trending":
-		db = db.Order(`
-			(
-				SELECT COUNT(*) FROM likes 
-				WHERE likes.post_id = posts.id 
-				AND likes.created_at >= NOW() - INTERVAL '24 hours'
-			) * 3 + 
-			(
-				SELECT COUNT(*) FROM comments 
-				WHERE comments.post_id = posts.id 
-				AND comments.created_at >= NOW() - INTERVAL '24 hours'
-			) * 2 +
-			(
-				EXTRACT(EPOCH FROM posts.created_at) / (
-					EXTRACT(EPOCH FROM NOW()) - EXTRACT(EPOCH FROM posts.created_at) + 7200
-				)
-			) DESC
-		`)
+		// Order yalnızca sütun kabul eder; parametreli ifade clause olarak eklenir
+		db = db.Clauses(trendingOrder(config.GetTrendingParams(), time.Now()))
 	case "friends_activity":
 		// Posts that friends have interacted with recently
 		db = db.Where(`
@@ -149,7 +205,8 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 				SELECT 1 FROM likes 
 				WHERE likes.post_id = posts.id 
 				AND likes.user_id IN (
-					SELECT following_id FROM follows WHERE follower_id = ?
+					SELECT following_user_id FROM follows
+					WHERE follower_user_id = ? AND status = 'accepted' AND deleted_at IS NULL
 				)
 				AND likes.created_at >= NOW() - INTERVAL '24 hours'
 			)
@@ -157,8 +214,10 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 			EXISTS (
 				SELECT 1 FROM comments
 				WHERE comments.post_id = posts.id
+				AND comments.deleted_at IS NULL
 				AND comments.user_id IN (
-					SELECT following_id FROM follows WHERE follower_id = ?
+					SELECT following_user_id FROM follows
+					WHERE follower_user_id = ? AND status = 'accepted' AND deleted_at IS NULL
 				)
 				AND comments.created_at >= NOW() - INTERVAL '24 hours'
 			)
@@ -173,6 +232,18 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 	var total int64
 	db.Count(&total)
 
+	// Aday sınırı dolduysa toplam yalnızca en yeni adaylar içindir, gerçek toplam daha büyük olabilir
+	approximate := false
+	if !query.NearbyPlaces {
+		var candidates int64
+		if err := fc.DB.Raw("SELECT COUNT(*) FROM ("+feedCandidatesSQL+") candidates", candidateLimit, userID, candidateLimit).
+			Scan(&candidates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+			return
+		}
+		approximate = candidates >= int64(candidateLimit)
+	}
+
 	// Structure to hold post data with additional information
 	var posts []struct {
 		models.Post
@@ -203,7 +274,7 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 				ELSE places.base_points 
 			END as place_point_value,
 			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
-			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id) as comments_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count,
 			EXISTS(SELECT 1 FROM likes WHERE likes.post_id = posts.id AND likes.user_id = ?) as is_liked,
 			CASE 
 				WHEN ? != 0 AND ? != 0 THEN 
@@ -219,9 +290,10 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 				SELECT array_agg(DISTINCT u.username)
 				FROM likes l
 				JOIN users u ON l.user_id = u.id
-				JOIN follows f ON l.user_id = f.following_id
+				JOIN follows f ON l.user_id = f.following_user_id
+					AND f.status = 'accepted' AND f.deleted_at IS NULL
 				WHERE l.post_id = posts.id
-				AND f.follower_id = ?
+				AND f.follower_user_id = ?
 				LIMIT 3
 			) as friends_liked
 		`, userID, userID, query.Latitude, query.Longitude, query.Latitude, query.Longitude, query.Latitude, userID).
@@ -234,13 +306,186 @@ func (fc *FeedController) GetUserFeed(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"posts": posts,
 		"pagination": gin.H{
 			"currentPage": query.Page,
 			"pageSize":    query.PageSize,
 			"totalItems":  total,
 			"totalPages":  math.Ceil(float64(total) / float64(query.PageSize)),
+			"approximate": approximate,
 		},
+		"meta": gin.H{
+			"mediaType": mediaTypeMeta(query.MediaType),
+		},
+	}
+	if query.Since != "" {
+		response["newCount"] = total
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// feedCandidatesSQL selects the IDs of the newest posts from the users the
+// viewer follows, at most the candidate limit in total. Arguments: the limit,
+// the viewer ID, the limit again. Each followed user contributes through an index scan
+// of their own newest posts (LATERAL), so the cost depends on the number of
+// follows and the limit, not on how many posts those users ever made.
+//
+// The feed is filtered and ranked within these candidates only. Filters
+// that match few recent posts (a rare category, a far away location, an
+// old time frame) can therefore return fewer posts than exist, and popular
+// or trending sorts only consider recent posts. totalItems counts the
+// filtered candidates and is marked approximate when the limit was reached.
+const feedCandidatesSQL = `
+	SELECT recent.id FROM follows
+	CROSS JOIN LATERAL (
+		SELECT p.id, p.created_at FROM posts p
+		WHERE p.user_id = follows.following_user_id AND p.deleted_at IS NULL
+		ORDER BY p.created_at DESC
+		LIMIT ?
+	) recent
+	WHERE follows.follower_user_id = ? AND follows.status = 'accepted' AND follows.deleted_at IS NULL
+	ORDER BY recent.created_at DESC
+	LIMIT ?`
+
+// feedSinceCondition builds the filter for posts newer than since, which is
+// either an RFC3339 timestamp or the ID of the newest post the client has.
+func (fc *FeedController) feedSinceCondition(since string) (string, []interface{}, error) {
+	if postID, err := strconv.ParseUint(since, 10, 64); err == nil {
+		var post models.Post
+		if err := fc.DB.Unscoped().Select("id, created_at").First(&post, postID).Error; err != nil {
+			return "", nil, errors.New("since post not found")
+		}
+		// Aynı zaman damgalı gönderiler ID ile ayrılır
+		return "(posts.created_at > ? OR (posts.created_at = ? AND posts.id > ?))",
+			[]interface{}{post.CreatedAt, post.CreatedAt, post.ID}, nil
+	}
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return "", nil, errors.New("since must be an RFC3339 timestamp or a post ID")
+	}
+	return "posts.created_at > ?", []interface{}{sinceTime}, nil
+}
+
+// applyFeedPreferences fills in feed filters the request did not specify
+// from the user's stored preferences; explicit query params always win. The
+// stored sort is not applied to since requests, so a stored popular or
+// trending sort does not make them fail.
+func (fc *FeedController) applyFeedPreferences(c *gin.Context, userID uint, query *FeedQuery) error {
+	var pref models.FeedPreference
+	if err := fc.DB.Where("user_id = ?", userID).First(&pref).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	params := c.Request.URL.Query()
+	if _, ok := params["sortBy"]; !ok && pref.SortBy != "" && query.Since == "" {
+		query.SortBy = pref.SortBy
+	}
+	if _, ok := params["radius"]; !ok && pref.Radius > 0 {
+		query.Radius = pref.Radius
+	}
+	if _, ok := params["categories"]; !ok && len(pref.Categories) > 0 {
+		query.Categories = pref.Categories
+	}
+	if _, ok := params["onlyFriends"]; !ok {
+		query.OnlyFriends = pref.OnlyFriends
+	}
+	return nil
+}
+
+// GetFeedPreferences godoc
+// @Summary Get the current user's default feed filters
+// @Tags feed
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /feed/preferences [get]
+func (fc *FeedController) GetFeedPreferences(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var pref models.FeedPreference
+	err := fc.DB.Where("user_id = ?", user.UserID).First(&pref).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching feed preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    feedPreferenceResponse(pref),
+	})
+}
+
+// UpdateFeedPreferences godoc
+// @Summary Replace the current user's default feed filters
+// @Description Stored values are applied by GET /feed when the matching query params are omitted
+// @Tags feed
+// @Accept json
+// @Produce json
+// @Param request body FeedPreferenceRequest true "Feed preferences"
+// @Success 200 {object} StandardResponse
+// @Router /feed/preferences [put]
+func (fc *FeedController) UpdateFeedPreferences(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req FeedPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var pref models.FeedPreference
+	err := fc.DB.Where("user_id = ?", user.UserID).First(&pref).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching feed preferences"})
+		return
+	}
+
+	pref.UserID = user.UserID
+	pref.SortBy = req.SortBy
+	pref.Radius = req.Radius
+	pref.Categories = req.Categories
+	pref.OnlyFriends = req.OnlyFriends
+
+	if err := fc.DB.Save(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving feed preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    feedPreferenceResponse(pref),
+		Message: "Feed preferences updated",
 	})
 }
+
+func feedPreferenceResponse(pref models.FeedPreference) gin.H {
+	categories := []string(pref.Categories)
+	if categories == nil {
+		categories = []string{}
+	}
+	return gin.H{
+		"sortBy":      pref.SortBy,
+		"radius":      pref.Radius,
+		"categories":  categories,
+		"onlyFriends": pref.OnlyFriends,
+	}
+}
@@ -0,0 +1,385 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestFeedPreferences(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "feedprefs")
+	fc := NewFeedController(db)
+
+	// Kayıtlı tercih yokken varsayılanlar döner
+	w := callHandler(fc.GetFeedPreferences, http.MethodGet, "/feed/preferences", nil, user.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	if w := callHandler(fc.UpdateFeedPreferences, http.MethodPut, "/feed/preferences",
+		strings.NewReader(`{"sortBy":"bogus"}`), user.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid sortBy: status = %d, want 400", w.Code)
+	}
+	w = callHandler(fc.UpdateFeedPreferences, http.MethodPut, "/feed/preferences",
+		strings.NewReader(`{"sortBy":"popular","radius":25,"categories":["cafe","park"],"onlyFriends":true}`), user.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("put: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = callHandler(fc.GetFeedPreferences, http.MethodGet, "/feed/preferences", nil, user.ID)
+	var resp struct {
+		Data FeedPreferenceRequest `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := FeedPreferenceRequest{SortBy: "popular", Radius: 25, Categories: []string{"cafe", "park"}, OnlyFriends: true}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("stored preferences = %+v, want %+v", resp.Data, want)
+	}
+
+	apply := func(target string) FeedQuery {
+		t.Helper()
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		var query FeedQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			t.Fatal(err)
+		}
+		if err := fc.applyFeedPreferences(c, user.ID, &query); err != nil {
+			t.Fatal(err)
+		}
+		return query
+	}
+
+	got := apply("/feed")
+	if got.SortBy != "popular" || got.Radius != 25 || !reflect.DeepEqual(got.Categories, []string{"cafe", "park"}) || !got.OnlyFriends {
+		t.Errorf("omitted params = %+v, want stored preferences", got)
+	}
+
+	// Açıkça verilen parametreler tercihleri ezer
+	got = apply("/feed?sortBy=newest&radius=5&categories=museum&onlyFriends=false")
+	if got.SortBy != "newest" || got.Radius != 5 || !reflect.DeepEqual(got.Categories, []string{"museum"}) || got.OnlyFriends {
+		t.Errorf("explicit params = %+v, want request values", got)
+	}
+
+	// Kayıtlı sıralama since isteklerine uygulanmaz
+	if got := apply("/feed?since=2024-01-01T00:00:00Z"); got.SortBy != "" {
+		t.Errorf("since request sortBy = %q, want the stored sort ignored", got.SortBy)
+	}
+
+	// Başka kullanıcının tercihleri uygulanmaz
+	other := createTestUser(t, db, "feedprefsother")
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/feed", nil)
+	var query FeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		t.Fatal(err)
+	}
+	if err := fc.applyFeedPreferences(c, other.ID, &query); err != nil {
+		t.Fatal(err)
+	}
+	if query.SortBy == "popular" || query.OnlyFriends {
+		t.Errorf("other user's query = %+v, want defaults", query)
+	}
+}
+
+func TestFeedOnlyFriendsPreference(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "friendsviewer")
+	friend := createTestUser(t, db, "friendsfriend")
+	stranger := createTestUser(t, db, "friendsstranger")
+	place := createTestPlace(t, db, "friendsplace")
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: friend.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	strangerPost := createTestPost(t, db, stranger, place, "stranger post", true)
+	friendPost := createTestPost(t, db, friend, place, "friend post", true)
+	if err := db.Model(&strangerPost).Update("created_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	fc := NewFeedController(db)
+
+	if w := callHandler(fc.UpdateFeedPreferences, http.MethodPut, "/feed/preferences",
+		strings.NewReader(`{"sortBy":"popular","onlyFriends":true}`), viewer.ID); w.Code != http.StatusOK {
+		t.Fatalf("put: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		target string
+		want   []uint
+	}{
+		// Yakındaki yerler herkesin gönderilerini getirir; kayıtlı onlyFriends yabancıları çıkarır
+		{"/feed?nearbyPlaces=true&sortBy=newest", []uint{friendPost.ID}},
+		{"/feed?nearbyPlaces=true&sortBy=newest&onlyFriends=false", []uint{friendPost.ID, strangerPost.ID}},
+		// Kayıtlı popular sıralaması since isteğini bozmaz
+		{"/feed?nearbyPlaces=true&since=" + strconv.Itoa(int(strangerPost.ID)), []uint{friendPost.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if ids, _ := feedPostIDs(t, fc, tt.target, viewer.ID); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("feed = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	// Açıkça verilen çakışan sıralama yine reddedilir
+	target := "/feed?sortBy=popular&since=" + strconv.Itoa(int(strangerPost.ID))
+	if w := callHandler(fc.GetUserFeed, http.MethodGet, target, nil, viewer.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("explicit popular with since: status = %d, want 400", w.Code)
+	}
+}
+
+func TestFeedSinceCondition(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "feedsince")
+	place := createTestPlace(t, db, "feedsinceplace")
+	fc := NewFeedController(db)
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var posts []models.Post
+	for _, at := range []time.Time{base, base, base.Add(time.Minute), base.Add(2 * time.Minute)} {
+		post := createTestPost(t, db, user, place, "", true)
+		if err := db.Model(&post).Update("created_at", at).Error; err != nil {
+			t.Fatal(err)
+		}
+		posts = append(posts, post)
+	}
+	newerThan := func(since string) []uint {
+		t.Helper()
+		cond, args, err := fc.feedSinceCondition(since)
+		if err != nil {
+			t.Fatalf("since %q: %v", since, err)
+		}
+		var ids []uint
+		if err := db.Model(&models.Post{}).Where(cond, args...).Order("id").Pluck("id", &ids).Error; err != nil {
+			t.Fatal(err)
+		}
+		return ids
+	}
+
+	// Aynı zaman damgalı ikinci gönderi ID ile ayrılır
+	if got, want := newerThan(strconv.Itoa(int(posts[0].ID))), []uint{posts[1].ID, posts[2].ID, posts[3].ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("since first post = %v, want %v", got, want)
+	}
+	if got, want := newerThan(base.Add(time.Minute).Format(time.RFC3339)), []uint{posts[3].ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("since timestamp = %v, want %v", got, want)
+	}
+	if got := newerThan(strconv.Itoa(int(posts[3].ID))); len(got) != 0 {
+		t.Errorf("since newest post = %v, want none", got)
+	}
+	for _, since := range []string{"999999", "yesterday"} {
+		if _, _, err := fc.feedSinceCondition(since); err == nil {
+			t.Errorf("since %q: expected error", since)
+		}
+	}
+
+	for _, target := range []string{"/feed?since=yesterday", "/feed?since=" + strconv.Itoa(int(posts[0].ID)) + "&sortBy=popular"} {
+		if w := callHandler(fc.GetUserFeed, http.MethodGet, target, nil, user.ID); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, w.Code)
+		}
+	}
+}
+
+// feedPostIDs calls GetUserFeed and returns the post ids and whether the total was approximate
+func feedPostIDs(t testing.TB, fc *FeedController, target string, userID uint) ([]uint, bool) {
+	t.Helper()
+	w := callHandler(fc.GetUserFeed, http.MethodGet, target, nil, userID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d, body = %s", target, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Posts []struct {
+			ID uint `json:"id"`
+		} `json:"posts"`
+		Pagination struct {
+			Approximate bool `json:"approximate"`
+		} `json:"pagination"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]uint, len(resp.Posts))
+	for i, post := range resp.Posts {
+		ids[i] = post.ID
+	}
+	return ids, resp.Pagination.Approximate
+}
+
+func TestFeedCandidateLimit(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "feedcapviewer")
+	followed := createTestUser(t, db, "feedcapfollowed")
+	stranger := createTestUser(t, db, "feedcapstranger")
+	place := createTestPlace(t, db, "feedcapplace")
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: followed.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	var posts []models.Post
+	for i := 0; i < 5; i++ {
+		post := createTestPost(t, db, followed, place, "", true)
+		if err := db.Model(&post).Update("created_at", base.Add(time.Duration(i)*time.Minute)).Error; err != nil {
+			t.Fatal(err)
+		}
+		posts = append(posts, post)
+	}
+	createTestPost(t, db, stranger, place, "", true)
+	fc := NewFeedController(db)
+
+	ids, approximate := feedPostIDs(t, fc, "/feed", viewer.ID)
+	if want := []uint{posts[4].ID, posts[3].ID, posts[2].ID, posts[1].ID, posts[0].ID}; !reflect.DeepEqual(ids, want) || approximate {
+		t.Errorf("uncapped feed = %v (approximate %v), want %v (exact)", ids, approximate, want)
+	}
+
+	// Yalnızca en yeni 3 gönderi aday olur
+	t.Setenv("FEED_CANDIDATE_LIMIT", "3")
+	ids, approximate = feedPostIDs(t, fc, "/feed", viewer.ID)
+	if want := []uint{posts[4].ID, posts[3].ID, posts[2].ID}; !reflect.DeepEqual(ids, want) || !approximate {
+		t.Errorf("capped feed = %v (approximate %v), want %v (approximate)", ids, approximate, want)
+	}
+}
+
+func TestTrendingHalfLifeReordersFeed(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "trendviewer")
+	author := createTestUser(t, db, "trendauthor")
+	place := createTestPlace(t, db, "trendplace")
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: author.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TRENDING_LIKE_WEIGHT", "3")
+	t.Setenv("TRENDING_BASE_SCORE", "1")
+
+	// 12 saatlik gönderi son saatte 5 beğeni aldı, yeni gönderi hiç almadı
+	popular := createTestPost(t, db, author, place, "", true)
+	if err := db.Model(&popular).Update("created_at", time.Now().Add(-12*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		fan := createTestUser(t, db, fmt.Sprintf("trendfan%d", i))
+		if err := db.Create(&models.Like{PostID: popular.ID, UserID: fan.ID}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	fresh := createTestPost(t, db, author, place, "", true)
+	fc := NewFeedController(db)
+
+	// 1 saatlik yarı ömürde 12 saatlik gönderinin puanı 16/4096'ya düşer
+	t.Setenv("TRENDING_HALF_LIFE", "1h")
+	if ids, _ := feedPostIDs(t, fc, "/feed?sortBy=trending", viewer.ID); !reflect.DeepEqual(ids, []uint{fresh.ID, popular.ID}) {
+		t.Errorf("short half-life = %v, want fresh post first %v", ids, []uint{fresh.ID, popular.ID})
+	}
+
+	// 48 saatlik yarı ömürde beğeniler yaşı telafi eder
+	t.Setenv("TRENDING_HALF_LIFE", "48h")
+	if ids, _ := feedPostIDs(t, fc, "/feed?sortBy=trending", viewer.ID); !reflect.DeepEqual(ids, []uint{popular.ID, fresh.ID}) {
+		t.Errorf("long half-life = %v, want liked post first %v", ids, []uint{popular.ID, fresh.ID})
+	}
+
+	// Beğeniler pencerenin dışında kalınca sayılmaz
+	t.Setenv("TRENDING_ENGAGEMENT_WINDOW", "1ns")
+	if ids, _ := feedPostIDs(t, fc, "/feed?sortBy=trending", viewer.ID); !reflect.DeepEqual(ids, []uint{fresh.ID, popular.ID}) {
+		t.Errorf("likes outside window = %v, want fresh post first %v", ids, []uint{fresh.ID, popular.ID})
+	}
+}
+
+// BenchmarkGetUserFeed measures the feed of a user following 2000 people with 5 posts each
+func BenchmarkGetUserFeed(b *testing.B) {
+	db := openTestDB(b)
+	viewer := createTestUser(b, db, "feedbenchviewer")
+	place := createTestPlace(b, db, "feedbenchplace")
+
+	const followed, postsPerUser = 2000, 5
+	users := make([]models.User, followed)
+	for i := range users {
+		name := fmt.Sprintf("feedbench%d", i)
+		users[i] = models.User{Username: name, Email: name + "@example.com", RoleID: viewer.RoleID, IsVerified: true}
+	}
+	if err := db.CreateInBatches(&users, 500).Error; err != nil {
+		b.Fatal(err)
+	}
+	follows := make([]models.Follow, 0, followed)
+	posts := make([]models.Post, 0, followed*postsPerUser)
+	start := time.Now().Add(-30 * 24 * time.Hour)
+	for i, user := range users {
+		follows = append(follows, models.Follow{FollowerUserID: viewer.ID, FollowingUserID: user.ID, Status: "accepted"})
+		for j := 0; j < postsPerUser; j++ {
+			posts = append(posts, models.Post{
+				UserID:    user.ID,
+				PlaceID:   place.ID,
+				Latitude:  place.Latitude,
+				Longitude: place.Longitude,
+				IsPublic:  true,
+				CreatedAt: start.Add(time.Duration(i*postsPerUser+j) * time.Minute),
+			})
+		}
+	}
+	if err := db.CreateInBatches(&follows, 500).Error; err != nil {
+		b.Fatal(err)
+	}
+	if err := db.CreateInBatches(&posts, 500).Error; err != nil {
+		b.Fatal(err)
+	}
+	fc := NewFeedController(db)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ids, _ := feedPostIDs(b, fc, "/feed?pageSize=20", viewer.ID); len(ids) != 20 {
+			b.Fatalf("feed returned %d posts, want 20", len(ids))
+		}
+	}
+}
+
+func TestFeedLanguageFilter(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "langviewer")
+	author := createTestUser(t, db, "langauthor")
+	place := createTestPlace(t, db, "langplace")
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: author.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	posts := map[string]models.Post{}
+	for i, language := range []string{"tr", "en", "de", ""} {
+		post := createTestPost(t, db, author, place, "caption "+language, true)
+		if err := db.Model(&post).Updates(map[string]interface{}{
+			"language":   language,
+			"created_at": base.Add(time.Duration(i) * time.Minute),
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+		posts[language] = post
+	}
+	fc := NewFeedController(db)
+
+	tests := []struct {
+		target string
+		want   []uint
+	}{
+		// Filtre verilmezse etiketsizler dahil tüm diller gelir
+		{"/feed", []uint{posts[""].ID, posts["de"].ID, posts["en"].ID, posts["tr"].ID}},
+		{"/feed?languages=tr", []uint{posts["tr"].ID}},
+		{"/feed?languages=EN-us", []uint{posts["en"].ID}},
+		{"/feed?languages=tr&languages=de", []uint{posts["de"].ID, posts["tr"].ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if ids, _ := feedPostIDs(t, fc, tt.target, viewer.ID); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("feed = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/snap-point/api-go/config"
+	"gorm.io/gorm/clause"
+)
+
+// trendingMaxHalfLives çok eski gönderilerin çarpanı bu kadar yarı ömürde
+// sabitlenir; aksi halde POWER float taşmasıyla (underflow) hata verir
+const trendingMaxHalfLives = 1000
+
+// trendingScoreSQL scores a post for the trending sort as described on
+// config.TrendingParams. Arguments: the engagement window start, the like
+// weight, the window start, the comment weight, the base score, the scoring
+// time, the half-life in seconds and trendingMaxHalfLives.
+const trendingScoreSQL = `(
+	(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id AND likes.created_at >= ?) * ?::float8 +
+	(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL
+		AND comments.created_at >= ?) * ?::float8 +
+	?::float8
+) * POWER(0.5, LEAST(GREATEST(EXTRACT(EPOCH FROM ?::timestamptz - posts.created_at), 0) / ?::float8, ?::float8))`
+
+// trendingOrder orders posts by their trending score at now, highest first;
+// add it with Clauses. Equal scores fall back to the newer post, then the
+// higher ID, so pages stay stable.
+func trendingOrder(params config.TrendingParams, now time.Time) clause.OrderBy {
+	windowStart := now.Add(-params.EngagementWindow)
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL: trendingScoreSQL + " DESC, posts.created_at DESC, posts.id DESC",
+		Vars: []interface{}{
+			windowStart, params.LikeWeight,
+			windowStart, params.CommentWeight,
+			params.BaseScore,
+			now, params.HalfLife.Seconds(), float64(trendingMaxHalfLives),
+		},
+		WithoutParentheses: true,
+	}}
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/snap-point/api-go/metrics"
+	"github.com/snap-point/api-go/types"
+)
+
+// Google Places uç noktası ve yeniden deneme ayarları; testlerde sahte sunucuya ve kısa sürelere çekilir
+var (
+	googlePlacesNearbyURL      = "https://maps.googleapis.com/maps/api/place/nearbysearch/json"
+	googlePlacesHTTPClient     = &http.Client{}
+	googlePlacesAttemptTimeout = 10 * time.Second
+	googlePlacesBackoff        = 500 * time.Millisecond
+)
+
+// Geçici hatalarda toplam deneme sayısı (ilk istek dahil)
+const googlePlacesMaxAttempts = 3
+
+// Metriklerde kullanılan Google API adları
+const (
+	googleAPINearbySearch = "places_nearby_search"
+	googleAPIPlacePhoto   = "places_photo"
+)
+
+// recordGoogleAPICall counts one Google API call by its HTTP status, or as
+// "error" when no response arrived, and records its latency.
+func recordGoogleAPICall(api string, start time.Time, resp *http.Response, err error) {
+	result := "error"
+	if err == nil {
+		result = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.GoogleAPIRequests.WithLabelValues(api, result).Inc()
+	metrics.GoogleAPIDuration.WithLabelValues(api).Observe(time.Since(start).Seconds())
+}
+
+// errGooglePlacesStatus is returned for non-retryable HTTP statuses
+var errGooglePlacesStatus = errors.New("unexpected Google Places API status")
+
+// getGooglePlaces fetches one Nearby Search page. Each attempt has its own
+// deadline; network errors, attempt timeouts and 5xx responses are retried
+// with exponential backoff. Cancelling ctx stops both the request and the
+// waits between attempts.
+func getGooglePlaces(ctx context.Context, url string) (types.GooglePlacesResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < googlePlacesMaxAttempts; attempt++ {
+		if attempt > 0 {
+			wait := googlePlacesBackoff << (attempt - 1)
+			log.Printf("Retrying Google Places request in %s (attempt %d/%d): %v", wait, attempt+1, googlePlacesMaxAttempts, lastErr)
+			select {
+			case <-ctx.Done():
+				return types.GooglePlacesResponse{}, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		response, retry, err := getGooglePlacesOnce(ctx, url)
+		if err == nil {
+			return response, nil
+		}
+		// İstemci vazgeçtiyse yeniden denemenin anlamı yok
+		if ctx.Err() != nil {
+			return types.GooglePlacesResponse{}, ctx.Err()
+		}
+		if !retry {
+			return types.GooglePlacesResponse{}, err
+		}
+		lastErr = err
+	}
+	return types.GooglePlacesResponse{}, fmt.Errorf("Google Places API failed after %d attempts: %w", googlePlacesMaxAttempts, lastErr)
+}
+
+// getGooglePlacesOnce makes a single attempt and reports whether its error is worth retrying
+func getGooglePlacesOnce(ctx context.Context, url string) (types.GooglePlacesResponse, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, googlePlacesAttemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return types.GooglePlacesResponse{}, false, err
+	}
+
+	start := time.Now()
+	resp, err := googlePlacesHTTPClient.Do(req)
+	recordGoogleAPICall(googleAPINearbySearch, start, resp, err)
+	if err != nil {
+		return types.GooglePlacesResponse{}, true, fmt.Errorf("error calling Google Places API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return types.GooglePlacesResponse{}, true, fmt.Errorf("%w: %d", errGooglePlacesStatus, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return types.GooglePlacesResponse{}, false, fmt.Errorf("%w: %d", errGooglePlacesStatus, resp.StatusCode)
+	}
+
+	var response types.GooglePlacesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		// Gövde okunurken süre dolduysa bağlantı sorunu sayılır
+		return types.GooglePlacesResponse{}, ctx.Err() != nil, fmt.Errorf("error decoding API response: %w", err)
+	}
+	return response, false, nil
+}
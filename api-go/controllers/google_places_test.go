@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/snap-point/api-go/metrics"
+)
+
+// withFastGoogleRetries shortens the attempt timeout and backoff for the duration of a test
+func withFastGoogleRetries(t *testing.T, attemptTimeout time.Duration) {
+	t.Helper()
+	prevTimeout, prevBackoff := googlePlacesAttemptTimeout, googlePlacesBackoff
+	googlePlacesAttemptTimeout = attemptTimeout
+	googlePlacesBackoff = 10 * time.Millisecond
+	t.Cleanup(func() {
+		googlePlacesAttemptTimeout, googlePlacesBackoff = prevTimeout, prevBackoff
+	})
+}
+
+func TestGetGooglePlacesTimesOut(t *testing.T) {
+	withFastGoogleRetries(t, 50*time.Millisecond)
+
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := getGooglePlaces(context.Background(), server.URL)
+	if err == nil {
+		t.Fatal("expected an error from a hanging server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetch took %s, want it bounded by the attempt timeouts", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != googlePlacesMaxAttempts {
+		t.Errorf("calls = %d, want %d", got, googlePlacesMaxAttempts)
+	}
+}
+
+func TestGetGooglePlacesStopsOnCancel(t *testing.T) {
+	withFastGoogleRetries(t, 5*time.Second)
+
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := getGooglePlaces(ctx, server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("fetch took %s after the caller gave up", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want no retries after cancellation", got)
+	}
+}
+
+func TestGetGooglePlacesRetriesServerError(t *testing.T) {
+	withFastGoogleRetries(t, time.Second)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"OK","results":[{"place_id":"abc","name":"Galata Kulesi"}]}`))
+	}))
+	defer server.Close()
+
+	failed := testutil.ToFloat64(metrics.GoogleAPIRequests.WithLabelValues(googleAPINearbySearch, "500"))
+	succeeded := testutil.ToFloat64(metrics.GoogleAPIRequests.WithLabelValues(googleAPINearbySearch, "200"))
+	response, err := getGooglePlaces(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+	// Her deneme ayrı sayılır
+	if got := testutil.ToFloat64(metrics.GoogleAPIRequests.WithLabelValues(googleAPINearbySearch, "500")) - failed; got != 1 {
+		t.Errorf("500 calls counted = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.GoogleAPIRequests.WithLabelValues(googleAPINearbySearch, "200")) - succeeded; got != 1 {
+		t.Errorf("200 calls counted = %v, want 1", got)
+	}
+	if response.Status != "OK" || len(response.Results) != 1 || response.Results[0].PlaceID != "abc" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestGetGooglePlacesDoesNotRetryClientError(t *testing.T) {
+	withFastGoogleRetries(t, time.Second)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := getGooglePlaces(context.Background(), server.URL)
+	if !errors.Is(err, errGooglePlacesStatus) {
+		t.Fatalf("err = %v, want errGooglePlacesStatus", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Hashtag sayfası ayarları
+const (
+	// hashtagRecentDays son dönem gönderi sayısının kapsadığı gün sayısı
+	hashtagRecentDays = 7
+	// hashtagTopPostsLimit etkileşime göre örneklenen en fazla gönderi sayısı
+	hashtagTopPostsLimit = 9
+	hashtagMaxLength     = 100
+)
+
+// hashtagCondition matches posts whose caption contains the tag; tags are
+// extracted the same way as in hashtag search. Argument: the lowercase tag.
+const hashtagCondition = `EXISTS(SELECT 1 FROM regexp_matches(posts.post_caption, '#([[:alnum:]_]+)', 'g') AS m WHERE LOWER(m[1]) = ?)`
+
+// postEngagementSQL bir gönderinin beğeni ve silinmemiş yorum sayısı toplamı
+const postEngagementSQL = `((SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) +
+	(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL))`
+
+// HashtagStats summarises the posts using a hashtag that the viewer can see
+type HashtagStats struct {
+	Tag         string        `json:"tag"`
+	TotalPosts  int64         `json:"totalPosts"`
+	UniqueUsers int64         `json:"uniqueUsers"`
+	RecentPosts int64         `json:"recentPosts"` // son RecentDays gündeki gönderiler
+	RecentDays  int           `json:"recentDays"`
+	FirstUsedAt *time.Time    `json:"firstUsedAt"`
+	LastUsedAt  *time.Time    `json:"lastUsedAt"`
+	TopPosts    []PostSummary `json:"topPosts"`
+}
+
+// normalizeHashtag strips a leading # and lowercases the tag; false when it
+// has characters a caption hashtag cannot contain.
+func normalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" || len(tag) > hashtagMaxLength {
+		return "", false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// GetHashtagStats godoc
+// @Summary Get aggregate stats for a hashtag
+// @Description Returns how many posts use the hashtag, by how many users, how many in the last 7 days, and up to 9 top posts by likes plus comments. Only posts the user can see are counted.
+// @Tags posts
+// @Produce json
+// @Param tag path string true "Hashtag, with or without #"
+// @Success 200 {object} StandardResponse{data=HashtagStats}
+// @Router /hashtags/{tag} [get]
+func (pc *PostController) GetHashtagStats(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	tag, ok := normalizeHashtag(c.Param("tag"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Invalid hashtag"})
+		return
+	}
+
+	visibility, visibilityArgs := visiblePostsCondition(user.UserID)
+	tagged := func() *gorm.DB {
+		return pc.DB.Model(&models.Post{}).
+			Joins("JOIN users ON posts.user_id = users.id").
+			Where(hashtagCondition, tag).
+			Where(visibility, visibilityArgs...)
+	}
+
+	stats := HashtagStats{Tag: tag, RecentDays: hashtagRecentDays, TopPosts: []PostSummary{}}
+	var aggregate struct {
+		TotalPosts  int64      `gorm:"column:total_posts"`
+		UniqueUsers int64      `gorm:"column:unique_users"`
+		RecentPosts int64      `gorm:"column:recent_posts"`
+		FirstUsedAt *time.Time `gorm:"column:first_used_at"`
+		LastUsedAt  *time.Time `gorm:"column:last_used_at"`
+	}
+	if err := tagged().
+		Select(`COUNT(*) AS total_posts, COUNT(DISTINCT posts.user_id) AS unique_users,
+			COUNT(*) FILTER (WHERE posts.created_at >= ?) AS recent_posts,
+			MIN(posts.created_at) AS first_used_at, MAX(posts.created_at) AS last_used_at`,
+			time.Now().AddDate(0, 0, -hashtagRecentDays)).
+		Scan(&aggregate).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching hashtag stats"})
+		return
+	}
+	stats.TotalPosts = aggregate.TotalPosts
+	stats.UniqueUsers = aggregate.UniqueUsers
+	stats.RecentPosts = aggregate.RecentPosts
+	stats.FirstUsedAt = aggregate.FirstUsedAt
+	stats.LastUsedAt = aggregate.LastUsedAt
+
+	if stats.TotalPosts > 0 {
+		var topIDs []uint
+		if err := tagged().
+			Order(postEngagementSQL+" DESC, posts.created_at DESC, posts.id DESC").
+			Limit(hashtagTopPostsLimit).
+			Pluck("posts.id", &topIDs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching hashtag stats"})
+			return
+		}
+
+		summaries, err := pc.postSummaries(user.UserID, pc.DB.Model(&models.Post{}).Where("posts.id IN ?", topIDs), 0, len(topIDs))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching hashtag stats"})
+			return
+		}
+		// postSummaries yeniden eskiye sıralar; etkileşim sırası geri kurulur
+		byID := make(map[uint]PostSummary, len(summaries))
+		for _, post := range summaries {
+			byID[post.ID] = post
+		}
+		for _, id := range topIDs {
+			if post, ok := byID[id]; ok {
+				stats.TopPosts = append(stats.TopPosts, post)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Data: stats})
+}
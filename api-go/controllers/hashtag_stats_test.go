@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetHashtagStats(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "tagviewer")
+	author := createTestUser(t, db, "tagauthor")
+	other := createTestUser(t, db, "tagother")
+	blocker := createTestUser(t, db, "tagblocker")
+	private := createTestUser(t, db, "tagprivate")
+	place := createTestPlace(t, db, "tagplace")
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	quiet := createTestPost(t, db, author, place, "evening #Sunset", true)
+	popular := createTestPost(t, db, other, place, "#sunset over the bay", true)
+	liked := createTestPost(t, db, author, place, "golden #sunset #beach", true)
+	createTestPost(t, db, author, place, "no tag here, #sunsets is different", true)
+	// Görülemeyen gönderiler sayılmaz ve örneğe girmez
+	hidden := []models.Post{
+		createTestPost(t, db, blocker, place, "#sunset", true),
+		createTestPost(t, db, private, place, "#sunset", true),
+		createTestPost(t, db, author, place, "followers only #sunset", false),
+	}
+
+	engage := func(post models.Post, likes, comments int) {
+		t.Helper()
+		for i := 0; i < likes; i++ {
+			liker := createTestUser(t, db, fmt.Sprintf("tagliker%d_%d", post.ID, i))
+			if err := db.Create(&models.Like{PostID: post.ID, UserID: liker.ID}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := 0; i < comments; i++ {
+			if err := db.Create(&models.Comment{PostID: post.ID, UserID: viewer.ID, TextContent: "nice"}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	engage(popular, 2, 2)
+	engage(liked, 1, 0)
+	for _, post := range hidden {
+		engage(post, 3, 3)
+	}
+
+	pc := NewPostController(db, nil)
+	w := callHandler(pc.GetHashtagStats, http.MethodGet, "/hashtags/%23SUNSET", nil, viewer.ID, gin.Param{Key: "tag", Value: "#SUNSET"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data HashtagStats `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	stats := resp.Data
+	if stats.Tag != "sunset" || stats.TotalPosts != 3 || stats.UniqueUsers != 2 || stats.RecentPosts != 3 {
+		t.Errorf("stats = %+v, want 3 posts by 2 users, all recent", stats)
+	}
+	var order []uint
+	for _, post := range stats.TopPosts {
+		order = append(order, post.ID)
+	}
+	if want := []uint{popular.ID, liked.ID, quiet.ID}; len(order) != 3 || order[0] != want[0] || order[1] != want[1] || order[2] != want[2] {
+		t.Errorf("top posts = %v, want %v by engagement", order, want)
+	}
+
+	if w := callHandler(pc.GetHashtagStats, http.MethodGet, "/hashtags/bad-tag", nil, viewer.ID, gin.Param{Key: "tag", Value: "bad-tag"}); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid tag: status = %d, want 400", w.Code)
+	}
+}
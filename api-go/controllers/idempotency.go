@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// İşlenmekte olan bir anahtar bu süreden eskiyse yarım kalmış sayılır ve yeniden alınabilir
+	idempotencyPendingTimeout = time.Minute
+)
+
+// claimIdempotencyKey reserves key for a post creation by user. When the key
+// was already used it answers the request itself: the original post for a
+// finished request, 409 while the first attempt is still running, 422 if the
+// key came with a different body. handled is true whenever a response was written.
+func (pc *PostController) claimIdempotencyKey(c *gin.Context, user *models.User, key string, req CreatePostRequest) (record *models.IdempotencyKey, handled bool) {
+	if len(key) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key can be at most 255 characters"})
+		return nil, true
+	}
+
+	body, _ := json.Marshal(req)
+	sum := sha256.Sum256(body)
+	requestHash := hex.EncodeToString(sum[:])
+
+	now := time.Now()
+	if err := pc.DB.Where("user_id = ? AND created_at < ?", user.ID, now.Add(-config.GetIdempotencyKeyTTL())).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+		return nil, true
+	}
+
+	// İkinci deneme yalnızca yarım kalmış bir kayıt silindikten sonra yapılır
+	for attempt := 0; attempt < 2; attempt++ {
+		record = &models.IdempotencyKey{
+			UserID:      user.ID,
+			Key:         key,
+			RequestHash: requestHash,
+		}
+		result := pc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			return nil, true
+		}
+		if result.RowsAffected == 1 {
+			return record, false
+		}
+
+		var existing models.IdempotencyKey
+		if err := pc.DB.Where("user_id = ? AND key = ?", user.ID, key).First(&existing).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			return nil, true
+		}
+
+		if existing.RequestHash != requestHash {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			return nil, true
+		}
+
+		if existing.PostID != nil {
+			var post models.Post
+			if err := pc.DB.First(&post, *existing.PostID).Error; err != nil {
+				c.JSON(http.StatusConflict, gin.H{"error": "The post created with this Idempotency-Key no longer exists"})
+				return nil, true
+			}
+			c.Header("Idempotent-Replayed", "true")
+			pc.respondCreatedPost(c, user, post, existing.PointsEarned)
+			return nil, true
+		}
+
+		if now.Sub(existing.CreatedAt) < idempotencyPendingTimeout {
+			c.Header("Retry-After", strconv.Itoa(int(idempotencyPendingTimeout.Seconds())))
+			c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+			return nil, true
+		}
+
+		if err := pc.DB.Where("id = ? AND post_id IS NULL", existing.ID).Delete(&models.IdempotencyKey{}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			return nil, true
+		}
+	}
+
+	c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+	return nil, true
+}
+
+// errIdempotencyKeyLost is returned when a reserved key was reclaimed by
+// another request before the post could be stored on it
+var errIdempotencyKeyLost = errors.New("idempotency key is no longer reserved")
+
+// completeIdempotencyKey stores the created post on the reserved key inside the
+// post's transaction tx, so the key and the post commit or roll back together.
+// It fails if the reservation was lost, rolling the post back with it.
+func completeIdempotencyKey(tx *gorm.DB, record *models.IdempotencyKey, postID uint, pointsEarned int64) error {
+	result := tx.Model(&models.IdempotencyKey{}).
+		Where("id = ? AND post_id IS NULL", record.ID).
+		Updates(map[string]interface{}{
+			"post_id":       postID,
+			"points_earned": pointsEarned,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errIdempotencyKeyLost
+	}
+	return nil
+}
+
+// releaseIdempotencyKey frees a reserved key after a failed request so the client can retry
+func releaseIdempotencyKey(db *gorm.DB, record *models.IdempotencyKey) {
+	if record == nil {
+		return
+	}
+	db.Delete(record)
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+func TestCreatePostIdempotencyKey(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "idempotentuser")
+	place := createTestPlace(t, db, "idempotentplace")
+	pc := NewPostController(db, nil)
+
+	create := func(key, caption string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"postCaption": caption,
+			"mediaItems":  []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/idempotent.jpg"}},
+			"placeId":     place.ID,
+			"latitude":    place.Latitude,
+			"longitude":   place.Longitude,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/posts", bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("Idempotency-Key", key)
+		c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: user.ID, Role: "user"})
+		pc.CreatePost(c)
+		return w
+	}
+	type createdPost struct {
+		ID           uint  `json:"id"`
+		PointsEarned int64 `json:"pointsEarned"`
+	}
+	decode := func(w *httptest.ResponseRecorder) createdPost {
+		t.Helper()
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var post createdPost
+		if err := json.Unmarshal(w.Body.Bytes(), &post); err != nil {
+			t.Fatal(err)
+		}
+		return post
+	}
+
+	first := decode(create("retry-1", "hello"))
+	var afterFirst models.User
+	if err := db.Select("total_points, posts_count").First(&afterFirst, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	w := create("retry-1", "hello")
+	if replayed := decode(w); replayed != first {
+		t.Errorf("retry = %+v, want the first post %+v", replayed, first)
+	}
+	if w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("retry is not marked as replayed")
+	}
+
+	var posts int64
+	if err := db.Model(&models.Post{}).Where("user_id = ?", user.ID).Count(&posts).Error; err != nil {
+		t.Fatal(err)
+	}
+	if posts != 1 {
+		t.Errorf("posts after retry = %d, want 1", posts)
+	}
+	var afterRetry models.User
+	if err := db.Select("total_points, posts_count").First(&afterRetry, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if afterRetry.TotalPoints != afterFirst.TotalPoints || afterRetry.PostsCount != 1 {
+		t.Errorf("after retry: points %d, posts %d; want %d and 1", afterRetry.TotalPoints, afterRetry.PostsCount, afterFirst.TotalPoints)
+	}
+
+	// Anahtar gönderiyle birlikte kaydedilir
+	var key models.IdempotencyKey
+	if err := db.Where("user_id = ? AND key = ?", user.ID, "retry-1").First(&key).Error; err != nil {
+		t.Fatal(err)
+	}
+	if key.PostID == nil || *key.PostID != first.ID || key.PointsEarned != first.PointsEarned {
+		t.Errorf("key = %+v, want post %d with %d points", key, first.ID, first.PointsEarned)
+	}
+
+	if w := create("retry-1", "a different caption"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body with the same key: status = %d, want 422", w.Code)
+	}
+}
+
+func TestCompleteIdempotencyKeyFailsWhenReservationIsLost(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "lostkeyuser")
+	record := models.IdempotencyKey{UserID: user.ID, Key: "lost", RequestHash: "hash"}
+	if err := db.Create(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Yarım kalmış sayılıp başka bir istek tarafından silinmiş
+	if err := db.Delete(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := completeIdempotencyKey(db, &record, 1, 10); err != errIdempotencyKeyLost {
+		t.Errorf("error = %v, want errIdempotencyKeyLost", err)
+	}
+}
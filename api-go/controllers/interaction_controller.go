@@ -1,19 +1,37 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InteractionController struct {
 	DB *gorm.DB
 }
 
+// Tek istekte takip edilebilecek en fazla kullanıcı sayısı
+const maxBatchFollowUsers = 50
+
+type BatchFollowRequest struct {
+	UserIDs []uint `json:"userIds" binding:"required,min=1"`
+}
+
+type BatchFollowResult struct {
+	UserID uint   `json:"userId"`
+	Status string `json:"status"` // followed, pending, error
+	Error  string `json:"error,omitempty"`
+}
+
 func NewInteractionController(db *gorm.DB) *InteractionController {
 	return &InteractionController{DB: db}
 }
@@ -28,8 +46,13 @@ func NewInteractionController(db *gorm.DB) *InteractionController {
 // @Success 200 {object} map[string]interface{}
 // @Router /posts/{id}/like [post]
 func (ic *InteractionController) LikePost(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
 	postID := c.Param("id")
-	userID := c.GetUint("userID") // Assuming this is set by auth middleware
+	userID := currentUser.UserID
 
 	var post models.Post
 	if err := ic.DB.First(&post, postID).Error; err != nil {
@@ -71,7 +94,21 @@ func (ic *InteractionController) LikePost(c *gin.Context) {
 			return
 		}
 
-		tx.Commit()
+		// Bildirim beğeniyle birlikte yazılır; biri başarısız olursa hiçbiri kalmaz
+		notification, err := createNotification(tx, post.UserID, userID, "like", &post.ID)
+		if err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like post"})
+			return
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to like post"})
+			return
+		}
+		if notification != nil {
+			deliverNotification(ic.DB, notification)
+		}
 		c.JSON(http.StatusOK, gin.H{"liked": true})
 	} else {
 		// Unlike post
@@ -81,7 +118,10 @@ func (ic *InteractionController) LikePost(c *gin.Context) {
 			return
 		}
 
-		tx.Commit()
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlike post"})
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{"liked": false})
 	}
 }
@@ -96,8 +136,13 @@ func (ic *InteractionController) LikePost(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /users/{userId}/follow [post]
 func (ic *InteractionController) FollowUser(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
 	targetUserID := c.Param("userId")
-	followerID := c.GetUint("userID") // Assuming this is set by auth middleware
+	followerID := currentUser.UserID
 
 	var targetUser models.User
 	if err := ic.DB.First(&targetUser, targetUserID).Error; err != nil {
@@ -111,8 +156,19 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 		return
 	}
 
+	// Engellenmiş kullanıcılar arasında takip yapılamaz (her iki yön)
+	blocked, err := isBlockedBetween(ic.DB, followerID, targetUser.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify block status"})
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot follow this user"})
+		return
+	}
+
 	var existingFollow models.Follow
-	result := ic.DB.Where("follower_id = ? AND following_id = ?", followerID, targetUser.ID).First(&existingFollow)
+	result := ic.DB.Where("follower_user_id = ? AND following_user_id = ?", followerID, targetUser.ID).First(&existingFollow)
 
 	tx := ic.DB.Begin()
 
@@ -121,7 +177,7 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 		follow := models.Follow{
 			FollowerUserID:  followerID,
 			FollowingUserID: targetUser.ID,
-			Status:          "pending",
+			Status:          followStatusFor(targetUser),
 		}
 
 		if err := tx.Create(&follow).Error; err != nil {
@@ -130,11 +186,20 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 			return
 		}
 
+		if follow.Status == "accepted" {
+			if err := adjustFollowCounts(tx, followerID, targetUser.ID, 1); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow user"})
+				return
+			}
+		}
+
 		// Create activity log
 		activity := models.ActivityLog{
-			UserID:    followerID,
-			Activity:  "user_followed",
-			CreatedAt: time.Now(),
+			UserID:       followerID,
+			TargetUserID: &targetUser.ID,
+			Activity:     "user_followed",
+			CreatedAt:    time.Now(),
 		}
 
 		if err := tx.Create(&activity).Error; err != nil {
@@ -144,13 +209,15 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 		}
 
 		tx.Commit()
+		notifyUser(ic.DB, targetUser.ID, followerID, followNotificationType(follow.Status), nil)
 		c.JSON(http.StatusOK, gin.H{
 			"following": true,
+			"status":    follow.Status,
 			"message":   "Successfully followed user",
 		})
 	} else {
-		// Unfollow user
-		if err := tx.Delete(&existingFollow).Error; err != nil {
+		// Unfollow user (kabul edilmiş takipler sayaçlardan düşülür)
+		if err := removeFollows(tx, []models.Follow{existingFollow}); err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
 			return
@@ -164,6 +231,340 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 	}
 }
 
+// BatchFollowUsers godoc
+// @Summary Follow several users at once
+// @Description Follows each user in the list in a single transaction and returns a per-user result. Unverified accounts younger than the minimum account age get 403 with retryAfter.
+// @Tags interactions
+// @Accept json
+// @Produce json
+// @Param request body BatchFollowRequest true "User IDs to follow"
+// @Success 200 {object} map[string]interface{}
+// @Router /users/follow/batch [post]
+func (ic *InteractionController) BatchFollowUsers(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	followerID := user.UserID
+
+	var req BatchFollowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.UserIDs) > maxBatchFollowUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cannot follow more than %d users at once", maxBatchFollowUsers)})
+		return
+	}
+
+	if !requireAccountAge(c, ic.DB, user, config.AccountAgeActionBatchFollow) {
+		return
+	}
+
+	// Tekrarlanan ID'leri ayıkla, sırayı koru
+	seen := make(map[uint]bool)
+	userIDs := make([]uint, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	var targets []models.User
+	if err := ic.DB.Select("id, is_private").Where("id IN ?", userIDs).Find(&targets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load users"})
+		return
+	}
+	targetsByID := make(map[uint]models.User, len(targets))
+	for _, target := range targets {
+		targetsByID[target.ID] = target
+	}
+
+	var blocks []models.Block
+	if err := ic.DB.Where("(blocker_user_id = ? AND blocked_user_id IN ?) OR (blocked_user_id = ? AND blocker_user_id IN ?)",
+		followerID, userIDs, followerID, userIDs).Find(&blocks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify block status"})
+		return
+	}
+	blockedIDs := make(map[uint]bool, len(blocks))
+	for _, block := range blocks {
+		if block.BlockerUserID == followerID {
+			blockedIDs[block.BlockedUserID] = true
+		} else {
+			blockedIDs[block.BlockerUserID] = true
+		}
+	}
+
+	var existingFollows []models.Follow
+	if err := ic.DB.Where("follower_user_id = ? AND following_user_id IN ?", followerID, userIDs).
+		Find(&existingFollows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load existing follows"})
+		return
+	}
+	existingStatus := make(map[uint]string, len(existingFollows))
+	for _, follow := range existingFollows {
+		existingStatus[follow.FollowingUserID] = follow.Status
+	}
+
+	results := make([]BatchFollowResult, 0, len(userIDs))
+	var newFollows []models.Follow
+	tx := ic.DB.Begin()
+
+	for _, targetID := range userIDs {
+		target, exists := targetsByID[targetID]
+		switch {
+		case targetID == followerID:
+			results = append(results, BatchFollowResult{UserID: targetID, Status: "error", Error: "Cannot follow yourself"})
+			continue
+		case !exists:
+			results = append(results, BatchFollowResult{UserID: targetID, Status: "error", Error: "User not found"})
+			continue
+		case blockedIDs[targetID]:
+			results = append(results, BatchFollowResult{UserID: targetID, Status: "error", Error: "Cannot follow this user"})
+			continue
+		}
+
+		// Zaten takip ediliyorsa mevcut durumu döndür
+		if status, ok := existingStatus[targetID]; ok {
+			results = append(results, BatchFollowResult{UserID: targetID, Status: batchFollowStatus(status)})
+			continue
+		}
+
+		follow := models.Follow{
+			FollowerUserID:  followerID,
+			FollowingUserID: targetID,
+			Status:          followStatusFor(target),
+		}
+
+		if err := tx.Create(&follow).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow users"})
+			return
+		}
+
+		if follow.Status == "accepted" {
+			if err := adjustFollowCounts(tx, followerID, targetID, 1); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow users"})
+				return
+			}
+		}
+
+		activity := models.ActivityLog{
+			UserID:       followerID,
+			TargetUserID: &target.ID,
+			Activity:     "user_followed",
+			CreatedAt:    time.Now(),
+		}
+
+		if err := tx.Create(&activity).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity log"})
+			return
+		}
+
+		results = append(results, BatchFollowResult{UserID: targetID, Status: batchFollowStatus(follow.Status)})
+		newFollows = append(newFollows, follow)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow users"})
+		return
+	}
+
+	for _, follow := range newFollows {
+		notifyUser(ic.DB, follow.FollowingUserID, followerID, followNotificationType(follow.Status), nil)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+	})
+}
+
+// followNotificationType maps a new follow's status to its notification type
+func followNotificationType(status string) string {
+	if status == "pending" {
+		return "follow_request"
+	}
+	return "follow"
+}
+
+// followStatusFor returns the initial status of a new follow: public accounts
+// are followed immediately, private accounts get a pending request.
+func followStatusFor(target models.User) string {
+	if target.IsPrivate {
+		return "pending"
+	}
+	return "accepted"
+}
+
+// batchFollowStatus maps a stored follow status to the batch result vocabulary
+func batchFollowStatus(status string) string {
+	if status == "accepted" {
+		return "followed"
+	}
+	return status
+}
+
+// AcceptFollowRequest godoc
+// @Summary Accept a pending follow request
+// @Description Accepts the pending follow request from the given user to the current user and notifies the requester
+// @Tags interactions
+// @Produce json
+// @Param userId path string true "Requesting user ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /users/follow-requests/{userId}/accept [post]
+func (ic *InteractionController) AcceptFollowRequest(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	followerID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	tx := ic.DB.Begin()
+
+	var request models.Follow
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("follower_user_id = ? AND following_user_id = ? AND status = ?", followerID, user.UserID, "pending").
+		First(&request).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Follow request not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept follow request"})
+		return
+	}
+
+	if err := acceptFollowRequest(tx, &request); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept follow request"})
+		return
+	}
+	notification, err := createNotification(tx, request.FollowerUserID, user.UserID, "follow_accepted", nil)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept follow request"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept follow request"})
+		return
+	}
+	if notification != nil {
+		deliverNotification(ic.DB, notification)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "accepted",
+	})
+}
+
+// AcceptAllFollowRequests godoc
+// @Summary Accept every pending follow request
+// @Description Accepts all pending follow requests to the current user in one transaction and notifies each requester
+// @Tags interactions
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /users/follow-requests/accept-all [post]
+func (ic *InteractionController) AcceptAllFollowRequests(c *gin.Context) {
+	ic.resolveAllFollowRequests(c, true)
+}
+
+// DeclineAllFollowRequests godoc
+// @Summary Decline every pending follow request
+// @Description Removes all pending follow requests to the current user in one transaction
+// @Tags interactions
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /users/follow-requests/decline-all [post]
+func (ic *InteractionController) DeclineAllFollowRequests(c *gin.Context) {
+	ic.resolveAllFollowRequests(c, false)
+}
+
+// resolveAllFollowRequests accepts or declines every pending request to the current user
+func (ic *InteractionController) resolveAllFollowRequests(c *gin.Context, accept bool) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	userID := user.UserID
+
+	action := "decline"
+	if accept {
+		action = "accept"
+	}
+	failed := func(tx *gorm.DB) {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " follow requests"})
+	}
+
+	tx := ic.DB.Begin()
+
+	// Bekleyen istekler kilitlenir: aynı anda geri çekilen istek iki kez işlenmez,
+	// bu sırada gelen yeni istekler ise bekleyen olarak kalır
+	var requests []models.Follow
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("following_user_id = ? AND status = ?", userID, "pending").
+		Find(&requests).Error; err != nil {
+		failed(tx)
+		return
+	}
+
+	followIDs := make([]uint, 0, len(requests))
+	for _, request := range requests {
+		followIDs = append(followIDs, request.ID)
+	}
+
+	var notifications []*models.Notification
+	if len(requests) > 0 {
+		if !accept {
+			if err := tx.Where("id IN ?", followIDs).Delete(&models.Follow{}).Error; err != nil {
+				failed(tx)
+				return
+			}
+		} else {
+			for i := range requests {
+				if err := acceptFollowRequest(tx, &requests[i]); err != nil {
+					failed(tx)
+					return
+				}
+				notification, err := createNotification(tx, requests[i].FollowerUserID, userID, "follow_accepted", nil)
+				if err != nil {
+					failed(tx)
+					return
+				}
+				if notification != nil {
+					notifications = append(notifications, notification)
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action + " follow requests"})
+		return
+	}
+
+	for _, notification := range notifications {
+		deliverNotification(ic.DB, notification)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"processed": len(requests),
+	})
+}
+
 // GetUserFollowers godoc
 // @Summary Get user's followers
 // @Description Returns paginated list of user's followers
@@ -177,17 +578,10 @@ func (ic *InteractionController) FollowUser(c *gin.Context) {
 // @Router /users/{userId}/followers [get]
 func (ic *InteractionController) GetUserFollowers(c *gin.Context) {
 	userID := c.Param("userId")
-	page, _ := c.GetQuery("page")
-	pageSize, _ := c.GetQuery("pageSize")
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
 
-	if page == "" {
-		page = "1"
-	}
-	if pageSize == "" {
-		pageSize = "20"
-	}
-
-	offset := (convertToInt(page) - 1) * convertToInt(pageSize)
+	offset := (page - 1) * pageSize
 
 	var followers []struct {
 		UserID    uint      `json:"userId"`
@@ -203,7 +597,7 @@ func (ic *InteractionController) GetUserFollowers(c *gin.Context) {
 		Joins("JOIN users ON users.id = follows.follower_id").
 		Where("follows.following_id = ?", userID).
 		Offset(offset).
-		Limit(convertToInt(pageSize)).
+		Limit(pageSize).
 		Find(&followers)
 
 	if result.Error != nil {
@@ -214,10 +608,10 @@ func (ic *InteractionController) GetUserFollowers(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"followers": followers,
 		"pagination": gin.H{
-			"currentPage": convertToInt(page),
-			"pageSize":    convertToInt(pageSize),
+			"currentPage": page,
+			"pageSize":    pageSize,
 			"totalItems":  total,
-			"totalPages":  (total + int64(convertToInt(pageSize)) - 1) / int64(convertToInt(pageSize)),
+			"totalPages":  (total + int64(pageSize) - 1) / int64(pageSize),
 		},
 	})
 }
@@ -235,17 +629,10 @@ func (ic *InteractionController) GetUserFollowers(c *gin.Context) {
 // @Router /users/{userId}/following [get]
 func (ic *InteractionController) GetUserFollowing(c *gin.Context) {
 	userID := c.Param("userId")
-	page, _ := c.GetQuery("page")
-	pageSize, _ := c.GetQuery("pageSize")
-
-	if page == "" {
-		page = "1"
-	}
-	if pageSize == "" {
-		pageSize = "20"
-	}
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
 
-	offset := (convertToInt(page) - 1) * convertToInt(pageSize)
+	offset := (page - 1) * pageSize
 
 	var following []struct {
 		UserID    uint      `json:"userId"`
@@ -261,7 +648,7 @@ func (ic *InteractionController) GetUserFollowing(c *gin.Context) {
 		Joins("JOIN users ON users.id = follows.following_id").
 		Where("follows.follower_id = ?", userID).
 		Offset(offset).
-		Limit(convertToInt(pageSize)).
+		Limit(pageSize).
 		Find(&following)
 
 	if result.Error != nil {
@@ -272,17 +659,20 @@ func (ic *InteractionController) GetUserFollowing(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"following": following,
 		"pagination": gin.H{
-			"currentPage": convertToInt(page),
-			"pageSize":    convertToInt(pageSize),
+			"currentPage": page,
+			"pageSize":    pageSize,
 			"totalItems":  total,
-			"totalPages":  (total + int64(convertToInt(pageSize)) - 1) / int64(convertToInt(pageSize)),
+			"totalPages":  (total + int64(pageSize) - 1) / int64(pageSize),
 		},
 	})
 }
 
-// Helper function to convert string to int
-func convertToInt(str string) int {
-	val := 0
-	fmt.Sscanf(str, "%d", &val)
-	return val
+// isBlockedBetween reports whether either user has blocked the other
+func isBlockedBetween(db *gorm.DB, userA, userB uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Block{}).
+		Where("(blocker_user_id = ? AND blocked_user_id = ?) OR (blocker_user_id = ? AND blocked_user_id = ?)",
+			userA, userB, userB, userA).
+		Count(&count).Error
+	return count > 0, err
 }
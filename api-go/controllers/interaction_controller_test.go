@@ -0,0 +1,330 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+func TestFollowUserRejectsBlockedUsers(t *testing.T) {
+	db := openTestDB(t)
+	blocker := createTestUser(t, db, "followblocker")
+	blocked := createTestUser(t, db, "followblocked")
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: blocked.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ic := NewInteractionController(db)
+	// Engel her iki yönde de takibi engeller
+	for _, tt := range []struct {
+		name             string
+		follower, target models.User
+	}{
+		{"blocked follows blocker", blocked, blocker},
+		{"blocker follows blocked", blocker, blocked},
+	} {
+		param := gin.Param{Key: "userId", Value: strconv.Itoa(int(tt.target.ID))}
+		w := callHandler(ic.FollowUser, http.MethodPost, "/users/"+param.Value+"/follow", nil, tt.follower.ID, param)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, http.StatusForbidden)
+		}
+	}
+
+	var follows int64
+	db.Model(&models.Follow{}).Count(&follows)
+	if follows != 0 {
+		t.Errorf("follows after rejected attempts = %d, want 0", follows)
+	}
+}
+
+func TestBatchFollowUsers(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "batchme")
+	public := createTestUser(t, db, "batchpublic")
+	private := createTestUser(t, db, "batchprivate")
+	blocker := createTestUser(t, db, "batchblocker")
+	already := createTestUser(t, db, "batchalready")
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: me.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Follow{FollowerUserID: me.ID, FollowingUserID: already.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	missingID := already.ID + 1000
+	body := fmt.Sprintf(`{"userIds":[%d,%d,%d,%d,%d,%d,%d]}`, public.ID, private.ID, blocker.ID, me.ID, missingID, already.ID, public.ID)
+	ic := NewInteractionController(db)
+	w := callHandler(ic.BatchFollowUsers, http.MethodPost, "/users/follow/batch", strings.NewReader(body), me.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Results []BatchFollowResult `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tekrarlanan ID tek sonuç verir, sıra korunur
+	want := []struct {
+		userID uint
+		status string
+	}{
+		{public.ID, "followed"},
+		{private.ID, "pending"},
+		{blocker.ID, "error"},
+		{me.ID, "error"},
+		{missingID, "error"},
+		{already.ID, "followed"},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(resp.Results), len(want), resp.Results)
+	}
+	for i, result := range resp.Results {
+		if result.UserID != want[i].userID || result.Status != want[i].status {
+			t.Errorf("result %d = %d/%s, want %d/%s", i, result.UserID, result.Status, want[i].userID, want[i].status)
+		}
+		if (result.Status == "error") != (result.Error != "") {
+			t.Errorf("result %d error message = %q", i, result.Error)
+		}
+	}
+
+	statuses := map[uint]string{}
+	var follows []models.Follow
+	db.Where("follower_user_id = ?", me.ID).Find(&follows)
+	for _, follow := range follows {
+		statuses[follow.FollowingUserID] = follow.Status
+	}
+	if len(statuses) != 3 || statuses[public.ID] != "accepted" || statuses[private.ID] != "pending" || statuses[already.ID] != "accepted" {
+		t.Errorf("stored follows = %v", statuses)
+	}
+}
+
+func TestBatchFollowUsersCapsList(t *testing.T) {
+	ids := make([]string, maxBatchFollowUsers+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+	ic := NewInteractionController(nil)
+	body := `{"userIds":[` + strings.Join(ids, ",") + `]}`
+	w := callHandler(ic.BatchFollowUsers, http.MethodPost, "/users/follow/batch", strings.NewReader(body), 1)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("%d user ids: status = %d, want %d", len(ids), w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestLikePostUsesAuthenticatedUser(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "likeauthor")
+	first := createTestUser(t, db, "likefirst")
+	second := createTestUser(t, db, "likesecond")
+	place := createTestPlace(t, db, "likeplace")
+	post := createTestPost(t, db, author, place, "like me", true)
+	ic := NewInteractionController(db)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+
+	like := func(user models.User) bool {
+		t.Helper()
+		w := callHandler(ic.LikePost, http.MethodPost, "/posts/"+param.Value+"/like", nil, user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("like by %s: status = %d, body = %s", user.Username, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Liked bool `json:"liked"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Liked
+	}
+	likers := func() map[uint]bool {
+		t.Helper()
+		var ids []uint
+		if err := db.Model(&models.Like{}).Where("post_id = ?", post.ID).Pluck("user_id", &ids).Error; err != nil {
+			t.Fatal(err)
+		}
+		set := map[uint]bool{}
+		for _, id := range ids {
+			set[id] = true
+		}
+		return set
+	}
+
+	// Her kullanıcının beğenisi kendi adına kaydedilir; 0 kullanıcısı oluşmaz
+	if !like(first) || !like(second) {
+		t.Fatal("first like of each user should report liked")
+	}
+	if got := likers(); len(got) != 2 || !got[first.ID] || !got[second.ID] {
+		t.Errorf("likers = %v, want %d and %d", got, first.ID, second.ID)
+	}
+
+	// Bir kullanıcının geri alması diğerinin beğenisine dokunmaz
+	if like(first) {
+		t.Error("second like by the same user should unlike")
+	}
+	if got := likers(); len(got) != 1 || !got[second.ID] {
+		t.Errorf("likers after unlike = %v, want only %d", got, second.ID)
+	}
+}
+
+func TestLikePostRollsBackWhenNotificationFails(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "rollbackauthor")
+	liker := createTestUser(t, db, "rollbackliker")
+	place := createTestPlace(t, db, "rollbackplace")
+	post := createTestPost(t, db, author, place, "rollback", true)
+
+	// Beğeni ve etkinlik kaydı yazıldıktan sonra bildirim oluşturma başarısız olur
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail_notifications", func(tx *gorm.DB) {
+		if tx.Statement.Table == "notifications" {
+			tx.AddError(errors.New("injected notification failure"))
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ic := NewInteractionController(db)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+	w := callHandler(ic.LikePost, http.MethodPost, "/posts/"+param.Value+"/like", nil, liker.ID, param)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	counts := map[string]interface{}{
+		"likes":         &models.Like{},
+		"activity_logs": &models.ActivityLog{},
+		"notifications": &models.Notification{},
+	}
+	for name, model := range counts {
+		var count int64
+		if err := db.Model(model).Where("post_id = ?", post.ID).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%s persisted after rollback: %d rows", name, count)
+		}
+	}
+}
+
+// createPendingRequests stores a pending follow request to target from each of n new users
+func createPendingRequests(t *testing.T, db *gorm.DB, target models.User, prefix string, n int) []models.User {
+	t.Helper()
+	requesters := make([]models.User, n)
+	for i := range requesters {
+		requesters[i] = createTestUser(t, db, fmt.Sprintf("%s%d", prefix, i))
+		if err := db.Create(&models.Follow{FollowerUserID: requesters[i].ID, FollowingUserID: target.ID, Status: "pending"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return requesters
+}
+
+func TestAcceptAllFollowRequests(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "acceptallme")
+	other := createTestUser(t, db, "acceptallother")
+	friend := createTestUser(t, db, "acceptallfriend")
+	requesters := createPendingRequests(t, db, me, "acceptallreq", 3)
+	// Kabul edilmiş takipler ve başkasına gelen istekler etkilenmez
+	for _, f := range []models.Follow{
+		{FollowerUserID: friend.ID, FollowingUserID: me.ID, Status: "accepted"},
+		{FollowerUserID: friend.ID, FollowingUserID: other.ID, Status: "pending"},
+	} {
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := reconcileUserCounts(db); err != nil {
+		t.Fatal(err)
+	}
+
+	ic := NewInteractionController(db)
+	// Aynı anda gelen iki istek her takip isteğini yalnızca bir kez işler
+	processed := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			w := callHandler(ic.AcceptAllFollowRequests, http.MethodPost, "/users/follow-requests/accept-all", nil, me.ID)
+			var resp struct {
+				Processed int `json:"processed"`
+			}
+			if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil {
+				t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			processed <- resp.Processed
+		}()
+	}
+	if total := <-processed + <-processed; total != len(requesters) {
+		t.Errorf("processed = %d, want %d", total, len(requesters))
+	}
+
+	var pending int64
+	db.Model(&models.Follow{}).Where("following_user_id = ? AND status = ?", me.ID, "pending").Count(&pending)
+	if pending != 0 {
+		t.Errorf("pending requests left = %d, want 0", pending)
+	}
+	db.Model(&models.Follow{}).Where("following_user_id = ? AND status = ?", other.ID, "pending").Count(&pending)
+	if pending != 1 {
+		t.Errorf("other user's pending requests = %d, want 1", pending)
+	}
+
+	assertCounts(t, db, "after accept-all", me, 4, 0)
+	for _, requester := range requesters {
+		assertCounts(t, db, "after accept-all", requester, 0, 1)
+		var notified int64
+		db.Model(&models.Notification{}).
+			Where("user_id = ? AND actor_user_id = ? AND type = ?", requester.ID, me.ID, "follow_accepted").
+			Count(&notified)
+		if notified != 1 {
+			t.Errorf("%s follow_accepted notifications = %d, want 1", requester.Username, notified)
+		}
+	}
+}
+
+func TestDeclineAllFollowRequests(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "declineallme")
+	requesters := createPendingRequests(t, db, me, "declineallreq", 4)
+
+	ic := NewInteractionController(db)
+	w := callHandler(ic.DeclineAllFollowRequests, http.MethodPost, "/users/follow-requests/decline-all", nil, me.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Processed int `json:"processed"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Processed != len(requesters) {
+		t.Errorf("processed = %d, want %d", resp.Processed, len(requesters))
+	}
+
+	var follows, notifications int64
+	db.Model(&models.Follow{}).Where("following_user_id = ?", me.ID).Count(&follows)
+	if follows != 0 {
+		t.Errorf("follows left = %d, want 0", follows)
+	}
+	db.Model(&models.Notification{}).Where("actor_user_id = ?", me.ID).Count(&notifications)
+	if notifications != 0 {
+		t.Errorf("notifications for declined requests = %d, want 0", notifications)
+	}
+	assertCounts(t, db, "after decline-all", me, 0, 0)
+
+	// İkinci çağrıda işlenecek istek kalmaz
+	w = callHandler(ic.DeclineAllFollowRequests, http.MethodPost, "/users/follow-requests/decline-all", nil, me.ID)
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Processed != 0 {
+		t.Errorf("second call processed = %d, err = %v, want 0", resp.Processed, err)
+	}
+}
@@ -1,12 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"math"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
 	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
 )
@@ -31,136 +34,145 @@ func NewLeaderboardController(db *gorm.DB) *LeaderboardController {
 	return &LeaderboardController{DB: db}
 }
 
-func (lc *LeaderboardController) GetLeaderboard(c *gin.Context) {
-	var query LeaderboardQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// LeaderboardUser is one row of the ranking
+type LeaderboardUser struct {
+	ID        uint    `json:"id" gorm:"column:id"`
+	Username  string  `json:"username" gorm:"column:username"`
+	FirstName string  `json:"first_name" gorm:"column:first_name"`
+	LastName  string  `json:"last_name" gorm:"column:last_name"`
+	Avatar    string  `json:"avatar" gorm:"column:avatar"`
+	Points    float64 `json:"points" gorm:"column:points"`
+	Rank      int     `json:"rank" gorm:"column:rank"`
+	Distance  float64 `json:"distance,omitempty" gorm:"column:distance"`
+}
 
+// rankedUsersQuery builds the ranking of verified users for the leaderboard
+// filters, one row per user with points and rank. It defaults the time filter
+// and caps the nearby distance on query; the error is a bad filter combination.
+func (lc *LeaderboardController) rankedUsersQuery(query *LeaderboardQuery, loc *time.Location) (*gorm.DB, error) {
 	// Default to all_time if not specified
 	if query.TimeFilter == "" {
 		query.TimeFilter = "all_time"
 	}
+	if query.IsCategory && query.CategoryID == "" {
+		return nil, errors.New("Category ID is required when isCategory is true")
+	}
+	if query.IsNearby && (query.Latitude == 0 || query.Longitude == 0) {
+		return nil, errors.New("Latitude and longitude are required when isNearby is true")
+	}
 
-	// Get current user from context
-	user := utils.GetUser(c)
-	userID := user.UserID
-
-	// Base query for users
-	baseQuery := lc.DB.Model(&models.User{}).
-		Where("is_verified = ?", true)
-
-	// Build the query based on filters
-	var selectClause, joinClause, whereClause string
-	var queryParams []interface{}
-	var orderByClause string // Rank ordering için kullanılacak alan
-
-	// Start with basic user fields
-	selectClause = "users.id, users.username, users.first_name, users.last_name, users.avatar"
+	userFields := "users.id, users.username, users.first_name, users.last_name, users.avatar"
+	ranked := lc.DB.Model(&models.User{}).Where("is_verified = ?", true)
+	groupBy := userFields
+	postsJoined := false
 
-	// Handle time filter
+	// Rank ordering için kullanılacak puan ifadesi
+	var pointsExpr string
 	switch query.TimeFilter {
 	case "weekly":
-		startOfWeek := time.Now().AddDate(0, 0, -int(time.Now().Weekday()))
-		startOfWeek = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, time.Local)
-
-		joinClause += " LEFT JOIN posts ON users.id = posts.user_id AND posts.created_at >= ?"
-		queryParams = append(queryParams, startOfWeek)
-		selectClause += ", COALESCE(SUM(posts.earned_points), 0) as points"
-		orderByClause = "COALESCE(SUM(posts.earned_points), 0)" // Window function için
-
+		startOfWeek := utils.StartOfWeek(time.Now(), loc, config.GetLeaderboardWeekStart())
+		ranked = ranked.Joins("LEFT JOIN posts ON users.id = posts.user_id AND posts.created_at >= ?", startOfWeek)
+		postsJoined = true
+		pointsExpr = "COALESCE(SUM(posts.earned_points), 0)"
 	case "monthly":
-		startOfMonth := time.Date(time.Now().Year(), time.Now().Month(), 1, 0, 0, 0, 0, time.Local)
-
-		joinClause += " LEFT JOIN posts ON users.id = posts.user_id AND posts.created_at >= ?"
-		queryParams = append(queryParams, startOfMonth)
-		selectClause += ", COALESCE(SUM(posts.earned_points), 0) as points"
-		orderByClause = "COALESCE(SUM(posts.earned_points), 0)" // Window function için
-
+		startOfMonth, _ := utils.PeriodStart("monthly", time.Now(), loc)
+		ranked = ranked.Joins("LEFT JOIN posts ON users.id = posts.user_id AND posts.created_at >= ?", startOfMonth)
+		postsJoined = true
+		pointsExpr = "COALESCE(SUM(posts.earned_points), 0)"
 	default: // all_time
-		selectClause += ", users.total_points as points"
-		orderByClause = "users.total_points" // Window function için
+		pointsExpr = "users.total_points"
+		groupBy += ", users.total_points"
 	}
 
-	// Add category filter if specified
-	if query.IsCategory {
-		if query.CategoryID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Category ID is required when isCategory is true"})
-			return
-		}
-
-		if query.TimeFilter == "all_time" {
-			// For all_time, we need to join on posts and places
-			joinClause = " LEFT JOIN posts ON users.id = posts.user_id"
-		}
+	if (query.IsCategory || query.IsNearby) && !postsJoined {
+		ranked = ranked.Joins("LEFT JOIN posts ON users.id = posts.user_id")
+	}
 
-		joinClause += " LEFT JOIN places ON posts.place_id = places.id"
-		whereClause += " AND ? = ANY(places.categories)"
-		queryParams = append(queryParams, query.CategoryID)
+	if query.IsCategory {
+		ranked = ranked.Joins("LEFT JOIN places ON posts.place_id = places.id").
+			Where("? = ANY(places.categories)", types.NormalizeCategory(query.CategoryID))
 	}
 
-	// Add nearby filter if specified
+	selectClause := userFields + ", " + pointsExpr + " AS points, RANK() OVER (ORDER BY " + pointsExpr + " DESC) AS rank"
+	var selectArgs []interface{}
 	if query.IsNearby {
-		if query.Latitude == 0 || query.Longitude == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Latitude and longitude are required when isNearby is true"})
-			return
-		}
-
 		// Limit max distance
 		if query.MaxDistance > 100 {
 			query.MaxDistance = 100 // Set an upper limit (100km)
 		}
 
-		// Add distance calculation to select
 		distanceCalc := "(6371 * acos(cos(radians(?)) * cos(radians(posts.latitude)) * " +
 			"cos(radians(posts.longitude) - radians(?)) + sin(radians(?)) * sin(radians(posts.latitude))))"
 
-		selectClause += ", " + distanceCalc + " AS distance"
-		queryParams = append(queryParams, query.Latitude, query.Longitude, query.Latitude)
-
-		if joinClause == "" {
-			joinClause = " LEFT JOIN posts ON users.id = posts.user_id"
-		}
-
-		whereClause += " AND " + distanceCalc + " <= ?"
-		queryParams = append(queryParams, query.Latitude, query.Longitude, query.Latitude, query.MaxDistance)
+		// Kullanıcı başına tek satır: en yakın gönderisinin uzaklığı
+		selectClause += ", MIN" + distanceCalc + " AS distance"
+		selectArgs = append(selectArgs, query.Latitude, query.Longitude, query.Latitude)
+		ranked = ranked.Where(distanceCalc+" <= ?", query.Latitude, query.Longitude, query.Latitude, query.MaxDistance)
 	}
 
-	// Complete the select clause with rank using direct field reference
-	selectClause += ", RANK() OVER (ORDER BY " + orderByClause + " DESC) as rank"
+	return ranked.Select(selectClause, selectArgs...).Group(groupBy), nil
+}
 
-	// Apply all clauses to the query
-	baseQuery = baseQuery.Select(selectClause)
+// viewerRank returns the viewer's row of the ranking, or a rank 0 row with just
+// the username when the filters leave them out.
+func (lc *LeaderboardController) viewerRank(ranked *gorm.DB, userID uint) LeaderboardUser {
+	// Sıra tüm kullanıcılar üzerinden hesaplandıktan sonra süzülür
+	var userRank LeaderboardUser
+	err := lc.DB.Table("(?) AS ranked", ranked).Where("id = ?", userID).Limit(1).Scan(&userRank).Error
 
-	// Apply joins if any
-	if joinClause != "" {
-		for _, param := range queryParams {
-			joinClause = lc.DB.Statement.Dialector.Explain(joinClause, param)
+	// Kullanıcı sıralamalarda yoksa
+	if err != nil || userRank.ID == 0 {
+		// Get the basic user info from the database
+		var basicUserInfo struct {
+			Username string `json:"username"`
+		}
+		lc.DB.Model(&models.User{}).Select("username").Where("id = ?", userID).First(&basicUserInfo)
+
+		userRank = LeaderboardUser{
+			ID:       userID,
+			Rank:     0,
+			Username: basicUserInfo.Username,
 		}
-		baseQuery = baseQuery.Joins(joinClause)
 	}
+	return userRank
+}
 
-	// Apply where clause if any
-	if whereClause != "" {
-		baseQuery = baseQuery.Where(whereClause, queryParams...)
+// leaderboardFilter echoes the applied filters back to the client
+func leaderboardFilter(query LeaderboardQuery) gin.H {
+	return gin.H{
+		"time_filter":  query.TimeFilter,
+		"is_category":  query.IsCategory,
+		"category_id":  query.CategoryID,
+		"is_nearby":    query.IsNearby,
+		"max_distance": query.MaxDistance,
 	}
+}
 
-	// Group by for aggregations
-	baseQuery = baseQuery.Group("users.id, users.username, users.first_name, users.last_name, users.avatar")
+func (lc *LeaderboardController) GetLeaderboard(c *gin.Context) {
+	var query LeaderboardQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if query.TimeFilter == "all_time" {
-		baseQuery = baseQuery.Group("users.total_points")
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if query.IsNearby {
-		baseQuery = baseQuery.Group("distance")
+	ranked, err := lc.rankedUsersQuery(&query, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	// Get current user from context
+	userID := utils.GetUser(c).UserID
+
 	// Get total count for pagination
 	var count int64
-	countQuery := baseQuery.Session(&gorm.Session{})
-	if err := countQuery.Count(&count).Error; err != nil {
+	if err := lc.DB.Table("(?) AS ranked", ranked).Count(&count).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting users: " + err.Error()})
 		return
 	}
@@ -168,60 +180,94 @@ func (lc *LeaderboardController) GetLeaderboard(c *gin.Context) {
 	// Calculate pagination
 	offset := (query.Page - 1) * query.PageSize
 
-	// Kullanıcı sıralamasını temsil edecek struct
-	type LeaderboardUser struct {
-		ID        uint    `json:"id" gorm:"column:id"`
-		Username  string  `json:"username" gorm:"column:username"`
-		FirstName string  `json:"first_name" gorm:"column:first_name"`
-		LastName  string  `json:"last_name" gorm:"column:last_name"`
-		Avatar    string  `json:"avatar" gorm:"column:avatar"`
-		Points    float64 `json:"points" gorm:"column:points"`
-		Rank      int     `json:"rank" gorm:"column:rank"`
-		Distance  float64 `json:"distance,omitempty" gorm:"column:distance"`
-	}
-
 	// Get top users for the current page
 	var leaderboardUsers []LeaderboardUser
-	if err := baseQuery.Order("rank").Offset(offset).Limit(query.PageSize).Scan(&leaderboardUsers).Error; err != nil {
+	if err := ranked.Session(&gorm.Session{}).Order("rank").Offset(offset).Limit(query.PageSize).Scan(&leaderboardUsers).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leaderboard: " + err.Error()})
 		return
 	}
 
-	// Find the current user's rank (specific to the filter type)
-	var userRank LeaderboardUser
-	userRankQuery := baseQuery.Session(&gorm.Session{})
-	err := userRankQuery.Where("users.id = ?", userID).Limit(1).Scan(&userRank).Error
-
-	// Kullanıcı sıralamalarda yoksa
-	if err != nil || userRank.ID == 0 {
-		// Get the basic user info from the database
-		var basicUserInfo struct {
-			Username string `json:"username"`
-		}
-		lc.DB.Model(&models.User{}).Select("username").Where("id = ?", userID).First(&basicUserInfo)
-
-		userRank = LeaderboardUser{
-			ID:       userID,
-			Rank:     0,
-			Username: basicUserInfo.Username,
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"leaderboard": leaderboardUsers,
-		"user_rank":   userRank,
+		"user_rank":   lc.viewerRank(ranked, userID),
 		"pagination": gin.H{
 			"current_page": query.Page,
 			"page_size":    query.PageSize,
 			"total_items":  count,
 			"total_pages":  math.Ceil(float64(count) / float64(query.PageSize)),
 		},
-		"filter": gin.H{
-			"time_filter":  query.TimeFilter,
-			"is_category":  query.IsCategory,
-			"category_id":  query.CategoryID,
-			"is_nearby":    query.IsNearby,
-			"max_distance": query.MaxDistance,
-		},
+		"filter": leaderboardFilter(query),
+	})
+}
+
+// LeaderboardAroundMeQuery; Window kullanıcının üstünde ve altında gösterilecek kişi sayısıdır
+type LeaderboardAroundMeQuery struct {
+	LeaderboardQuery
+	Window int `form:"window,default=5" binding:"min=1,max=25"`
+}
+
+// GetLeaderboardAroundMe godoc
+// @Summary Get the leaderboard around the current user
+// @Description Returns the current user with the `window` users ranked immediately above and below them, using the same time, category and nearby filters as the leaderboard. The slice is empty when the filters leave the user out.
+// @Tags leaderboard
+// @Produce json
+// @Param timeFilter query string false "all_time, weekly or monthly (default: all_time)"
+// @Param window query integer false "Users shown above and below (default: 5, max: 25)"
+// @Success 200 {object} map[string]interface{}
+// @Router /leaderboard/around-me [get]
+func (lc *LeaderboardController) GetLeaderboardAroundMe(c *gin.Context) {
+	var query LeaderboardAroundMeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ranked, err := lc.rankedUsersQuery(&query.LeaderboardQuery, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := utils.GetUser(c).UserID
+
+	// Eşit puanlılar aynı sırayı paylaşır; dilim için kesin bir konum gerekir
+	positioned := lc.DB.Table("(?) AS ranked", ranked).
+		Select("ranked.*, ROW_NUMBER() OVER (ORDER BY ranked.points DESC, ranked.id) AS position")
+
+	var position int64
+	if err := lc.DB.Table("(?) AS positioned", positioned).Select("position").
+		Where("id = ?", userID).Scan(&position).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leaderboard: " + err.Error()})
+		return
+	}
+
+	neighbors := []LeaderboardUser{}
+	userRank := LeaderboardUser{ID: userID}
+	if position > 0 {
+		if err := lc.DB.Table("(?) AS positioned", positioned).
+			Where("position BETWEEN ? AND ?", position-int64(query.Window), position+int64(query.Window)).
+			Order("position").Scan(&neighbors).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leaderboard: " + err.Error()})
+			return
+		}
+		for _, user := range neighbors {
+			if user.ID == userID {
+				userRank = user
+			}
+		}
+	} else {
+		userRank = lc.viewerRank(ranked, userID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"leaderboard": neighbors,
+		"user_rank":   userRank,
+		"window":      query.Window,
+		"filter":      leaderboardFilter(query.LeaderboardQuery),
 	})
 }
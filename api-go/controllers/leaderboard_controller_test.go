@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetLeaderboardAroundMe(t *testing.T) {
+	db := openTestDB(t)
+	// 90, 80, ..., 10 puanlı dokuz kullanıcı
+	users := make([]models.User, 9)
+	for i := range users {
+		users[i] = createTestUser(t, db, fmt.Sprintf("rankuser%d", i))
+		if err := db.Model(&users[i]).Update("total_points", (9-i)*10).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lc := NewLeaderboardController(db)
+	aroundMe := func(viewer models.User, params string) (names []string, me LeaderboardUser) {
+		t.Helper()
+		w := callHandler(lc.GetLeaderboardAroundMe, http.MethodGet, "/leaderboard/around-me?"+params, nil, viewer.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", params, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Leaderboard []LeaderboardUser `json:"leaderboard"`
+			UserRank    LeaderboardUser   `json:"user_rank"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		names = []string{}
+		for _, user := range resp.Leaderboard {
+			names = append(names, user.Username)
+		}
+		return names, resp.UserRank
+	}
+
+	names, me := aroundMe(users[4], "window=2")
+	if want := []string{"rankuser2", "rankuser3", "rankuser4", "rankuser5", "rankuser6"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("slice = %v, want %v", names, want)
+	}
+	if me.ID != users[4].ID || me.Rank != 5 || me.Points != 50 {
+		t.Errorf("user rank = %+v, want rank 5 with 50 points", me)
+	}
+
+	// Listenin başında yalnızca alttakiler gösterilir
+	names, me = aroundMe(users[0], "window=2")
+	if want := []string{"rankuser0", "rankuser1", "rankuser2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("top slice = %v, want %v", names, want)
+	}
+	if me.Rank != 1 {
+		t.Errorf("top user rank = %d, want 1", me.Rank)
+	}
+
+	// Süzgeç kullanıcıyı dışarıda bırakırsa dilim boştur
+	names, me = aroundMe(users[4], "isCategory=true&categoryId=museum")
+	if len(names) != 0 || me.Rank != 0 || me.Username != "rankuser4" {
+		t.Errorf("filtered out: slice = %v, user rank = %+v; want empty and rank 0", names, me)
+	}
+
+	if w := callHandler(lc.GetLeaderboardAroundMe, http.MethodGet, "/leaderboard/around-me?window=100", nil, users[4].ID); w.Code != http.StatusBadRequest {
+		t.Errorf("window over the limit: status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// GetLikedPosts godoc
+// @Summary List the posts the current user has liked
+// @Description Returns liked posts, most recently liked first. Posts that were deleted or that the user can no longer see, e.g. after they went private, are left out.
+// @Tags posts
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse{data=[]PostSummary}
+// @Router /users/me/liked-posts [get]
+func (pc *PostController) GetLikedPosts(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+	offset := (page - 1) * pageSize
+
+	// Görünürlük her istekte yeniden uygulanır; beğeni kaydı tek başına yetmez
+	visibility, visibilityArgs := visiblePostsCondition(user.UserID)
+	likedPosts := func() *gorm.DB {
+		return pc.DB.Table("likes").
+			Joins("JOIN posts ON posts.id = likes.post_id AND posts.deleted_at IS NULL").
+			Joins("JOIN users ON posts.user_id = users.id").
+			Joins("JOIN places ON posts.place_id = places.id").
+			Where("likes.user_id = ?", user.UserID).
+			Where(visibility, visibilityArgs...)
+	}
+
+	var total int64
+	if err := likedPosts().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting liked posts"})
+		return
+	}
+
+	var rawPosts []struct {
+		ID            uint      `gorm:"column:id"`
+		Caption       string    `gorm:"column:post_caption"`
+		PlaceID       uint      `gorm:"column:place_id"`
+		PlaceName     string    `gorm:"column:place_name"`
+		PlaceImage    string    `gorm:"column:place_image"`
+		UserID        uint      `gorm:"column:user_id"`
+		Username      string    `gorm:"column:username"`
+		FirstName     string    `gorm:"column:first_name"`
+		LastName      string    `gorm:"column:last_name"`
+		Avatar        string    `gorm:"column:avatar"`
+		Latitude      float64   `gorm:"column:latitude"`
+		Longitude     float64   `gorm:"column:longitude"`
+		EarnedPoints  int64     `gorm:"column:earned_points"`
+		ThumbnailURL  string    `gorm:"column:thumbnail_url"`
+		MediaType     string    `gorm:"column:media_type"`
+		MediaCount    int64     `gorm:"column:media_count"`
+		LikesCount    int64     `gorm:"column:likes_count"`
+		CommentsCount int64     `gorm:"column:comments_count"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+		UpdatedAt     time.Time `gorm:"column:updated_at"`
+	}
+	if err := likedPosts().
+		Select(`
+			posts.id,
+			posts.post_caption,
+			posts.place_id,
+			places.name as place_name,
+			places.place_image,
+			posts.user_id,
+			users.username,
+			users.first_name,
+			users.last_name,
+			users.avatar,
+			posts.latitude,
+			posts.longitude,
+			posts.earned_points,
+			posts.created_at,
+			posts.updated_at,
+			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
+			(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count,
+			(SELECT COUNT(*) FROM likes AS post_likes WHERE post_likes.post_id = posts.id) as likes_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count
+		`).
+		Order("likes.created_at DESC, likes.like_id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Scan(&rawPosts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching liked posts"})
+		return
+	}
+
+	posts := make([]PostSummary, len(rawPosts))
+	for i, raw := range rawPosts {
+		posts[i] = PostSummary{
+			ID:           raw.ID,
+			Caption:      raw.Caption,
+			CreatedAt:    raw.CreatedAt,
+			UpdatedAt:    raw.UpdatedAt,
+			Latitude:     raw.Latitude,
+			Longitude:    raw.Longitude,
+			EarnedPoints: raw.EarnedPoints,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
+			MediaType:    raw.MediaType,
+			MediaCount:   raw.MediaCount,
+			User: PostUser{
+				ID:        raw.UserID,
+				Username:  raw.Username,
+				FirstName: raw.FirstName,
+				LastName:  raw.LastName,
+				Avatar:    raw.Avatar,
+			},
+			Place: PostPlace{
+				ID:    raw.PlaceID,
+				Name:  raw.PlaceName,
+				Image: raw.PlaceImage,
+			},
+			Interaction: PostInteraction{
+				LikesCount:    raw.LikesCount,
+				CommentsCount: raw.CommentsCount,
+				IsLiked:       true,
+			},
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
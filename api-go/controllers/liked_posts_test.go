@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetLikedPostsOmitsDeletedAndHiddenPosts(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "liker")
+	author := createTestUser(t, db, "likedauthor")
+	place := createTestPlace(t, db, "likedplace")
+	pc := NewPostController(db, nil)
+
+	older := createTestPost(t, db, author, place, "liked first", true)
+	newer := createTestPost(t, db, author, place, "liked second", true)
+	deleted := createTestPost(t, db, author, place, "deleted later", true)
+	hidden := createTestPost(t, db, author, place, "made private later", true)
+	unliked := createTestPost(t, db, author, place, "never liked", true)
+	base := time.Now().Add(-time.Hour)
+	for i, post := range []models.Post{older, newer, deleted, hidden} {
+		if err := db.Create(&models.Like{PostID: post.ID, UserID: me.ID, CreatedAt: base.Add(time.Duration(i) * time.Minute)}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Başka kullanıcının beğenisi listeyi etkilemez
+	if err := db.Create(&models.Like{PostID: unliked.ID, UserID: author.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	list := func() []PostSummary {
+		t.Helper()
+		w := callHandler(pc.GetLikedPosts, http.MethodGet, "/users/me/liked-posts", nil, me.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []PostSummary `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+
+	if got := list(); len(got) != 4 || got[0].ID != hidden.ID || got[3].ID != older.ID {
+		t.Fatalf("liked posts = %+v, want the four liked posts newest like first", got)
+	}
+
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&hidden).Update("is_public", false).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	got := list()
+	if len(got) != 2 || got[0].ID != newer.ID || got[1].ID != older.ID {
+		t.Fatalf("liked posts = %+v, want [%d %d]", got, newer.ID, older.ID)
+	}
+	if !got[0].Interaction.IsLiked || got[0].Interaction.LikesCount != 1 {
+		t.Errorf("interaction = %+v, want liked with one like", got[0].Interaction)
+	}
+}
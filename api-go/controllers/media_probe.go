@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/snap-point/api-go/media"
+)
+
+const (
+	// Boyutlar başlıktan okunur; büyük EXIF bloklarını da kapsayacak kadar bayt indirilir
+	imageHeaderBytes  = 256 * 1024
+	mediaProbeTimeout = 10 * time.Second
+	// Küçük bir dosya başlığında dev boyutlar bildirip çözümlemede belleği tüketebilir
+	maxDecodePixels = 40_000_000
+)
+
+// errImageTooLarge is returned for images above maxDecodePixels
+var errImageTooLarge = errors.New("image dimensions exceed the decode limit")
+
+// mediaDimensions are the width, height and (for video) duration in seconds of a media file
+type mediaDimensions struct {
+	Width    int `json:"width"`
+	Height   int `json:"height"`
+	Duration int `json:"duration"`
+}
+
+// probeVideo runs ffprobe on a video URL; replaced in tests
+var probeVideo = ffprobeDimensions
+
+// extractMediaDimensions reads the real dimensions of an uploaded file: the
+// image header from R2 for photos, ffprobe on the media URL for videos. A
+// private bucket is probed through a presigned GET URL.
+func (uc *UploadController) extractMediaDimensions(ctx context.Context, key, mediaType string) (mediaDimensions, error) {
+	ctx, cancel := context.WithTimeout(ctx, mediaProbeTimeout)
+	defer cancel()
+
+	if mediaType == media.Video {
+		url := fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, key)
+		if uc.R2Config.PrivateMedia {
+			signedURL, err := uc.createPresignedGetURL(key)
+			if err != nil {
+				return mediaDimensions{}, err
+			}
+			url = signedURL
+		}
+		return probeVideo(ctx, url)
+	}
+
+	object, err := uc.R2Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(uc.R2Config.BucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", imageHeaderBytes-1)),
+	})
+	if err != nil {
+		return mediaDimensions{}, err
+	}
+	defer object.Body.Close()
+
+	return imageDimensions(object.Body)
+}
+
+// imagePerceptualHash downloads a photo or GIF from R2 and returns its
+// difference hash; formats the server cannot decode return an error
+func (uc *UploadController) imagePerceptualHash(ctx context.Context, key, mediaType string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, mediaProbeTimeout)
+	defer cancel()
+
+	object, err := uc.R2Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(uc.R2Config.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer object.Body.Close()
+
+	img, err := decodeImageLimited(io.LimitReader(object.Body, media.MaxSize(mediaType)))
+	if err != nil {
+		return "", err
+	}
+	return media.DifferenceHash(img), nil
+}
+
+// decodeImageLimited decodes an image after checking from its header that it
+// has at most maxDecodePixels pixels
+func decodeImageLimited(r io.Reader) (image.Image, error) {
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxDecodePixels {
+		return nil, errImageTooLarge
+	}
+	img, _, err := image.Decode(io.MultiReader(&header, r))
+	return img, err
+}
+
+// imageDimensions decodes only the header of a JPEG, PNG or GIF image
+func imageDimensions(r io.Reader) (mediaDimensions, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return mediaDimensions{}, err
+	}
+	return mediaDimensions{Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// ffprobeDimensions reads the first video stream's size and the container duration
+func ffprobeDimensions(ctx context.Context, url string) (mediaDimensions, error) {
+	path, err := exec.LookPath("ffprobe")
+	if err != nil {
+		return mediaDimensions{}, err
+	}
+	out, err := exec.CommandContext(ctx, path,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		url,
+	).Output()
+	if err != nil {
+		return mediaDimensions{}, err
+	}
+	return parseFFprobeOutput(out)
+}
+
+// parseFFprobeOutput parses `ffprobe -of json` output; the duration is rounded up to whole seconds
+func parseFFprobeOutput(out []byte) (mediaDimensions, error) {
+	var probe struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return mediaDimensions{}, err
+	}
+	if len(probe.Streams) == 0 {
+		return mediaDimensions{}, fmt.Errorf("no video stream found")
+	}
+
+	dims := mediaDimensions{Width: probe.Streams[0].Width, Height: probe.Streams[0].Height}
+	if duration, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil && duration > 0 {
+		dims.Duration = int(math.Ceil(duration))
+	}
+	return dims, nil
+}
+
+// resolveMediaDimensions prefers the extracted values and falls back to what
+// the client declared when extraction failed or returned no size. The bool
+// reports whether the extracted values were used.
+func resolveMediaDimensions(declared, extracted mediaDimensions, err error) (mediaDimensions, bool) {
+	if err != nil || extracted.Width <= 0 || extracted.Height <= 0 {
+		return declared, false
+	}
+	// Süre okunamadıysa istemcinin bildirdiği süre korunur
+	if extracted.Duration == 0 {
+		extracted.Duration = declared.Duration
+	}
+	return extracted, true
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestImageDimensions(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	var pngData, jpegData bytes.Buffer
+	if err := png.Encode(&pngData, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(&jpegData, img.SubImage(image.Rect(0, 0, 300, 200)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want mediaDimensions
+	}{
+		{"png", pngData.Bytes(), mediaDimensions{Width: 640, Height: 480}},
+		{"jpeg", jpegData.Bytes(), mediaDimensions{Width: 300, Height: 200}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := imageDimensions(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("dimensions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := imageDimensions(strings.NewReader("not an image")); err == nil {
+		t.Error("expected an error for non-image data")
+	}
+}
+
+// pngHeader returns a PNG signature and IHDR chunk declaring width x height;
+// enough for image.DecodeConfig without any pixel data
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12], ihdr[13] = 8, 6 // 8 bit RGBA
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)-4))
+	buf.Write(ihdr)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	return buf.Bytes()
+}
+
+func TestDecodeImageLimited(t *testing.T) {
+	var small bytes.Buffer
+	if err := png.Encode(&small, image.NewRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := decodeImageLimited(bytes.NewReader(small.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(64, 32) {
+		t.Errorf("decoded size = %v, want 64x32", got)
+	}
+
+	// Başlık 20000x20000 bildiren dosya çözümlenmeden reddedilir
+	if _, err := decodeImageLimited(bytes.NewReader(pngHeader(20000, 20000))); !errors.Is(err, errImageTooLarge) {
+		t.Errorf("oversized image: err = %v, want errImageTooLarge", err)
+	}
+}
+
+func TestParseFFprobeOutput(t *testing.T) {
+	out := []byte(`{"streams":[{"width":1080,"height":1920}],"format":{"duration":"12.300000"}}`)
+	got, err := parseFFprobeOutput(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (mediaDimensions{Width: 1080, Height: 1920, Duration: 13}); got != want {
+		t.Errorf("dimensions = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseFFprobeOutput([]byte(`{"streams":[],"format":{}}`)); err == nil {
+		t.Error("expected an error when there is no video stream")
+	}
+}
+
+func TestResolveMediaDimensions(t *testing.T) {
+	declared := mediaDimensions{Width: 4000, Height: 100, Duration: 30}
+
+	tests := []struct {
+		name         string
+		extracted    mediaDimensions
+		err          error
+		want         mediaDimensions
+		wantVerified bool
+	}{
+		{"extracted wins over declared", mediaDimensions{Width: 1080, Height: 1350}, nil, mediaDimensions{Width: 1080, Height: 1350, Duration: 30}, true},
+		{"extracted duration wins", mediaDimensions{Width: 720, Height: 1280, Duration: 12}, nil, mediaDimensions{Width: 720, Height: 1280, Duration: 12}, true},
+		{"extraction failed", mediaDimensions{}, errors.New("ffprobe not found"), declared, false},
+		{"no size extracted", mediaDimensions{Width: 1080}, nil, declared, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, verified := resolveMediaDimensions(declared, tt.extracted, tt.err)
+			if got != tt.want || verified != tt.wantVerified {
+				t.Errorf("resolve = %+v, %v; want %+v, %v", got, verified, tt.want, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestExtractVideoDimensionsURL(t *testing.T) {
+	var probed string
+	orig := probeVideo
+	probeVideo = func(ctx context.Context, url string) (mediaDimensions, error) {
+		probed = url
+		return mediaDimensions{Width: 720, Height: 1280, Duration: 12}, nil
+	}
+	t.Cleanup(func() { probeVideo = orig })
+	key := "uploads/video/7/1_a.mp4"
+
+	uc := newTestUploadController(t)
+	if _, err := uc.extractMediaDimensions(context.Background(), key, "video"); err != nil {
+		t.Fatal(err)
+	}
+	if probed != uc.R2Config.PublicURL+"/"+key {
+		t.Errorf("public bucket probed %s, want the public URL", probed)
+	}
+
+	// Özel kovada herkese açık URL okunamaz; ffprobe imzalı URL alır
+	t.Setenv("CLOUDFLARE_PRIVATE_MEDIA", "true")
+	uc = newTestUploadController(t)
+	if _, err := uc.extractMediaDimensions(context.Background(), key, "video"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(probed, key) || !strings.Contains(probed, "X-Amz-Signature=") {
+		t.Errorf("private bucket probed %s, want a presigned GET URL", probed)
+	}
+}
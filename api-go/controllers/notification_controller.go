@@ -0,0 +1,554 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Proxy'lerin boştaki akışı kapatmaması için gönderilen ping aralığı
+const notificationHeartbeatInterval = 25 * time.Second
+
+type NotificationController struct {
+	DB *gorm.DB
+}
+
+// NotificationPreferenceRequest; gönderilmeyen kanal değişmeden kalır
+type NotificationPreferenceRequest struct {
+	Email []string `json:"email"`
+	Push  []string `json:"push"`
+}
+
+// NotificationItem is the shape of a notification in lists and stream events
+type NotificationItem struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Actor     *PostUser `json:"actor,omitempty"`
+	PostID    *uint     `json:"postId,omitempty"`
+	IsRead    bool      `json:"isRead"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// notificationRow is a notification joined with its actor
+type notificationRow struct {
+	ID             uint      `gorm:"column:id"`
+	Type           string    `gorm:"column:type"`
+	PostID         *uint     `gorm:"column:post_id"`
+	IsRead         bool      `gorm:"column:is_read"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	ActorID        *uint     `gorm:"column:actor_id"`
+	ActorUsername  string    `gorm:"column:actor_username"`
+	ActorFirstName string    `gorm:"column:actor_first_name"`
+	ActorLastName  string    `gorm:"column:actor_last_name"`
+	ActorAvatar    string    `gorm:"column:actor_avatar"`
+}
+
+const notificationRowSelect = `notifications.id, notifications.type, notifications.post_id,
+	notifications.is_read, notifications.created_at, actor.id as actor_id,
+	actor.username as actor_username, actor.first_name as actor_first_name,
+	actor.last_name as actor_last_name, actor.avatar as actor_avatar`
+
+// notificationActorNotBlocked hides notifications caused by a user who has
+// blocked, or been blocked by, the recipient. They reappear after an unblock.
+const notificationActorNotBlocked = `(notifications.actor_user_id IS NULL OR NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+	((blocks.blocker_user_id = notifications.user_id AND blocks.blocked_user_id = notifications.actor_user_id) OR
+	 (blocks.blocker_user_id = notifications.actor_user_id AND blocks.blocked_user_id = notifications.user_id))))`
+
+// userNotifications scopes notifications to those of userID that are not hidden by a block
+func userNotifications(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Model(&models.Notification{}).
+		Where("notifications.user_id = ?", userID).
+		Where(notificationActorNotBlocked)
+}
+
+func NewNotificationController(db *gorm.DB) *NotificationController {
+	return &NotificationController{DB: db}
+}
+
+// notifyUser stores a notification for userID, pushes it to their open
+// streams and hands it to email/push delivery. Failures are logged, never
+// returned: a missing notification must not fail the like or follow that
+// caused it. Call it after the causing transaction has committed. An
+// actorUserID of 0 marks a system notification with no actor.
+func notifyUser(db *gorm.DB, userID, actorUserID uint, notificationType string, postID *uint) {
+	notification, err := createNotification(db, userID, actorUserID, notificationType, postID)
+	if err != nil {
+		log.Printf("Failed to create %s notification for user %d: %v", notificationType, userID, err)
+		return
+	}
+	if notification != nil {
+		deliverNotification(db, notification)
+	}
+}
+
+// createNotification stores a notification without delivering it, so it can be
+// written in the transaction of the action that caused it. It returns nil when
+// the user acted on their own content or when a block exists between the user
+// and the actor in either direction.
+func createNotification(tx *gorm.DB, userID, actorUserID uint, notificationType string, postID *uint) (*models.Notification, error) {
+	// Kullanıcı kendi eylemi için bildirim almaz
+	if userID == actorUserID {
+		return nil, nil
+	}
+	if actorUserID != 0 {
+		if blocked, err := isBlockedBetween(tx, userID, actorUserID); err != nil {
+			return nil, err
+		} else if blocked {
+			return nil, nil
+		}
+	}
+
+	notification := models.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		PostID: postID,
+	}
+	// actorUserID 0 ise bildirim sistemden gelir (ör. moderasyon)
+	if actorUserID != 0 {
+		notification.ActorUserID = &actorUserID
+	}
+	if err := tx.Create(&notification).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// deliverNotification streams a stored notification to the user's open
+// connections and sends it through their outbound channels. Call it only
+// after the notification is committed.
+func deliverNotification(db *gorm.DB, notification *models.Notification) {
+	userID := notification.UserID
+	var row notificationRow
+	if err := notificationQuery(db).Where("notifications.id = ?", notification.ID).Scan(&row).Error; err != nil {
+		log.Printf("Failed to load notification %d for streaming: %v", notification.ID, err)
+		return
+	}
+	item := notificationItem(row)
+	notifications.publish(userID, item)
+	deliverOutbound(db, userID, item)
+}
+
+func notificationQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Notification{}).
+		Select(notificationRowSelect).
+		Joins("LEFT JOIN users actor ON actor.id = notifications.actor_user_id")
+}
+
+func notificationItem(row notificationRow) NotificationItem {
+	item := NotificationItem{
+		ID:        row.ID,
+		Type:      row.Type,
+		PostID:    row.PostID,
+		IsRead:    row.IsRead,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ActorID != nil {
+		item.Actor = &PostUser{
+			ID:        *row.ActorID,
+			Username:  row.ActorUsername,
+			FirstName: row.ActorFirstName,
+			LastName:  row.ActorLastName,
+			Avatar:    row.ActorAvatar,
+		}
+	}
+	return item
+}
+
+// NotificationListQuery; BeforeID verilirse sayfa numarası yerine imleçle sayfalanır
+type NotificationListQuery struct {
+	Page     int  `form:"page,default=1" binding:"min=1"`
+	PageSize int  `form:"pageSize,default=20" binding:"min=1,max=50"`
+	BeforeID uint `form:"beforeId"`
+}
+
+// GetNotifications godoc
+// @Summary List the current user's notifications
+// @Description Returns notifications newest first with the unread count and nextCursor in meta. Notifications caused by a user blocked in either direction are left out. Pass nextCursor back as beforeId to page by cursor, which stays stable while new notifications arrive; cursor pages have no pagination block. Notifications delivered by the stream are newer than the first page.
+// @Tags notifications
+// @Produce json
+// @Param page query integer false "Page number (default: 1), ignored with beforeId"
+// @Param pageSize query integer false "Items per page (default: 20, max: 50)"
+// @Param beforeId query integer false "Return notifications with a lower ID than this cursor"
+// @Success 200 {object} StandardResponse
+// @Router /notifications [get]
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var query NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var unread int64
+	if err := userNotifications(nc.DB, user.UserID).Where("notifications.is_read = false").Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching notifications"})
+		return
+	}
+
+	rowsQuery := notificationQuery(nc.DB).
+		Where("notifications.user_id = ?", user.UserID).
+		Where(notificationActorNotBlocked).
+		Order("notifications.id DESC")
+	if query.BeforeID > 0 {
+		rowsQuery = rowsQuery.Where("notifications.id < ?", query.BeforeID)
+	} else {
+		rowsQuery = rowsQuery.Offset((query.Page - 1) * query.PageSize)
+	}
+
+	// Sonraki sayfanın varlığını anlamak için bir fazla satır okunur
+	var rows []notificationRow
+	if err := rowsQuery.Limit(query.PageSize + 1).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching notifications"})
+		return
+	}
+
+	var nextCursor *uint
+	if len(rows) > query.PageSize {
+		rows = rows[:query.PageSize]
+		nextCursor = &rows[len(rows)-1].ID
+	}
+
+	items := make([]NotificationItem, len(rows))
+	for i, row := range rows {
+		items[i] = notificationItem(row)
+	}
+
+	response := StandardResponse{
+		Success: true,
+		Data:    items,
+		Meta:    gin.H{"unreadCount": unread, "nextCursor": nextCursor},
+	}
+	if query.BeforeID == 0 {
+		var total int64
+		if err := userNotifications(nc.DB, user.UserID).Count(&total).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching notifications"})
+			return
+		}
+		response.Pagination = &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUnreadCount godoc
+// @Summary Get the number of unread notifications
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /notifications/unread-count [get]
+func (nc *NotificationController) GetUnreadCount(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var unread int64
+	if err := userNotifications(nc.DB, user.UserID).Where("notifications.is_read = false").Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"unreadCount": unread},
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark one notification as read
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} StandardResponse
+// @Router /notifications/{id}/read [put]
+func (nc *NotificationController) MarkNotificationRead(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var notification models.Notification
+	if err := nc.DB.Where("user_id = ?", user.UserID).First(&notification, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Notification not found"})
+		return
+	}
+
+	if !notification.IsRead {
+		if err := nc.DB.Model(&notification).Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update notification"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Message: "Notification marked as read",
+	})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /notifications/read-all [post]
+func (nc *NotificationController) MarkAllNotificationsRead(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	result := nc.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = false", user.UserID).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"updated": result.RowsAffected},
+	})
+}
+
+// MarkNotificationsReadRequest; boş alanlar süzgeç uygulamaz
+type MarkNotificationsReadRequest struct {
+	Type     string `json:"type"`
+	BeforeID uint   `json:"beforeId"`
+}
+
+// MarkNotificationsRead godoc
+// @Summary Mark a filtered set of notifications as read
+// @Description Marks the current user's unread notifications as read, optionally only those of one type and/or with an ID lower than beforeId. Returns the number of notifications marked.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body MarkNotificationsReadRequest false "Filters"
+// @Success 200 {object} StandardResponse
+// @Router /notifications/read [post]
+func (nc *NotificationController) MarkNotificationsRead(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var req MarkNotificationsReadRequest
+	// Gövdesiz istek tüm okunmamışları işaretler; chunked boş gövde EOF ile biter
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+			return
+		}
+	}
+	if req.Type != "" && !containsString(notificationTypes, req.Type) {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Invalid notification type: " + req.Type})
+		return
+	}
+
+	// Engel nedeniyle gizlenen bildirimler kullanıcıya görünmez; sayıma da girmez
+	query := userNotifications(nc.DB, user.UserID).Where("notifications.is_read = false")
+	if req.Type != "" {
+		query = query.Where("notifications.type = ?", req.Type)
+	}
+	if req.BeforeID > 0 {
+		query = query.Where("notifications.id < ?", req.BeforeID)
+	}
+
+	result := query.Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"updated": result.RowsAffected},
+	})
+}
+
+// StreamNotifications godoc
+// @Summary Stream new notifications with Server-Sent Events
+// @Description Sends a "ready" event with the unread count, then a "notification" event per new notification and a "ping" event periodically. EventSource cannot set headers, so the access token may be passed as the token query parameter. Clients should fall back to polling unread-count when the stream is unavailable.
+// @Tags notifications
+// @Produce text/event-stream
+// @Param token query string false "Access token when the Authorization header cannot be set"
+// @Success 200 {string} string "event stream"
+// @Router /notifications/stream [get]
+func (nc *NotificationController) StreamNotifications(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	events, unsubscribe, ok := notifications.subscribe(user.UserID)
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many open notification streams"})
+		return
+	}
+	defer unsubscribe()
+
+	var unread int64
+	if err := userNotifications(nc.DB, user.UserID).Where("notifications.is_read = false").Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting notifications"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("ready", gin.H{"unreadCount": unread})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(notificationHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case item := <-events:
+			c.SSEvent("notification", item)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+			c.Writer.Flush()
+		}
+	}
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get which notification types are sent by email and push
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /notifications/preferences [get]
+func (nc *NotificationController) GetNotificationPreferences(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	pref, err := nc.loadNotificationPreference(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    notificationPreferenceResponse(pref),
+	})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Choose which notification types are sent by email and push
+// @Description Each channel takes the full list of types to deliver; omit a channel to leave it unchanged
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body NotificationPreferenceRequest true "Types per channel"
+// @Success 200 {object} StandardResponse
+// @Router /notifications/preferences [put]
+func (nc *NotificationController) UpdateNotificationPreferences(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var req NotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	for _, types := range [][]string{req.Email, req.Push} {
+		for _, notificationType := range types {
+			if !containsString(notificationTypes, notificationType) {
+				c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Invalid notification type: " + notificationType})
+				return
+			}
+		}
+	}
+
+	pref, err := nc.loadNotificationPreference(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching notification preferences"})
+		return
+	}
+	if req.Email != nil {
+		pref.EmailTypes = req.Email
+	}
+	if req.Push != nil {
+		pref.PushTypes = req.Push
+	}
+
+	if err := nc.DB.Save(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to save notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    notificationPreferenceResponse(pref),
+		Message: "Notification preferences updated",
+	})
+}
+
+// loadNotificationPreference returns the stored preference or the defaults
+// (push for every type, no email) for a user who never saved one.
+func (nc *NotificationController) loadNotificationPreference(userID uint) (models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := nc.DB.Where("user_id = ?", userID).First(&pref).Error
+	if err == gorm.ErrRecordNotFound {
+		return models.NotificationPreference{
+			UserID:     userID,
+			EmailTypes: pq.StringArray{},
+			PushTypes:  pq.StringArray(append([]string{}, notificationTypes...)),
+		}, nil
+	}
+	return pref, err
+}
+
+func notificationPreferenceResponse(pref models.NotificationPreference) gin.H {
+	email := []string(pref.EmailTypes)
+	if email == nil {
+		email = []string{}
+	}
+	push := []string(pref.PushTypes)
+	if push == nil {
+		push = []string{}
+	}
+	return gin.H{
+		"email":          email,
+		"push":           push,
+		"availableTypes": notificationTypes,
+	}
+}
@@ -0,0 +1,369 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+func TestStreamNotificationsDeliversNewNotifications(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "streamme")
+	liker := createTestUser(t, db, "streamliker")
+	post := createTestPost(t, db, me, createTestPlace(t, db, "streamplace"), "", true)
+	nc := NewNotificationController(db)
+
+	r := gin.New()
+	r.GET("/notifications/stream", func(c *gin.Context) {
+		c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: me.ID, Role: "user"})
+	}, nc.StreamNotifications)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/notifications/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// event: satırından sonraki data: satırını döndürür
+	lines := bufio.NewScanner(resp.Body)
+	nextEvent := func() (string, string) {
+		t.Helper()
+		var event string
+		for lines.Scan() {
+			line := lines.Text()
+			if strings.HasPrefix(line, "event:") {
+				event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			} else if strings.HasPrefix(line, "data:") {
+				return event, strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			}
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return "", ""
+	}
+
+	if event, data := nextEvent(); event != "ready" || !strings.Contains(data, `"unreadCount":0`) {
+		t.Fatalf("first event = %s %s, want ready with unreadCount 0", event, data)
+	}
+
+	notifyUser(db, me.ID, liker.ID, "like", &post.ID)
+
+	event, data := nextEvent()
+	if event != "notification" {
+		t.Fatalf("event = %s, want notification", event)
+	}
+	var item NotificationItem
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Type != "like" || item.PostID == nil || *item.PostID != post.ID || item.Actor == nil || item.Actor.ID != liker.ID {
+		t.Errorf("notification = %+v, want like on post %d by %d", item, post.ID, liker.ID)
+	}
+}
+
+func TestNotificationsReadState(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "notifyme")
+	fan := createTestUser(t, db, "notifyfan")
+	nc := NewNotificationController(db)
+
+	notifyUser(db, me.ID, fan.ID, "follow", nil)
+	notifyUser(db, me.ID, fan.ID, "follow_request", nil)
+	notifyUser(db, me.ID, me.ID, "like", nil) // kendi eylemi bildirim oluşturmaz
+
+	unread := func() int64 {
+		t.Helper()
+		w := callHandler(nc.GetUnreadCount, http.MethodGet, "/notifications/unread-count", nil, me.ID)
+		var resp struct {
+			Data struct {
+				UnreadCount int64 `json:"unreadCount"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.UnreadCount
+	}
+	if got := unread(); got != 2 {
+		t.Fatalf("unread = %d, want 2", got)
+	}
+
+	var first models.Notification
+	if err := db.Where("user_id = ?", me.ID).Order("id").First(&first).Error; err != nil {
+		t.Fatal(err)
+	}
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(first.ID))}
+	if w := callHandler(nc.MarkNotificationRead, http.MethodPut, "/notifications/"+param.Value+"/read", nil, fan.ID, param); w.Code != http.StatusNotFound {
+		t.Errorf("mark another user's notification: status = %d, want 404", w.Code)
+	}
+	if w := callHandler(nc.MarkNotificationRead, http.MethodPut, "/notifications/"+param.Value+"/read", nil, me.ID, param); w.Code != http.StatusOK {
+		t.Fatalf("mark read: status = %d", w.Code)
+	}
+	if got := unread(); got != 1 {
+		t.Errorf("unread after marking one = %d, want 1", got)
+	}
+
+	callHandler(nc.MarkAllNotificationsRead, http.MethodPost, "/notifications/read-all", nil, me.ID)
+	if got := unread(); got != 0 {
+		t.Errorf("unread after read-all = %d, want 0", got)
+	}
+}
+
+func TestMarkNotificationsReadFiltered(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "filterreadme")
+	fan := createTestUser(t, db, "filterreadfan")
+	nc := NewNotificationController(db)
+
+	notifyUser(db, me.ID, fan.ID, "like", nil)
+	notifyUser(db, me.ID, fan.ID, "follow", nil)
+	notifyUser(db, me.ID, fan.ID, "like", nil)
+	notifyUser(db, me.ID, fan.ID, "follow_request", nil)
+
+	var ids []uint
+	if err := db.Model(&models.Notification{}).Where("user_id = ?", me.ID).Order("id").Pluck("id", &ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 4 {
+		t.Fatalf("notifications = %d, want 4", len(ids))
+	}
+
+	markRead := func(body string) int64 {
+		t.Helper()
+		w := callHandler(nc.MarkNotificationsRead, http.MethodPost, "/notifications/read", strings.NewReader(body), me.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", body, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Updated int64 `json:"updated"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.Updated
+	}
+	unreadTypes := func() []string {
+		t.Helper()
+		var types []string
+		if err := db.Model(&models.Notification{}).Where("user_id = ? AND is_read = false", me.ID).Order("id").Pluck("type", &types).Error; err != nil {
+			t.Fatal(err)
+		}
+		return types
+	}
+
+	if got := markRead(`{"type":"like"}`); got != 2 {
+		t.Errorf("marked likes = %d, want 2", got)
+	}
+	if got := unreadTypes(); strings.Join(got, ",") != "follow,follow_request" {
+		t.Errorf("unread after marking likes = %v, want follow and follow_request", got)
+	}
+
+	// Yalnızca verilen ID'den öncekiler işaretlenir
+	if got := markRead(`{"beforeId":` + strconv.Itoa(int(ids[3])) + `}`); got != 1 {
+		t.Errorf("marked before the last = %d, want 1", got)
+	}
+	if got := unreadTypes(); strings.Join(got, ",") != "follow_request" {
+		t.Errorf("unread after beforeId = %v, want follow_request", got)
+	}
+
+	if w := callHandler(nc.MarkNotificationsRead, http.MethodPost, "/notifications/read", strings.NewReader(`{"type":"poke"}`), me.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown type: status = %d, want 400", w.Code)
+	}
+	if got := markRead(`{}`); got != 1 {
+		t.Errorf("marked with no filters = %d, want 1", got)
+	}
+}
+
+func TestMarkNotificationsReadSkipsBlockedActors(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "blockreadme")
+	fan := createTestUser(t, db, "blockreadfan")
+	blocked := createTestUser(t, db, "blockreadblocked")
+	nc := NewNotificationController(db)
+
+	notifyUser(db, me.ID, blocked.ID, "like", nil)
+	notifyUser(db, me.ID, fan.ID, "like", nil)
+	if err := db.Create(&models.Block{BlockerUserID: me.ID, BlockedUserID: blocked.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Chunked boş gövdenin uzunluğu bilinmez; süzgeçsiz istek sayılır
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/notifications/read", strings.NewReader(""))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.ContentLength = -1
+	c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: me.ID, Role: "user"})
+	nc.MarkNotificationsRead(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Updated int64 `json:"updated"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// Engellenen kullanıcının gizli bildirimi sayılmaz
+	if resp.Data.Updated != 1 {
+		t.Errorf("updated = %d, want only the visible notification", resp.Data.Updated)
+	}
+	var unread int64
+	if err := db.Model(&models.Notification{}).Where("user_id = ? AND actor_user_id = ? AND is_read = false", me.ID, blocked.ID).
+		Count(&unread).Error; err != nil {
+		t.Fatal(err)
+	}
+	if unread != 1 {
+		t.Errorf("hidden notification marked read")
+	}
+}
+
+func TestGetNotificationsCursorIsStable(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "cursorme")
+	fan := createTestUser(t, db, "cursorfan")
+	nc := NewNotificationController(db)
+	for i := 0; i < 5; i++ {
+		notifyUser(db, me.ID, fan.ID, "like", nil)
+	}
+
+	type page struct {
+		IDs        []uint
+		NextCursor *uint
+		Paginated  bool
+	}
+	list := func(params string) page {
+		t.Helper()
+		w := callHandler(nc.GetNotifications, http.MethodGet, "/notifications?"+params, nil, me.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", params, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []NotificationItem `json:"data"`
+			Meta struct {
+				NextCursor *uint `json:"nextCursor"`
+			} `json:"meta"`
+			Pagination *PaginationMeta `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		result := page{NextCursor: resp.Meta.NextCursor, Paginated: resp.Pagination != nil}
+		for _, item := range resp.Data {
+			result.IDs = append(result.IDs, item.ID)
+		}
+		return result
+	}
+
+	first := list("pageSize=2")
+	if len(first.IDs) != 2 || first.NextCursor == nil || *first.NextCursor != first.IDs[1] || !first.Paginated {
+		t.Fatalf("first page = %+v, want two items and the last as cursor", first)
+	}
+
+	// Sayfalar arasında yeni bildirimler gelir
+	notifyUser(db, me.ID, fan.ID, "follow", nil)
+	notifyUser(db, me.ID, fan.ID, "follow", nil)
+
+	second := list(fmt.Sprintf("pageSize=2&beforeId=%d", *first.NextCursor))
+	if len(second.IDs) != 2 || second.IDs[0] >= first.IDs[1] || second.Paginated {
+		t.Fatalf("second page = %+v, want two older items without pagination", second)
+	}
+	third := list(fmt.Sprintf("pageSize=2&beforeId=%d", *second.NextCursor))
+	if len(third.IDs) != 1 || third.NextCursor != nil || third.IDs[0] >= second.IDs[1] {
+		t.Fatalf("third page = %+v, want the oldest item and no cursor", third)
+	}
+
+	// Sayfa numarasıyla ikinci sayfa yeni bildirimler yüzünden kayar
+	if shifted := list("pageSize=2&page=2"); shifted.IDs[0] != first.IDs[0] {
+		t.Errorf("offset page 2 = %v, want it to repeat %d after new arrivals", shifted.IDs, first.IDs[0])
+	}
+}
+
+func TestBlockedUsersNotificationsAreSuppressed(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "blocknotifyme")
+	fan := createTestUser(t, db, "blocknotifyfan")
+	other := createTestUser(t, db, "blocknotifyother")
+	place := createTestPlace(t, db, "blocknotifyplace")
+	post := createTestPost(t, db, me, place, "like me", true)
+	ic := NewInteractionController(db)
+	nc := NewNotificationController(db)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+
+	like := func(user models.User) {
+		t.Helper()
+		if w := callHandler(ic.LikePost, http.MethodPost, "/posts/"+param.Value+"/like", nil, user.ID, param); w.Code != http.StatusOK {
+			t.Fatalf("like by %s: status = %d, body = %s", user.Username, w.Code, w.Body.String())
+		}
+	}
+	list := func() ([]NotificationItem, int64) {
+		t.Helper()
+		w := callHandler(nc.GetNotifications, http.MethodGet, "/notifications", nil, me.ID)
+		var resp struct {
+			Data []NotificationItem `json:"data"`
+			Meta struct {
+				UnreadCount int64 `json:"unreadCount"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data, resp.Meta.UnreadCount
+	}
+
+	like(fan)
+	like(other)
+	if items, unread := list(); len(items) != 2 || unread != 2 {
+		t.Fatalf("before block: %d notifications, %d unread; want 2, 2", len(items), unread)
+	}
+
+	if err := db.Create(&models.Block{BlockerUserID: me.ID, BlockedUserID: fan.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	items, unread := list()
+	if len(items) != 1 || items[0].Actor == nil || items[0].Actor.ID != other.ID || unread != 1 {
+		t.Fatalf("after block: %+v with %d unread, want only %s's like", items, unread, other.Username)
+	}
+
+	// Beğeniyi geri alıp yeniden beğenmek bildirim oluşturmaz
+	like(fan)
+	like(fan)
+	var stored int64
+	db.Model(&models.Notification{}).Where("user_id = ? AND actor_user_id = ?", me.ID, fan.ID).Count(&stored)
+	if stored != 1 {
+		t.Errorf("notifications from blocked fan = %d, want only the one from before the block", stored)
+	}
+
+	// Engel ters yönde de geçerlidir
+	if err := db.Create(&models.Block{BlockerUserID: other.ID, BlockedUserID: me.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if items, unread := list(); len(items) != 0 || unread != 0 {
+		t.Errorf("after being blocked: %d notifications, %d unread; want none", len(items), unread)
+	}
+}
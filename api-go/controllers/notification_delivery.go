@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/notifier"
+	"gorm.io/gorm"
+)
+
+// notificationTypes lists every notification type users can route to channels
+var notificationTypes = []string{"like", "follow", "follow_request", "follow_accepted", "comment_removed", "place_discovered", "referral_completed"}
+
+const (
+	// Aynı anda yürüyebilecek en fazla dış gönderim; dolduğunda yeni gönderimler atlanır
+	maxOutboundDeliveries = 32
+	outboundTimeout       = 15 * time.Second
+)
+
+var (
+	outboundNotifier     notifier.Notifier
+	outboundNotifierOnce sync.Once
+	outboundSlots        = make(chan struct{}, maxOutboundDeliveries)
+)
+
+// getOutboundNotifier builds the notifier on first use, after main has loaded .env
+func getOutboundNotifier() notifier.Notifier {
+	outboundNotifierOnce.Do(func() {
+		if outboundNotifier == nil {
+			outboundNotifier = notifier.NewFromEnv()
+		}
+	})
+	return outboundNotifier
+}
+
+// notificationChannels returns whether the user wants notificationType by email and by push
+func notificationChannels(db *gorm.DB, userID uint, notificationType string) (email bool, push bool, err error) {
+	var pref models.NotificationPreference
+	if err := db.Where("user_id = ?", userID).First(&pref).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, true, nil
+		}
+		return false, false, err
+	}
+	return containsString(pref.EmailTypes, notificationType), containsString(pref.PushTypes, notificationType), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// outboundMessage renders the email/push text for a notification
+func outboundMessage(item NotificationItem) notifier.Message {
+	actor := "Someone"
+	if item.Actor != nil {
+		actor = "@" + item.Actor.Username
+	}
+
+	var body string
+	switch item.Type {
+	case "like":
+		body = actor + " liked your post"
+	case "follow":
+		body = actor + " started following you"
+	case "follow_request":
+		body = actor + " requested to follow you"
+	case "follow_accepted":
+		body = actor + " accepted your follow request"
+	case "comment_removed":
+		body = "One of your comments was removed by a moderator"
+	case "place_discovered":
+		body = "You were the first to post here and earned a discovery bonus"
+	case "referral_completed":
+		body = actor + " joined with your invite and you both earned bonus points"
+	default:
+		body = "You have a new notification"
+	}
+
+	data := map[string]string{
+		"notificationId": fmt.Sprint(item.ID),
+		"type":           item.Type,
+	}
+	if item.PostID != nil {
+		data["postId"] = fmt.Sprint(*item.PostID)
+	}
+
+	return notifier.Message{Title: "SnapPoint", Body: body, Data: data}
+}
+
+// deliverOutbound sends item by email and/or push according to the user's
+// preferences. It returns immediately; delivery runs in the background and
+// errors are only logged so provider outages never affect the API.
+func deliverOutbound(db *gorm.DB, userID uint, item NotificationItem) {
+	select {
+	case outboundSlots <- struct{}{}:
+	default:
+		log.Printf("Outbound delivery skipped for notification %d: too many deliveries in flight", item.ID)
+		return
+	}
+
+	go func() {
+		defer func() { <-outboundSlots }()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Outbound delivery for notification %d panicked: %v", item.ID, r)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), outboundTimeout)
+		defer cancel()
+
+		sendOutbound(ctx, db, getOutboundNotifier(), userID, item)
+	}()
+}
+
+// sendOutbound sends item through the channels the user enabled for its type
+func sendOutbound(ctx context.Context, db *gorm.DB, sender notifier.Notifier, userID uint, item NotificationItem) {
+	email, push, err := notificationChannels(db, userID, item.Type)
+	if err != nil {
+		log.Printf("Failed to load notification preferences for user %d: %v", userID, err)
+		return
+	}
+
+	msg := outboundMessage(item)
+
+	if email {
+		var user models.User
+		if err := db.Select("id, email, email_verified").First(&user, userID).Error; err != nil {
+			log.Printf("Failed to load user %d for email notification: %v", userID, err)
+		} else if user.Email != "" && user.EmailVerified {
+			if err := sender.SendEmail(ctx, user.Email, msg); err != nil && err != notifier.ErrChannelNotConfigured {
+				log.Printf("Email notification %d to user %d failed: %v", item.ID, userID, err)
+			}
+		}
+	}
+
+	if push {
+		sendPushToDevices(ctx, db, sender, userID, item.ID, msg)
+	}
+}
+
+// sendPushToDevices sends msg to each of the user's fresh device tokens and
+// deletes tokens the provider reports as no longer valid.
+func sendPushToDevices(ctx context.Context, db *gorm.DB, sender notifier.Notifier, userID, notificationID uint, msg notifier.Message) {
+	var devices []models.DeviceToken
+	if err := db.Where("user_id = ? AND last_seen >= ?", userID, time.Now().Add(-deviceTokenStaleAfter)).
+		Find(&devices).Error; err != nil {
+		log.Printf("Failed to load device tokens for user %d: %v", userID, err)
+		return
+	}
+
+	for _, device := range devices {
+		err := sender.SendPush(ctx, device.Token, msg)
+		switch {
+		case err == nil, err == notifier.ErrChannelNotConfigured:
+		case err == notifier.ErrInvalidDeviceToken:
+			if err := db.Delete(&device).Error; err != nil {
+				log.Printf("Failed to remove invalid device token %d: %v", device.ID, err)
+			}
+		default:
+			log.Printf("Push notification %d to device %d failed: %v", notificationID, device.ID, err)
+		}
+	}
+}
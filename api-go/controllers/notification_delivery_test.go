@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/snap-point/api-go/notifier"
+)
+
+// fakeNotifier records what would have been sent on each channel
+type fakeNotifier struct {
+	mu     sync.Mutex
+	emails []string
+	pushes []string
+	// Sağlayıcının geçersiz saydığı token'lar
+	invalidTokens map[string]bool
+}
+
+func (f *fakeNotifier) SendEmail(ctx context.Context, to string, msg notifier.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.emails = append(f.emails, to+" "+msg.Data["type"])
+	return nil
+}
+
+func (f *fakeNotifier) SendPush(ctx context.Context, deviceToken string, msg notifier.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.invalidTokens[deviceToken] {
+		return notifier.ErrInvalidDeviceToken
+	}
+	f.pushes = append(f.pushes, deviceToken+" "+msg.Data["type"])
+	return nil
+}
+
+func TestOutboundChannelsFollowPreferences(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "outboundme")
+	unverified := createTestUser(t, db, "outboundunverified")
+	defaults := createTestUser(t, db, "outbounddefaults")
+	if err := db.Model(&me).Update("email_verified", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	nc := NewNotificationController(db)
+
+	for _, user := range []uint{me.ID, unverified.ID} {
+		w := callHandler(nc.UpdateNotificationPreferences, http.MethodPut, "/notifications/preferences",
+			strings.NewReader(`{"email":["like","follow_request"],"push":["follow"]}`), user)
+		if w.Code != http.StatusOK {
+			t.Fatalf("save preferences: status = %d, body = %s", w.Code, w.Body.String())
+		}
+	}
+	if w := callHandler(nc.UpdateNotificationPreferences, http.MethodPut, "/notifications/preferences",
+		strings.NewReader(`{"email":["bogus"]}`), me.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown type: status = %d, want 400", w.Code)
+	}
+
+	tests := []struct {
+		user             uint
+		notificationType string
+		email, push      bool
+	}{
+		{me.ID, "like", true, false},
+		{me.ID, "follow", false, true},
+		{me.ID, "follow_request", true, false},
+		{defaults.ID, "like", false, true},
+		{defaults.ID, "follow", false, true},
+	}
+	for _, tt := range tests {
+		email, push, err := notificationChannels(db, tt.user, tt.notificationType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if email != tt.email || push != tt.push {
+			t.Errorf("user %d %s: email = %v, push = %v; want %v, %v", tt.user, tt.notificationType, email, push, tt.email, tt.push)
+		}
+	}
+
+	fake := &fakeNotifier{}
+	for _, tt := range []struct {
+		user             uint
+		notificationType string
+	}{
+		{me.ID, "like"},
+		{me.ID, "follow"},
+		{unverified.ID, "like"}, // doğrulanmamış adrese e-posta gitmez
+		{defaults.ID, "like"},
+	} {
+		sendOutbound(context.Background(), db, fake, tt.user, NotificationItem{ID: 1, Type: tt.notificationType})
+	}
+	if want := []string{me.Email + " like"}; !reflect.DeepEqual(fake.emails, want) {
+		t.Errorf("emails = %v, want %v", fake.emails, want)
+	}
+	if len(fake.pushes) != 0 {
+		t.Errorf("pushes = %v, want none without device tokens", fake.pushes)
+	}
+}
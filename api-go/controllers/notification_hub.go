@@ -0,0 +1,61 @@
+package controllers
+
+import "sync"
+
+// Bir kullanıcının aynı anda açık tutabileceği en fazla akış bağlantısı
+const maxStreamsPerUser = 5
+
+// Yavaş istemciler için kanal tamponu; dolarsa olay atlanır, istemci listeyi yeniden çekebilir
+const notificationStreamBuffer = 16
+
+// notificationHub is an in-process pub/sub that fans new notifications out to
+// the user's open SSE streams. It only reaches clients connected to this
+// instance; everyone else still sees the notification via GET /notifications.
+type notificationHub struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan NotificationItem]struct{}
+}
+
+var notifications = &notificationHub{
+	subscribers: make(map[uint]map[chan NotificationItem]struct{}),
+}
+
+// subscribe registers a stream for userID. ok is false when the user already
+// has maxStreamsPerUser open streams. The returned func must be called on disconnect.
+func (h *notificationHub) subscribe(userID uint) (<-chan NotificationItem, func(), bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscribers[userID]) >= maxStreamsPerUser {
+		return nil, nil, false
+	}
+
+	ch := make(chan NotificationItem, notificationStreamBuffer)
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan NotificationItem]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+	}
+	return ch, unsubscribe, true
+}
+
+// publish delivers item to every stream of userID without blocking.
+func (h *notificationHub) publish(userID uint, item NotificationItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- item:
+		default:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationHub(t *testing.T) {
+	hub := &notificationHub{subscribers: make(map[uint]map[chan NotificationItem]struct{})}
+
+	events, unsubscribe, ok := hub.subscribe(1)
+	if !ok {
+		t.Fatal("first subscribe refused")
+	}
+	other, unsubscribeOther, _ := hub.subscribe(2)
+	defer unsubscribeOther()
+
+	hub.publish(1, NotificationItem{ID: 10, Type: "like"})
+	select {
+	case item := <-events:
+		if item.ID != 10 || item.Type != "like" {
+			t.Errorf("received %+v, want notification 10", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification published after subscribe was not delivered")
+	}
+	select {
+	case item := <-other:
+		t.Errorf("other user received %+v", item)
+	default:
+	}
+
+	// Dolu tampon yayını bloklamaz, fazla olaylar atlanır
+	for i := 0; i < notificationStreamBuffer+5; i++ {
+		hub.publish(1, NotificationItem{ID: uint(i)})
+	}
+	if len(events) != notificationStreamBuffer {
+		t.Errorf("buffered events = %d, want %d", len(events), notificationStreamBuffer)
+	}
+
+	unsubscribe()
+	if _, ok := hub.subscribers[1]; ok {
+		t.Error("subscriber still registered after unsubscribe")
+	}
+
+	var unsubscribes []func()
+	for i := 0; i < maxStreamsPerUser; i++ {
+		_, unsub, ok := hub.subscribe(3)
+		if !ok {
+			t.Fatalf("subscribe %d refused", i+1)
+		}
+		unsubscribes = append(unsubscribes, unsub)
+	}
+	if _, _, ok := hub.subscribe(3); ok {
+		t.Errorf("subscribe beyond %d streams accepted", maxStreamsPerUser)
+	}
+	unsubscribes[0]()
+	if _, unsub, ok := hub.subscribe(3); !ok {
+		t.Error("subscribe after a disconnect refused")
+	} else {
+		unsub()
+	}
+}
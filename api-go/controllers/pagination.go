@@ -0,0 +1,25 @@
+package controllers
+
+import "strconv"
+
+// clampPage parses a page number; missing, invalid or non-positive values become 1.
+func clampPage(raw string) int {
+	page, err := strconv.Atoi(raw)
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+// clampPageSize parses a page size, falling back to def for missing, invalid
+// or non-positive values and capping the result at max.
+func clampPageSize(raw string, def, max int) int {
+	size, err := strconv.Atoi(raw)
+	if err != nil || size < 1 {
+		size = def
+	}
+	if size > max {
+		return max
+	}
+	return size
+}
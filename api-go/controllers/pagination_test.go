@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestClampPage(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int
+	}{
+		{"", 1},
+		{"3", 3},
+		{"0", 1},
+		{"-2", 1},
+		{"abc", 1},
+	}
+	for _, tt := range tests {
+		if got := clampPage(tt.raw); got != tt.want {
+			t.Errorf("clampPage(%q) = %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestClampPageSize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		def  int
+		max  int
+		want int
+	}{
+		{"", 20, 50, 20},
+		{"10", 20, 50, 10},
+		{"50", 20, 50, 50},
+		{"51", 20, 50, 50},
+		{"0", 20, 50, 20},
+		{"-5", 20, 50, 20},
+		{"ten", 20, 50, 20},
+		{"", 30, 25, 25}, // varsayılan da üst sınıra çekilir
+	}
+	for _, tt := range tests {
+		if got := clampPageSize(tt.raw, tt.def, tt.max); got != tt.want {
+			t.Errorf("clampPageSize(%q, %d, %d) = %d, want %d", tt.raw, tt.def, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestSearchUsersClampsPageSize(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "pagesizeuser")
+	uc := NewUserController(db, nil)
+
+	pageSize := func(query string) (int, int) {
+		t.Helper()
+		w := callHandler(uc.SearchUsers, http.MethodGet, "/users/search?q=page"+query, nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", query, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Page     int `json:"page"`
+			PageSize int `json:"pageSize"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Page, resp.PageSize
+	}
+
+	tests := []struct {
+		query    string
+		page     int
+		pageSize int
+	}{
+		{"&pageSize=100000", 1, 50},
+		{"&pageSize=0", 1, 20},
+		{"&pageSize=-3&page=-1", 1, 20},
+		{"&pageSize=10&page=2", 2, 10},
+	}
+	for _, tt := range tests {
+		if page, size := pageSize(tt.query); page != tt.page || size != tt.pageSize {
+			t.Errorf("%s: page %d, pageSize %d; want %d, %d", tt.query, page, size, tt.page, tt.pageSize)
+		}
+	}
+
+	t.Setenv("MAX_PAGE_SIZE", "5")
+	if _, size := pageSize("&pageSize=30"); size != 5 {
+		t.Errorf("with MAX_PAGE_SIZE=5: pageSize %d, want 5", size)
+	}
+}
@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Canlı akış yeni aktiviteleri bu aralıkla veritabanından çeker; böylece
+// başka sunuculardan gelen aktiviteler de görünür
+const placeActivityPollInterval = 5 * time.Second
+
+// placeActivityKinds mekan sayfasındaki akışta gösterilen aktivite tipleri
+var placeActivityKinds = []string{"post_created", "place_discovered", "place_visited"}
+
+// PlaceActivityQuery; Since bir RFC3339 zaman damgası ya da istemcideki en yeni aktivitenin ID'sidir
+type PlaceActivityQuery struct {
+	Since string `form:"since"`
+	Limit int    `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// PlaceActivityItem is one entry of a place's recent activity. PostID is set
+// for post activities and omitted for check-ins.
+type PlaceActivityItem struct {
+	ID        uint      `json:"id"`
+	Kind      string    `json:"kind"` // activity tipi, ör. post_created
+	CreatedAt time.Time `json:"createdAt"`
+	PostID    *uint     `json:"postId,omitempty"`
+	Points    int       `json:"points"`
+	User      PostUser  `json:"user"`
+}
+
+// placeActivitySinceCondition builds the filter for activity newer than since,
+// either an RFC3339 timestamp or the ID of the newest activity the client has.
+func placeActivitySinceCondition(db *gorm.DB, since string) (string, []interface{}, error) {
+	if activityID, err := strconv.ParseUint(since, 10, 64); err == nil {
+		var activity models.ActivityLog
+		if err := db.Unscoped().Select("id, created_at").First(&activity, activityID).Error; err != nil {
+			return "", nil, errors.New("since activity not found")
+		}
+		// Aynı zaman damgalı aktiviteler ID ile ayrılır
+		return "(activity_logs.created_at > ? OR (activity_logs.created_at = ? AND activity_logs.id > ?))",
+			[]interface{}{activity.CreatedAt, activity.CreatedAt, activity.ID}, nil
+	}
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return "", nil, errors.New("since must be an RFC3339 timestamp or an activity ID")
+	}
+	return "activity_logs.created_at > ?", []interface{}{sinceTime}, nil
+}
+
+// visibleCheckInCondition limits post-less activity (check-ins) to actors the
+// viewer may see: not blocked either way, and public or followed. The query
+// must join users on activity_logs.user_id.
+func visibleCheckInCondition(viewerID uint) (string, []interface{}) {
+	condition := `(activity_logs.user_id = ? OR (
+		NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+			((blocks.blocker_user_id = ? AND blocks.blocked_user_id = activity_logs.user_id) OR
+			 (blocks.blocker_user_id = activity_logs.user_id AND blocks.blocked_user_id = ?)))
+		AND (
+			users.is_private = false
+			OR EXISTS(SELECT 1 FROM follows WHERE follows.deleted_at IS NULL
+				AND follows.follower_user_id = ? AND follows.following_user_id = activity_logs.user_id
+				AND follows.status = 'accepted')
+		)
+	))`
+	return condition, []interface{}{viewerID, viewerID, viewerID, viewerID}
+}
+
+// placeActivity returns the place's activity visible to the viewer, newest
+// first. Post activities follow the post's visibility; a deleted post hides them.
+func (pc *PlaceController) placeActivity(viewerID, placeID uint, sinceClause string, sinceArgs []interface{}, limit int) ([]PlaceActivityItem, error) {
+	checkInVisibility, checkInArgs := visibleCheckInCondition(viewerID)
+	postVisibility, postArgs := visiblePostsCondition(viewerID)
+	muted, mutedArgs := notMutedCondition("activity_logs.user_id", viewerID)
+
+	query := pc.DB.Table("activity_logs").
+		Select(`activity_logs.id, activity_logs.activity, activity_logs.created_at, activity_logs.post_id, activity_logs.points,
+			users.id AS user_id, users.username, users.first_name, users.last_name, users.avatar`).
+		// Gönderi sahibi aktiviteyi yapan kullanıcıdır; görünürlük koşulları bu birleştirmeye dayanır
+		Joins("JOIN users ON users.id = activity_logs.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN posts ON posts.id = activity_logs.post_id AND posts.deleted_at IS NULL").
+		Where("activity_logs.deleted_at IS NULL AND activity_logs.place_id = ? AND activity_logs.activity IN ?", placeID, placeActivityKinds).
+		Where("(COALESCE(activity_logs.post_id, 0) = 0 AND "+checkInVisibility+") OR (posts.id IS NOT NULL AND "+postVisibility+")",
+			append(checkInArgs, postArgs...)...).
+		Where(muted, mutedArgs...)
+	if sinceClause != "" {
+		query = query.Where(sinceClause, sinceArgs...)
+	}
+
+	var rows []struct {
+		ID        uint      `gorm:"column:id"`
+		Activity  string    `gorm:"column:activity"`
+		CreatedAt time.Time `gorm:"column:created_at"`
+		PostID    uint      `gorm:"column:post_id"`
+		Points    int       `gorm:"column:points"`
+		UserID    uint      `gorm:"column:user_id"`
+		Username  string    `gorm:"column:username"`
+		FirstName string    `gorm:"column:first_name"`
+		LastName  string    `gorm:"column:last_name"`
+		Avatar    string    `gorm:"column:avatar"`
+	}
+	if err := query.
+		Order("activity_logs.created_at DESC, activity_logs.id DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]PlaceActivityItem, len(rows))
+	for i, row := range rows {
+		items[i] = PlaceActivityItem{
+			ID:        row.ID,
+			Kind:      row.Activity,
+			CreatedAt: row.CreatedAt,
+			Points:    row.Points,
+			User: PostUser{
+				ID:        row.UserID,
+				Username:  row.Username,
+				FirstName: row.FirstName,
+				LastName:  row.LastName,
+				Avatar:    row.Avatar,
+			},
+		}
+		if row.PostID != 0 {
+			postID := row.PostID
+			items[i].PostID = &postID
+		}
+	}
+	return items, nil
+}
+
+// activePlaceID resolves the placeId path parameter to a place that is not awaiting review
+func (pc *PlaceController) activePlaceID(c *gin.Context) (uint, bool) {
+	placeID, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Place ID must be a valid number"})
+		return 0, false
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id").Where("id = ? AND needs_review = false", placeID).First(&place).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return 0, false
+	}
+	return place.ID, true
+}
+
+// GetPlaceRecentActivity godoc
+// @Summary Get recent activity at a place
+// @Description Returns posts, discoveries and check-ins at the place, newest first, with the acting user. Activity of blocked, muted or otherwise hidden users is left out. Pass since (an RFC3339 timestamp or the latestId of the previous response) to poll for newer activity only.
+// @Tags places
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param since query string false "Only activity after this RFC3339 timestamp or activity ID"
+// @Param limit query integer false "Maximum items (default: 20, max: 50)"
+// @Success 200 {object} StandardResponse{data=[]PlaceActivityItem}
+// @Router /places/{placeId}/recent-activity [get]
+func (pc *PlaceController) GetPlaceRecentActivity(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var query PlaceActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var sinceClause string
+	var sinceArgs []interface{}
+	if query.Since != "" {
+		var err error
+		if sinceClause, sinceArgs, err = placeActivitySinceCondition(pc.DB, query.Since); err != nil {
+			c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+			return
+		}
+	}
+
+	placeID, ok := pc.activePlaceID(c)
+	if !ok {
+		return
+	}
+
+	items, err := pc.placeActivity(user.UserID, placeID, sinceClause, sinceArgs, query.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching recent activity"})
+		return
+	}
+
+	// İstemci bir sonraki istekte since olarak latestId'yi gönderir
+	var latestID *uint
+	if len(items) > 0 {
+		latestID = &items[0].ID
+	}
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    items,
+		Meta:    gin.H{"latestId": latestID},
+	})
+}
+
+// streamCounter caps how many streams of one kind a user may keep open
+type streamCounter struct {
+	mu     sync.Mutex
+	counts map[uint]int
+}
+
+var placeActivityStreams = &streamCounter{counts: make(map[uint]int)}
+
+// acquire reserves a stream for userID; false when maxStreamsPerUser are already open
+func (s *streamCounter) acquire(userID uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts[userID] >= maxStreamsPerUser {
+		return false
+	}
+	s.counts[userID]++
+	return true
+}
+
+func (s *streamCounter) release(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts[userID]--; s.counts[userID] <= 0 {
+		delete(s.counts, userID)
+	}
+}
+
+// StreamPlaceRecentActivity godoc
+// @Summary Stream new activity at a place with Server-Sent Events
+// @Description Sends a "ready" event with the latest activity ID, then an "activity" event per new visible activity, oldest first, and a "ping" event periodically. New activity is picked up within a few seconds. The access token may be passed as the token query parameter. Clients should fall back to polling recent-activity when the stream is unavailable.
+// @Tags places
+// @Produce text/event-stream
+// @Param placeId path string true "Place ID"
+// @Param token query string false "Access token when the Authorization header cannot be set"
+// @Success 200 {string} string "event stream"
+// @Router /places/{placeId}/recent-activity/stream [get]
+func (pc *PlaceController) StreamPlaceRecentActivity(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	placeID, ok := pc.activePlaceID(c)
+	if !ok {
+		return
+	}
+
+	if !placeActivityStreams.acquire(user.UserID) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many open activity streams"})
+		return
+	}
+	defer placeActivityStreams.release(user.UserID)
+
+	// Akış, bağlantı anındaki en yeni aktiviteden sonrasını gönderir
+	var latest models.ActivityLog
+	if err := pc.DB.Select("id, created_at").Where("place_id = ?", placeID).
+		Order("created_at DESC, id DESC").Limit(1).Find(&latest).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching recent activity"})
+		return
+	}
+	sinceClause, sinceArgs := "activity_logs.created_at > ?", []interface{}{time.Now()}
+	if latest.ID != 0 {
+		sinceClause = "(activity_logs.created_at > ? OR (activity_logs.created_at = ? AND activity_logs.id > ?))"
+		sinceArgs = []interface{}{latest.CreatedAt, latest.CreatedAt, latest.ID}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("ready", gin.H{"latestId": latest.ID})
+	c.Writer.Flush()
+
+	poll := time.NewTicker(placeActivityPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(notificationHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-poll.C:
+			items, err := pc.placeActivity(user.UserID, placeID, sinceClause, sinceArgs, 50)
+			if err != nil || len(items) == 0 {
+				continue
+			}
+			for i := len(items) - 1; i >= 0; i-- {
+				c.SSEvent("activity", items[i])
+			}
+			c.Writer.Flush()
+			newest := items[0]
+			sinceClause = "(activity_logs.created_at > ? OR (activity_logs.created_at = ? AND activity_logs.id > ?))"
+			sinceArgs = []interface{}{newest.CreatedAt, newest.CreatedAt, newest.ID}
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+			c.Writer.Flush()
+		}
+	}
+}
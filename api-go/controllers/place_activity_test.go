@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetPlaceRecentActivity(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "activityviewer")
+	author := createTestUser(t, db, "activityauthor")
+	blocked := createTestUser(t, db, "activityblocked")
+	place := createTestPlace(t, db, "activityplace")
+	if err := db.Create(&models.Block{BlockerUserID: viewer.ID, BlockedUserID: blocked.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	logActivity := func(user models.User, kind string, post *models.Post, at time.Time) models.ActivityLog {
+		t.Helper()
+		activity := models.ActivityLog{
+			UserID: user.ID, PlaceID: place.ID, Activity: kind, Points: 5,
+			Latitude: place.Latitude, Longitude: place.Longitude, CreatedAt: at,
+		}
+		if post != nil {
+			activity.PostID = post.ID
+		}
+		if err := db.Create(&activity).Error; err != nil {
+			t.Fatal(err)
+		}
+		return activity
+	}
+
+	oldPost := createTestPost(t, db, author, place, "old", true)
+	old := logActivity(author, "post_created", &oldPost, now.Add(-2*time.Hour))
+	newPost := createTestPost(t, db, author, place, "new", true)
+	posted := logActivity(author, "post_created", &newPost, now.Add(-20*time.Minute))
+	checkIn := logActivity(author, "place_visited", nil, now.Add(-10*time.Minute))
+	blockedPost := createTestPost(t, db, blocked, place, "blocked", true)
+	logActivity(blocked, "post_created", &blockedPost, now.Add(-5*time.Minute))
+	logActivity(blocked, "place_visited", nil, now.Add(-4*time.Minute))
+	// Takipçilere özel gönderinin aktivitesi de gizlenir
+	hiddenPost := createTestPost(t, db, author, place, "hidden", false)
+	logActivity(author, "post_created", &hiddenPost, now.Add(-3*time.Minute))
+
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	recent := func(since string) []PlaceActivityItem {
+		t.Helper()
+		target := "/places/" + param.Value + "/recent-activity?since=" + url.QueryEscape(since)
+		w := callHandler(pc.GetPlaceRecentActivity, http.MethodGet, target, nil, viewer.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("since %q: status = %d, body = %s", since, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []PlaceActivityItem `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+
+	items := recent(now.Add(-time.Hour).Format(time.RFC3339))
+	if len(items) != 2 || items[0].ID != checkIn.ID || items[1].ID != posted.ID {
+		t.Fatalf("activity since an hour ago = %+v, want the check-in then the new post", items)
+	}
+	if items[0].PostID != nil || items[0].User.Username != author.Username {
+		t.Errorf("check-in = %+v, want no post and the author", items[0])
+	}
+	if items[1].PostID == nil || *items[1].PostID != newPost.ID {
+		t.Errorf("post activity = %+v, want post %d", items[1], newPost.ID)
+	}
+
+	if items := recent(strconv.Itoa(int(old.ID))); len(items) != 2 {
+		t.Errorf("activity since the old post = %+v, want 2 items", items)
+	}
+	if items := recent(""); len(items) != 3 {
+		t.Errorf("all activity = %+v, want 3 items", items)
+	}
+
+	if w := callHandler(pc.GetPlaceRecentActivity, http.MethodGet, "/places/"+param.Value+"/recent-activity?since=yesterday", nil, viewer.ID, param); w.Code != http.StatusBadRequest {
+		t.Errorf("bad since: status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Kategoriler normalize edilirken mekanlar bu boyutta gruplar halinde okunur
+const placeCategoriesBatchSize = 500
+
+// normalizePlaceCategories re-runs types.NormalizeCategories over every stored
+// place and writes the places whose categories change. The raw Google types
+// are the source; places saved before google_types existed kept the raw types
+// in categories, so those are moved to google_types first. Returns the number
+// of places updated.
+func normalizePlaceCategories(tx *gorm.DB) (int64, error) {
+	type placeCategories struct {
+		id          uint
+		categories  pq.StringArray
+		googleTypes pq.StringArray
+	}
+	var changes []placeCategories
+	var places []models.Place
+	result := tx.Model(&models.Place{}).
+		Select("id, categories, google_types").
+		FindInBatches(&places, placeCategoriesBatchSize, func(batch *gorm.DB, _ int) error {
+			for _, place := range places {
+				googleTypes := place.GoogleTypes
+				legacy := len(googleTypes) == 0 && len(place.Categories) > 0
+				if legacy {
+					googleTypes = place.Categories
+				}
+				categories := pq.StringArray(types.NormalizeCategories(googleTypes))
+				if !legacy && slices.Equal(categories, place.Categories) {
+					continue
+				}
+				changes = append(changes, placeCategories{id: place.ID, categories: categories, googleTypes: googleTypes})
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// Okuma bittikten sonra yazılır; toplu okuma sırasında güncelleme yapılmaz
+	for _, change := range changes {
+		if err := tx.Model(&models.Place{}).Where("id = ?", change.id).Updates(map[string]interface{}{
+			"categories":   change.categories,
+			"google_types": change.googleTypes,
+		}).Error; err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(changes)), nil
+}
+
+// NormalizePlaceCategories godoc
+// @Summary Normalize stored place categories (admin)
+// @Description Re-maps every place's raw Google types to app categories with the current taxonomy, e.g. for places saved before a type was mapped, then recomputes base points from the new categories.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/normalize-categories [post]
+func (pc *PlaceController) NormalizePlaceCategories(c *gin.Context) {
+	tx := pc.DB.Begin()
+
+	updated, err := normalizePlaceCategories(tx)
+	var pointChanges []PlacePointsChange
+	if err == nil {
+		// Kategoriler değişince puanlar da yeni kategorilerle hesaplanır
+		pointChanges, _, err = recomputePlacePoints(tx, false)
+	}
+	if err == nil {
+		err = recordAdminAction(tx, utils.GetUser(c).UserID, "place_categories_normalize", "system", 0, gin.H{"updated": updated, "pointsChanged": len(pointChanges)})
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to normalize place categories"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to normalize place categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"updated": updated, "pointsChanged": len(pointChanges)},
+		Message: "Place categories normalized",
+	})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestNormalizePlaceCategories(t *testing.T) {
+	db := openTestDB(t)
+	legacy := createTestPlace(t, db, "legacycategories")
+	imported := createTestPlace(t, db, "importedcategories")
+	current := createTestPlace(t, db, "currentcategories")
+	if err := db.Model(&legacy).Update("categories", pq.StringArray{"gas_station", "point_of_interest"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&imported).Updates(map[string]interface{}{
+		"categories":   pq.StringArray{"lodging"},
+		"google_types": pq.StringArray{"lodging", "establishment"},
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&current).Updates(map[string]interface{}{
+		"categories":   pq.StringArray{"museum"},
+		"google_types": pq.StringArray{"museum", "point_of_interest"},
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := normalizePlaceCategories(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("updated = %d, want 2", updated)
+	}
+
+	tests := []struct {
+		name        string
+		id          uint
+		categories  []string
+		googleTypes []string
+	}{
+		{"legacy raw categories", legacy.ID, []string{"gas_station"}, []string{"gas_station", "point_of_interest"}},
+		{"stale categories", imported.ID, []string{"hotel"}, []string{"lodging", "establishment"}},
+		{"already normalized", current.ID, []string{"museum"}, []string{"museum", "point_of_interest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var place models.Place
+			if err := db.Select("id, categories, google_types").First(&place, tt.id).Error; err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(place.Categories, tt.categories) {
+				t.Errorf("categories = %v, want %v", place.Categories, tt.categories)
+			}
+			if !slices.Equal(place.GoogleTypes, tt.googleTypes) {
+				t.Errorf("google_types = %v, want %v", place.GoogleTypes, tt.googleTypes)
+			}
+		})
+	}
+
+	if updated, err := normalizePlaceCategories(db); err != nil || updated != 0 {
+		t.Errorf("second run: updated = %d, err = %v, want 0", updated, err)
+	}
+}
@@ -1,7 +1,7 @@
 package controllers
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"math"
@@ -13,6 +13,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
 	"github.com/snap-point/api-go/types"
 	"github.com/snap-point/api-go/utils"
@@ -21,7 +22,8 @@ import (
 )
 
 type PlaceController struct {
-	DB *gorm.DB
+	DB               *gorm.DB
+	UploadController *UploadController
 }
 
 type NearbyPlacesQuery struct {
@@ -32,6 +34,8 @@ type NearbyPlacesQuery struct {
 	HideVisited    bool    `form:"hideVisited"`
 	CategoryFilter string  `form:"category"`
 	MaxPlaces      int     `form:"maxPlaces"`
+	// MinRating altındaki mekanlar gizlenir; puansız mekanlar NULL_RATING_POLICY'ye göre ele alınır
+	MinRating *float64 `form:"minRating" binding:"omitempty,min=0,max=5"`
 }
 
 type PlacePostsQuery struct {
@@ -41,8 +45,23 @@ type PlacePostsQuery struct {
 	TimeFrame string `form:"timeFrame" binding:"omitempty,oneof=today this_week this_month all_time"`
 }
 
-func NewPlaceController(db *gorm.DB) *PlaceController {
-	return &PlaceController{DB: db}
+type CheckInRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required"`
+	Longitude float64 `json:"longitude" binding:"required"`
+}
+
+// PostRadiusOverrideRequest; Radius nil ise özel yarıçap kaldırılır
+type PostRadiusOverrideRequest struct {
+	Radius *int `json:"radius" binding:"omitempty,min=10,max=10000"` // meters
+}
+
+// PlaceReviewRequest; approve mekanı doğrular, reject mekanı gizler (soft delete)
+type PlaceReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject"`
+}
+
+func NewPlaceController(db *gorm.DB, uploadController *UploadController) *PlaceController {
+	return &PlaceController{DB: db, UploadController: uploadController}
 }
 
 type SimplifiedPlace struct {
@@ -72,6 +91,7 @@ type SimplifiedPlace struct {
 // @Param userId query integer false "User ID (required if hideVisited is true)"
 // @Param category query string false "Filter by category"
 // @Param maxPlaces query integer false "Maximum number of places to return"
+// @Param minRating query number false "Hide places rated below this (0-5); unrated places follow NULL_RATING_POLICY"
 // @Success 200 {object} types.NearbyPlacesResponse
 // @Router /places/nearby [get]
 func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
@@ -94,6 +114,10 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 		query.HideVisited = parseBool(c.Query("params[hideVisited]"))
 		query.CategoryFilter = c.Query("params[category]")
 		query.MaxPlaces = parseInt(c.Query("params[maxPlaces]"))
+		if minRating := c.Query("params[minRating]"); minRating != "" {
+			value := parseFloat(minRating)
+			query.MinRating = &value
+		}
 		
 		// Validate required fields
 		if query.Latitude == 0 || query.Longitude == 0 || query.ZoomLevel == 0 {
@@ -118,6 +142,13 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 			})
 			return
 		}
+
+		if query.MinRating != nil && (*query.MinRating < 0 || *query.MinRating > 5) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "minRating must be between 0 and 5",
+			})
+			return
+		}
 	}
 
 	// Use user-provided coordinates and radius
@@ -169,11 +200,25 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 			(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) + sin(radians(?)) * sin(radians(latitude)))) AS distance`,
 			user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints, latitude, longitude, latitude).
 		Where("(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) + sin(radians(?)) * sin(radians(latitude)))) <= ?",
-			latitude, longitude, latitude, radius)
+			latitude, longitude, latitude, radius).
+		Where("needs_review = false")
 
 	// Apply category filter if provided
 	if query.CategoryFilter != "" {
-		db = db.Where("? = ANY(categories)", query.CategoryFilter)
+		db = db.Where("? = ANY(categories)", types.NormalizeCategory(query.CategoryFilter))
+	}
+
+	filters := types.NearbyPlacesFilters{
+		Radius:      radius,
+		ZoomLevel:   query.ZoomLevel,
+		HideVisited: query.HideVisited,
+		Category:    query.CategoryFilter,
+	}
+	if query.MinRating != nil {
+		condition, args, policy := minRatingCondition("rating", *query.MinRating)
+		db = db.Where(condition, args...)
+		filters.MinRating = query.MinRating
+		filters.NullRating = policy
 	}
 
 	// Order by distance and limit results
@@ -188,6 +233,9 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 		IsVerified bool           `json:"is_verified"`
 		Distance   float64        `json:"distance"`
 		Categories pq.StringArray `json:"categories"`
+		IsFavorite bool           `json:"is_favorite"`
+		// Yöneticinin belirlediği özel yarıçap (metre)
+		PostRadiusOverride *int `json:"-"`
 	}
 	
 	result := db.Select(`id, latitude, longitude, 
@@ -200,13 +248,14 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 		END as point_value, 
 		is_verified, 
 		(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) + sin(radians(?)) * sin(radians(latitude)))) AS distance,
-		categories`,
-		user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints, latitude, longitude, latitude).Find(&places)
+		categories, post_radius_override,
+		EXISTS(SELECT 1 FROM favorite_places WHERE favorite_places.place_id = places.id AND favorite_places.user_id = ?) AS is_favorite`,
+		user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints, latitude, longitude, latitude, user.UserID).Find(&places)
 	
 	// Markers'ı yarıçap bilgileriyle birlikte oluştur
 	var markers []types.PlaceWithRadius
 	for _, place := range places {
-		postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadius(place.Categories)
+		postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadiusWithOverride(place.Categories, place.PostRadiusOverride)
 		
 		marker := types.PlaceWithRadius{
 			ID:                place.ID,
@@ -219,6 +268,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 			CoverageArea:      coverageArea,
 			RadiusType:        radiusType,
 			RadiusDescription: radiusDescription,
+			IsFavorite:        place.IsFavorite,
 		}
 		markers = append(markers, marker)
 	}
@@ -226,7 +276,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 	if result.RowsAffected < 20 {
 		// Google Places API'den yeni yerler al ve kaydet
 		log.Printf("Attempting to fetch places from Google Places API for location: %f,%f with radius: %f", latitude, longitude, radius)
-		err := fetchAndSaveFromGooglePlaces(pc.DB, latitude, longitude, radius)
+		err := fetchAndSaveFromGooglePlaces(c.Request.Context(), pc.DB, latitude, longitude, radius)
 		if err != nil {
 			log.Printf("Google Places API error: %v", err)
 			// API hatası durumunda graceful fallback - mevcut verilerle devam et
@@ -237,17 +287,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 				log.Printf("No existing markers found, returning empty result")
 				response := types.NearbyPlacesResponse{
 					Markers: []types.PlaceWithRadius{},
-					Filters: struct {
-						Radius      float64 `json:"radius"`
-						ZoomLevel   int     `json:"zoomLevel"`
-						HideVisited bool    `json:"hideVisited"`
-						Category    string  `json:"category"`
-					}{
-						Radius:      radius,
-						ZoomLevel:   query.ZoomLevel,
-						HideVisited: query.HideVisited,
-						Category:    query.CategoryFilter,
-					},
+					Filters: filters,
 				}
 				c.JSON(http.StatusOK, response)
 				return
@@ -264,6 +304,9 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 				IsVerified bool           `json:"is_verified"`
 				Distance   float64        `json:"distance"`
 				Categories pq.StringArray `json:"categories"`
+				IsFavorite bool           `json:"is_favorite"`
+				// Yöneticinin belirlediği özel yarıçap (metre)
+				PostRadiusOverride *int `json:"-"`
 			}{}
 			result = db.Select(`id, latitude, longitude, 
 				CASE 
@@ -275,8 +318,9 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 				END as point_value, 
 				is_verified, 
 				(6371 * acos(cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) + sin(radians(?)) * sin(radians(latitude)))) AS distance,
-				categories`,
-				user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints, latitude, longitude, latitude).Find(&places)
+				categories, post_radius_override,
+				EXISTS(SELECT 1 FROM favorite_places WHERE favorite_places.place_id = places.id AND favorite_places.user_id = ?) AS is_favorite`,
+				user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints, latitude, longitude, latitude, user.UserID).Find(&places)
 			if result.Error != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching updated places"})
 				return
@@ -285,7 +329,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 			// Güncellenmiş markers'ı oluştur
 			markers = []types.PlaceWithRadius{}
 			for _, place := range places {
-				postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadius(place.Categories)
+				postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadiusWithOverride(place.Categories, place.PostRadiusOverride)
 				
 				marker := types.PlaceWithRadius{
 					ID:                place.ID,
@@ -298,6 +342,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 					CoverageArea:      coverageArea,
 					RadiusType:        radiusType,
 					RadiusDescription: radiusDescription,
+					IsFavorite:        place.IsFavorite,
 				}
 				markers = append(markers, marker)
 			}
@@ -312,17 +357,7 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 
 	response := types.NearbyPlacesResponse{
 		Markers: markers,
-		Filters: struct {
-			Radius      float64 `json:"radius"`
-			ZoomLevel   int     `json:"zoomLevel"`
-			HideVisited bool    `json:"hideVisited"`
-			Category    string  `json:"category"`
-		}{
-			Radius:      radius,
-			ZoomLevel:   query.ZoomLevel,
-			HideVisited: query.HideVisited,
-			Category:    query.CategoryFilter,
-		},
+		Filters: filters,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -330,11 +365,14 @@ func (pc *PlaceController) GetNearbyPlaces(c *gin.Context) {
 
 
 
-func fetchAndSaveFromGooglePlaces(db *gorm.DB, lat, lng, radius float64) error {
-	return fetchAndSaveFromGooglePlacesWithToken(db, lat, lng, radius, "", 0)
+func fetchAndSaveFromGooglePlaces(ctx context.Context, db *gorm.DB, lat, lng, radius float64) error {
+	return fetchAndSaveFromGooglePlacesWithToken(ctx, db, lat, lng, radius, "", 0)
 }
 
-func fetchAndSaveFromGooglePlacesWithToken(db *gorm.DB, lat, lng, radius float64, pageToken string, pageCount int) error {
+// fetchAndSaveFromGooglePlacesWithToken imports one page of nearby places and
+// follows the next page token. ctx is the caller's request context: when the
+// client goes away the fetch stops instead of holding the goroutine.
+func fetchAndSaveFromGooglePlacesWithToken(ctx context.Context, db *gorm.DB, lat, lng, radius float64, pageToken string, pageCount int) error {
 	// Maksimum sayfa sayısını sınırla (rate limiting için)
 	if pageCount >= 3 {
 		return nil
@@ -349,35 +387,31 @@ func fetchAndSaveFromGooglePlacesWithToken(db *gorm.DB, lat, lng, radius float64
 	// Google Places API URL hazırla
 	var url string
 	if pageToken != "" {
-		url = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/nearbysearch/json?pagetoken=%s&key=%s", pageToken, apiKey)
+		url = fmt.Sprintf("%s?pagetoken=%s&key=%s", googlePlacesNearbyURL, pageToken, apiKey)
 	} else {
 		// radius kilometre cinsinden geldiği için metre'ye çevir
 		radiusInMeters := radius * 1000
 		if radiusInMeters > 50000 { // Google API max 50km
 			radiusInMeters = 50000
 		}
-		url = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/nearbysearch/json?location=%f,%f&radius=%.0f&key=%s", lat, lng, radiusInMeters, apiKey)
+		url = fmt.Sprintf("%s?location=%f,%f&radius=%.0f&key=%s", googlePlacesNearbyURL, lat, lng, radiusInMeters, apiKey)
 	}
 
 	// NextPageToken kullanıyorsak kısa bir bekleme süresi ekle (Google'ın önerisi)
 	if pageToken != "" {
-		time.Sleep(2 * time.Second)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
 	}
 
 	fmt.Printf("Fetching page %d, URL: %s\n", pageCount+1, url)
 	
-	// HTTP GET isteği gönder
-	resp, err := http.Get(url)
+	// Zaman aşımı ve geçici hatalarda yeniden denenir
+	apiResponse, err := getGooglePlaces(ctx, url)
 	if err != nil {
-		return fmt.Errorf("error calling Google Places API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Cevabı çözümle
-	var apiResponse types.GooglePlacesResponse
-
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return fmt.Errorf("error decoding API response: %w", err)
+		return err
 	}
 
 	// API response status kontrolü
@@ -441,82 +475,53 @@ func fetchAndSaveFromGooglePlacesWithToken(db *gorm.DB, lat, lng, radius float64
 	
 	// Seçilen yerleri veritabanına kaydet
 	savedCount := 0
+	flaggedCount := 0
 	filteredCount := len(apiResponse.Results) - len(candidatePlaces)
 	clusteredCount := len(candidatePlaces) - len(selectedPlaces)
 	
 	for _, place := range selectedPlaces {
+		dbPlace := placeFromGoogleResult(place)
+
 		// Seçilen yeri mevcut listesine ekle
 		existingPlaces = append(existingPlaces, types.PlaceForClustering{
-			Latitude:   place.Geometry.Location.Lat,
-			Longitude:  place.Geometry.Location.Lng,
-			Categories: place.Types,
-			Rating:     place.Rating,
-			Name:       place.Name,
+			Latitude:   dbPlace.Latitude,
+			Longitude:  dbPlace.Longitude,
+			Categories: []string(dbPlace.Categories),
+			Rating:     dbPlace.Rating,
+			Name:       dbPlace.Name,
 		})
-		// Kategori bilgilerini al
-		categories := pq.StringArray(place.Types)
-		
-		// Adres bilgisini vicinity'den al
-		address := ""
-		if place.Vicinity != nil {
-			address = *place.Vicinity
-		}
 
-		// Fotoğraf referanslarını al
-		var photoReferences pq.StringArray
-		for _, photo := range place.Photos {
-			photoReferences = append(photoReferences, photo.PhotoReference)
-		}
-
-		// Plus code bilgilerini al
-		plusCode := ""
-		if place.PlusCode != nil {
-			plusCode = place.PlusCode.GlobalCode
-		}
-
-		// Business status kontrolü
-		businessStatus := ""
-		if place.BusinessStatus != nil {
-			businessStatus = *place.BusinessStatus
+		// Uygunsuz isimli mekanlar sessizce kaydedilmez, yönetici onayına düşer
+		needsReview := false
+		if word, banned := utils.FindBannedWord(place.Name); banned {
+			// Yöneticinin daha önce onayladığı mekan tekrar kuyruğa alınmaz
+			var approved int64
+			db.Model(&models.Place{}).Where("google_place_id = ? AND is_verified = true", place.PlaceID).Count(&approved)
+			if approved == 0 {
+				needsReview = true
+				flaggedCount++
+				log.Printf("Place name flagged for review: google_place_id=%s name=%q matched=%q", place.PlaceID, place.Name, word)
+			}
 		}
 
-		// Gelişmiş puan hesaplama sistemi
-		basePoints := types.CalculatePlacePoints(place.Types, place.Rating, place.UserRatingsTotal)
-
-		// Handle opening hours - set to nil if not available
-		var openingHours *string
-		if place.OpeningHours != nil {
-			// Convert opening hours to JSON string if available
-			jsonStr := `{"periods":[],"weekday_text":[]}`
-			openingHours = &jsonStr
+		updateColumns := []string{
+			"name", "latitude", "longitude", "address", "categories", "google_types",
+			"rating", "user_ratings_total", "business_status", "icon",
+			"photo_references", "plus_code", "updated_at",
 		}
-
-		dbPlace := models.Place{
-			Name:              place.Name,
-			Latitude:          place.Geometry.Location.Lat,
-			Longitude:         place.Geometry.Location.Lng,
-			Address:           address,
-			PlaceType:         "google_place",
-			Categories:        categories,
-			BasePoints:        basePoints,
-			GooglePlaceID:     place.PlaceID,
-			Rating:            place.Rating,
-			UserRatingsTotal:  place.UserRatingsTotal,
-			BusinessStatus:    businessStatus,
-			Icon:              place.Icon,
-			PhotoReferences:   photoReferences,
-			PlusCode:          plusCode,
-			OpeningHours:      openingHours,
+		if needsReview {
+			updateColumns = append(updateColumns, "needs_review")
 		}
+		dbPlace.NeedsReview = needsReview
 
-		// Google Place ID ile çakışma varsa güncelle, yoksa ekle
+		// Google Place ID ile çakışma varsa güncelle, yoksa ekle; mevcut görsel korunur
+		assignments := append(clause.AssignmentColumns(updateColumns), clause.Assignment{
+			Column: clause.Column{Name: "place_image"},
+			Value:  gorm.Expr("COALESCE(NULLIF(places.place_image, ''), excluded.place_image)"),
+		})
 		result := db.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "google_place_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{
-				"name", "latitude", "longitude", "address", "categories",
-				"rating", "user_ratings_total", "business_status", "icon",
-				"photo_references", "plus_code", "updated_at",
-			}),
+			DoUpdates: clause.Set(assignments),
 		}).Create(&dbPlace)
 		
 		if result.Error != nil {
@@ -526,21 +531,80 @@ func fetchAndSaveFromGooglePlacesWithToken(db *gorm.DB, lat, lng, radius float64
 		}
 	}
 
-	log.Printf("Page %d results: %d total, %d filtered, %d clustered, %d saved using smart distribution algorithm", 
-		pageCount+1, len(apiResponse.Results), filteredCount, clusteredCount, savedCount)
+	log.Printf("Page %d results: %d total, %d filtered, %d clustered, %d saved (%d flagged for review) using smart distribution algorithm",
+		pageCount+1, len(apiResponse.Results), filteredCount, clusteredCount, savedCount, flaggedCount)
 
 	// NextPageToken varsa ve daha fazla sayfa alınabiliyorsa, bir sonraki sayfayı al
 	if apiResponse.NextPageToken != "" && pageCount < 2 {
 		log.Printf("NextPageToken found, fetching next page...")
-		return fetchAndSaveFromGooglePlacesWithToken(db, lat, lng, radius, apiResponse.NextPageToken, pageCount+1)
+		return fetchAndSaveFromGooglePlacesWithToken(ctx, db, lat, lng, radius, apiResponse.NextPageToken, pageCount+1)
 	}
 
 	return nil
 }
 
+// placeFromGoogleResult builds the place stored for a Google Places result. Google
+// types are kept as they are in GoogleTypes; Categories and base points use the
+// normalized app categories.
+func placeFromGoogleResult(place types.GooglePlaceResult) models.Place {
+	categories := pq.StringArray(types.NormalizeCategories(place.Types))
+
+	// Adres bilgisini vicinity'den al
+	address := ""
+	if place.Vicinity != nil {
+		address = *place.Vicinity
+	}
+
+	// Fotoğraf referanslarını al
+	var photoReferences pq.StringArray
+	for _, photo := range place.Photos {
+		photoReferences = append(photoReferences, photo.PhotoReference)
+	}
+
+	// Plus code bilgilerini al
+	plusCode := ""
+	if place.PlusCode != nil {
+		plusCode = place.PlusCode.GlobalCode
+	}
+
+	// Business status kontrolü
+	businessStatus := ""
+	if place.BusinessStatus != nil {
+		businessStatus = *place.BusinessStatus
+	}
+
+	// Handle opening hours - set to nil if not available
+	var openingHours *string
+	if place.OpeningHours != nil {
+		// Convert opening hours to JSON string if available
+		jsonStr := `{"periods":[],"weekday_text":[]}`
+		openingHours = &jsonStr
+	}
+
+	return models.Place{
+		Name:             place.Name,
+		Latitude:         place.Geometry.Location.Lat,
+		Longitude:        place.Geometry.Location.Lng,
+		Address:          address,
+		PlaceType:        "google_place",
+		Categories:       categories,
+		GoogleTypes:      pq.StringArray(place.Types),
+		BasePoints:       types.CalculatePlacePoints(categories, place.Rating, place.UserRatingsTotal),
+		GooglePlaceID:    place.PlaceID,
+		Rating:           place.Rating,
+		UserRatingsTotal: place.UserRatingsTotal,
+		BusinessStatus:   businessStatus,
+		Icon:             place.Icon,
+		PhotoReferences:  photoReferences,
+		PlaceImage:       placeImageURL(bestPhotoReference(place.Photos)),
+		PlusCode:         plusCode,
+		OpeningHours:     openingHours,
+	}
+}
+
 // GetPlaceProfile godoc
 // @Summary Get detailed profile information about a place
-// @Description Returns comprehensive place information including stats and recent activity
+// @Description Returns comprehensive place information including stats and recent activity. A deleted place answers 410 Gone with data.deleted set, an unknown ID 404.
 // @Tags places
 // @Accept json
 // @Produce json
@@ -601,7 +665,13 @@ func (pc *PlaceController) GetPlaceProfile(c *gin.Context) {
 	
 	// First get the basic place data
 	var placeModel models.Place
-	if err := pc.DB.Where("id = ?", placeId).First(&placeModel).Error; err != nil {
+	// İncelemedeki mekanlar onaylanana kadar gösterilmez
+	if err := pc.DB.Where("id = ? AND needs_review = false", placeId).First(&placeModel).Error; err != nil {
+		// Silinmiş mekan 410 ile ayırt edilir
+		if deleted, err := isSoftDeleted(pc.DB, &models.Place{}, placeId); err == nil && deleted {
+			respondDeleted(c, "Place has been deleted")
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
 		return
 	}
@@ -646,6 +716,9 @@ func (pc *PlaceController) GetPlaceProfile(c *gin.Context) {
 	place.IsVerified = placeModel.IsVerified
 	place.Features = placeModel.Features
 
+	var favorites int64
+	pc.DB.Model(&models.FavoritePlace{}).Where("user_id = ? AND place_id = ?", user.UserID, placeId).Count(&favorites)
+
 	// Stat bilgileri
 	var stats struct {
 		TotalPosts    int64     `json:"totalPosts"`
@@ -729,6 +802,7 @@ func (pc *PlaceController) GetPlaceProfile(c *gin.Context) {
 		"place_type":         place.PlaceType,
 		"is_verified":        place.IsVerified,
 		"features":           place.Features,
+		"is_favorite":        favorites > 0,
 		"stats":              stats,
 		"user_posts":         userPosts,
 		"top_users":          topUsers,
@@ -737,6 +811,44 @@ func (pc *PlaceController) GetPlaceProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetPlacePointsBreakdown godoc
+// @Summary Explain how a place's base points were derived
+// @Description Re-runs the place scoring with the stored categories, rating and ratings total
+// @Tags places
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Success 200 {object} StandardResponse{data=types.PlacePointsBreakdown}
+// @Router /places/{placeId}/points-breakdown [get]
+func (pc *PlaceController) GetPlacePointsBreakdown(c *gin.Context) {
+	placeId, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Place ID must be a valid number"})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id, name, categories, rating, user_ratings_total, base_points").
+		First(&place, placeId).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
+		return
+	}
+
+	breakdown := types.CalculatePlacePointsBreakdown(place.Categories, place.Rating, place.UserRatingsTotal)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    breakdown,
+		Meta: gin.H{
+			"placeId":          place.ID,
+			"placeName":        place.Name,
+			"storedBasePoints": place.BasePoints,
+			// Puan kuralları değiştiyse kayıtlı değer güncel hesaptan farklı olabilir
+			"matchesStored": breakdown.FinalPoints == place.BasePoints,
+		},
+	})
+}
+
 // GetPlacePosts godoc
 // @Summary Get posts from a specific place with sorting and pagination
 // @Description Returns paginated posts from a place with various sorting options
@@ -748,86 +860,182 @@ func (pc *PlaceController) GetPlaceProfile(c *gin.Context) {
 // @Param page query integer false "Page number (default: 1)"
 // @Param pageSize query integer false "Items per page (default: 10, max: 50)"
 // @Param timeFrame query string false "Time frame: today, this_week, this_month, all_time"
-// @Success 200 {object} map[string]interface{}
+// @Param timezone query string false "IANA timezone for time frame boundaries (e.g. Europe/Istanbul)"
+// @Param tzOffset query integer false "UTC offset in minutes east of UTC, used when timezone is not given"
+// @Success 200 {object} StandardResponse{data=[]PostSummary}
 // @Router /places/{placeId}/posts [get]
 func (pc *PlaceController) GetPlacePosts(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
 	placeIdStr := c.Param("placeId")
 	
 	// Validate placeId parameter
 	if placeIdStr == "" || placeIdStr == "undefined" || placeIdStr == "null" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid place ID"})
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Invalid place ID"})
 		return
 	}
 	
 	// Convert to integer to ensure it's a valid ID
 	placeId, err := strconv.Atoi(placeIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Place ID must be a valid number"})
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Place ID must be a valid number"})
 		return
 	}
 	
 	var query PlacePostsQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
 		return
 	}
 
-	db := pc.DB.Model(&models.Post{}).Where("place_id = ?", placeId)
+	var place PostPlace
+	if err := pc.DB.Model(&models.Place{}).
+		Select("id, name, address, base_points as point_value, place_image as image").
+		Where("id = ?", placeId).
+		First(&place).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
 
-	// Apply time frame filter
-	switch query.TimeFrame {
-	case "today":
-		db = db.Where("created_at >= CURRENT_DATE")
-	case "this_week":
-		db = db.Where("created_at >= DATE_TRUNC('week', CURRENT_DATE)")
-	case "this_month":
-		db = db.Where("created_at >= DATE_TRUNC('month', CURRENT_DATE)")
+	// Kolonlar users join'i ile çakışmaması için posts. ile nitelenir
+	visibility, visibilityArgs := visiblePostsCondition(currentUser.UserID)
+	db := pc.DB.Model(&models.Post{}).
+		Joins("JOIN users ON users.id = posts.user_id").
+		Where("posts.place_id = ?", placeId).
+		Where(visibility, visibilityArgs...)
+
+	// Apply time frame filter (kullanıcının saat dilimine göre)
+	if start, ok := utils.PeriodStart(query.TimeFrame, time.Now(), loc); ok {
+		db = db.Where("posts.created_at >= ?", start)
+	}
+
+	// Sıralamadan önce sayılır; most_liked select'teki likes_count takma adına göre sıralar
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching posts"})
+		return
 	}
 
-	// Apply sorting
+	// Apply sorting; eşitlikte en yeni gönderi önce gelir
 	switch query.SortBy {
 	case "highest_rated":
-		db = db.Order("points DESC")
+		db = db.Order("posts.earned_points DESC, posts.created_at DESC, posts.id DESC")
 	case "most_liked":
-		db = db.Order("(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) DESC")
+		db = db.Order("likes_count DESC, posts.created_at DESC, posts.id DESC")
 	default: // "newest" or empty
-		db = db.Order("created_at DESC")
+		db = db.Order("posts.created_at DESC, posts.id DESC")
 	}
 
 	// Calculate pagination
 	offset := (query.Page - 1) * query.PageSize
 
-	var total int64
-	db.Count(&total)
-
-	var posts []struct {
-		models.Post
-		LikesCount    int64  `json:"likesCount"`
-		CommentsCount int64  `json:"commentsCount"`
-		Username      string `json:"username"`
+	var rawPosts []struct {
+		ID            uint      `gorm:"column:id"`
+		Caption       string    `gorm:"column:post_caption"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+		UpdatedAt     time.Time `gorm:"column:updated_at"`
+		Latitude      float64   `gorm:"column:latitude"`
+		Longitude     float64   `gorm:"column:longitude"`
+		EarnedPoints  int64     `gorm:"column:earned_points"`
+		UserID        uint      `gorm:"column:user_id"`
+		Username      string    `gorm:"column:username"`
+		FirstName     string    `gorm:"column:first_name"`
+		LastName      string    `gorm:"column:last_name"`
+		Avatar        string    `gorm:"column:avatar"`
+		ThumbnailURL  string    `gorm:"column:thumbnail_url"`
+		MediaType     string    `gorm:"column:media_type"`
+		MediaCount    int64     `gorm:"column:media_count"`
+		LikesCount    int64     `gorm:"column:likes_count"`
+		CommentsCount int64     `gorm:"column:comments_count"`
+		IsLiked       bool      `gorm:"column:is_liked"`
 	}
 
 	result := db.
-		Select("posts.*, users.username, " +
-			"(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count, " +
-			"(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id) as comments_count").
-		Joins("JOIN users ON users.id = posts.user_id").
+		Select(`
+			posts.id,
+			posts.post_caption,
+			posts.created_at,
+			posts.updated_at,
+			posts.latitude,
+			posts.longitude,
+			posts.earned_points,
+			posts.user_id,
+			users.username,
+			users.first_name,
+			users.last_name,
+			users.avatar,
+			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
+			(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count,
+			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count,
+			EXISTS(SELECT 1 FROM likes WHERE likes.post_id = posts.id AND likes.user_id = ?) as is_liked
+		`, currentUser.UserID).
 		Offset(offset).
 		Limit(query.PageSize).
-		Find(&posts)
+		Find(&rawPosts)
 
 	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching posts"})
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching posts"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"posts": posts,
-		"pagination": gin.H{
-			"currentPage": query.Page,
-			"pageSize":    query.PageSize,
-			"totalItems":  total,
-			"totalPages":  math.Ceil(float64(total) / float64(query.PageSize)),
+	// Transform to standard format
+	posts := make([]PostSummary, len(rawPosts))
+	for i, raw := range rawPosts {
+		posts[i] = PostSummary{
+			ID:           raw.ID,
+			Caption:      raw.Caption,
+			CreatedAt:    raw.CreatedAt,
+			UpdatedAt:    raw.UpdatedAt,
+			Latitude:     raw.Latitude,
+			Longitude:    raw.Longitude,
+			EarnedPoints: raw.EarnedPoints,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
+			MediaType:    raw.MediaType,
+			MediaCount:   raw.MediaCount,
+			User: PostUser{
+				ID:        raw.UserID,
+				Username:  raw.Username,
+				FirstName: raw.FirstName,
+				LastName:  raw.LastName,
+				Avatar:    raw.Avatar,
+			},
+			Place: place,
+			Interaction: PostInteraction{
+				LikesCount:    raw.LikesCount,
+				CommentsCount: raw.CommentsCount,
+				IsLiked:       raw.IsLiked,
+			},
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Meta: gin.H{
+			"place":     place,
+			"sortBy":    query.SortBy,
+			"timeFrame": query.TimeFrame,
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
 		},
 	})
 }
@@ -881,7 +1089,7 @@ func (pc *PlaceController) ValidatePostLocation(c *gin.Context) {
 
 	// Get place information using the actual model to avoid pq.StringArray issues
 	var placeModel models.Place
-	if err := pc.DB.Select("id, name, latitude, longitude, categories").
+	if err := pc.DB.Select("id, name, latitude, longitude, categories, post_radius_override").
 		Where("id = ?", placeId).
 		First(&placeModel).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
@@ -893,7 +1101,7 @@ func (pc *PlaceController) ValidatePostLocation(c *gin.Context) {
 	distanceMeters := distance * 1000 // Convert to meters
 
 	// Get place post radius
-	postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadius(placeModel.Categories)
+	postRadius, radiusType, radiusDescription, coverageArea := types.GetPlacePostRadiusWithOverride(placeModel.Categories, placeModel.PostRadiusOverride)
 
 	// Debug logging
 	log.Printf("ValidatePostLocation - Place: %s, User: (%.6f,%.6f), Place: (%.6f,%.6f), Distance: %.2fm, Required: %dm, Categories: %v", 
@@ -914,6 +1122,7 @@ func (pc *PlaceController) ValidatePostLocation(c *gin.Context) {
 		"coverage_area":       coverageArea,
 		"radius_type":         radiusType,
 		"radius_description":  radiusDescription,
+		"radius_override":     placeModel.PostRadiusOverride != nil,
 		"is_within_radius":    true,
 		"categories":          placeModel.Categories,
 		"can_post":            true,
@@ -932,6 +1141,314 @@ func (pc *PlaceController) ValidatePostLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// CheckIn godoc
+// @Summary Check in at a place without posting
+// @Description Awards a small number of points once per place per day when the user is within the place's post radius
+// @Tags places
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param request body CheckInRequest true "User's current coordinates"
+// @Param timezone query string false "IANA timezone used for the once-per-day boundary"
+// @Param tzOffset query integer false "UTC offset in minutes east of UTC, used when timezone is not given"
+// @Success 200 {object} StandardResponse
+// @Router /places/{placeId}/check-in [post]
+func (pc *PlaceController) CheckIn(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	placeId, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Place ID must be a valid number"})
+		return
+	}
+
+	var req CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id, name, latitude, longitude, categories, post_radius_override").
+		First(&place, placeId).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
+
+	// Gönderi ile aynı yarıçap kuralı
+	distanceMeters := types.CalculateDistance(req.Latitude, req.Longitude, place.Latitude, place.Longitude) * 1000
+	postRadius, _, _, _ := types.GetPlacePostRadiusWithOverride(place.Categories, place.PostRadiusOverride)
+	if config.GetPostRadiusPolicy() == config.PostRadiusEnforce && distanceMeters > float64(postRadius) {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "You must be at the location to check in",
+			Data: gin.H{
+				"distance": int(distanceMeters),
+				"required": postRadius,
+			},
+		})
+		return
+	}
+
+	startOfDay, _ := utils.PeriodStart("today", time.Now(), loc)
+	points := types.GetPointsConfig().CheckInPoints
+
+	tx := pc.DB.Begin()
+
+	// Kullanıcı satırını kilitle: aynı anda gelen check-in'ler çift puan almasın
+	var current models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&current, user.UserID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "User not found"})
+		return
+	}
+
+	var existing int64
+	if err := tx.Model(&models.ActivityLog{}).
+		Where("user_id = ? AND place_id = ? AND activity = ? AND created_at >= ?", user.UserID, place.ID, "place_visited", startOfDay).
+		Count(&existing).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to check previous visits"})
+		return
+	}
+	if existing > 0 {
+		tx.Rollback()
+		c.JSON(http.StatusConflict, StandardResponse{
+			Success: false,
+			Message: "Already checked in at this place today",
+			Data: gin.H{
+				"nextCheckInAt": startOfDay.AddDate(0, 0, 1),
+			},
+		})
+		return
+	}
+
+	activity := models.ActivityLog{
+		UserID:    user.UserID,
+		PlaceID:   place.ID,
+		Activity:  "place_visited",
+		Points:    points,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to record check-in"})
+		return
+	}
+
+	if err := tx.Model(&models.User{}).Where("id = ?", user.UserID).
+		Update("total_points", gorm.Expr("total_points + ?", points)).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update user points"})
+		return
+	}
+
+	var totalPoints int64
+	if err := tx.Model(&models.User{}).Where("id = ?", user.UserID).Select("total_points").Scan(&totalPoints).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to read user points"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to commit check-in"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"placeId":      place.ID,
+			"placeName":    place.Name,
+			"pointsEarned": points,
+			"totalPoints":  totalPoints,
+			"distance":     int(distanceMeters),
+		},
+		Message: "Checked in successfully",
+	})
+}
+
+// SetPostRadiusOverride godoc
+// @Summary Set or clear a place's custom post radius (admin)
+// @Description A radius in meters overrides the category-derived radius; null restores category behavior
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param request body PostRadiusOverrideRequest true "Radius in meters, or null to clear"
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/{placeId}/post-radius [put]
+func (pc *PlaceController) SetPostRadiusOverride(c *gin.Context) {
+	var req PostRadiusOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id, name, categories, post_radius_override").First(&place, c.Param("placeId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
+
+	tx := pc.DB.Begin()
+	if err := tx.Model(&place).Update("post_radius_override", req.Radius).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update post radius"})
+		return
+	}
+
+	if err := recordAdminAction(tx, utils.GetUser(c).UserID, "place_post_radius_set", "place", place.ID, gin.H{
+		"previous": place.PostRadiusOverride,
+		"radius":   req.Radius,
+	}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update post radius"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to update post radius"})
+		return
+	}
+
+	postRadius, radiusType, _, _ := types.GetPlacePostRadiusWithOverride(place.Categories, req.Radius)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"placeId":        place.ID,
+			"placeName":      place.Name,
+			"radiusOverride": req.Radius,
+			"postRadius":     postRadius,
+			"radiusType":     radiusType,
+		},
+		Message: "Post radius updated",
+	})
+}
+
+// GetPlaceReviewQueue godoc
+// @Summary List imported places awaiting name review (admin)
+// @Description Returns places whose imported name matched the banned-words filter, oldest first
+// @Tags admin
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/review-queue [get]
+func (pc *PlaceController) GetPlaceReviewQueue(c *gin.Context) {
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+
+	db := pc.DB.Model(&models.Place{}).Where("needs_review = true")
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch review queue"})
+		return
+	}
+
+	var places []struct {
+		ID            uint           `json:"id"`
+		Name          string         `json:"name"`
+		Address       string         `json:"address"`
+		Categories    pq.StringArray `json:"categories"`
+		GooglePlaceID string         `json:"googlePlaceId"`
+		CreatedAt     time.Time      `json:"createdAt"`
+	}
+	if err := db.Select("id, name, address, categories, google_place_id, created_at").
+		Order("created_at, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch review queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    places,
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+// ReviewPlace godoc
+// @Summary Approve or reject a flagged place (admin)
+// @Description Approving verifies the place so later imports do not flag it again; rejecting hides it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param request body PlaceReviewRequest true "approve or reject"
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/{placeId}/review [post]
+func (pc *PlaceController) ReviewPlace(c *gin.Context) {
+	var req PlaceReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id, name").Where("needs_review = true").First(&place, c.Param("placeId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found in review queue"})
+		return
+	}
+
+	tx := pc.DB.Begin()
+
+	var err error
+	if req.Action == "approve" {
+		err = tx.Model(&place).Updates(map[string]interface{}{"needs_review": false, "is_verified": true}).Error
+	} else {
+		err = tx.Delete(&place).Error
+	}
+	if err == nil {
+		err = recordAdminAction(tx, utils.GetUser(c).UserID, "place_review_"+req.Action, "place", place.ID, gin.H{"name": place.Name})
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review place"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review place"})
+		return
+	}
+
+	log.Printf("Place review: admin=%d place=%d name=%q action=%s", utils.GetUser(c).UserID, place.ID, place.Name, req.Action)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"placeId": place.ID,
+			"action":  req.Action,
+		},
+		Message: "Place reviewed",
+	})
+}
+
 // Helper functions for parsing query parameters
 func parseFloat(s string) float64 {
 	if s == "" {
@@ -0,0 +1,494 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestGetPlacePostsSortBy(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "placesortauthor")
+	place := createTestPlace(t, db, "placesortplace")
+
+	// oldest: en çok puan, newest: en çok beğeni, middle: ikisinde de ortada
+	oldest := createTestPost(t, db, author, place, "oldest", true)
+	middle := createTestPost(t, db, author, place, "middle", true)
+	newest := createTestPost(t, db, author, place, "newest", true)
+	now := time.Now()
+	for post, fields := range map[uint]map[string]interface{}{
+		oldest.ID: {"created_at": now.Add(-3 * time.Hour), "earned_points": 50},
+		middle.ID: {"created_at": now.Add(-2 * time.Hour), "earned_points": 20},
+		newest.ID: {"created_at": now.Add(-1 * time.Hour), "earned_points": 10},
+	} {
+		if err := db.Model(&models.Post{}).Where("id = ?", post).Updates(fields).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// newest: 3 beğeni, middle: 1 beğeni, oldest: 0
+	for i := 0; i < 3; i++ {
+		liker := createTestUser(t, db, "placesortliker"+strconv.Itoa(i))
+		liked := []models.Post{newest}
+		if i == 0 {
+			liked = append(liked, middle)
+		}
+		for _, post := range liked {
+			if err := db.Create(&models.Like{PostID: post.ID, UserID: liker.ID}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	tests := []struct {
+		sortBy string
+		want   []uint
+	}{
+		{"", []uint{newest.ID, middle.ID, oldest.ID}},
+		{"newest", []uint{newest.ID, middle.ID, oldest.ID}},
+		{"highest_rated", []uint{oldest.ID, middle.ID, newest.ID}},
+		{"most_liked", []uint{newest.ID, middle.ID, oldest.ID}},
+	}
+	for _, tt := range tests {
+		t.Run("sortBy="+tt.sortBy, func(t *testing.T) {
+			target := "/places/" + param.Value + "/posts?sortBy=" + tt.sortBy
+			if got := listPostIDs(t, pc.GetPlacePosts, target, author.ID, param); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacePostListingsHideInvisiblePosts(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "placeviewer")
+	author := createTestUser(t, db, "placeauthor")
+	blocker := createTestUser(t, db, "placeblocker")
+	private := createTestUser(t, db, "placeprivate")
+	place := createTestPlace(t, db, "visibilityplace")
+
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	own := createTestPost(t, db, viewer, place, "own private post", false)
+	public := createTestPost(t, db, author, place, "public post", true)
+	createTestPost(t, db, author, place, "hidden post", false)
+	createTestPost(t, db, blocker, place, "blocked post", true)
+	createTestPost(t, db, private, place, "private account post", true)
+	want := []uint{own.ID, public.ID}
+
+	placeParam := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	handlers := map[string]gin.HandlerFunc{
+		"posts": NewPlaceController(db, nil).GetPlacePosts,
+		"grid":  NewPostController(db, nil).GetPlacePostsGrid,
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			w := callHandler(handler, http.MethodGet, "/places/"+placeParam.Value+"/posts", nil, viewer.ID, placeParam)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			var resp postListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			var got []uint
+			for _, post := range resp.Data {
+				got = append(got, post.ID)
+			}
+			if !sameIDs(got, want) {
+				t.Errorf("posts = %v, want %v", got, want)
+			}
+			if resp.Pagination.TotalItems != int64(len(want)) {
+				t.Errorf("total = %d, want %d", resp.Pagination.TotalItems, len(want))
+			}
+		})
+	}
+}
+
+func TestGetPlacePostsMatchesGridShape(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "shapeviewer")
+	place := createTestPlace(t, db, "shapeplace")
+	post := createTestPost(t, db, viewer, place, "shape", true)
+	if err := db.Create(&models.PostMedia{PostID: post.ID, MediaURL: "https://cdn.example.com/a.jpg", MediaType: "image"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Like{PostID: post.ID, UserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	// keys returns the sorted keys of the first item, its user and interaction, and the pagination
+	keys := func(handler gin.HandlerFunc) map[string][]string {
+		t.Helper()
+		w := callHandler(handler, http.MethodGet, "/places/"+param.Value+"/posts", nil, viewer.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data       []map[string]json.RawMessage `json:"data"`
+			Pagination map[string]json.RawMessage   `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Data) != 1 {
+			t.Fatalf("data has %d items, want 1", len(resp.Data))
+		}
+		var user, interaction map[string]json.RawMessage
+		json.Unmarshal(resp.Data[0]["user"], &user)
+		json.Unmarshal(resp.Data[0]["interaction"], &interaction)
+
+		sortedKeys := func(m map[string]json.RawMessage) []string {
+			out := []string{}
+			for k := range m {
+				out = append(out, k)
+			}
+			sort.Strings(out)
+			return out
+		}
+		return map[string][]string{
+			"item":        sortedKeys(resp.Data[0]),
+			"user":        sortedKeys(user),
+			"interaction": sortedKeys(interaction),
+			"pagination":  sortedKeys(resp.Pagination),
+		}
+	}
+
+	posts := keys(NewPlaceController(db, nil).GetPlacePosts)
+	grid := keys(NewPostController(db, nil).GetPlacePostsGrid)
+	if !reflect.DeepEqual(posts, grid) {
+		t.Errorf("GetPlacePosts keys = %v, grid keys = %v", posts, grid)
+	}
+}
+
+func TestPostRadiusOverride(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "radiususer")
+	place := createTestPlace(t, db, "radiusplace")
+	if err := db.Model(&place).Update("categories", pq.StringArray{"cafe"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	// Mekandan ~300 m kuzey
+	lat, lng := place.Latitude+0.0027, place.Longitude
+
+	setRadius := func(body string) {
+		t.Helper()
+		w := callHandler(pc.SetPostRadiusOverride, http.MethodPut, "/admin/places/"+param.Value+"/post-radius", strings.NewReader(body), user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("set radius %s: status = %d, body = %s", body, w.Code, w.Body.String())
+		}
+	}
+	validate := func() (int, bool) {
+		t.Helper()
+		target := fmt.Sprintf("/places/%d/validate-location?latitude=%f&longitude=%f", place.ID, lat, lng)
+		w := callHandler(pc.ValidatePostLocation, http.MethodGet, target, nil, user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("validate: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			PostRadius     int  `json:"post_radius"`
+			RadiusOverride bool `json:"radius_override"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.PostRadius, resp.RadiusOverride
+	}
+	createPost := func() int {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"mediaItems": []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/test.jpg"}},
+			"placeId":    place.ID,
+			"latitude":   lat,
+			"longitude":  lng,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(NewPostController(db, nil).CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), user.ID).Code
+	}
+
+	if w := callHandler(pc.SetPostRadiusOverride, http.MethodPut, "/admin/places/"+param.Value+"/post-radius",
+		strings.NewReader(`{"radius":5}`), user.ID, param); w.Code != http.StatusBadRequest {
+		t.Errorf("radius below minimum: status = %d, want 400", w.Code)
+	}
+
+	setRadius(`{"radius":500}`)
+	if radius, override := validate(); radius != 500 || !override {
+		t.Errorf("with override: radius = %d, override = %v; want 500, true", radius, override)
+	}
+	if code := createPost(); code != http.StatusCreated {
+		t.Errorf("create inside override radius: status = %d, want 201", code)
+	}
+
+	// null özel yarıçapı kaldırır, kategori yarıçapı geri gelir
+	setRadius(`{"radius":null}`)
+	want, _, _, _ := types.GetPlacePostRadius([]string{"cafe"})
+	if radius, override := validate(); radius != want || override {
+		t.Errorf("after clearing: radius = %d, override = %v; want %d, false", radius, override, want)
+	}
+	if code := createPost(); code != http.StatusBadRequest {
+		t.Errorf("create outside category radius: status = %d, want 400", code)
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "checkinuser")
+	place := createTestPlace(t, db, "checkinplace")
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	points := int64(types.GetPointsConfig().CheckInPoints)
+
+	checkIn := func(lat, lng float64) (int, int64) {
+		t.Helper()
+		body := fmt.Sprintf(`{"latitude":%f,"longitude":%f}`, lat, lng)
+		w := callHandler(pc.CheckIn, http.MethodPost, "/places/"+param.Value+"/check-in", strings.NewReader(body), user.ID, param)
+		var resp struct {
+			Data struct {
+				TotalPoints int64 `json:"totalPoints"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, resp.Data.TotalPoints
+	}
+	visits := func() int64 {
+		t.Helper()
+		var n int64
+		if err := db.Model(&models.ActivityLog{}).Where("user_id = ? AND activity = ?", user.ID, "place_visited").Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// ~300 m uzakta, varsayılan yarıçapın dışında
+	if code, _ := checkIn(place.Latitude+0.0027, place.Longitude); code != http.StatusBadRequest {
+		t.Errorf("out of radius: status = %d, want 400", code)
+	}
+	if n := visits(); n != 0 {
+		t.Errorf("visits after out-of-radius check-in = %d, want 0", n)
+	}
+
+	code, total := checkIn(place.Latitude, place.Longitude)
+	if code != http.StatusOK {
+		t.Fatalf("in radius: status = %d, want 200", code)
+	}
+	if total != points || totalPoints(t, db, user) != points {
+		t.Errorf("total points = %d (stored %d), want %d", total, totalPoints(t, db, user), points)
+	}
+
+	// Aynı gün ikinci check-in puan vermez
+	if code, _ := checkIn(place.Latitude, place.Longitude); code != http.StatusConflict {
+		t.Errorf("repeat same day: status = %d, want 409", code)
+	}
+	if n, got := visits(), totalPoints(t, db, user); n != 1 || got != points {
+		t.Errorf("after repeat: visits = %d, points = %d; want 1, %d", n, got, points)
+	}
+
+	// Dünkü ziyaret bugünkü check-in'i engellemez
+	if err := db.Model(&models.ActivityLog{}).Where("user_id = ?", user.ID).
+		Update("created_at", time.Now().Add(-48*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, total := checkIn(place.Latitude, place.Longitude); code != http.StatusOK || total != 2*points {
+		t.Errorf("next day: status = %d, total = %d; want 200, %d", code, total, 2*points)
+	}
+}
+
+func TestGetPlaceMediaShowsOnlyVisiblePosts(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "galleryviewer")
+	author := createTestUser(t, db, "galleryauthor")
+	blocker := createTestUser(t, db, "galleryblocker")
+	muted := createTestUser(t, db, "gallerymuted")
+	place := createTestPlace(t, db, "galleryplace")
+
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Mute{MuterUserID: viewer.ID, MutedUserID: muted.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	addMedia := func(post models.Post, count int) []uint {
+		t.Helper()
+		ids := make([]uint, count)
+		for i := range ids {
+			media := models.PostMedia{PostID: post.ID, MediaType: "photo", MediaURL: fmt.Sprintf("https://cdn.example.com/%d-%d.jpg", post.ID, i), OrderIndex: i}
+			if err := db.Create(&media).Error; err != nil {
+				t.Fatal(err)
+			}
+			ids[i] = media.ID
+		}
+		return ids
+	}
+	public := createTestPost(t, db, author, place, "public", true)
+	publicMedia := addMedia(public, 2)
+	addMedia(createTestPost(t, db, author, place, "hidden", false), 1)
+	addMedia(createTestPost(t, db, blocker, place, "blocked", true), 1)
+	addMedia(createTestPost(t, db, muted, place, "muted", true), 1)
+	own := createTestPost(t, db, viewer, place, "own private", false)
+	ownMedia := addMedia(own, 1)
+
+	pc := NewPostController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	gallery := func(query string) ([]uint, map[uint]uint, int64) {
+		t.Helper()
+		w := callHandler(pc.GetPlaceMedia, http.MethodGet, "/places/"+param.Value+"/media"+query, nil, viewer.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data       []PlaceMediaItem `json:"data"`
+			Pagination PaginationMeta   `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids := []uint{}
+		posts := map[uint]uint{}
+		for _, item := range resp.Data {
+			ids = append(ids, item.ID)
+			posts[item.ID] = item.PostID
+		}
+		return ids, posts, resp.Pagination.TotalItems
+	}
+
+	ids, posts, total := gallery("")
+	want := []uint{ownMedia[0], publicMedia[0]}
+	if !reflect.DeepEqual(ids, want) || total != 2 {
+		t.Errorf("first media = %v (total %d), want %v newest first", ids, total, want)
+	}
+	if posts[publicMedia[0]] != public.ID || posts[ownMedia[0]] != own.ID {
+		t.Errorf("postIds = %v, want %d and %d", posts, public.ID, own.ID)
+	}
+
+	ids, _, total = gallery("?all=true")
+	want = []uint{ownMedia[0], publicMedia[0], publicMedia[1]}
+	if !reflect.DeepEqual(ids, want) || total != 3 {
+		t.Errorf("all media = %v (total %d), want %v", ids, total, want)
+	}
+}
+
+func TestFlaggedPlacesHiddenUntilReviewed(t *testing.T) {
+	t.Setenv("GOOGLE_PLACES_API_KEY", "")
+	db := openTestDB(t)
+	user := createTestUser(t, db, "reviewuser")
+	clean := createTestPlace(t, db, "cleanplace")
+	flagged := createTestPlace(t, db, "flaggedplace")
+	rejected := createTestPlace(t, db, "rejectedplace")
+	if err := db.Model(&models.Place{}).Where("id IN ?", []uint{flagged.ID, rejected.ID}).Update("needs_review", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	pc := NewPlaceController(db, nil)
+
+	nearbyIDs := func() map[uint]bool {
+		t.Helper()
+		target := fmt.Sprintf("/places/nearby?latitude=%f&longitude=%f&zoomLevel=15", clean.Latitude, clean.Longitude)
+		w := callHandler(pc.GetNearbyPlaces, http.MethodGet, target, nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("nearby: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp types.NearbyPlacesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids := map[uint]bool{}
+		for _, marker := range resp.Markers {
+			ids[marker.ID] = true
+		}
+		return ids
+	}
+	review := func(place models.Place, action string) int {
+		t.Helper()
+		param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+		return callHandler(pc.ReviewPlace, http.MethodPost, "/admin/places/"+param.Value+"/review",
+			strings.NewReader(`{"action":"`+action+`"}`), user.ID, param).Code
+	}
+
+	if ids := nearbyIDs(); !ids[clean.ID] || ids[flagged.ID] || ids[rejected.ID] {
+		t.Errorf("nearby before review = %v, want only %d", ids, clean.ID)
+	}
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(flagged.ID))}
+	if w := callHandler(pc.GetPlaceProfile, http.MethodGet, "/places/"+param.Value+"/profile", nil, user.ID, param); w.Code != http.StatusNotFound {
+		t.Errorf("flagged place profile: status = %d, want 404", w.Code)
+	}
+
+	w := callHandler(pc.GetPlaceReviewQueue, http.MethodGet, "/admin/places/review-queue", nil, user.ID)
+	var queue struct {
+		Data []struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &queue); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.Data) != 2 || queue.Data[0].ID != flagged.ID || queue.Data[1].ID != rejected.ID {
+		t.Errorf("review queue = %+v, want places %d and %d", queue.Data, flagged.ID, rejected.ID)
+	}
+
+	if code := review(clean, "approve"); code != http.StatusNotFound {
+		t.Errorf("review of unflagged place: status = %d, want 404", code)
+	}
+	if code := review(flagged, "approve"); code != http.StatusOK {
+		t.Fatalf("approve: status = %d", code)
+	}
+	if code := review(rejected, "reject"); code != http.StatusOK {
+		t.Fatalf("reject: status = %d", code)
+	}
+	if ids := nearbyIDs(); !ids[clean.ID] || !ids[flagged.ID] || ids[rejected.ID] {
+		t.Errorf("nearby after review = %v, want %d and %d", ids, clean.ID, flagged.ID)
+	}
+	if err := db.First(&flagged, flagged.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !flagged.IsVerified || flagged.NeedsReview {
+		t.Errorf("approved place: verified = %v, needs review = %v", flagged.IsVerified, flagged.NeedsReview)
+	}
+}
+
+func TestPlaceFromGoogleResultNormalizesCategories(t *testing.T) {
+	rating := 4.6
+	total := 1500
+	place := types.GooglePlaceResult{
+		Name:             "Galata Kulesi",
+		PlaceID:          "galata",
+		Types:            []string{"tourist_attraction", "point_of_interest", "museum", "establishment", "Lodging", "lodging"},
+		Rating:           &rating,
+		UserRatingsTotal: &total,
+	}
+
+	got := placeFromGoogleResult(place)
+
+	if want := []string{"tourist_attraction", "museum", "hotel"}; !reflect.DeepEqual([]string(got.Categories), want) {
+		t.Errorf("categories = %v, want %v", got.Categories, want)
+	}
+	if !reflect.DeepEqual([]string(got.GoogleTypes), place.Types) {
+		t.Errorf("google types = %v, want the raw types %v", got.GoogleTypes, place.Types)
+	}
+	if want := types.CalculatePlacePoints([]string{"tourist_attraction", "museum", "hotel"}, &rating, &total); got.BasePoints != want {
+		t.Errorf("base points = %d, want %d from the normalized categories", got.BasePoints, want)
+	}
+}
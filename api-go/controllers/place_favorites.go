@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FavoritePlacesQuery; Latitude ve Longitude verilirse her mekanın uzaklığı hesaplanır
+type FavoritePlacesQuery struct {
+	Page      int      `form:"page,default=1" binding:"min=1"`
+	PageSize  int      `form:"pageSize,default=20" binding:"min=1,max=50"`
+	Latitude  *float64 `form:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `form:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// FavoritePlaceItem is a saved place with the points the viewer would earn there
+type FavoritePlaceItem struct {
+	SimplifiedPlace
+	PointValue  int       `json:"pointValue"`
+	Distance    *float64  `json:"distance,omitempty"` // km, yalnızca konum verildiğinde
+	FavoritedAt time.Time `json:"favoritedAt"`
+}
+
+// ToggleFavoritePlace godoc
+// @Summary Save or unsave a place
+// @Description Adds the place to the current user's favorites, or removes it when already saved
+// @Tags places
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /places/{placeId}/favorite [post]
+func (pc *PlaceController) ToggleFavoritePlace(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	placeID, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Place ID must be a valid number"})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id").Where("id = ? AND needs_review = false", placeID).First(&place).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
+		return
+	}
+
+	// Kayıt varsa kaldırılır; yoksa eklenir
+	removed := pc.DB.Where("user_id = ? AND place_id = ?", user.UserID, place.ID).Delete(&models.FavoritePlace{})
+	if removed.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update favorite"})
+		return
+	}
+	if removed.RowsAffected > 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"success":   true,
+			"message":   "Place removed from favorites",
+			"favorited": false,
+		})
+		return
+	}
+
+	favorite := models.FavoritePlace{UserID: user.UserID, PlaceID: place.ID}
+	// Eşzamanlı iki istek aynı kaydı eklemeye çalışırsa ikincisi yok sayılır
+	if err := pc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update favorite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Place added to favorites",
+		"favorited": true,
+	})
+}
+
+// GetFavoritePlaces godoc
+// @Summary List the current user's favorite places
+// @Description Returns saved places, most recently saved first, with the points the user would earn posting there and, when latitude and longitude are given, the distance in km
+// @Tags places
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20, max: 50)"
+// @Param latitude query number false "Latitude to measure distance from"
+// @Param longitude query number false "Longitude to measure distance from"
+// @Success 200 {object} StandardResponse{data=[]FavoritePlaceItem}
+// @Router /users/me/favorite-places [get]
+func (pc *PlaceController) GetFavoritePlaces(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var query FavoritePlacesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if (query.Latitude == nil) != (query.Longitude == nil) {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "latitude and longitude must be given together"})
+		return
+	}
+
+	db := pc.DB.Table("favorite_places").
+		Joins("JOIN places ON places.id = favorite_places.place_id AND places.deleted_at IS NULL AND places.needs_review = false").
+		Where("favorite_places.user_id = ?", user.UserID)
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting favorite places"})
+		return
+	}
+
+	// Puan değeri yakındaki mekanlar ve mekan profiliyle aynı kuralla hesaplanır
+	pointsConfig := types.GetPointsConfig()
+	selectClause := `places.id, places.name, places.categories, places.address, places.latitude, places.longitude,
+		places.base_points AS base_score, places.place_type, places.place_image, places.is_verified, places.features,
+		CASE
+			WHEN EXISTS(SELECT 1 FROM posts WHERE posts.place_id = places.id AND posts.user_id = ? AND posts.deleted_at IS NULL)
+			THEN ?
+			WHEN NOT EXISTS(SELECT 1 FROM posts WHERE posts.place_id = places.id AND posts.deleted_at IS NULL)
+			THEN places.base_points + ?
+			ELSE places.base_points
+		END AS point_value,
+		favorite_places.created_at AS favorited_at`
+	selectArgs := []interface{}{user.UserID, pointsConfig.UserVisitedPoints, pointsConfig.NoPostsBonusPoints}
+	if query.Latitude != nil {
+		selectClause += `, (6371 * acos(LEAST(1, cos(radians(?)) * cos(radians(places.latitude)) * cos(radians(places.longitude) - radians(?)) + sin(radians(?)) * sin(radians(places.latitude))))) AS distance`
+		selectArgs = append(selectArgs, *query.Latitude, *query.Longitude, *query.Latitude)
+	}
+
+	places := []FavoritePlaceItem{}
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Select(selectClause, selectArgs...).
+		Order("favorite_places.created_at DESC, favorite_places.id DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Scan(&places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching favorite places"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    places,
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		},
+	})
+}
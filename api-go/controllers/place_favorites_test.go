@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestToggleFavoritePlace(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "favoriteuser")
+	place := createTestPlace(t, db, "favoriteplace")
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+
+	toggle := func() bool {
+		t.Helper()
+		w := callHandler(pc.ToggleFavoritePlace, http.MethodPost, "/places/"+param.Value+"/favorite", nil, user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("toggle: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Favorited bool `json:"favorited"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Favorited
+	}
+	isFavorite := func() bool {
+		t.Helper()
+		w := callHandler(pc.GetPlaceProfile, http.MethodGet, "/places/"+param.Value+"/profile", nil, user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("profile: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			IsFavorite bool `json:"is_favorite"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.IsFavorite
+	}
+
+	if !toggle() || !isFavorite() {
+		t.Errorf("after first toggle: want the place saved")
+	}
+	nearby := fmt.Sprintf("/places/nearby?latitude=%f&longitude=%f&zoomLevel=15", place.Latitude, place.Longitude)
+	w := callHandler(pc.GetNearbyPlaces, http.MethodGet, nearby, nil, user.ID)
+	var markers types.NearbyPlacesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &markers); err != nil {
+		t.Fatal(err)
+	}
+	if len(markers.Markers) != 1 || !markers.Markers[0].IsFavorite {
+		t.Errorf("nearby markers = %+v, want the place marked as favorite", markers.Markers)
+	}
+
+	if toggle() || isFavorite() {
+		t.Errorf("after second toggle: want the place removed")
+	}
+	var count int64
+	db.Model(&models.FavoritePlace{}).Where("user_id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("favorite rows = %d, want 0", count)
+	}
+
+	missing := gin.Param{Key: "placeId", Value: "999999"}
+	if w := callHandler(pc.ToggleFavoritePlace, http.MethodPost, "/places/999999/favorite", nil, user.ID, missing); w.Code != http.StatusNotFound {
+		t.Errorf("unknown place: status = %d, want 404", w.Code)
+	}
+}
+
+func TestGetFavoritePlaces(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "favoritelister")
+	visited := createTestPlace(t, db, "favoritevisited")
+	fresh := createTestPlace(t, db, "favoritefresh")
+	other := createTestPlace(t, db, "favoriteother")
+	for _, place := range []models.Place{visited, fresh, other} {
+		if err := db.Model(&place).Update("base_points", 20).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	createTestPost(t, db, user, visited, "been here", true)
+	// Yalnızca kendi kayıtları listelenir
+	if err := db.Create(&models.FavoritePlace{UserID: createTestUser(t, db, "favoritestranger").ID, PlaceID: other.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, place := range []models.Place{visited, fresh} {
+		if err := db.Create(&models.FavoritePlace{UserID: user.ID, PlaceID: place.ID}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pc := NewPlaceController(db, nil)
+	list := func(target string) []FavoritePlaceItem {
+		t.Helper()
+		w := callHandler(pc.GetFavoritePlaces, http.MethodGet, target, nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []FavoritePlaceItem `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+
+	items := list("/users/me/favorite-places")
+	if len(items) != 2 || items[0].ID != fresh.ID || items[1].ID != visited.ID {
+		t.Fatalf("favorites = %+v, want fresh then visited", items)
+	}
+	pointsConfig := types.GetPointsConfig()
+	if items[0].PointValue != 20+pointsConfig.NoPostsBonusPoints || items[0].BaseScore != 20 {
+		t.Errorf("fresh place points = %d (base %d), want %d", items[0].PointValue, items[0].BaseScore, 20+pointsConfig.NoPostsBonusPoints)
+	}
+	if items[1].PointValue != pointsConfig.UserVisitedPoints {
+		t.Errorf("visited place points = %d, want %d", items[1].PointValue, pointsConfig.UserVisitedPoints)
+	}
+	if items[0].Distance != nil {
+		t.Errorf("distance without a location = %v, want none", *items[0].Distance)
+	}
+
+	// Test mekanlarından yaklaşık 1.1 km kuzeyde
+	items = list(fmt.Sprintf("/users/me/favorite-places?latitude=%f&longitude=%f", fresh.Latitude+0.01, fresh.Longitude))
+	if len(items) != 2 || items[0].Distance == nil || *items[0].Distance < 1 || *items[0].Distance > 1.2 {
+		t.Errorf("favorites with location = %+v, want a distance around 1.1 km", items)
+	}
+
+	if w := callHandler(pc.GetFavoritePlaces, http.MethodGet, "/users/me/favorite-places?latitude=41", nil, user.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("latitude alone: status = %d, want 400", w.Code)
+	}
+}
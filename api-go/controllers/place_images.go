@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// placeImagePathPrefix mekan görsellerini sunan fotoğraf proxy'sinin yolu; API anahtarı istemciye gitmez
+	placeImagePathPrefix = "/api/places/photos/"
+	placePhotoMaxWidth   = 1600
+	// Bir çalıştırmada görseli doldurulacak en fazla mekan; kalanlar sonraki çalıştırmaya kalır
+	placeImageBackfillBatchSize = 500
+)
+
+// Google Place Photo uç noktası; testlerde sahte sunucuya çekilir
+var googlePlacesPhotoURL = "https://maps.googleapis.com/maps/api/place/photo"
+
+// bestPhotoReference returns the reference of the highest-resolution photo, or
+// "" when there is none. Ties keep Google's order.
+func bestPhotoReference(photos []types.Photo) string {
+	best, bestArea := "", -1
+	for _, photo := range photos {
+		if photo.PhotoReference == "" {
+			continue
+		}
+		if area := photo.Width * photo.Height; area > bestArea {
+			best, bestArea = photo.PhotoReference, area
+		}
+	}
+	return best
+}
+
+// placeImageURL is the photo proxy path serving a photo reference
+func placeImageURL(reference string) string {
+	if reference == "" {
+		return ""
+	}
+	return placeImagePathPrefix + reference
+}
+
+// backfillPlaceImages sets PlaceImage for places that have photo references
+// but no image yet. Stored references carry no size, so the first one (Google's
+// primary photo) is used. Returns the number of places updated.
+func backfillPlaceImages(db *gorm.DB, now time.Time) (int64, error) {
+	result := db.Exec(`
+		UPDATE places SET place_image = ? || photo_references[1], updated_at = ?
+		WHERE id IN (
+			SELECT id FROM places
+			WHERE deleted_at IS NULL
+			  AND (place_image IS NULL OR place_image = '')
+			  AND cardinality(photo_references) > 0 AND photo_references[1] <> ''
+			ORDER BY id
+			LIMIT ?
+		)`, placeImagePathPrefix, now, placeImageBackfillBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+// GetPlacePhoto godoc
+// @Summary Get a place image
+// @Description Streams the Google Places photo selected as a place's image. Only references used as a place image are served.
+// @Tags places
+// @Produce image/jpeg
+// @Param reference path string true "Photo reference"
+// @Success 200 {file} binary
+// @Router /places/photos/{reference} [get]
+func (pc *PlaceController) GetPlacePhoto(c *gin.Context) {
+	reference := c.Param("reference")
+
+	// Yalnızca bir mekanın görseli olarak seçilmiş referanslar sunulur; anahtar başka amaçla kullanılamaz
+	var count int64
+	if reference != "" {
+		if err := pc.DB.Model(&models.Place{}).Where("place_image = ?", placeImageURL(reference)).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch photo"})
+			return
+		}
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Photo not found"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), googlePlacesAttemptTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s?maxwidth=%d&photo_reference=%s&key=%s", googlePlacesPhotoURL, placePhotoMaxWidth, reference, os.Getenv("GOOGLE_PLACES_API_KEY"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch photo"})
+		return
+	}
+	start := time.Now()
+	resp, err := googlePlacesHTTPClient.Do(req)
+	recordGoogleAPICall(googleAPIPlacePhoto, start, resp, err)
+	if err != nil {
+		log.Printf("Place photo fetch failed: %v", err)
+		c.JSON(http.StatusBadGateway, StandardResponse{Success: false, Message: "Failed to fetch photo"})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Place photo fetch failed: status %d", resp.StatusCode)
+		c.JSON(http.StatusBadGateway, StandardResponse{Success: false, Message: "Failed to fetch photo"})
+		return
+	}
+
+	// Referans değişmediği sürece görsel aynıdır; istemci ve CDN önbelleğe alabilir
+	c.DataFromReader(http.StatusOK, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, map[string]string{
+		"Cache-Control": "public, max-age=86400",
+	})
+}
+
+// BackfillPlaceImages godoc
+// @Summary Fill missing place images from photo references (admin)
+// @Description Sets the image of places that have photo references but no image. Runs at most 500 places; call again until updated is 0.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/backfill-images [post]
+func (pc *PlaceController) BackfillPlaceImages(c *gin.Context) {
+	tx := pc.DB.Begin()
+
+	updated, err := backfillPlaceImages(tx, time.Now())
+	if err == nil {
+		err = recordAdminAction(tx, utils.GetUser(c).UserID, "place_images_backfill", "system", 0, gin.H{"updated": updated})
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to backfill place images"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to backfill place images"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    gin.H{"updated": updated},
+		Message: "Place images backfilled",
+	})
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestPlaceFromGoogleResultSetsPlaceImage(t *testing.T) {
+	place := types.GooglePlaceResult{
+		Name:    "Kız Kulesi",
+		PlaceID: "kiz-kulesi",
+		Photos: []types.Photo{
+			{PhotoReference: "small", Width: 400, Height: 300},
+			{PhotoReference: "large", Width: 4032, Height: 3024},
+			{PhotoReference: "medium", Width: 1600, Height: 1200},
+		},
+	}
+
+	got := placeFromGoogleResult(place)
+	if want := "/api/places/photos/large"; got.PlaceImage != want {
+		t.Errorf("place image = %q, want %q", got.PlaceImage, want)
+	}
+	if len(got.PhotoReferences) != 3 {
+		t.Errorf("photo references = %v, want all 3 kept", got.PhotoReferences)
+	}
+
+	place.Photos = nil
+	if got := placeFromGoogleResult(place); got.PlaceImage != "" {
+		t.Errorf("place image without photos = %q, want empty", got.PlaceImage)
+	}
+}
+
+func TestBackfillPlaceImages(t *testing.T) {
+	db := openTestDB(t)
+	missing := createTestPlace(t, db, "missingimage")
+	existing := createTestPlace(t, db, "existingimage")
+	noPhotos := createTestPlace(t, db, "nophotos")
+	if err := db.Model(&missing).Update("photo_references", pq.StringArray{"first", "second"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&existing).Updates(map[string]interface{}{
+		"photo_references": pq.StringArray{"other"},
+		"place_image":      "https://cdn.example.com/custom.jpg",
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := backfillPlaceImages(db, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 1 {
+		t.Errorf("updated = %d, want 1", updated)
+	}
+
+	images := map[uint]string{}
+	for _, id := range []uint{missing.ID, existing.ID, noPhotos.ID} {
+		var place models.Place
+		if err := db.Select("id, place_image").First(&place, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		images[id] = place.PlaceImage
+	}
+	if images[missing.ID] != "/api/places/photos/first" {
+		t.Errorf("backfilled image = %q, want the first reference", images[missing.ID])
+	}
+	if images[existing.ID] != "https://cdn.example.com/custom.jpg" {
+		t.Errorf("existing image overwritten: %q", images[existing.ID])
+	}
+	if images[noPhotos.ID] != "" {
+		t.Errorf("place without references got image %q", images[noPhotos.ID])
+	}
+
+	if updated, err := backfillPlaceImages(db, time.Now()); err != nil || updated != 0 {
+		t.Errorf("second run: updated = %d, err = %v, want 0", updated, err)
+	}
+}
+
+func TestGetPlacePhotoServesOnlyPlaceImages(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "photouser")
+	place := createTestPlace(t, db, "photoplace")
+	if err := db.Model(&place).Update("place_image", placeImageURL("known")).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("photo_reference") != "known" {
+			t.Errorf("unexpected photo reference %q", r.URL.Query().Get("photo_reference"))
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("jpeg-bytes"))
+	}))
+	defer server.Close()
+	prev := googlePlacesPhotoURL
+	googlePlacesPhotoURL = server.URL
+	defer func() { googlePlacesPhotoURL = prev }()
+
+	pc := NewPlaceController(db, nil)
+	w := callHandler(pc.GetPlacePhoto, http.MethodGet, "/places/photos/known", nil, user.ID, gin.Param{Key: "reference", Value: "known"})
+	if w.Code != http.StatusOK || w.Body.String() != "jpeg-bytes" || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("known reference: status = %d, type = %q, body = %q", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+	}
+
+	w = callHandler(pc.GetPlacePhoto, http.MethodGet, "/places/photos/unknown", nil, user.ID, gin.Param{Key: "reference", Value: "unknown"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown reference: status = %d, want 404", w.Code)
+	}
+}
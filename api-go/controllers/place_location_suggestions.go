@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Konum önerisi kuralları (metre)
+const (
+	minLocationCorrection = 20   // bundan yakın öneriler düzeltme sayılmaz
+	maxLocationCorrection = 2000 // bundan uzak öneriler başka bir yeri gösterir
+	onSiteSuggestionRange = 100  // kullanıcı önerdiği noktaya bu kadar yakınsa yerinde sayılır
+	suggestionClusterSize = 50   // birbirine bu kadar yakın öneriler aynı kümededir
+	// Bu kadar farklı kullanıcı aynı noktayı önerirse mekan kuyrukta öne çıkar
+	suggestionClusterMinUsers = 3
+)
+
+// Konum önerisi durumları
+const (
+	LocationSuggestionPending    = "pending"
+	LocationSuggestionAccepted   = "accepted"
+	LocationSuggestionRejected   = "rejected"
+	LocationSuggestionSuperseded = "superseded"
+)
+
+// SuggestPlaceLocationRequest; UserLatitude/UserLongitude kullanıcının o anki konumudur
+type SuggestPlaceLocationRequest struct {
+	Latitude      float64  `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude     float64  `json:"longitude" binding:"required,min=-180,max=180"`
+	UserLatitude  *float64 `json:"userLatitude" binding:"omitempty,min=-90,max=90"`
+	UserLongitude *float64 `json:"userLongitude" binding:"omitempty,min=-180,max=180"`
+}
+
+// LocationSuggestionItem is one pending coordinate suggestion
+type LocationSuggestionItem struct {
+	ID             uint      `json:"id"`
+	UserID         uint      `json:"userId"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	DistanceMeters int       `json:"distanceMeters"` // mekanın kayıtlı konumuna
+	OnSite         bool      `json:"onSite"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// LocationSuggestionGroup collects the pending suggestions for one place. The
+// cluster is the largest set of suggestions within 50 m of one another;
+// Clustered marks places where enough users agree to review first.
+type LocationSuggestionGroup struct {
+	PlaceID          uint                     `json:"placeId"`
+	PlaceName        string                   `json:"placeName"`
+	Latitude         float64                  `json:"latitude"`
+	Longitude        float64                  `json:"longitude"`
+	Suggestions      []LocationSuggestionItem `json:"suggestions"`
+	ClusterSize      int                      `json:"clusterSize"`
+	ClusterLatitude  float64                  `json:"clusterLatitude"`
+	ClusterLongitude float64                  `json:"clusterLongitude"`
+	Clustered        bool                     `json:"clustered"`
+}
+
+// SuggestPlaceLocation godoc
+// @Summary Suggest corrected coordinates for a place
+// @Description Stores the suggestion for admin review; a user has one pending suggestion per place, and suggesting again replaces it. Sending the user's own position marks the suggestion as made on site when it is within 100 m of the suggested point. The point must be 20 m to 2 km from the stored location.
+// @Tags places
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param request body SuggestPlaceLocationRequest true "Suggested coordinates"
+// @Success 201 {object} StandardResponse{data=LocationSuggestionItem}
+// @Router /places/{placeId}/suggest-location [post]
+func (pc *PlaceController) SuggestPlaceLocation(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	placeID, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Place ID must be a valid number"})
+		return
+	}
+
+	var req SuggestPlaceLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if (req.UserLatitude == nil) != (req.UserLongitude == nil) {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "userLatitude and userLongitude must be given together"})
+		return
+	}
+
+	var place models.Place
+	if err := pc.DB.Select("id, latitude, longitude").Where("needs_review = false").First(&place, placeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
+
+	distance := types.CalculateDistance(place.Latitude, place.Longitude, req.Latitude, req.Longitude) * 1000
+	if distance < minLocationCorrection {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Suggested location is the same as the current one"})
+		return
+	}
+	if distance > maxLocationCorrection {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Suggested location is too far from the place"})
+		return
+	}
+	onSite := req.UserLatitude != nil &&
+		types.CalculateDistance(*req.UserLatitude, *req.UserLongitude, req.Latitude, req.Longitude)*1000 <= onSiteSuggestionRange
+
+	var suggestion models.PlaceLocationSuggestion
+	err = pc.DB.Where("place_id = ? AND user_id = ? AND status = ?", place.ID, user.UserID, LocationSuggestionPending).
+		First(&suggestion).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to save suggestion"})
+		return
+	}
+	suggestion.PlaceID, suggestion.UserID = place.ID, user.UserID
+	suggestion.Latitude, suggestion.Longitude, suggestion.OnSite = req.Latitude, req.Longitude, onSite
+	suggestion.Status = LocationSuggestionPending
+	if err := pc.DB.Save(&suggestion).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to save suggestion"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, StandardResponse{
+		Success: true,
+		Data:    locationSuggestionItem(suggestion, place),
+		Message: "Location suggestion submitted for review",
+	})
+}
+
+func locationSuggestionItem(suggestion models.PlaceLocationSuggestion, place models.Place) LocationSuggestionItem {
+	return LocationSuggestionItem{
+		ID:             suggestion.ID,
+		UserID:         suggestion.UserID,
+		Latitude:       suggestion.Latitude,
+		Longitude:      suggestion.Longitude,
+		DistanceMeters: int(types.CalculateDistance(place.Latitude, place.Longitude, suggestion.Latitude, suggestion.Longitude) * 1000),
+		OnSite:         suggestion.OnSite,
+		CreatedAt:      suggestion.CreatedAt,
+	}
+}
+
+// clusterSuggestions sets the group's largest cluster: the suggestion with
+// the most others within suggestionClusterSize, averaged with those others.
+func clusterSuggestions(group *LocationSuggestionGroup) {
+	for _, center := range group.Suggestions {
+		var size int
+		var latSum, lngSum float64
+		for _, other := range group.Suggestions {
+			if types.CalculateDistance(center.Latitude, center.Longitude, other.Latitude, other.Longitude)*1000 <= suggestionClusterSize {
+				size++
+				latSum += other.Latitude
+				lngSum += other.Longitude
+			}
+		}
+		if size > group.ClusterSize {
+			group.ClusterSize = size
+			group.ClusterLatitude = latSum / float64(size)
+			group.ClusterLongitude = lngSum / float64(size)
+		}
+	}
+	group.Clustered = group.ClusterSize >= suggestionClusterMinUsers
+}
+
+// GetLocationSuggestionQueue godoc
+// @Summary List places with pending location suggestions (admin)
+// @Description Groups pending suggestions by place. Places where at least three users independently suggested points within 50 m of each other come first, by cluster size; the rest follow oldest first.
+// @Tags admin
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse{data=[]LocationSuggestionGroup}
+// @Router /admin/places/location-suggestions [get]
+func (pc *PlaceController) GetLocationSuggestionQueue(c *gin.Context) {
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+
+	var rows []struct {
+		models.PlaceLocationSuggestion
+		PlaceName      string  `gorm:"column:place_name"`
+		PlaceLatitude  float64 `gorm:"column:place_latitude"`
+		PlaceLongitude float64 `gorm:"column:place_longitude"`
+	}
+	if err := pc.DB.Model(&models.PlaceLocationSuggestion{}).
+		Select(`place_location_suggestions.*, places.name AS place_name,
+			places.latitude AS place_latitude, places.longitude AS place_longitude`).
+		Joins("JOIN places ON places.id = place_location_suggestions.place_id AND places.deleted_at IS NULL").
+		Where("place_location_suggestions.status = ?", LocationSuggestionPending).
+		Order("place_location_suggestions.created_at, place_location_suggestions.id").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch location suggestions"})
+		return
+	}
+
+	// Satırlar eskiden yeniye sıralı; grup sırası ilk önerinin zamanıdır
+	groups := []*LocationSuggestionGroup{}
+	byPlace := make(map[uint]*LocationSuggestionGroup)
+	for _, row := range rows {
+		group, ok := byPlace[row.PlaceID]
+		if !ok {
+			group = &LocationSuggestionGroup{
+				PlaceID:   row.PlaceID,
+				PlaceName: row.PlaceName,
+				Latitude:  row.PlaceLatitude,
+				Longitude: row.PlaceLongitude,
+			}
+			byPlace[row.PlaceID] = group
+			groups = append(groups, group)
+		}
+		place := models.Place{Latitude: row.PlaceLatitude, Longitude: row.PlaceLongitude}
+		group.Suggestions = append(group.Suggestions, locationSuggestionItem(row.PlaceLocationSuggestion, place))
+	}
+	for _, group := range groups {
+		clusterSuggestions(group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Clustered != groups[j].Clustered {
+			return groups[i].Clustered
+		}
+		return groups[i].Clustered && groups[i].ClusterSize > groups[j].ClusterSize
+	})
+
+	total := int64(len(groups))
+	pageGroups := []*LocationSuggestionGroup{}
+	if offset := (page - 1) * pageSize; offset < len(groups) {
+		end := offset + pageSize
+		if end > len(groups) {
+			end = len(groups)
+		}
+		pageGroups = groups[offset:end]
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    pageGroups,
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+// ReviewLocationSuggestionRequest; accept mekanı önerilen konuma taşır
+type ReviewLocationSuggestionRequest struct {
+	Action string `json:"action" binding:"required,oneof=accept reject"`
+}
+
+// ReviewLocationSuggestion godoc
+// @Summary Accept or reject a location suggestion (admin)
+// @Description Accepting moves the place to the suggested coordinates and closes the place's other pending suggestions as superseded; rejecting closes only this suggestion.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param suggestionId path string true "Suggestion ID"
+// @Param request body ReviewLocationSuggestionRequest true "accept or reject"
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/location-suggestions/{suggestionId}/review [post]
+func (pc *PlaceController) ReviewLocationSuggestion(c *gin.Context) {
+	adminID := utils.GetUser(c).UserID
+
+	var req ReviewLocationSuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var suggestion models.PlaceLocationSuggestion
+	if err := pc.DB.Where("status = ?", LocationSuggestionPending).First(&suggestion, c.Param("suggestionId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Pending suggestion not found"})
+		return
+	}
+	var place models.Place
+	if err := pc.DB.Select("id, name, latitude, longitude").First(&place, suggestion.PlaceID).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
+
+	now := time.Now()
+	review := map[string]interface{}{"reviewed_by_user_id": adminID, "reviewed_at": now}
+	tx := pc.DB.Begin()
+
+	var err error
+	if req.Action == "accept" {
+		review["status"] = LocationSuggestionAccepted
+		err = tx.Model(&place).Updates(map[string]interface{}{"latitude": suggestion.Latitude, "longitude": suggestion.Longitude}).Error
+		if err == nil {
+			err = tx.Model(&models.PlaceLocationSuggestion{}).
+				Where("place_id = ? AND status = ? AND id <> ?", place.ID, LocationSuggestionPending, suggestion.ID).
+				Updates(map[string]interface{}{"status": LocationSuggestionSuperseded, "reviewed_by_user_id": adminID, "reviewed_at": now}).Error
+		}
+	} else {
+		review["status"] = LocationSuggestionRejected
+	}
+	if err == nil {
+		err = tx.Model(&suggestion).Updates(review).Error
+	}
+	if err == nil {
+		err = recordAdminAction(tx, adminID, "place_location_"+req.Action, "place", place.ID, gin.H{
+			"suggestionId": suggestion.ID,
+			"from":         []float64{place.Latitude, place.Longitude},
+			"to":           []float64{suggestion.Latitude, suggestion.Longitude},
+		})
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review suggestion"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review suggestion"})
+		return
+	}
+
+	data := gin.H{"suggestionId": suggestion.ID, "placeId": place.ID, "action": req.Action}
+	if req.Action == "accept" {
+		data["latitude"], data["longitude"] = suggestion.Latitude, suggestion.Longitude
+	}
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Data: data, Message: "Suggestion reviewed"})
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestPlaceLocationSuggestions(t *testing.T) {
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "locationadmin")
+	lone := createTestPlace(t, db, "locationlone")
+	place := createTestPlace(t, db, "locationmoved")
+	pc := NewPlaceController(db, nil)
+
+	suggest := func(user models.User, target models.Place, body string) int {
+		t.Helper()
+		param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(target.ID))}
+		return callHandler(pc.SuggestPlaceLocation, http.MethodPost, "/places/"+param.Value+"/suggest-location", strings.NewReader(body), user.ID, param).Code
+	}
+	// Enlemde 0.0018 derece yaklaşık 200 m
+	at := func(target models.Place, north, east float64) string {
+		return fmt.Sprintf(`"latitude":%f,"longitude":%f`, target.Latitude+north, target.Longitude+east)
+	}
+
+	// İlk öneri diğer mekana: kümelenmediği için kuyrukta sonra gelir
+	if code := suggest(createTestUser(t, db, "locationloner"), lone, "{"+at(lone, 0.0018, 0)+"}"); code != http.StatusCreated {
+		t.Fatalf("lone suggestion: status %d", code)
+	}
+
+	users := make([]models.User, 4)
+	for i := range users {
+		users[i] = createTestUser(t, db, fmt.Sprintf("locationuser%d", i))
+	}
+	if code := suggest(users[0], place, "{"+at(place, 0.00002, 0)+"}"); code != http.StatusBadRequest {
+		t.Errorf("suggestion a few meters away: status %d, want 400", code)
+	}
+	if code := suggest(users[0], place, "{"+at(place, 0.05, 0)+"}"); code != http.StatusBadRequest {
+		t.Errorf("suggestion kilometers away: status %d, want 400", code)
+	}
+	// Önce yanlış nokta, sonra düzeltme: kullanıcının tek bekleyen önerisi güncellenir
+	if code := suggest(users[0], place, "{"+at(place, 0, 0.006)+"}"); code != http.StatusCreated {
+		t.Fatalf("first suggestion: status %d", code)
+	}
+	onSite := fmt.Sprintf(`{%s,"userLatitude":%f,"userLongitude":%f}`, at(place, 0.0018, 0), place.Latitude+0.0018, place.Longitude)
+	if code := suggest(users[0], place, onSite); code != http.StatusCreated {
+		t.Fatalf("replacing suggestion: status %d", code)
+	}
+	for i, offset := range []float64{0.0019, 0.0017} {
+		if code := suggest(users[i+1], place, "{"+at(place, offset, 0)+"}"); code != http.StatusCreated {
+			t.Fatalf("suggestion %d: status %d", i+1, code)
+		}
+	}
+	if code := suggest(users[3], place, "{"+at(place, 0, 0.006)+"}"); code != http.StatusCreated {
+		t.Fatalf("outlier suggestion: status %d", code)
+	}
+
+	var mine []models.PlaceLocationSuggestion
+	db.Where("user_id = ?", users[0].ID).Find(&mine)
+	if len(mine) != 1 || !mine[0].OnSite {
+		t.Fatalf("first user's suggestions = %+v, want one on-site suggestion", mine)
+	}
+
+	w := callHandler(pc.GetLocationSuggestionQueue, http.MethodGet, "/admin/places/location-suggestions", nil, admin.ID)
+	var queue struct {
+		Data []LocationSuggestionGroup `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &queue); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.Data) != 2 || queue.Data[0].PlaceID != place.ID || queue.Data[1].PlaceID != lone.ID {
+		t.Fatalf("queue = %+v, want the clustered place first", queue.Data)
+	}
+	group := queue.Data[0]
+	if len(group.Suggestions) != 4 || group.ClusterSize != 3 || !group.Clustered {
+		t.Errorf("group = %+v, want four suggestions with a cluster of three", group)
+	}
+	if math.Abs(group.ClusterLatitude-(place.Latitude+0.0018)) > 0.0001 {
+		t.Errorf("cluster latitude = %f, want about %f", group.ClusterLatitude, place.Latitude+0.0018)
+	}
+	if queue.Data[1].Clustered {
+		t.Errorf("single suggestion marked clustered")
+	}
+
+	review := func(id uint, action string) int {
+		t.Helper()
+		param := gin.Param{Key: "suggestionId", Value: strconv.Itoa(int(id))}
+		return callHandler(pc.ReviewLocationSuggestion, http.MethodPost, "/admin/places/location-suggestions/"+param.Value+"/review",
+			strings.NewReader(`{"action":"`+action+`"}`), admin.ID, param).Code
+	}
+	if code := review(mine[0].ID, "accept"); code != http.StatusOK {
+		t.Fatalf("accept: status %d", code)
+	}
+	var moved models.Place
+	if err := db.First(&moved, place.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(moved.Latitude-mine[0].Latitude) > 1e-6 || math.Abs(moved.Longitude-mine[0].Longitude) > 1e-6 {
+		t.Errorf("place at %f,%f, want %f,%f", moved.Latitude, moved.Longitude, mine[0].Latitude, mine[0].Longitude)
+	}
+	var statuses []string
+	db.Model(&models.PlaceLocationSuggestion{}).Where("place_id = ?", place.ID).Order("id").Pluck("status", &statuses)
+	if strings.Join(statuses, ",") != "accepted,superseded,superseded,superseded" {
+		t.Errorf("statuses = %v, want the accepted one and the rest superseded", statuses)
+	}
+	if code := review(mine[0].ID, "accept"); code != http.StatusNotFound {
+		t.Errorf("accept twice: status %d, want 404", code)
+	}
+
+	var loneSuggestion models.PlaceLocationSuggestion
+	db.Where("place_id = ?", lone.ID).First(&loneSuggestion)
+	if code := review(loneSuggestion.ID, "reject"); code != http.StatusOK {
+		t.Fatalf("reject: status %d", code)
+	}
+	var unchanged models.Place
+	db.First(&unchanged, lone.ID)
+	if math.Abs(unchanged.Latitude-lone.Latitude) > 1e-6 {
+		t.Errorf("rejected suggestion moved the place to %f", unchanged.Latitude)
+	}
+
+	var audits int64
+	db.Model(&models.AdminAuditLog{}).Where("action IN ?", []string{"place_location_accept", "place_location_reject"}).Count(&audits)
+	if audits != 2 {
+		t.Errorf("audit entries = %d, want 2", audits)
+	}
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+)
+
+// Tek istekte konumu doğrulanabilecek en fazla mekan sayısı
+const maxValidateLocationPlaces = 50
+
+// ValidateLocationsRequest is the user's position and the places to check it against
+type ValidateLocationsRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	PlaceIDs  []uint  `json:"placeIds" binding:"required,min=1"`
+}
+
+// PlaceLocationCheck tells whether the user is within posting range of one place
+type PlaceLocationCheck struct {
+	PlaceID        uint `json:"placeId"`
+	DistanceMeters int  `json:"distanceMeters"`
+	PostRadius     int  `json:"postRadius"`
+	CanPost        bool `json:"canPost"`
+}
+
+// ValidatePostLocations godoc
+// @Summary Validate the user's position against several places
+// @Description For each place returns the distance from the given position, the place's post radius and whether a post is allowed there. Results follow the order of placeIds; unknown IDs are listed in meta.notFound.
+// @Tags places
+// @Accept json
+// @Produce json
+// @Param request body ValidateLocationsRequest true "User's current coordinates and place IDs (max 50)"
+// @Success 200 {object} StandardResponse{data=[]PlaceLocationCheck}
+// @Router /places/validate-locations [post]
+func (pc *PlaceController) ValidatePostLocations(c *gin.Context) {
+	var req ValidateLocationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if len(req.PlaceIDs) > maxValidateLocationPlaces {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: fmt.Sprintf("placeIds cannot contain more than %d places", maxValidateLocationPlaces),
+		})
+		return
+	}
+
+	// Tüm mekanlar tek sorguda okunur
+	var places []models.Place
+	if err := pc.DB.Select("id, latitude, longitude, categories, post_radius_override").
+		Where("id IN ?", req.PlaceIDs).
+		Find(&places).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching places"})
+		return
+	}
+	byID := make(map[uint]models.Place, len(places))
+	for _, place := range places {
+		byID[place.ID] = place
+	}
+
+	// Sonuçlar istekteki sırayla döner; tekrarlanan ID'ler bir kez listelenir
+	enforceRadius := config.GetPostRadiusPolicy() == config.PostRadiusEnforce
+	checks := []PlaceLocationCheck{}
+	notFound := []uint{}
+	seen := make(map[uint]bool, len(req.PlaceIDs))
+	for _, placeID := range req.PlaceIDs {
+		if seen[placeID] {
+			continue
+		}
+		seen[placeID] = true
+
+		place, ok := byID[placeID]
+		if !ok {
+			notFound = append(notFound, placeID)
+			continue
+		}
+		distanceMeters := types.CalculateDistance(req.Latitude, req.Longitude, place.Latitude, place.Longitude) * 1000
+		postRadius, _, _, _ := types.GetPlacePostRadiusWithOverride(place.Categories, place.PostRadiusOverride)
+		checks = append(checks, PlaceLocationCheck{
+			PlaceID:        place.ID,
+			DistanceMeters: int(distanceMeters),
+			PostRadius:     postRadius,
+			CanPost:        !enforceRadius || distanceMeters <= float64(postRadius),
+		})
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    checks,
+		Meta: gin.H{
+			"notFound": notFound,
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidatePostLocations(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "locationsuser")
+	near := createTestPlace(t, db, "locationsnear")
+	far := createTestPlace(t, db, "locationsfar")
+	wide := createTestPlace(t, db, "locationswide")
+	// Kullanıcıdan ~300 m kuzeydeki iki mekan; biri geniş özel yarıçaplı
+	for _, id := range []uint{far.ID, wide.ID} {
+		if err := db.Table("places").Where("id = ?", id).Update("latitude", near.Latitude+0.0027).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Table("places").Where("id = ?", wide.ID).Update("post_radius_override", 500).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewPlaceController(db, nil)
+	validate := func(placeIDs string) (checks []PlaceLocationCheck, notFound []uint) {
+		t.Helper()
+		body := fmt.Sprintf(`{"latitude":%f,"longitude":%f,"placeIds":%s}`, near.Latitude, near.Longitude, placeIDs)
+		w := callHandler(pc.ValidatePostLocations, http.MethodPost, "/places/validate-locations", strings.NewReader(body), user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", placeIDs, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []PlaceLocationCheck `json:"data"`
+			Meta struct {
+				NotFound []uint `json:"notFound"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data, resp.Meta.NotFound
+	}
+
+	checks, notFound := validate(fmt.Sprintf("[%d,%d,999999,%d,%d]", far.ID, near.ID, wide.ID, near.ID))
+	if len(checks) != 3 {
+		t.Fatalf("checks = %+v, want one per known place", checks)
+	}
+	if !reflect.DeepEqual(notFound, []uint{999999}) {
+		t.Errorf("notFound = %v, want [999999]", notFound)
+	}
+	want := []struct {
+		placeID uint
+		radius  int
+		canPost bool
+	}{
+		{far.ID, 25, false},
+		{near.ID, 25, true},
+		{wide.ID, 500, true},
+	}
+	for i, w := range want {
+		check := checks[i]
+		if check.PlaceID != w.placeID || check.PostRadius != w.radius || check.CanPost != w.canPost {
+			t.Errorf("check %d = %+v, want place %d radius %d canPost %v", i, check, w.placeID, w.radius, w.canPost)
+		}
+	}
+	if checks[0].DistanceMeters < 290 || checks[0].DistanceMeters > 310 || checks[1].DistanceMeters != 0 {
+		t.Errorf("distances = %d and %d m, want ~300 and 0", checks[0].DistanceMeters, checks[1].DistanceMeters)
+	}
+
+	// Yarıçap politikası kapalıyken her mekanda paylaşım yapılabilir
+	t.Setenv("POST_RADIUS_POLICY", "off")
+	if checks, _ := validate(fmt.Sprintf("[%d]", far.ID)); len(checks) != 1 || !checks[0].CanPost {
+		t.Errorf("radius policy off: checks = %+v, want canPost", checks)
+	}
+
+	ids := make([]string, maxValidateLocationPlaces+1)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+	body := fmt.Sprintf(`{"latitude":41,"longitude":29,"placeIds":[%s]}`, strings.Join(ids, ","))
+	if w := callHandler(pc.ValidatePostLocations, http.MethodPost, "/places/validate-locations", strings.NewReader(body), user.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("too many places: status = %d, want 400", w.Code)
+	}
+}
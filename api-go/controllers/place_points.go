@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// Puanları yeniden hesaplanırken mekanlar bu boyutta gruplar halinde okunur
+const placePointsRecomputeBatchSize = 500
+
+// RecomputePlacePointsQuery; DryRun değişiklikleri yazmadan yalnızca raporlar
+type RecomputePlacePointsQuery struct {
+	DryRun bool `form:"dryRun"`
+}
+
+// PlacePointsChange is a place whose stored BasePoints differ from the current scoring
+type PlacePointsChange struct {
+	PlaceID   uint   `json:"placeId"`
+	Name      string `json:"name"`
+	OldPoints int    `json:"oldPoints"`
+	NewPoints int    `json:"newPoints"`
+}
+
+// recomputePlacePoints recalculates BasePoints of every place from its stored
+// categories, rating and ratings total with the current PlaceScoring and
+// returns the places whose points change, with how many were checked. Nothing
+// is written when dryRun is set.
+func recomputePlacePoints(tx *gorm.DB, dryRun bool) (changes []PlacePointsChange, checked int64, err error) {
+	changes = []PlacePointsChange{}
+	var places []models.Place
+	result := tx.Model(&models.Place{}).
+		Select("id, name, categories, rating, user_ratings_total, base_points").
+		FindInBatches(&places, placePointsRecomputeBatchSize, func(batch *gorm.DB, _ int) error {
+			for _, place := range places {
+				points := types.CalculatePlacePoints(place.Categories, place.Rating, place.UserRatingsTotal)
+				if points == place.BasePoints {
+					continue
+				}
+				changes = append(changes, PlacePointsChange{PlaceID: place.ID, Name: place.Name, OldPoints: place.BasePoints, NewPoints: points})
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	// Okuma bittikten sonra yazılır; toplu okuma sırasında güncelleme yapılmaz
+	if !dryRun {
+		for _, change := range changes {
+			if err := tx.Model(&models.Place{}).Where("id = ?", change.PlaceID).
+				Update("base_points", change.NewPoints).Error; err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return changes, result.RowsAffected, nil
+}
+
+// RecomputePlacePoints godoc
+// @Summary Recompute place base points (admin)
+// @Description Re-runs the place scoring over every place's stored categories, rating and ratings total and updates BasePoints that changed, e.g. after the scoring rules change. With dryRun=true the changes are only reported.
+// @Tags admin
+// @Produce json
+// @Param dryRun query boolean false "Report the changes without writing them"
+// @Success 200 {object} StandardResponse
+// @Router /admin/places/recompute-points [post]
+func (pc *PlaceController) RecomputePlacePoints(c *gin.Context) {
+	var query RecomputePlacePointsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	tx := pc.DB.Begin()
+
+	changes, checked, err := recomputePlacePoints(tx, query.DryRun)
+	if err == nil && !query.DryRun {
+		err = recordAdminAction(tx, utils.GetUser(c).UserID, "place_points_recompute", "system", 0, gin.H{"checked": checked, "changed": len(changes)})
+	}
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to recompute place points"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to recompute place points"})
+		return
+	}
+
+	message := "Place points recomputed"
+	if query.DryRun {
+		message = "Place points not changed (dry run)"
+	}
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"checked": checked,
+			"changed": len(changes),
+			"dryRun":  query.DryRun,
+			"changes": changes,
+		},
+		Message: message,
+	})
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestRecomputePlacePoints(t *testing.T) {
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "pointsadmin")
+	stale := createTestPlace(t, db, "stalepoints")
+	current := createTestPlace(t, db, "currentpoints")
+
+	// Eski kurallarla hesaplanmış puan: güncel hesaptan farklı
+	rating, total := 4.6, 1200
+	want := types.CalculatePlacePoints([]string{"museum"}, &rating, &total)
+	if err := db.Model(&stale).Updates(map[string]interface{}{
+		"categories": pq.StringArray{"museum"}, "rating": rating, "user_ratings_total": total, "base_points": want - 10,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&current).Update("base_points", types.CalculatePlacePoints(nil, nil, nil)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewPlaceController(db, nil)
+	recompute := func(target string) (changed int, changes []PlacePointsChange) {
+		t.Helper()
+		w := callHandler(pc.RecomputePlacePoints, http.MethodPost, target, nil, admin.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Checked int                 `json:"checked"`
+				Changed int                 `json:"changed"`
+				Changes []PlacePointsChange `json:"changes"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Data.Checked != 2 {
+			t.Errorf("%s: checked = %d, want 2", target, resp.Data.Checked)
+		}
+		return resp.Data.Changed, resp.Data.Changes
+	}
+	storedPoints := func() int {
+		t.Helper()
+		var place models.Place
+		if err := db.Select("base_points").First(&place, stale.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return place.BasePoints
+	}
+
+	changed, changes := recompute("/admin/places/recompute-points?dryRun=true")
+	wantChange := PlacePointsChange{PlaceID: stale.ID, Name: stale.Name, OldPoints: want - 10, NewPoints: want}
+	if changed != 1 || len(changes) != 1 || changes[0] != wantChange {
+		t.Fatalf("dry run changes = %d %+v, want %+v", changed, changes, wantChange)
+	}
+	if got := storedPoints(); got != want-10 {
+		t.Errorf("dry run wrote base points %d", got)
+	}
+
+	if changed, _ := recompute("/admin/places/recompute-points"); changed != 1 {
+		t.Errorf("changed = %d, want 1", changed)
+	}
+	if got := storedPoints(); got != want {
+		t.Errorf("base points = %d, want %d", got, want)
+	}
+	var audits int64
+	db.Model(&models.AdminAuditLog{}).Where("action = ?", "place_points_recompute").Count(&audits)
+	if audits != 1 {
+		t.Errorf("audit entries = %d, want 1 (dry runs are not logged)", audits)
+	}
+
+	if changed, _ := recompute("/admin/places/recompute-points"); changed != 0 {
+		t.Errorf("second run changed = %d, want 0", changed)
+	}
+}
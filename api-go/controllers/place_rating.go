@@ -0,0 +1,14 @@
+package controllers
+
+import "github.com/snap-point/api-go/config"
+
+// minRatingCondition filters out places rated below minRating. Unrated places
+// pass or fail according to NULL_RATING_POLICY, which is returned so
+// responses can echo the applied filter.
+func minRatingCondition(column string, minRating float64) (string, []interface{}, string) {
+	policy := config.GetNullRatingPolicy()
+	if policy == config.NullRatingExclude {
+		return column + " >= ?", []interface{}{minRating}, policy
+	}
+	return "(" + column + " >= ? OR " + column + " IS NULL)", []interface{}{minRating}, policy
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/types"
+)
+
+func TestMinRatingFilter(t *testing.T) {
+	t.Setenv("GOOGLE_PLACES_API_KEY", "")
+	db := openTestDB(t)
+	user := createTestUser(t, db, "ratinguser")
+	above, at, below := 4.5, 3.0, 2.9
+	names := map[uint]string{}
+	for _, seed := range []struct {
+		name   string
+		rating *float64
+	}{
+		{"ratingabove", &above},
+		{"ratingat", &at},
+		{"ratingbelow", &below},
+		{"ratingunrated", nil},
+	} {
+		place := createTestPlace(t, db, seed.name)
+		if seed.rating != nil {
+			if err := db.Model(&place).Update("rating", *seed.rating).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		names[place.ID] = seed.name
+	}
+
+	pc := NewPlaceController(db, nil)
+	nearby := func(params string) ([]string, types.NearbyPlacesFilters) {
+		t.Helper()
+		target := fmt.Sprintf("/places/nearby?latitude=41.0082&longitude=28.9784&zoomLevel=15%s", params)
+		w := callHandler(pc.GetNearbyPlaces, http.MethodGet, target, nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, w.Code, w.Body.String())
+		}
+		var resp types.NearbyPlacesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, marker := range resp.Markers {
+			got = append(got, names[marker.ID])
+		}
+		sort.Strings(got)
+		return got, resp.Filters
+	}
+	sc := NewSearchController(db)
+	search := func(params string) ([]string, map[string]interface{}) {
+		t.Helper()
+		w := callHandler(sc.Search, http.MethodGet, "/search?q=rating&type=places"+params, nil, user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("search %s: status = %d, body = %s", params, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Places []SearchPlaceResult `json:"places"`
+			} `json:"data"`
+			Meta map[string]interface{} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, place := range resp.Data.Places {
+			got = append(got, place.Name)
+		}
+		sort.Strings(got)
+		return got, resp.Meta
+	}
+
+	all := []string{"ratingabove", "ratingat", "ratingbelow", "ratingunrated"}
+	if got, filters := nearby(""); !reflect.DeepEqual(got, all) || filters.MinRating != nil || filters.NullRating != "" {
+		t.Errorf("nearby without minRating = %v %+v, want every place and no rating filter", got, filters)
+	}
+	if got, meta := search(""); !reflect.DeepEqual(got, all) || meta["minRating"] != nil {
+		t.Errorf("search without minRating = %v %v, want every place", got, meta)
+	}
+
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{config.NullRatingInclude, []string{"ratingabove", "ratingat", "ratingunrated"}},
+		{config.NullRatingExclude, []string{"ratingabove", "ratingat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			t.Setenv("NULL_RATING_POLICY", tt.policy)
+
+			got, filters := nearby("&minRating=3")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nearby = %v, want %v", got, tt.want)
+			}
+			if filters.MinRating == nil || *filters.MinRating != 3 || filters.NullRating != tt.policy {
+				t.Errorf("nearby filters = %+v, want minRating 3 with %s", filters, tt.policy)
+			}
+
+			got, meta := search("&minRating=3")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search = %v, want %v", got, tt.want)
+			}
+			if meta["minRating"] != 3.0 || meta["nullRating"] != tt.policy {
+				t.Errorf("search meta = %v, want minRating 3 with %s", meta, tt.policy)
+			}
+		})
+	}
+
+	if w := callHandler(sc.Search, http.MethodGet, "/search?q=rating&minRating=6", nil, user.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("search minRating over 5: status = %d, want 400", w.Code)
+	}
+}
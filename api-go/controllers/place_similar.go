@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+// SimilarPlacesQuery; Radius km cinsindendir
+type SimilarPlacesQuery struct {
+	Radius float64 `form:"radius,default=5" binding:"gt=0,max=50"`
+	Limit  int     `form:"limit,default=10" binding:"min=1,max=50"`
+}
+
+// SimilarPlaceItem is a nearby place sharing categories with the source place
+type SimilarPlaceItem struct {
+	SimplifiedPlace
+	SharedCategories []string `json:"sharedCategories" gorm:"-"`
+	Rating           *float64 `json:"rating,omitempty"`
+	Distance         float64  `json:"distance"` // kaynak mekana km
+}
+
+// similarPlaceDistanceSQL kaynak mekana uzaklık (km); argümanlar: enlem, boylam, enlem
+const similarPlaceDistanceSQL = `(6371 * acos(LEAST(1, cos(radians(?)) * cos(radians(places.latitude)) * cos(radians(places.longitude) - radians(?)) + sin(radians(?)) * sin(radians(places.latitude)))))`
+
+// GetSimilarPlaces godoc
+// @Summary Suggest similar places near a place
+// @Description Returns places within radius km of the place that share at least one category with it, ranked by the number of shared categories, then rating, then distance. The place itself and places under review are excluded.
+// @Tags places
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param radius query number false "Search radius in km (default: 5, max: 50)"
+// @Param limit query integer false "Maximum places (default: 10, max: 50)"
+// @Success 200 {object} StandardResponse{data=[]SimilarPlaceItem}
+// @Router /places/{placeId}/similar [get]
+func (pc *PlaceController) GetSimilarPlaces(c *gin.Context) {
+	if utils.GetUser(c) == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	placeID, err := strconv.Atoi(c.Param("placeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Place ID must be a valid number"})
+		return
+	}
+
+	var query SimilarPlacesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	var source models.Place
+	if err := pc.DB.Select("id, categories, latitude, longitude").
+		Where("needs_review = false").First(&source, placeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Place not found"})
+		return
+	}
+
+	places := []SimilarPlaceItem{}
+	if len(source.Categories) > 0 {
+		if err := pc.DB.Model(&models.Place{}).
+			Select(`places.id, places.name, places.categories, places.address, places.latitude, places.longitude,
+				places.base_points AS base_score, places.place_type, places.place_image, places.is_verified, places.features,
+				places.rating, `+similarPlaceDistanceSQL+` AS distance,
+				cardinality(ARRAY(SELECT unnest(places.categories) INTERSECT SELECT unnest(?::text[]))) AS shared_count`,
+				source.Latitude, source.Longitude, source.Latitude, source.Categories).
+			Where("places.id <> ? AND places.needs_review = false AND places.categories && ?::text[]", source.ID, source.Categories).
+			Where(similarPlaceDistanceSQL+" <= ?", source.Latitude, source.Longitude, source.Latitude, query.Radius).
+			Order("shared_count DESC, places.rating DESC NULLS LAST, distance, places.id").
+			Limit(query.Limit).
+			Scan(&places).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching similar places"})
+			return
+		}
+	}
+
+	for i := range places {
+		places[i].SharedCategories = sharedCategories(source.Categories, places[i].Categories)
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    places,
+		Meta: gin.H{
+			"placeId":    source.ID,
+			"categories": source.Categories,
+			"radius":     query.Radius,
+		},
+	})
+}
+
+// sharedCategories returns the categories of source also found in other, in source order
+func sharedCategories(source, other pq.StringArray) []string {
+	shared := []string{}
+	for _, category := range source {
+		for _, candidate := range other {
+			if candidate == category {
+				shared = append(shared, category)
+				break
+			}
+		}
+	}
+	return shared
+}
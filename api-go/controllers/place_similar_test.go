@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetSimilarPlaces(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "similarviewer")
+	place := func(name string, categories []string, rating float64, northKm float64) models.Place {
+		t.Helper()
+		p := createTestPlace(t, db, name)
+		// Enlemde 0.009 derece yaklaşık 1 km
+		if err := db.Model(&p).Updates(map[string]interface{}{
+			"categories": pq.StringArray(categories), "rating": rating, "latitude": p.Latitude + northKm*0.009,
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	source := place("similarsource", []string{"museum", "tourist_attraction"}, 4.5, 0)
+	gallery := place("similargallery", []string{"art_gallery", "museum", "tourist_attraction"}, 4.1, 2)
+	museum := place("similarmuseum", []string{"museum"}, 4.8, 1)
+	attractionCafe := place("similarviewcafe", []string{"cafe", "tourist_attraction"}, 4.3, 0.5)
+	place("similarcafe", []string{"cafe"}, 4.9, 0.2)
+	place("similarfarmuseum", []string{"museum"}, 5, 40)
+	deleted := place("similardeleted", []string{"museum"}, 5, 1)
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewPlaceController(db, nil)
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(source.ID))}
+	w := callHandler(pc.GetSimilarPlaces, http.MethodGet, "/places/"+param.Value+"/similar", nil, user.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []SimilarPlaceItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ortak kategori sayısı, sonra puan: müze ve galeri ilgisiz kafelerden önce gelir
+	var ids []uint
+	for _, item := range resp.Data {
+		ids = append(ids, item.ID)
+	}
+	if want := []uint{gallery.ID, museum.ID, attractionCafe.ID}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("similar places = %v, want %v", ids, want)
+	}
+	if got := resp.Data[0].SharedCategories; !reflect.DeepEqual(got, []string{"museum", "tourist_attraction"}) {
+		t.Errorf("gallery shared categories = %v", got)
+	}
+	if got := resp.Data[2].SharedCategories; !reflect.DeepEqual(got, []string{"tourist_attraction"}) {
+		t.Errorf("cafe shared categories = %v", got)
+	}
+	if d := resp.Data[1].Distance; d < 0.9 || d > 1.1 {
+		t.Errorf("museum distance = %f km, want about 1", d)
+	}
+
+	missing := gin.Param{Key: "placeId", Value: "999999"}
+	if w := callHandler(pc.GetSimilarPlaces, http.MethodGet, "/places/999999/similar", nil, user.ID, missing); w.Code != http.StatusNotFound {
+		t.Errorf("unknown place: status = %d, want 404", w.Code)
+	}
+}
@@ -5,16 +5,24 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/media"
 	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
 	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostController struct {
-	DB *gorm.DB
+	DB               *gorm.DB
+	UploadController *UploadController
 }
 
 // Common response structures
@@ -45,6 +53,7 @@ type PostMediaItem struct {
 	Height     int      `json:"height"`
 	Duration   int      `json:"duration"`
 	Tags       []string `json:"tags"`
+	IsAnimated bool     `json:"isAnimated"` // GIF/canlı fotoğraf: istemci hareketli oynatır
 }
 
 type PostInteraction struct {
@@ -77,6 +86,7 @@ type PostDetail struct {
 	Latitude      float64         `json:"latitude"`
 	Longitude     float64         `json:"longitude"`
 	EarnedPoints  int64           `json:"earnedPoints"`
+	IsDiscovery   bool            `json:"isDiscovery"`
 	IsPublic      bool            `json:"isPublic"`
 	AllowComments bool            `json:"allowComments"`
 	User          PostUser        `json:"user"`
@@ -90,31 +100,37 @@ type PostDetail struct {
 		CreatedAt time.Time `json:"createdAt"`
 		User      PostUser  `json:"user"`
 	} `json:"recentComments"`
+	// Gömülü listelerden fazla beğeni/yorum varsa tam liste ekranı açılabilir
+	RecentLikesHasMore    bool `json:"recentLikesHasMore"`
+	RecentCommentsHasMore bool `json:"recentCommentsHasMore"`
+}
+
+type CreatePostMediaItem struct {
+	MediaType string   `json:"mediaType" binding:"required,mediatype"`
+	MediaURL  string   `json:"mediaUrl" binding:"required"`
+	Width     int      `json:"width"`
+	Height    int      `json:"height"`
+	Duration  int      `json:"duration"`
+	AltText   string   `json:"altText"`
+	Tags      []string `json:"tags"`
 }
 
 type CreatePostRequest struct {
-	PostCaption string `json:"postCaption" binding:"omitempty"`
-	MediaItems  []struct {
-		MediaType string   `json:"mediaType" binding:"required,oneof=photo video"`
-		MediaURL  string   `json:"mediaUrl" binding:"required"`
-		Width     int      `json:"width"`
-		Height    int      `json:"height"`
-		Duration  int      `json:"duration"`
-		AltText   string   `json:"altText"`
-		Tags      []string `json:"tags"`
-	} `json:"mediaItems" binding:"required,dive"`
-	PlaceID       uint    `json:"placeId" binding:"required"`
-	Latitude      float64 `json:"latitude" binding:"required"`
-	Longitude     float64 `json:"longitude" binding:"required"`
-	IsPublic      bool    `json:"isPublic" default:"true"`
-	AllowComments bool    `json:"allowComments" default:"true"`
+	PostCaption   string                `json:"postCaption" binding:"omitempty"`
+	MediaItems    []CreatePostMediaItem `json:"mediaItems" binding:"required,dive"`
+	PlaceID       uint                  `json:"placeId" binding:"required"`
+	Latitude      float64               `json:"latitude" binding:"required"`
+	Longitude     float64               `json:"longitude" binding:"required"`
+	IsPublic      bool                  `json:"isPublic" default:"true"`
+	AllowComments bool                  `json:"allowComments" default:"true"`
+	Language      string                `json:"language"` // ISO 639-1 code, e.g. "tr" or "en-US"
 }
 
 type UpdatePostRequest struct {
 	Content    string `json:"content"`
 	MediaItems []struct {
 		MediaID    uint     `json:"mediaId,omitempty"`
-		MediaType  string   `json:"mediaType" binding:"omitempty,oneof=photo video"`
+		MediaType  string   `json:"mediaType" binding:"omitempty,mediatype"`
 		MediaURL   string   `json:"mediaUrl"`
 		Width      int      `json:"width"`
 		Height     int      `json:"height"`
@@ -123,12 +139,42 @@ type UpdatePostRequest struct {
 		OrderIndex int      `json:"orderIndex"`
 		Tags       []string `json:"tags"`
 	} `json:"mediaItems"`
-	IsPublic      *bool `json:"isPublic"`
-	AllowComments *bool `json:"allowComments"`
+	IsPublic      *bool   `json:"isPublic"`
+	AllowComments *bool   `json:"allowComments"`
+	Language      *string `json:"language"`
+}
+
+// Varsayılan gönderi sınırı: 10 dakikada en fazla 5 gönderi (RATE_LIMIT_POST_CREATE ile değiştirilebilir)
+const (
+	defaultPostCooldownPosts  = 5
+	defaultPostCooldownWindow = 10 * time.Minute
+)
+
+// Tek istekte sorgulanabilecek en fazla mekan sayısı
+const maxGridPlaceIDs = 50
+
+// Gönderi detayına gömülen en yeni beğeni ve yorum sayısı
+const (
+	postDetailRecentLikes    = 10
+	postDetailRecentComments = 20
+)
+
+// LikedStatusRequest; tek istekte en fazla 100 gönderi sorgulanabilir
+type LikedStatusRequest struct {
+	PostIDs []uint `json:"postIds" binding:"required,min=1,max=100"`
+}
+
+type MultiPlacePostsGridRequest struct {
+	PlaceIDs []uint `json:"placeIds" binding:"required,min=1"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"pageSize"`
 }
 
-func NewPostController(db *gorm.DB) *PostController {
-	return &PostController{DB: db}
+func NewPostController(db *gorm.DB, uploadController *UploadController) *PostController {
+	return &PostController{
+		DB:               db,
+		UploadController: uploadController,
+	}
 }
 
 // CreatePost godoc
@@ -138,10 +184,17 @@ func NewPostController(db *gorm.DB) *PostController {
 // @Accept json
 // @Produce json
 // @Param post body CreatePostRequest true "Post creation request"
+// @Param Idempotency-Key header string false "Retries with the same key return the first post instead of creating another"
 // @Success 201 {object} models.Post
 // @Router /posts [post]
 func (pc *PostController) CreatePost(c *gin.Context) {
-	user := utils.GetUser(c)
+	// Satır burada bir kez yüklenir; yanıt yazılırken aynı istekte tekrar sorgulanmaz
+	user, err := utils.GetCurrentUser(c, pc.DB)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
 	var req CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		fmt.Println(err, "burda err var")
@@ -149,17 +202,109 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 		return
 	}
 
+	var idempotencyKey *models.IdempotencyKey
+	if key := strings.TrimSpace(c.GetHeader("Idempotency-Key")); key != "" {
+		var handled bool
+		idempotencyKey, handled = pc.claimIdempotencyKey(c, user, key, req)
+		if handled {
+			return
+		}
+	}
+
+	// Anahtar gönderiyle aynı transaction içinde tamamlanır; biri olmadan diğeri kalmaz
+	var beforeCommit func(tx *gorm.DB, post models.Post, earnedPoints int64) error
+	if idempotencyKey != nil {
+		beforeCommit = func(tx *gorm.DB, post models.Post, earnedPoints int64) error {
+			return completeIdempotencyKey(tx, idempotencyKey, post.ID, earnedPoints)
+		}
+	}
+	post, earnedPoints, ok := pc.createPost(c, user.ID, req, beforeCommit)
+	if !ok {
+		releaseIdempotencyKey(pc.DB, idempotencyKey)
+		return
+	}
+
+	pc.respondCreatedPost(c, user, post, earnedPoints)
+}
+
+// createPost validates req (media, location, language) and creates the post with its
+// media, activity log and points in one transaction. The first post ever made at a
+// place is marked as a discovery and earns NoPostsBonusPoints on top. beforeCommit,
+// when not nil, runs inside that transaction last with the new post and its points. On failure it has already written
+// the error response and returns false. Shared by CreatePost and draft publishing.
+func (pc *PostController) createPost(c *gin.Context, userID uint, req CreatePostRequest, beforeCommit func(tx *gorm.DB, post models.Post, earnedPoints int64) error) (models.Post, int64, bool) {
 	// Validate that at least one media item is provided
 	if len(req.MediaItems) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one media item is required"})
-		return
+		return models.Post{}, 0, false
+	}
+
+	if field, msg := validateCaption("postCaption", req.PostCaption); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+		return models.Post{}, 0, false
+	}
+
+	mediaURLs := make([]string, len(req.MediaItems))
+	mediaTypes := make([]string, len(req.MediaItems))
+	for i, item := range req.MediaItems {
+		mediaURLs[i] = item.MediaURL
+		mediaTypes[i] = item.MediaType
+	}
+	if field, msg := validateMediaItems(mediaURLs); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+		return models.Post{}, 0, false
+	}
+	if field, msg := validateMediaTypesEnabled(mediaTypes); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+		return models.Post{}, 0, false
+	}
+	if field, msg := pc.validateMediaOwnership(mediaURLs, userID); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+		return models.Post{}, 0, false
+	}
+
+	var language string
+	if req.Language != "" {
+		code, ok := utils.NormalizeLanguageCode(req.Language)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Language must be an ISO 639-1 code", "field": "language"})
+			return models.Post{}, 0, false
+		}
+		language = code
+	}
+
+	// Spam önleme: yöneticiler hariç kısa sürede çok fazla gönderi engellenir
+	if !utils.GetUser(c).IsAdmin() {
+		cooldowns := []struct {
+			limit   config.RateLimit
+			placeID uint
+		}{
+			{config.GetRateLimit("post_create", defaultPostCooldownPosts, defaultPostCooldownWindow), 0},
+			{config.GetRateLimit("post_create_place", 0, 0), req.PlaceID}, // RATE_LIMIT_POST_CREATE_PLACE ile açılır
+		}
+		for _, cooldown := range cooldowns {
+			remaining, err := pc.postCooldownRemaining(userID, cooldown.placeID, cooldown.limit)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check posting limit"})
+				return models.Post{}, 0, false
+			}
+			if remaining > 0 {
+				retryAfter := int(math.Ceil(remaining.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+				c.JSON(http.StatusTooManyRequests, gin.H{
+					"error":      "You are posting too frequently, please wait",
+					"retryAfter": retryAfter,
+				})
+				return models.Post{}, 0, false
+			}
+		}
 	}
 
 	// Get place details
 	var place models.Place
 	if err := pc.DB.First(&place, req.PlaceID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
-		return
+		return models.Post{}, 0, false
 	}
 
 	// Verify user's location is near the place
@@ -168,9 +313,10 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 		place.Latitude, place.Longitude,
 	)
 
-	// Maximum allowed distance in meters (e.g., 100 meters)
-	const maxDistance = 100.0
-	if distance > maxDistance {
+	// Maximum allowed distance in meters: place override, else category radius
+	postRadius, _, _, _ := types.GetPlacePostRadiusWithOverride(place.Categories, place.PostRadiusOverride)
+	maxDistance := float64(postRadius)
+	if config.GetPostRadiusPolicy() == config.PostRadiusEnforce && distance > maxDistance {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "You must be at the location to create a post",
 			"distance": gin.H{
@@ -178,34 +324,100 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 				"maximum": maxDistance,
 			},
 		})
-		return
+		return models.Post{}, 0, false
+	}
+
+	// Yükleme onayında okunan boyutlar istemcinin bildirdiklerinin yerine geçer
+	uploads, err := pc.verifiedUploads(mediaURLs, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load media dimensions"})
+		return models.Post{}, 0, false
+	}
+
+	// Aynı görüntünün aynı mekanda kısa sürede tekrar paylaşılması politikaya göre reddedilir veya işaretlenir
+	needsReview := false
+	if policy := config.GetDuplicatePostPolicy(); policy != config.DuplicatePostOff {
+		hashes := make([]string, 0, len(uploads))
+		for _, upload := range uploads {
+			if upload.PerceptualHash != "" {
+				hashes = append(hashes, upload.PerceptualHash)
+			}
+		}
+		duplicateOf, err := pc.findDuplicatePost(userID, req.PlaceID, mediaURLs, hashes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for duplicate posts"})
+			return models.Post{}, 0, false
+		}
+		if duplicateOf != 0 {
+			if policy == config.DuplicatePostReject {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":       "You already posted this media at this place recently",
+					"duplicateOf": duplicateOf,
+				})
+				return models.Post{}, 0, false
+			}
+			needsReview = true
+		}
 	}
 
 	// Start transaction
 	tx := pc.DB.Begin()
 
+	// Mekan satırını kilitle: aynı anda gelen ilk gönderilerden yalnızca biri keşif bonusu alsın
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Place{}, place.ID).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Place not found"})
+		return models.Post{}, 0, false
+	}
+
+	// Silinen gönderiler de sayılır; sil-yeniden-paylaş ile bonus tekrar alınamaz
+	var priorPosts int64
+	if err := tx.Unscoped().Model(&models.Post{}).Where("place_id = ?", place.ID).Count(&priorPosts).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check place posts"})
+		return models.Post{}, 0, false
+	}
+	isDiscovery := priorPosts == 0
+
 	// Create post
 	earnedPoints := calculateInitialPoints(place.BasePoints, req.MediaItems[0].MediaType)
+	discoveryBonus := 0
+	if isDiscovery {
+		discoveryBonus = types.GetPointsConfig().NoPostsBonusPoints
+		earnedPoints += int64(discoveryBonus)
+	}
+	// İşaretlenen gönderi puanını ancak yönetici onayladığında kazanır
+	if needsReview {
+		earnedPoints, discoveryBonus = 0, 0
+	}
 	post := models.Post{
 		PostCaption:   req.PostCaption,
-		UserID:        user.UserID,
+		UserID:        userID,
 		PlaceID:       req.PlaceID,
 		Latitude:      req.Latitude,
 		Longitude:     req.Longitude,
 		IsPublic:      req.IsPublic,
 		AllowComments: req.AllowComments,
+		Language:      language,
 		EarnedPoints:  earnedPoints,
+		IsDiscovery:   isDiscovery,
+		NeedsReview:   needsReview,
 		CreatedAt:     time.Now(),
 	}
 
 	if err := tx.Create(&post).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
-		return
+		return models.Post{}, 0, false
 	}
 
 	// Create media items
 	for i, mediaItem := range req.MediaItems {
+		upload, verified := uploads[mediaItem.MediaURL]
+		if verified {
+			mediaItem.Width, mediaItem.Height, mediaItem.Duration = upload.Width, upload.Height, upload.Duration
+		}
+
 		postMedia := models.PostMedia{
 			PostID:     post.ID,
 			MediaType:  mediaItem.MediaType,
@@ -216,21 +428,24 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 			Height:     mediaItem.Height,
 			Duration:   mediaItem.Duration,
 			Tags:       mediaItem.Tags,
+			// Onaylanmamış yüklemelerin özeti yoktur
+			PerceptualHash: upload.PerceptualHash,
 		}
 
 		if err := tx.Create(&postMedia).Error; err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create media items"})
-			return
+			return models.Post{}, 0, false
 		}
 	}
 
 	// Create activity log
 	activity := models.ActivityLog{
-		UserID:    user.UserID,
+		UserID:    userID,
 		PlaceID:   req.PlaceID,
 		PostID:    post.ID,
 		Activity:  "post_created",
+		Points:    int(earnedPoints),
 		Latitude:  req.Latitude,
 		Longitude: req.Longitude,
 		CreatedAt: time.Now(),
@@ -239,15 +454,103 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 	if err := tx.Create(&activity).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity log"})
-		return
+		return models.Post{}, 0, false
+	}
+
+	// Keşif bonusu ayrıca kaydedilir; puanı post_created kaydındaki toplamın içindedir
+	if isDiscovery {
+		discovery := models.ActivityLog{
+			UserID:    userID,
+			PlaceID:   req.PlaceID,
+			PostID:    post.ID,
+			Activity:  "place_discovered",
+			Points:    discoveryBonus,
+			Latitude:  req.Latitude,
+			Longitude: req.Longitude,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&discovery).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create activity log"})
+			return models.Post{}, 0, false
+		}
+	}
+
+	// Update user points (add earned points atomically, mirrors DeletePost)
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).
+		Update("total_points", gorm.Expr("total_points + ?", earnedPoints)).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user points"})
+		return models.Post{}, 0, false
+	}
+
+	if err := adjustPostsCount(tx, userID, 1); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		return models.Post{}, 0, false
+	}
+
+	// Bekleyen davet, iki hesap da yaş koşulunu sağlıyorsa bu gönderiyle tamamlanır
+	referral, err := completeReferral(tx, userID, post.ID)
+	if err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+		return models.Post{}, 0, false
+	}
+
+	if beforeCommit != nil {
+		if err := beforeCommit(tx, post, earnedPoints); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create post"})
+			return models.Post{}, 0, false
+		}
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
+		return models.Post{}, 0, false
+	}
+
+	if isDiscovery {
+		notifyUser(pc.DB, userID, 0, "place_discovered", &post.ID)
 	}
+	if referral != nil {
+		notifyUser(pc.DB, referral.ReferrerUserID, userID, "referral_completed", nil)
+	}
+
+	return post, earnedPoints, true
+}
+
+// postCooldownRemaining returns how long userID must wait before posting again under limit,
+// counting posts at placeID only when it is non-zero. A zero limit disables the check.
+// Deleted posts still count so delete-and-repost cannot bypass the limit.
+func (pc *PostController) postCooldownRemaining(userID, placeID uint, limit config.RateLimit) (time.Duration, error) {
+	if limit.Requests < 1 || limit.Window <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	db := pc.DB.Unscoped().Model(&models.Post{}).
+		Where("user_id = ? AND created_at >= ?", userID, now.Add(-limit.Window))
+	if placeID != 0 {
+		db = db.Where("place_id = ?", placeID)
+	}
+
+	// Penceredeki N. en yeni gönderi pencereden çıktığında tekrar gönderi atılabilir
+	var createdAt []time.Time
+	if err := db.Order("created_at DESC").Offset(limit.Requests-1).Limit(1).Pluck("created_at", &createdAt).Error; err != nil {
+		return 0, err
+	}
+	if len(createdAt) == 0 {
+		return 0, nil
+	}
+
+	return createdAt[0].Add(limit.Window).Sub(now), nil
+}
 
+// respondCreatedPost writes the 201 response for a newly created post of author
+func (pc *PostController) respondCreatedPost(c *gin.Context, author *models.User, post models.Post, earnedPoints int64) {
 	// Return created post with additional info
 	type PostResponse struct {
 		models.Post
@@ -260,16 +563,21 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 	var postResponse PostResponse
 
 	pc.DB.Model(&post).
-		Select("posts.*, users.username, places.name as place_name").
-		Joins("JOIN users ON posts.user_id = users.id").
+		Select("posts.*, places.name as place_name").
 		Joins("JOIN places ON posts.place_id = places.id").
 		First(&postResponse)
+	postResponse.Username = author.Username
 
 	// Get media items
 	pc.DB.Model(&models.PostMedia{}).
 		Where("post_id = ?", post.ID).
 		Order("order_index").
 		Find(&postResponse.MediaItems)
+	for i := range postResponse.MediaItems {
+		item := &postResponse.MediaItems[i]
+		item.MediaURL = pc.UploadController.mediaReadURL(item.MediaURL)
+		item.ThumbnailURL = pc.UploadController.mediaReadURL(item.ThumbnailURL)
+	}
 
 	postResponse.PointsEarned = earnedPoints
 
@@ -287,7 +595,12 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 // @Success 200 {object} models.Post
 // @Router /posts/{id} [put]
 func (pc *PostController) UpdatePost(c *gin.Context) {
-	userID := c.GetUint("userID")
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	userID := currentUser.UserID
 	postID := c.Param("id")
 	var req UpdatePostRequest
 
@@ -309,14 +622,52 @@ func (pc *PostController) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	if field, msg := validateCaption("content", req.Content); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+		return
+	}
+
+	if len(req.MediaItems) > 0 {
+		mediaURLs := make([]string, len(req.MediaItems))
+		mediaTypes := make([]string, len(req.MediaItems))
+		for i, item := range req.MediaItems {
+			mediaURLs[i] = item.MediaURL
+			mediaTypes[i] = item.MediaType
+		}
+		if field, msg := validateMediaItems(mediaURLs); field != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+			return
+		}
+		if field, msg := validateMediaTypesEnabled(mediaTypes); field != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+			return
+		}
+		if field, msg := pc.validateMediaOwnership(mediaURLs, userID); field != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg, "field": field})
+			return
+		}
+	}
+
+	// Boş değer dil etiketini kaldırır
+	var language string
+	if req.Language != nil && *req.Language != "" {
+		code, ok := utils.NormalizeLanguageCode(*req.Language)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Language must be an ISO 639-1 code", "field": "language"})
+			return
+		}
+		language = code
+	}
+
 	// Start transaction
 	tx := pc.DB.Begin()
 
 	// Update post fields if provided
 	updates := make(map[string]interface{})
 
+	// Açıklama post_caption sütununda tutulur
 	if req.Content != "" {
-		updates["content"] = req.Content
+		updates["post_caption"] = req.Content
 	}
 	if req.IsPublic != nil {
 		updates["is_public"] = *req.IsPublic
@@ -324,6 +675,9 @@ func (pc *PostController) UpdatePost(c *gin.Context) {
 	if req.AllowComments != nil {
 		updates["allow_comments"] = *req.AllowComments
 	}
+	if req.Language != nil {
+		updates["language"] = language
+	}
 	updates["updated_at"] = time.Now()
 
 	// Update post
@@ -463,7 +817,12 @@ func (pc *PostController) UpdatePost(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /posts/{id} [delete]
 func (pc *PostController) DeletePost(c *gin.Context) {
-	userID := c.GetUint("userID")
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+	userID := currentUser.UserID
 	postID := c.Param("id")
 
 	// Get existing post
@@ -497,16 +856,18 @@ func (pc *PostController) DeletePost(c *gin.Context) {
 	}
 
 	// Delete comments
-	if err := tx.Where("post_id = ?", postID).Delete(&models.Comment{}).Error; err != nil {
+	if err := tx.Unscoped().Where("post_id = ?", postID).Delete(&models.Comment{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comments"})
 		return
 	}
 
 	// Create activity log before deleting post
+	// (gönderi soft-delete edildiği için post_id referansı geçerli kalır)
 	activity := models.ActivityLog{
 		UserID:    userID,
 		PlaceID:   post.PlaceID,
+		PostID:    post.ID,
 		Activity:  "post_deleted",
 		CreatedAt: time.Now(),
 	}
@@ -525,6 +886,12 @@ func (pc *PostController) DeletePost(c *gin.Context) {
 		return
 	}
 
+	if err := adjustPostsCount(tx, userID, -1); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete post"})
+		return
+	}
+
 	// Delete post
 	if err := tx.Delete(&post).Error; err != nil {
 		tx.Rollback()
@@ -553,20 +920,106 @@ func (pc *PostController) DeletePost(c *gin.Context) {
 // @Param userId path string true "User ID"
 // @Param page query integer false "Page number (default: 1)"
 // @Param pageSize query integer false "Items per page (default: 30)"
+// @Param mediaType query string false "Only posts whose first media is of this type, e.g. photo or video"
 // @Success 200 {object} StandardResponse
 // @Router /users/{userId}/posts [get]
 func (pc *PostController) GetUserPosts(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
 	userID := c.Param("userId")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "30"))
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 30, config.GetMaxPageSize())
+
+	var filter PostMediaTypeQuery
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "mediaType must be one of " + strings.Join(media.Names(), ", "),
+		})
+		return
+	}
 
 	offset := (page - 1) * pageSize
 
+	var owner models.User
+	if err := pc.DB.Select("id, is_private").First(&owner, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+
+	visibilityClause, visible, err := pc.userPostsVisibility(currentUser.UserID, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error checking post visibility",
+		})
+		return
+	}
+	if !visible {
+		// Gizli hesap veya engel: takipçi olmayanlara hiçbir gönderi gösterilmez
+		c.JSON(http.StatusOK, StandardResponse{
+			Success: true,
+			Data:    []PostSummary{},
+			Meta: gin.H{
+				"isPrivate": owner.IsPrivate,
+				"mediaType": mediaTypeMeta(filter.MediaType),
+			},
+			Pagination: &PaginationMeta{
+				CurrentPage: page,
+				PageSize:    pageSize,
+				TotalItems:  0,
+				TotalPages:  0,
+			},
+		})
+		return
+	}
+
+	postsQuery := filterByMediaType(pc.DB.Model(&models.Post{}), filter.MediaType).
+		Where("posts.user_id = ?", owner.ID).
+		Where(visibilityClause)
+
 	// Count total posts
 	var total int64
-	pc.DB.Model(&models.Post{}).Where("user_id = ?", userID).Count(&total)
+	postsQuery.Session(&gorm.Session{}).Count(&total)
+
+	posts, err := pc.postSummaries(currentUser.UserID, postsQuery, offset, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching posts",
+		})
+		return
+	}
+
+	// Standard response
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Meta: gin.H{
+			"mediaType": mediaTypeMeta(filter.MediaType),
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
 
-	// Get posts data
+// postSummaries lists the posts matched by postsQuery, newest first, as
+// PostSummary with the viewer's like state.
+func (pc *PostController) postSummaries(viewerID uint, postsQuery *gorm.DB, offset, limit int) ([]PostSummary, error) {
 	var rawPosts []struct {
 		ID           uint      `gorm:"column:id"`
 		Caption      string    `gorm:"column:post_caption"`
@@ -589,7 +1042,7 @@ func (pc *PostController) GetUserPosts(c *gin.Context) {
 		MediaCount   int64     `gorm:"column:media_count"`
 	}
 
-	result := pc.DB.Model(&models.Post{}).
+	if err := postsQuery.
 		Select(`
 			posts.id,
 			posts.post_caption,
@@ -606,25 +1059,18 @@ func (pc *PostController) GetUserPosts(c *gin.Context) {
 			users.last_name,
 			users.avatar,
 			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
-			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id) as comments_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count,
 			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
 			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
 			(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count
 		`).
 		Joins("JOIN users ON posts.user_id = users.id").
 		Joins("JOIN places ON posts.place_id = places.id").
-		Where("posts.user_id = ?", userID).
 		Order("posts.created_at DESC").
 		Offset(offset).
-		Limit(pageSize).
-		Find(&rawPosts)
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, StandardResponse{
-			Success: false,
-			Message: "Error fetching posts",
-		})
-		return
+		Limit(limit).
+		Find(&rawPosts).Error; err != nil {
+		return nil, err
 	}
 
 	// Transform to standard format
@@ -638,7 +1084,7 @@ func (pc *PostController) GetUserPosts(c *gin.Context) {
 			Latitude:     raw.Latitude,
 			Longitude:    raw.Longitude,
 			EarnedPoints: raw.EarnedPoints,
-			ThumbnailURL: raw.ThumbnailURL,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
 			MediaType:    raw.MediaType,
 			MediaCount:   raw.MediaCount,
 			User: PostUser{
@@ -659,22 +1105,68 @@ func (pc *PostController) GetUserPosts(c *gin.Context) {
 		}
 	}
 
-	// Standard response
-	c.JSON(http.StatusOK, StandardResponse{
-		Success: true,
-		Data:    posts,
-		Pagination: &PaginationMeta{
-			CurrentPage: page,
-			PageSize:    pageSize,
-			TotalItems:  total,
-			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
-		},
-	})
+	if err := pc.fillIsLiked(viewerID, posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// userPostsVisibility returns the SQL condition limiting which of the owner's
+// posts the viewer may see. visible is false when the viewer may see none:
+// blocked in either direction, or a private account the viewer doesn't follow.
+func (pc *PostController) userPostsVisibility(viewerID uint, owner models.User) (clause string, visible bool, err error) {
+	// Sahibi incelemedeki gönderiler dahil her şeyi görür
+	if viewerID == owner.ID {
+		return "1 = 1", true, nil
+	}
+
+	blocked, err := isBlockedBetween(pc.DB, viewerID, owner.ID)
+	if err != nil || blocked {
+		return "", false, err
+	}
+
+	var followCount int64
+	if err := pc.DB.Model(&models.Follow{}).
+		Where("follower_user_id = ? AND following_user_id = ? AND status = ?", viewerID, owner.ID, "accepted").
+		Count(&followCount).Error; err != nil {
+		return "", false, err
+	}
+
+	// Onaylı takipçiler arşivlenmemiş tüm gönderileri (herkese açık + takipçilere özel) görür
+	if followCount > 0 {
+		return "posts.is_archived = false AND posts.needs_review = false", true, nil
+	}
+
+	if owner.IsPrivate {
+		return "", false, nil
+	}
+
+	return "posts.is_archived = false AND posts.needs_review = false AND posts.is_public = true", true, nil
+}
+
+// visiblePostsCondition is the per-row counterpart of userPostsVisibility for
+// queries spanning many authors. The query must join users on posts.user_id.
+// Posts flagged for review are visible to their owner only.
+func visiblePostsCondition(viewerID uint) (string, []interface{}) {
+	condition := `(posts.user_id = ? OR (
+		NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+			((blocks.blocker_user_id = ? AND blocks.blocked_user_id = posts.user_id) OR
+			 (blocks.blocker_user_id = posts.user_id AND blocks.blocked_user_id = ?)))
+		AND posts.is_archived = false
+		AND posts.needs_review = false
+		AND (
+			(posts.is_public = true AND users.is_private = false)
+			OR EXISTS(SELECT 1 FROM follows WHERE follows.deleted_at IS NULL
+				AND follows.follower_user_id = ? AND follows.following_user_id = posts.user_id
+				AND follows.status = 'accepted')
+		)
+	))`
+	return condition, []interface{}{viewerID, viewerID, viewerID, viewerID}
 }
 
 // GetPostDetail godoc
 // @Summary Get detailed information about a specific post
-// @Description Returns comprehensive post information including user, place, media, likes, and comments
+// @Description Returns comprehensive post information including user, place, media, likes, and comments. A deleted post the user could have seen answers 410 Gone with data.deleted set; an unknown ID or a post the user may not see, deleted or not, 404. With private media enabled, media URLs are short-lived presigned URLs.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -693,6 +1185,12 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 
 	postID := c.Param("id")
 
+	// Görülemeyen gönderi bulunamamış gibi yanıtlanır; adminler her gönderiyi görür
+	visibility, visibilityArgs := "1 = 1", []interface{}{}
+	if !user.IsAdmin() {
+		visibility, visibilityArgs = visiblePostsCondition(user.UserID)
+	}
+
 	// Get post with all related information
 	var rawPost struct {
 		ID              uint      `gorm:"column:id"`
@@ -702,6 +1200,7 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 		Latitude        float64   `gorm:"column:latitude"`
 		Longitude       float64   `gorm:"column:longitude"`
 		EarnedPoints    int64     `gorm:"column:earned_points"`
+		IsDiscovery     bool      `gorm:"column:is_discovery"`
 		IsPublic        bool      `gorm:"column:is_public"`
 		AllowComments   bool      `gorm:"column:allow_comments"`
 		UserID          uint      `gorm:"column:user_id"`
@@ -729,6 +1228,7 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 			posts.latitude,
 			posts.longitude,
 			posts.earned_points,
+			posts.is_discovery,
 			posts.is_public,
 			posts.allow_comments,
 			posts.user_id,
@@ -747,16 +1247,28 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 				ELSE places.base_points 
 			END as place_point_value,
 			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
-			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id) as comments_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count,
 			EXISTS(SELECT 1 FROM likes WHERE likes.post_id = posts.id AND likes.user_id = ?) as is_liked
 		`, user.UserID, user.UserID).
 		Joins("JOIN users ON posts.user_id = users.id").
 		Joins("JOIN places ON posts.place_id = places.id").
 		Where("posts.id = ?", postID).
+		Where(visibility, visibilityArgs...).
 		First(&rawPost)
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
+			// Silinmiş gönderi 410 ile ayırt edilir; istemci "içerik kaldırıldı" gösterebilir.
+			// Yalnızca görebileceği gönderi için: gizli ya da engelli gönderinin varlığı sızmaz
+			var deleted int64
+			if err := pc.DB.Unscoped().Model(&models.Post{}).
+				Joins("JOIN users ON posts.user_id = users.id").
+				Where("posts.id = ? AND posts.deleted_at IS NOT NULL", postID).
+				Where(visibility, visibilityArgs...).
+				Count(&deleted).Error; err == nil && deleted > 0 {
+				respondDeleted(c, "Post has been deleted")
+				return
+			}
 			c.JSON(http.StatusNotFound, StandardResponse{
 				Success: false,
 				Message: "Post not found",
@@ -776,21 +1288,22 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 
 	// Transform media items
 	mediaItems := make([]PostMediaItem, len(rawMediaItems))
-	for i, media := range rawMediaItems {
+	for i, item := range rawMediaItems {
 		mediaItems[i] = PostMediaItem{
-			ID:         media.ID,
-			MediaType:  media.MediaType,
-			MediaURL:   media.MediaURL,
-			OrderIndex: media.OrderIndex,
-			AltText:    media.AltText,
-			Width:      media.Width,
-			Height:     media.Height,
-			Duration:   media.Duration,
-			Tags:       media.Tags,
+			ID:         item.ID,
+			MediaType:  item.MediaType,
+			MediaURL:   pc.UploadController.mediaReadURL(item.MediaURL),
+			OrderIndex: item.OrderIndex,
+			AltText:    item.AltText,
+			Width:      item.Width,
+			Height:     item.Height,
+			Duration:   item.Duration,
+			Tags:       item.Tags,
+			IsAnimated: media.IsAnimated(item.MediaType),
 		}
 	}
 
-	// Get recent likes (last 10)
+	// Get recent likes
 	var rawRecentLikes []struct {
 		UserID    uint   `gorm:"column:user_id"`
 		Username  string `gorm:"column:username"`
@@ -803,7 +1316,7 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 		Joins("JOIN users ON users.id = likes.user_id").
 		Where("likes.post_id = ?", postID).
 		Order("likes.created_at DESC").
-		Limit(10).
+		Limit(postDetailRecentLikes).
 		Find(&rawRecentLikes)
 
 	// Transform recent likes
@@ -818,7 +1331,7 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 		}
 	}
 
-	// Get recent comments (last 20)
+	// Get recent comments
 	var rawRecentComments []struct {
 		ID        uint      `gorm:"column:comment_id"`
 		Content   string    `gorm:"column:text_content"`
@@ -832,9 +1345,9 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 	pc.DB.Table("comments").
 		Select("comments.comment_id, comments.text_content, comments.created_at, users.id as user_id, users.username, users.first_name, users.last_name, users.avatar").
 		Joins("JOIN users ON users.id = comments.user_id").
-		Where("comments.post_id = ?", postID).
+		Where("comments.post_id = ? AND comments.deleted_at IS NULL", postID).
 		Order("comments.created_at DESC").
-		Limit(20).
+		Limit(postDetailRecentComments).
 		Find(&rawRecentComments)
 
 	// Transform recent comments
@@ -873,6 +1386,7 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 		Latitude:      rawPost.Latitude,
 		Longitude:     rawPost.Longitude,
 		EarnedPoints:  rawPost.EarnedPoints,
+		IsDiscovery:   rawPost.IsDiscovery,
 		IsPublic:      rawPost.IsPublic,
 		AllowComments: rawPost.AllowComments,
 		User: PostUser{
@@ -896,8 +1410,10 @@ func (pc *PostController) GetPostDetail(c *gin.Context) {
 			CommentsCount: rawPost.CommentsCount,
 			IsLiked:       rawPost.IsLiked,
 		},
-		RecentLikes:    recentLikes,
-		RecentComments: recentComments,
+		RecentLikes:           recentLikes,
+		RecentComments:        recentComments,
+		RecentLikesHasMore:    rawPost.LikesCount > postDetailRecentLikes,
+		RecentCommentsHasMore: rawPost.CommentsCount > postDetailRecentComments,
 	}
 
 	c.JSON(http.StatusOK, StandardResponse{
@@ -930,8 +1446,8 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 
 	userID := c.Param("userId")
 	placeID := c.Param("placeId")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "30"))
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 30, config.GetMaxPageSize())
 
 	offset := (page - 1) * pageSize
 
@@ -949,18 +1465,8 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 	}
 
 	// Get place info
-	var placeInfo PostPlace
-	if err := pc.DB.Model(&models.Place{}).
-		Select(`
-			id, name, address, place_image as image,
-			CASE 
-				WHEN EXISTS(SELECT 1 FROM posts WHERE posts.place_id = places.id AND posts.user_id = ?) 
-				THEN 1 
-				ELSE base_points 
-			END as point_value
-		`, currentUser.UserID).
-		Where("id = ?", placeID).
-		First(&placeInfo).Error; err != nil {
+	placeInfo, err := pc.postPlaceInfo(placeID, currentUser.UserID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, StandardResponse{
 			Success: false,
 			Message: "Place not found",
@@ -996,24 +1502,83 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 		return
 	}
 
-	// Get posts data
+	posts, err := pc.userPostsAtPlace(currentUser.UserID, userInfo, placeInfo, offset, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching posts",
+		})
+		return
+	}
+
+	// Get summary statistics
+	summary, err := pc.placeVisitSummary(userInfo.ID, placeInfo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching posts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Meta: gin.H{
+			"user":  userInfo,
+			"place": placeInfo,
+			"summary": gin.H{
+				"totalPosts":  summary.TotalPosts,
+				"totalPoints": summary.TotalPoints,
+			},
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  totalPosts,
+			TotalPages:  int(math.Ceil(float64(totalPosts) / float64(pageSize))),
+		},
+	})
+}
+
+// postPlaceInfo loads the place card shown on a user's posts at the place, with
+// the points viewerID would earn posting there
+func (pc *PostController) postPlaceInfo(placeID interface{}, viewerID uint) (PostPlace, error) {
+	var placeInfo PostPlace
+	err := pc.DB.Model(&models.Place{}).
+		Select(`
+			id, name, address, place_image as image,
+			CASE 
+				WHEN EXISTS(SELECT 1 FROM posts WHERE posts.place_id = places.id AND posts.user_id = ?) 
+				THEN 1 
+				ELSE base_points 
+			END as point_value
+		`, viewerID).
+		Where("id = ?", placeID).
+		First(&placeInfo).Error
+	return placeInfo, err
+}
+
+// userPostsAtPlace returns a page of the user's posts at the place, newest first,
+// with likes and comments counted and liked status for viewerID
+func (pc *PostController) userPostsAtPlace(viewerID uint, userInfo PostUser, placeInfo PostPlace, offset, limit int) ([]PostSummary, error) {
 	var rawPosts []struct {
-		ID           uint      `gorm:"column:id"`
-		Caption      string    `gorm:"column:post_caption"`
-		CreatedAt    time.Time `gorm:"column:created_at"`
-		UpdatedAt    time.Time `gorm:"column:updated_at"`
-		Latitude     float64   `gorm:"column:latitude"`
-		Longitude    float64   `gorm:"column:longitude"`
-		EarnedPoints int64     `gorm:"column:earned_points"`
-		LikesCount   int64     `gorm:"column:likes_count"`
-		CommentsCount int64    `gorm:"column:comments_count"`
-		ThumbnailURL string    `gorm:"column:thumbnail_url"`
-		MediaType    string    `gorm:"column:media_type"`
-		MediaCount   int64     `gorm:"column:media_count"`
-		IsLiked      bool      `gorm:"column:is_liked"`
+		ID            uint      `gorm:"column:id"`
+		Caption       string    `gorm:"column:post_caption"`
+		CreatedAt     time.Time `gorm:"column:created_at"`
+		UpdatedAt     time.Time `gorm:"column:updated_at"`
+		Latitude      float64   `gorm:"column:latitude"`
+		Longitude     float64   `gorm:"column:longitude"`
+		EarnedPoints  int64     `gorm:"column:earned_points"`
+		LikesCount    int64     `gorm:"column:likes_count"`
+		CommentsCount int64     `gorm:"column:comments_count"`
+		ThumbnailURL  string    `gorm:"column:thumbnail_url"`
+		MediaType     string    `gorm:"column:media_type"`
+		MediaCount    int64     `gorm:"column:media_count"`
+		IsLiked       bool      `gorm:"column:is_liked"`
 	}
 
-	result := pc.DB.Model(&models.Post{}).
+	if err := pc.DB.Model(&models.Post{}).
 		Select(`
 			posts.id,
 			posts.post_caption,
@@ -1023,24 +1588,18 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 			posts.longitude,
 			posts.earned_points,
 			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
-			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id) as comments_count,
+			(SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL) as comments_count,
 			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
 			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
 			(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count,
 			EXISTS(SELECT 1 FROM likes WHERE likes.post_id = posts.id AND likes.user_id = ?) as is_liked
-		`, currentUser.UserID).
-		Where("posts.user_id = ? AND posts.place_id = ?", userID, placeID).
+		`, viewerID).
+		Where("posts.user_id = ? AND posts.place_id = ?", userInfo.ID, placeInfo.ID).
 		Order("posts.created_at DESC").
 		Offset(offset).
-		Limit(pageSize).
-		Find(&rawPosts)
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, StandardResponse{
-			Success: false,
-			Message: "Error fetching posts",
-		})
-		return
+		Limit(limit).
+		Find(&rawPosts).Error; err != nil {
+		return nil, err
 	}
 
 	// Transform to standard format
@@ -1054,7 +1613,7 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 			Latitude:     raw.Latitude,
 			Longitude:    raw.Longitude,
 			EarnedPoints: raw.EarnedPoints,
-			ThumbnailURL: raw.ThumbnailURL,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
 			MediaType:    raw.MediaType,
 			MediaCount:   raw.MediaCount,
 			User:         userInfo,
@@ -1066,36 +1625,147 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 			},
 		}
 	}
+	return posts, nil
+}
 
-	// Get summary statistics
-	var summary struct {
-		TotalPosts  int64 `gorm:"column:total_posts"`
-		TotalPoints int64 `gorm:"column:total_points"`
-	}
-	pc.DB.Model(&models.Post{}).
+// PlaceVisitSummary totals a user's posts at one place; the visit times are nil without posts
+type PlaceVisitSummary struct {
+	TotalPosts   int64      `json:"totalPosts" gorm:"column:total_posts"`
+	TotalPoints  int64      `json:"totalPoints" gorm:"column:total_points"`
+	FirstVisitAt *time.Time `json:"firstVisitAt" gorm:"column:first_visit_at"`
+	LastVisitAt  *time.Time `json:"lastVisitAt" gorm:"column:last_visit_at"`
+}
+
+// PlaceVisitMonth is one month of a user's posts at a place
+type PlaceVisitMonth struct {
+	Month  string `json:"month"` // YYYY-MM, isteğin saat diliminde
+	Posts  int64  `json:"posts"`
+	Points int64  `json:"points"`
+}
+
+// placeVisitSummary aggregates the user's posts at the place
+func (pc *PostController) placeVisitSummary(userID, placeID uint) (PlaceVisitSummary, error) {
+	var summary PlaceVisitSummary
+	err := pc.DB.Model(&models.Post{}).
 		Select(`
 			COUNT(*) as total_posts,
-			COALESCE(SUM(earned_points), 0) as total_points
+			COALESCE(SUM(earned_points), 0) as total_points,
+			MIN(created_at) as first_visit_at,
+			MAX(created_at) as last_visit_at
 		`).
 		Where("user_id = ? AND place_id = ?", userID, placeID).
-		Scan(&summary)
+		Scan(&summary).Error
+	return summary, err
+}
+
+// placeVisitMonths groups the user's posts at the place by calendar month in
+// loc, newest month first
+func (pc *PostController) placeVisitMonths(userID, placeID uint, loc *time.Location) ([]PlaceVisitMonth, error) {
+	var visits []struct {
+		CreatedAt    time.Time
+		EarnedPoints int64
+	}
+	if err := pc.DB.Model(&models.Post{}).Select("created_at, earned_points").
+		Where("user_id = ? AND place_id = ?", userID, placeID).
+		Order("created_at DESC").
+		Scan(&visits).Error; err != nil {
+		return nil, err
+	}
+
+	// Ay sınırları istemcinin saat dilimine göre belirlenir; gönderiler yeniden eskiye sıralı
+	months := []PlaceVisitMonth{}
+	for _, visit := range visits {
+		month := visit.CreatedAt.In(loc).Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, PlaceVisitMonth{Month: month})
+		}
+		months[len(months)-1].Posts++
+		months[len(months)-1].Points += visit.EarnedPoints
+	}
+	return months, nil
+}
+
+// GetMyPlaceHistory godoc
+// @Summary Get the current user's history at a place
+// @Description Returns the current user's posts at a place, newest first, with how many they posted, the points earned, the first and last visit and a monthly breakdown. Months follow the `timezone` or `tzOffset` query param.
+// @Tags places
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 30)"
+// @Param timezone query string false "IANA timezone for the monthly breakdown"
+// @Param tzOffset query integer false "Minutes east of UTC, used when timezone is not given"
+// @Success 200 {object} StandardResponse{data=[]PostSummary}
+// @Router /places/{placeId}/my-history [get]
+func (pc *PostController) GetMyPlaceHistory(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	loc, err := utils.ResolveLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 30, config.GetMaxPageSize())
+
+	var userInfo PostUser
+	if err := pc.DB.Model(&models.User{}).
+		Select("id, username, first_name, last_name, avatar").
+		Where("id = ?", currentUser.UserID).
+		First(&userInfo).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+
+	placeInfo, err := pc.postPlaceInfo(c.Param("placeId"), currentUser.UserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{
+			Success: false,
+			Message: "Place not found",
+		})
+		return
+	}
+
+	summary, err := pc.placeVisitSummary(userInfo.ID, placeInfo.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching history"})
+		return
+	}
+	months, err := pc.placeVisitMonths(userInfo.ID, placeInfo.ID, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching history"})
+		return
+	}
+	posts, err := pc.userPostsAtPlace(currentUser.UserID, userInfo, placeInfo, (page-1)*pageSize, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching history"})
+		return
+	}
 
 	c.JSON(http.StatusOK, StandardResponse{
 		Success: true,
 		Data:    posts,
 		Meta: gin.H{
-			"user":  userInfo,
-			"place": placeInfo,
-			"summary": gin.H{
-				"totalPosts":  summary.TotalPosts,
-				"totalPoints": summary.TotalPoints,
-			},
+			"place":   placeInfo,
+			"summary": summary,
+			"monthly": months,
 		},
 		Pagination: &PaginationMeta{
 			CurrentPage: page,
 			PageSize:    pageSize,
-			TotalItems:  totalPosts,
-			TotalPages:  int(math.Ceil(float64(totalPosts) / float64(pageSize))),
+			TotalItems:  summary.TotalPosts,
+			TotalPages:  int(math.Ceil(float64(summary.TotalPosts) / float64(pageSize))),
 		},
 	})
 }
@@ -1109,6 +1779,7 @@ func (pc *PostController) GetUserPostsAtPlace(c *gin.Context) {
 // @Param placeId path string true "Place ID"
 // @Param page query integer false "Page number (default: 1)"
 // @Param pageSize query integer false "Items per page (default: 30)"
+// @Param mediaType query string false "Only posts whose first media is of this type, e.g. photo or video"
 // @Success 200 {object} StandardResponse
 // @Router /places/{placeId}/posts/grid [get]
 func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
@@ -1122,8 +1793,17 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 	}
 
 	placeID := c.Param("placeId")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "30"))
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 30, config.GetMaxPageSize())
+
+	var filter PostMediaTypeQuery
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "mediaType must be one of " + strings.Join(media.Names(), ", "),
+		})
+		return
+	}
 
 	offset := (page - 1) * pageSize
 
@@ -1140,9 +1820,15 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 		return
 	}
 
+	visibility, visibilityArgs := visiblePostsCondition(user.UserID)
+	postsQuery := filterByMediaType(pc.DB.Model(&models.Post{}), filter.MediaType).
+		Joins("JOIN users ON posts.user_id = users.id").
+		Where("posts.place_id = ?", placeID).
+		Where(visibility, visibilityArgs...)
+
 	// Count total posts
 	var totalPosts int64
-	pc.DB.Model(&models.Post{}).Where("place_id = ?", placeID).Count(&totalPosts)
+	postsQuery.Session(&gorm.Session{}).Count(&totalPosts)
 
 	// Get grid posts data
 	var rawPosts []struct {
@@ -1162,7 +1848,7 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 		UpdatedAt    time.Time `gorm:"column:updated_at"`
 	}
 
-	result := pc.DB.Model(&models.Post{}).
+	result := postsQuery.
 		Select(`
 			posts.id,
 			posts.user_id,
@@ -1179,8 +1865,6 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 			(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count,
 			(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count
 		`).
-		Joins("JOIN users ON posts.user_id = users.id").
-		Where("posts.place_id = ?", placeID).
 		Order("posts.created_at DESC").
 		Offset(offset).
 		Limit(pageSize).
@@ -1203,7 +1887,7 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 			UpdatedAt:    raw.UpdatedAt,
 			Latitude:     raw.Latitude,
 			Longitude:    raw.Longitude,
-			ThumbnailURL: raw.ThumbnailURL,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
 			MediaType:    raw.MediaType,
 			MediaCount:   raw.MediaCount,
 			User: PostUser{
@@ -1220,11 +1904,341 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 		}
 	}
 
+	if err := pc.fillIsLiked(user.UserID, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching posts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Meta: gin.H{
+			"place":     place,
+			"mediaType": mediaTypeMeta(filter.MediaType),
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  totalPosts,
+			TotalPages:  int(math.Ceil(float64(totalPosts) / float64(pageSize))),
+		},
+	})
+}
+
+// PlaceMediaItem is a gallery entry for a place, linked back to its post.
+type PlaceMediaItem struct {
+	PostMediaItem
+	PostID       uint      `json:"postId"`
+	ThumbnailURL string    `json:"thumbnailUrl,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// GetPlaceMedia godoc
+// @Summary Get the media gallery of a place
+// @Description Returns a flat, paginated list of media from visible posts at a place, newest first. By default only the first media of each post is returned; pass all=true for every item.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param placeId path string true "Place ID"
+// @Param all query boolean false "Return all media of each post (default: false)"
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 30)"
+// @Success 200 {object} StandardResponse
+// @Router /places/{placeId}/media [get]
+func (pc *PostController) GetPlaceMedia(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	placeID := c.Param("placeId")
+	allMedia, _ := strconv.ParseBool(c.Query("all"))
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 30, config.GetMaxPageSize())
+
+	var place models.Place
+	if err := pc.DB.Select("id").First(&place, placeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{
+			Success: false,
+			Message: "Place not found",
+		})
+		return
+	}
+
+	visibleCondition, visibleArgs := visiblePostsCondition(user.UserID)
+	mutedCondition, mutedArgs := notMutedCondition("posts.user_id", user.UserID)
+
+	db := pc.DB.Model(&models.PostMedia{}).
+		Joins("JOIN posts ON posts.id = post_media.post_id AND posts.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = posts.user_id").
+		Where("posts.place_id = ?", place.ID).
+		Where(visibleCondition, visibleArgs...).
+		Where(mutedCondition, mutedArgs...)
+
+	if !allMedia {
+		// Her gönderinin yalnızca ilk medyası
+		db = db.Where(`NOT EXISTS(SELECT 1 FROM post_media earlier WHERE earlier.post_id = post_media.post_id
+			AND earlier.deleted_at IS NULL
+			AND (earlier.order_index < post_media.order_index
+				OR (earlier.order_index = post_media.order_index AND earlier.id < post_media.id)))`)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching media",
+		})
+		return
+	}
+
+	var rawMedia []struct {
+		ID           uint           `gorm:"column:id"`
+		PostID       uint           `gorm:"column:post_id"`
+		MediaType    string         `gorm:"column:media_type"`
+		MediaURL     string         `gorm:"column:media_url"`
+		ThumbnailURL string         `gorm:"column:thumbnail_url"`
+		OrderIndex   int            `gorm:"column:order_index"`
+		AltText      string         `gorm:"column:alt_text"`
+		Width        int            `gorm:"column:width"`
+		Height       int            `gorm:"column:height"`
+		Duration     int            `gorm:"column:duration"`
+		Tags         pq.StringArray `gorm:"column:tags;type:text[]"`
+		CreatedAt    time.Time      `gorm:"column:created_at"`
+	}
+
+	if err := db.Select(`post_media.id, post_media.post_id, post_media.media_type, post_media.media_url,
+			post_media.thumbnail_url, post_media.order_index, post_media.alt_text, post_media.width,
+			post_media.height, post_media.duration, post_media.tags, posts.created_at`).
+		Order("posts.created_at DESC, post_media.post_id DESC, post_media.order_index, post_media.id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rawMedia).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching media",
+		})
+		return
+	}
+
+	items := make([]PlaceMediaItem, len(rawMedia))
+	for i, raw := range rawMedia {
+		items[i] = PlaceMediaItem{
+			PostMediaItem: PostMediaItem{
+				ID:         raw.ID,
+				MediaType:  raw.MediaType,
+				MediaURL:   pc.UploadController.mediaReadURL(raw.MediaURL),
+				OrderIndex: raw.OrderIndex,
+				AltText:    raw.AltText,
+				Width:      raw.Width,
+				Height:     raw.Height,
+				Duration:   raw.Duration,
+				Tags:       raw.Tags,
+				IsAnimated: media.IsAnimated(raw.MediaType),
+			},
+			PostID:       raw.PostID,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
+			CreatedAt:    raw.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    items,
+		Meta: gin.H{
+			"placeId": place.ID,
+			"all":     allMedia,
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+// GetMultiPlacePostsGrid godoc
+// @Summary Get posts from several places
+// @Description Returns posts from the given places merged by recency, with per-place counts in meta
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body MultiPlacePostsGridRequest true "Place IDs and pagination"
+// @Success 200 {object} StandardResponse{data=[]PostSummary}
+// @Router /places/posts/grid [post]
+func (pc *PostController) GetMultiPlacePostsGrid(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req MultiPlacePostsGridRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if len(req.PlaceIDs) > maxGridPlaceIDs {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: fmt.Sprintf("placeIds cannot contain more than %d places", maxGridPlaceIDs),
+		})
+		return
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 30
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	visibility, visibilityArgs := visiblePostsCondition(user.UserID)
+
+	// Mekan bazında görünür gönderi sayıları (Meta için gruplama)
+	var placeGroups []struct {
+		ID        uint   `gorm:"column:id" json:"id"`
+		Name      string `gorm:"column:name" json:"name"`
+		PostCount int64  `gorm:"column:post_count" json:"postCount"`
+	}
+	if err := pc.DB.Model(&models.Post{}).
+		Select("places.id, places.name, COUNT(posts.id) as post_count").
+		Joins("JOIN users ON posts.user_id = users.id").
+		Joins("JOIN places ON posts.place_id = places.id").
+		Where("posts.place_id IN ?", req.PlaceIDs).
+		Where(visibility, visibilityArgs...).
+		Group("places.id, places.name").
+		Order("places.id").
+		Scan(&placeGroups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching posts",
+		})
+		return
+	}
+
+	var totalPosts int64
+	for _, group := range placeGroups {
+		totalPosts += group.PostCount
+	}
+
+	var rawPosts []struct {
+		ID           uint      `gorm:"column:id"`
+		Caption      string    `gorm:"column:post_caption"`
+		PlaceID      uint      `gorm:"column:place_id"`
+		PlaceName    string    `gorm:"column:place_name"`
+		UserID       uint      `gorm:"column:user_id"`
+		Username     string    `gorm:"column:username"`
+		FirstName    string    `gorm:"column:first_name"`
+		LastName     string    `gorm:"column:last_name"`
+		Avatar       string    `gorm:"column:avatar"`
+		Latitude     float64   `gorm:"column:latitude"`
+		Longitude    float64   `gorm:"column:longitude"`
+		ThumbnailURL string    `gorm:"column:thumbnail_url"`
+		MediaType    string    `gorm:"column:media_type"`
+		MediaCount   int64     `gorm:"column:media_count"`
+		LikesCount   int64     `gorm:"column:likes_count"`
+		IsLiked      bool      `gorm:"column:is_liked"`
+		CreatedAt    time.Time `gorm:"column:created_at"`
+		UpdatedAt    time.Time `gorm:"column:updated_at"`
+	}
+
+	if totalPosts > 0 {
+		result := pc.DB.Model(&models.Post{}).
+			Select(`
+				posts.id,
+				posts.post_caption,
+				posts.place_id,
+				places.name as place_name,
+				posts.user_id,
+				users.username,
+				users.first_name,
+				users.last_name,
+				users.avatar,
+				posts.latitude,
+				posts.longitude,
+				posts.created_at,
+				posts.updated_at,
+				(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+				(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
+				(SELECT COUNT(*) FROM post_media WHERE post_media.post_id = posts.id) as media_count,
+				(SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id) as likes_count,
+				EXISTS(SELECT 1 FROM likes WHERE likes.post_id = posts.id AND likes.user_id = ?) as is_liked
+			`, user.UserID).
+			Joins("JOIN users ON posts.user_id = users.id").
+			Joins("JOIN places ON posts.place_id = places.id").
+			Where("posts.place_id IN ?", req.PlaceIDs).
+			Where(visibility, visibilityArgs...).
+			Order("posts.created_at DESC, posts.id DESC").
+			Offset(offset).
+			Limit(pageSize).
+			Find(&rawPosts)
+
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{
+				Success: false,
+				Message: "Error fetching posts",
+			})
+			return
+		}
+	}
+
+	posts := make([]PostSummary, len(rawPosts))
+	for i, raw := range rawPosts {
+		posts[i] = PostSummary{
+			ID:           raw.ID,
+			Caption:      raw.Caption,
+			CreatedAt:    raw.CreatedAt,
+			UpdatedAt:    raw.UpdatedAt,
+			Latitude:     raw.Latitude,
+			Longitude:    raw.Longitude,
+			ThumbnailURL: pc.UploadController.mediaReadURL(raw.ThumbnailURL),
+			MediaType:    raw.MediaType,
+			MediaCount:   raw.MediaCount,
+			User: PostUser{
+				ID:        raw.UserID,
+				Username:  raw.Username,
+				FirstName: raw.FirstName,
+				LastName:  raw.LastName,
+				Avatar:    raw.Avatar,
+			},
+			Place: PostPlace{
+				ID:   raw.PlaceID,
+				Name: raw.PlaceName,
+			},
+			Interaction: PostInteraction{
+				LikesCount: raw.LikesCount,
+				IsLiked:    raw.IsLiked,
+			},
+		}
+	}
+
 	c.JSON(http.StatusOK, StandardResponse{
 		Success: true,
 		Data:    posts,
 		Meta: gin.H{
-			"place": place,
+			"places": placeGroups,
 		},
 		Pagination: &PaginationMeta{
 			CurrentPage: page,
@@ -1235,6 +2249,81 @@ func (pc *PostController) GetPlacePostsGrid(c *gin.Context) {
 	})
 }
 
+// LikedStatus godoc
+// @Summary Check which posts the current user has liked
+// @Description Returns a postId -> liked map for up to 100 posts, for clients rendering grids
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body LikedStatusRequest true "Post IDs"
+// @Success 200 {object} StandardResponse
+// @Router /posts/liked-status [post]
+func (pc *PostController) LikedStatus(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	var req LikedStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	liked, err := pc.likedPostIDs(user.UserID, req.PostIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error checking likes"})
+		return
+	}
+
+	status := make(map[uint]bool, len(req.PostIDs))
+	for _, postID := range req.PostIDs {
+		status[postID] = liked[postID]
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// likedPostIDs returns the subset of postIDs the user has liked, in one query.
+func (pc *PostController) likedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []uint
+	if err := pc.DB.Model(&models.Like{}).
+		Where("post_id IN ? AND user_id = ?", postIDs, userID).
+		Pluck("post_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// fillIsLiked sets Interaction.IsLiked on posts for the viewer
+func (pc *PostController) fillIsLiked(viewerID uint, posts []PostSummary) error {
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	liked, err := pc.likedPostIDs(viewerID, postIDs)
+	if err != nil {
+		return err
+	}
+	for i := range posts {
+		posts[i].Interaction.IsLiked = liked[posts[i].ID]
+	}
+	return nil
+}
+
 // Helper function to calculate distance between two points using Haversine formula
 func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const R = 6371000 // Earth's radius in meters
@@ -1252,17 +2341,87 @@ func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	return R * c // Distance in meters
 }
 
+// validateMediaItems checks the media count against the configured per-post
+// limit and rejects blank URLs. It returns the offending field and a message,
+// or an empty field when the items are valid.
+func validateMediaItems(mediaURLs []string) (string, string) {
+	maxItems := config.GetMaxMediaItemsPerPost()
+	if len(mediaURLs) > maxItems {
+		return "mediaItems", fmt.Sprintf("A post can contain at most %d media items", maxItems)
+	}
+
+	for i, mediaURL := range mediaURLs {
+		if strings.TrimSpace(mediaURL) == "" {
+			return fmt.Sprintf("mediaItems[%d].mediaUrl", i), "Media URL cannot be empty"
+		}
+	}
+
+	return "", ""
+}
+
+// isMediaTypeEnabled reports whether the feature flags currently accept the media type
+func isMediaTypeEnabled(mediaType string) bool {
+	return mediaType != media.Video || config.IsVideoEnabled()
+}
+
+// validateMediaTypesEnabled rejects media items of a type switched off by the feature flags.
+func validateMediaTypesEnabled(mediaTypes []string) (string, string) {
+	for i, mediaType := range mediaTypes {
+		if !isMediaTypeEnabled(mediaType) {
+			return fmt.Sprintf("mediaItems[%d].mediaType", i), fmt.Sprintf("%s posts are currently disabled", mediaType)
+		}
+	}
+	return "", ""
+}
+
+// validateCaption checks the caption against the configured limit. Length is
+// counted in runes so emoji and Turkish characters count as one each.
+func validateCaption(field, caption string) (string, string) {
+	maxLength := config.GetMaxCaptionLength()
+	if utf8.RuneCountInString(caption) > maxLength {
+		return field, fmt.Sprintf("Caption can be at most %d characters", maxLength)
+	}
+	return "", ""
+}
+
+// validateMediaOwnership rejects media URLs that are hotlinked from outside
+// our storage or that point at another user's upload.
+func (pc *PostController) validateMediaOwnership(mediaURLs []string, userID uint) (string, string) {
+	if pc.UploadController == nil {
+		return "", ""
+	}
+
+	for i, mediaURL := range mediaURLs {
+		if err := pc.UploadController.validateMediaURL(strings.TrimSpace(mediaURL), userID); err != nil {
+			return fmt.Sprintf("mediaItems[%d].mediaUrl", i), err.Error()
+		}
+	}
+
+	return "", ""
+}
+
+// verifiedUploads returns the records ConfirmUpload stored for the user's media
+// URLs, with the real dimensions and perceptual hash of each file. URLs without
+// a record are missing from the map.
+func (pc *PostController) verifiedUploads(mediaURLs []string, userID uint) (map[string]models.MediaUpload, error) {
+	var uploads []models.MediaUpload
+	if err := pc.DB.Where("user_id = ? AND media_url IN ?", userID, mediaURLs).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+
+	byURL := make(map[string]models.MediaUpload, len(uploads))
+	for _, upload := range uploads {
+		byURL[upload.MediaURL] = upload
+	}
+	return byURL, nil
+}
+
 // Helper function to calculate initial points for a post
 func calculateInitialPoints(placePointValue int, mediaType string) int64 {
 	basePoints := placePointValue
 
 	// Bonus points for media type
-	switch mediaType {
-	case "video":
-		basePoints += 5 // Extra points for video content
-	case "photo":
-		basePoints += 2 // Extra points for photo content
-	}
+	basePoints += media.PointsBonus(mediaType)
 
 	return int64(basePoints)
 }
@@ -0,0 +1,1112 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/media"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/types"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+type postListResponse struct {
+	Data       []PostSummary  `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// listPostIDs calls a PostSummary listing handler and returns the ids in response order
+func listPostIDs(t *testing.T, handler gin.HandlerFunc, target string, userID uint, params ...gin.Param) []uint {
+	t.Helper()
+	w := callHandler(handler, http.MethodGet, target, nil, userID, params...)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d, body = %s", target, w.Code, w.Body.String())
+	}
+	var resp postListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]uint, len(resp.Data))
+	for i, post := range resp.Data {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func sameIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	got = append([]uint(nil), got...)
+	want = append([]uint(nil), want...)
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGetUserPostsVisibility(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "visibilityowner")
+	follower := createTestUser(t, db, "visibilityfollower")
+	stranger := createTestUser(t, db, "visibilitystranger")
+	place := createTestPlace(t, db, "visibilityplace")
+	if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: owner.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	public := createTestPost(t, db, owner, place, "public", true)
+	followersOnly := createTestPost(t, db, owner, place, "followers only", false)
+	archived := createTestPost(t, db, owner, place, "archived", true)
+	if err := db.Model(&archived).Update("is_archived", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewPostController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(owner.ID))}
+	target := "/users/" + param.Value + "/posts"
+	tests := []struct {
+		name   string
+		viewer models.User
+		want   []uint
+	}{
+		{"owner", owner, []uint{public.ID, followersOnly.ID, archived.ID}},
+		{"follower", follower, []uint{public.ID, followersOnly.ID}},
+		{"stranger", stranger, []uint{public.ID}},
+	}
+	for _, tt := range tests {
+		if got := listPostIDs(t, pc.GetUserPosts, target, tt.viewer.ID, param); !sameIDs(got, tt.want) {
+			t.Errorf("%s sees %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Gizli hesap takipçi olmayanlara hiçbir şey göstermez
+	if err := db.Model(&owner).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := listPostIDs(t, pc.GetUserPosts, target, stranger.ID, param); len(got) != 0 {
+		t.Errorf("stranger sees %v on a private account, want none", got)
+	}
+	if got := listPostIDs(t, pc.GetUserPosts, target, follower.ID, param); !sameIDs(got, []uint{public.ID, followersOnly.ID}) {
+		t.Errorf("follower sees %v on a private account, want %v", got, []uint{public.ID, followersOnly.ID})
+	}
+}
+
+func TestGetMultiPlacePostsGrid(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "gridviewer")
+	author := createTestUser(t, db, "gridauthor")
+	hidden := createTestUser(t, db, "gridhidden")
+	first := createTestPlace(t, db, "gridfirst")
+	second := createTestPlace(t, db, "gridsecond")
+	elsewhere := createTestPlace(t, db, "gridelsewhere")
+	if err := db.Model(&hidden).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	var want []uint
+	for i, place := range []models.Place{first, second, first} {
+		post := createTestPost(t, db, author, place, "grid", true)
+		// Daha yeni gönderiler önce gelir
+		if err := db.Model(&post).Update("created_at", base.Add(time.Duration(i)*time.Minute)).Error; err != nil {
+			t.Fatal(err)
+		}
+		want = append([]uint{post.ID}, want...)
+	}
+	createTestPost(t, db, author, elsewhere, "other place", true)
+	createTestPost(t, db, hidden, second, "private account", true)
+	if err := db.Create(&models.Like{PostID: want[0], UserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewPostController(db, nil)
+	body := `{"placeIds":[` + strconv.Itoa(int(first.ID)) + `,` + strconv.Itoa(int(second.ID)) + `]}`
+	w := callHandler(pc.GetMultiPlacePostsGrid, http.MethodPost, "/places/posts/grid", strings.NewReader(body), viewer.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []PostSummary `json:"data"`
+		Meta struct {
+			Places []struct {
+				ID        uint  `json:"id"`
+				PostCount int64 `json:"postCount"`
+			} `json:"places"`
+		} `json:"meta"`
+		Pagination PaginationMeta `json:"pagination"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Data) != len(want) {
+		t.Fatalf("got %d posts, want %d", len(resp.Data), len(want))
+	}
+	for i, post := range resp.Data {
+		if post.ID != want[i] {
+			t.Errorf("post %d = %d, want %d", i, post.ID, want[i])
+		}
+		if liked := post.ID == want[0]; post.Interaction.IsLiked != liked {
+			t.Errorf("post %d isLiked = %v, want %v", post.ID, post.Interaction.IsLiked, liked)
+		}
+	}
+	if resp.Pagination.TotalItems != int64(len(want)) {
+		t.Errorf("totalItems = %d, want %d", resp.Pagination.TotalItems, len(want))
+	}
+	counts := map[uint]int64{}
+	for _, place := range resp.Meta.Places {
+		counts[place.ID] = place.PostCount
+	}
+	if counts[first.ID] != 2 || counts[second.ID] != 1 || len(counts) != 2 {
+		t.Errorf("meta.places counts = %v, want %d:2 and %d:1", counts, first.ID, second.ID)
+	}
+
+	// Mekan sayısı sınırı
+	ids := make([]string, maxGridPlaceIDs+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+	body = `{"placeIds":[` + strings.Join(ids, ",") + `]}`
+	w = callHandler(pc.GetMultiPlacePostsGrid, http.MethodPost, "/places/posts/grid", strings.NewReader(body), viewer.ID)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("%d place ids: status = %d, want %d", len(ids), w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestValidateMediaItems(t *testing.T) {
+	t.Setenv("MAX_MEDIA_ITEMS_PER_POST", "3")
+
+	urls := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = "https://media.example.com/" + strconv.Itoa(i) + ".jpg"
+		}
+		return out
+	}
+	tests := []struct {
+		name      string
+		mediaURLs []string
+		wantField string
+	}{
+		{"one item", urls(1), ""},
+		{"at limit", urls(3), ""},
+		{"over limit", urls(4), "mediaItems"},
+		{"empty url", []string{"https://media.example.com/a.jpg", ""}, "mediaItems[1].mediaUrl"},
+		{"whitespace url", []string{" \t\n"}, "mediaItems[0].mediaUrl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, msg := validateMediaItems(tt.mediaURLs)
+			if field != tt.wantField {
+				t.Errorf("field = %q, want %q", field, tt.wantField)
+			}
+			if (msg == "") != (tt.wantField == "") {
+				t.Errorf("message = %q", msg)
+			}
+		})
+	}
+}
+
+// testPhotoSeq gives every postCreateRequest its own photo so repeated posts
+// are not caught as duplicates
+var testPhotoSeq atomic.Int64
+
+// postCreateRequest builds a CreatePost body for a photo taken at place
+func postCreateRequest(t testing.TB, place models.Place) *bytes.Reader {
+	t.Helper()
+	mediaURL := fmt.Sprintf("https://cdn.example.com/test-%d.jpg", testPhotoSeq.Add(1))
+	body, err := json.Marshal(gin.H{
+		"postCaption": "test post",
+		"mediaItems":  []gin.H{{"mediaType": "photo", "mediaUrl": mediaURL}},
+		"placeId":     place.ID,
+		"latitude":    place.Latitude,
+		"longitude":   place.Longitude,
+		"isPublic":    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(body)
+}
+
+func createPostAs(t testing.TB, db *gorm.DB, user models.User, place models.Place) *httptest.ResponseRecorder {
+	t.Helper()
+	return callHandler(NewPostController(db, nil).CreatePost, http.MethodPost, "/posts", postCreateRequest(t, place), user.ID)
+}
+
+func totalPoints(t *testing.T, db *gorm.DB, user models.User) int64 {
+	t.Helper()
+	var row models.User
+	if err := db.Select("total_points").First(&row, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return row.TotalPoints
+}
+
+func TestPostPointsRiseOnCreateAndFallOnDelete(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "pointsuser")
+	place := createTestPlace(t, db, "pointsplace")
+	if err := db.Model(&place).Update("base_points", 10).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&user).Update("total_points", 100).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	w := createPostAs(t, db, user, place)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var post models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	if post.EarnedPoints <= 0 {
+		t.Fatalf("earned points = %d, want > 0", post.EarnedPoints)
+	}
+	if got, want := totalPoints(t, db, user), 100+post.EarnedPoints; got != want {
+		t.Errorf("total after create = %d, want %d", got, want)
+	}
+
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+	w = callHandler(NewPostController(db, nil).DeletePost, http.MethodDelete, "/posts/"+param.Value, nil, user.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := totalPoints(t, db, user); got != 100 {
+		t.Errorf("total after delete = %d, want 100", got)
+	}
+}
+
+func TestFirstPostAtPlaceEarnsDiscoveryBonus(t *testing.T) {
+	db := openTestDB(t)
+	first := createTestUser(t, db, "discoveryfirst")
+	second := createTestUser(t, db, "discoverysecond")
+	place := createTestPlace(t, db, "discoveryplace")
+	if err := db.Model(&place).Update("base_points", 10).Error; err != nil {
+		t.Fatal(err)
+	}
+	bonus := int64(types.GetPointsConfig().NoPostsBonusPoints)
+	base := calculateInitialPoints(10, "photo")
+
+	postBy := func(user models.User) models.Post {
+		t.Helper()
+		if w := createPostAs(t, db, user, place); w.Code != http.StatusCreated {
+			t.Fatalf("create by %s: status = %d, body = %s", user.Username, w.Code, w.Body.String())
+		}
+		var post models.Post
+		if err := db.Where("user_id = ?", user.ID).Order("id DESC").First(&post).Error; err != nil {
+			t.Fatal(err)
+		}
+		return post
+	}
+	count := func(model interface{}, query string, args ...interface{}) int64 {
+		t.Helper()
+		var n int64
+		if err := db.Model(model).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	post := postBy(first)
+	if !post.IsDiscovery || post.EarnedPoints != base+bonus {
+		t.Errorf("first post: discovery = %v, points = %d; want true, %d", post.IsDiscovery, post.EarnedPoints, base+bonus)
+	}
+	if got := totalPoints(t, db, first); got != base+bonus {
+		t.Errorf("first poster total = %d, want %d", got, base+bonus)
+	}
+	if n := count(&models.ActivityLog{}, "user_id = ? AND activity = ? AND points = ?", first.ID, "place_discovered", bonus); n != 1 {
+		t.Errorf("place_discovered activities = %d, want 1", n)
+	}
+	if n := count(&models.Notification{}, "user_id = ? AND type = ? AND post_id = ?", first.ID, "place_discovered", post.ID); n != 1 {
+		t.Errorf("place_discovered notifications = %d, want 1", n)
+	}
+
+	post = postBy(second)
+	if post.IsDiscovery || post.EarnedPoints != base {
+		t.Errorf("second post: discovery = %v, points = %d; want false, %d", post.IsDiscovery, post.EarnedPoints, base)
+	}
+	if n := count(&models.ActivityLog{}, "user_id = ? AND activity = ?", second.ID, "place_discovered"); n != 0 {
+		t.Errorf("second poster place_discovered activities = %d, want 0", n)
+	}
+	if n := count(&models.Notification{}, "user_id = ? AND type = ?", second.ID, "place_discovered"); n != 0 {
+		t.Errorf("second poster place_discovered notifications = %d, want 0", n)
+	}
+
+	// Tüm gönderiler silinse de mekan yeniden keşfedilemez
+	if err := db.Where("place_id = ?", place.ID).Delete(&models.Post{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if post := postBy(second); post.IsDiscovery {
+		t.Errorf("post after deleting all posts at the place is a discovery")
+	}
+}
+
+func TestPostDetailRecentHasMore(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "detailauthor")
+	post := createTestPost(t, db, author, createTestPlace(t, db, "detailplace"), "", true)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+
+	hasMore := func() (likes, comments bool) {
+		t.Helper()
+		w := callHandler(NewPostController(db, nil).GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, author.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("post detail: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data PostDetail `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data.RecentLikesHasMore, resp.Data.RecentCommentsHasMore
+	}
+	addLikes := func(from, to int) {
+		t.Helper()
+		for i := from; i < to; i++ {
+			liker := createTestUser(t, db, fmt.Sprintf("detailliker%d", i))
+			if err := db.Create(&models.Like{PostID: post.ID, UserID: liker.ID}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	addComments := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if err := db.Create(&models.Comment{PostID: post.ID, UserID: author.ID, TextContent: "nice"}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Sınıra kadar her şey gömülü listelerde
+	addLikes(0, postDetailRecentLikes)
+	addComments(postDetailRecentComments)
+	if likes, comments := hasMore(); likes || comments {
+		t.Errorf("at the limits: likes has more = %v, comments has more = %v; want false, false", likes, comments)
+	}
+
+	addLikes(postDetailRecentLikes, postDetailRecentLikes+1)
+	addComments(1)
+	if likes, comments := hasMore(); !likes || !comments {
+		t.Errorf("over the limits: likes has more = %v, comments has more = %v; want true, true", likes, comments)
+	}
+}
+
+func TestCreatePostUsesVerifiedDimensions(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "dimensionsuser")
+	other := createTestUser(t, db, "dimensionsother")
+	place := createTestPlace(t, db, "dimensionsplace")
+
+	// İstemci 4000x100 bildirir, yükleme onayı gerçek boyutu kaydetmiştir
+	if err := db.Create(&models.MediaUpload{UserID: user.ID, MediaURL: "https://cdn.example.com/test.jpg", MediaType: "photo", Width: 1080, Height: 1350}).Error; err != nil {
+		t.Fatal(err)
+	}
+	create := func(author models.User) models.PostMedia {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"mediaItems": []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/test.jpg", "width": 4000, "height": 100}},
+			"placeId":    place.ID,
+			"latitude":   place.Latitude,
+			"longitude":  place.Longitude,
+			"isPublic":   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := callHandler(NewPostController(db, nil).CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), author.ID)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var media models.PostMedia
+		if err := db.Joins("JOIN posts ON posts.id = post_media.post_id").Where("posts.user_id = ?", author.ID).First(&media).Error; err != nil {
+			t.Fatal(err)
+		}
+		return media
+	}
+
+	if media := create(user); media.Width != 1080 || media.Height != 1350 {
+		t.Errorf("stored %dx%d, want the verified 1080x1350", media.Width, media.Height)
+	}
+	// Başkasının kaydı kullanılmaz, bildirilen değerlere dönülür
+	if media := create(other); media.Width != 4000 || media.Height != 100 {
+		t.Errorf("other user stored %dx%d, want the declared 4000x100", media.Width, media.Height)
+	}
+}
+
+func TestCreatePostWithGIF(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "gifuser")
+	place := createTestPlace(t, db, "gifplace")
+	if err := db.Model(&place).Update("base_points", 10).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Yerdeki ilk gönderi keşif bonusu almasın
+	createTestPost(t, db, createTestUser(t, db, "gifearlier"), place, "earlier", true)
+
+	body, err := json.Marshal(gin.H{
+		"mediaItems": []gin.H{
+			{"mediaType": media.GIF, "mediaUrl": "https://cdn.example.com/dance.gif", "width": 480, "height": 270},
+			{"mediaType": media.LivePhoto, "mediaUrl": "https://cdn.example.com/live.heic"},
+		},
+		"placeId":   place.ID,
+		"latitude":  place.Latitude,
+		"longitude": place.Longitude,
+		"isPublic":  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := NewPostController(db, nil)
+	w := callHandler(pc.CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), user.ID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	var post models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(10 + media.PointsBonus(media.GIF)); post.EarnedPoints != want {
+		t.Errorf("earned points = %d, want %d", post.EarnedPoints, want)
+	}
+	if got := totalPoints(t, db, user); got != post.EarnedPoints {
+		t.Errorf("user points = %d, want %d", got, post.EarnedPoints)
+	}
+
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+	w = callHandler(pc.GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, user.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("detail: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var detail struct {
+		Data struct {
+			MediaItems []PostMediaItem `json:"mediaItems"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatal(err)
+	}
+	items := detail.Data.MediaItems
+	if len(items) != 2 {
+		t.Fatalf("media items = %+v, want 2", items)
+	}
+	if items[0].MediaType != media.GIF || !items[0].IsAnimated || items[0].Width != 480 {
+		t.Errorf("gif item = %+v, want an animated 480px gif", items[0])
+	}
+	if items[1].MediaType != media.LivePhoto || !items[1].IsAnimated {
+		t.Errorf("live photo item = %+v, want an animated live photo", items[1])
+	}
+}
+
+func TestPostLanguage(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "languageuser")
+	place := createTestPlace(t, db, "languageplace")
+	pc := NewPostController(db, nil)
+
+	create := func(language string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"postCaption": "merhaba",
+			"mediaItems":  []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/test.jpg"}},
+			"placeId":     place.ID,
+			"latitude":    place.Latitude,
+			"longitude":   place.Longitude,
+			"isPublic":    true,
+			"language":    language,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(pc.CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), user.ID)
+	}
+
+	if w := create("xx"); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown code: status = %d, want 400", w.Code)
+	}
+	if w := create("en-US"); w.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var post models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	if post.Language != "en" {
+		t.Errorf("stored language = %q, want en", post.Language)
+	}
+}
+
+func TestGetUserFeedRejectsUnknownLanguage(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "feedlanguage")
+	w := callHandler(NewFeedController(db).GetUserFeed, http.MethodGet, "/feed?languages=tr&languages=xx", nil, user.ID)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestPostCooldown(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "cooldownuser")
+	admin := createTestUser(t, db, "cooldownadmin")
+	first := createTestPlace(t, db, "cooldownfirst")
+	second := createTestPlace(t, db, "cooldownsecond")
+	t.Setenv("RATE_LIMIT_POST_CREATE", "3/10m")
+	t.Setenv("RATE_LIMIT_POST_CREATE_PLACE", "2/1h")
+
+	create := func(place models.Place) *httptest.ResponseRecorder {
+		t.Helper()
+		return createPostAs(t, db, user, place)
+	}
+	backdate := func(age time.Duration) {
+		t.Helper()
+		if err := db.Model(&models.Post{}).Where("user_id = ?", user.ID).
+			Update("created_at", time.Now().Add(-age)).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := create(first); w.Code != http.StatusCreated {
+			t.Fatalf("post %d: status = %d, body = %s", i+1, w.Code, w.Body.String())
+		}
+	}
+	// Mekan başı sınır doldu, başka mekan hâlâ serbest
+	w := create(first)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("third post at the same place: status = %d, want 429", w.Code)
+	}
+	if w := create(second); w.Code != http.StatusCreated {
+		t.Fatalf("post at another place: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	// Genel sınır doldu
+	w = create(second)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("fourth post: status = %d, want 429", w.Code)
+	}
+	var resp struct {
+		RetryAfter int `json:"retryAfter"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.RetryAfter < 1 || resp.RetryAfter > 600 || w.Header().Get("Retry-After") != strconv.Itoa(resp.RetryAfter) {
+		t.Errorf("retryAfter = %d, header %q; want 1..600 and matching", resp.RetryAfter, w.Header().Get("Retry-After"))
+	}
+
+	// Silinen gönderiler sınırı aşmaya yaramaz
+	if err := db.Where("user_id = ?", user.ID).Delete(&models.Post{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if w := create(second); w.Code != http.StatusTooManyRequests {
+		t.Errorf("after deleting posts: status = %d, want 429", w.Code)
+	}
+
+	// Genel pencere geçince serbest, mekan penceresi hâlâ sürüyor
+	backdate(11 * time.Minute)
+	if w := create(second); w.Code != http.StatusCreated {
+		t.Errorf("after the global window: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := create(first); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same place within its window: status = %d, want 429", w.Code)
+	}
+	backdate(2 * time.Hour)
+	if w := create(first); w.Code != http.StatusCreated {
+		t.Errorf("after the place window: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	// Yöneticiler sınırdan muaftır
+	for i := 0; i < 4; i++ {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPost, "/posts", postCreateRequest(t, first))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: admin.ID, Role: utils.AdminRoleName})
+		NewPostController(db, nil).CreatePost(c)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("admin post %d: status = %d, body = %s", i+1, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestUpdatePostOwnership(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "updateowner")
+	other := createTestUser(t, db, "updateother")
+	place := createTestPlace(t, db, "updateplace")
+	post := createTestPost(t, db, owner, place, "original", true)
+	pc := NewPostController(db, nil)
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+
+	update := func(user models.User, body string) int {
+		t.Helper()
+		return callHandler(pc.UpdatePost, http.MethodPut, "/posts/"+param.Value, strings.NewReader(body), user.ID, param).Code
+	}
+	stored := func() models.Post {
+		t.Helper()
+		var row models.Post
+		if err := db.First(&row, post.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return row
+	}
+
+	if code := update(other, `{"isPublic":false,"language":"de"}`); code != http.StatusForbidden {
+		t.Errorf("update by another user: status = %d, want 403", code)
+	}
+	if row := stored(); !row.IsPublic || row.Language != "" {
+		t.Errorf("post changed by another user: isPublic %v, language %q", row.IsPublic, row.Language)
+	}
+
+	if code := update(owner, `{"isPublic":false,"language":"TR"}`); code != http.StatusOK {
+		t.Fatalf("update by owner: status = %d, want 200", code)
+	}
+	if row := stored(); row.IsPublic || row.Language != "tr" {
+		t.Errorf("after owner update: isPublic %v, language %q; want false, tr", row.IsPublic, row.Language)
+	}
+	if code := update(owner, `{"language":"klingon"}`); code != http.StatusBadRequest {
+		t.Errorf("invalid language: status = %d, want 400", code)
+	}
+}
+
+func TestValidateCaption(t *testing.T) {
+	t.Setenv("MAX_CAPTION_LENGTH", "5")
+
+	tests := []struct {
+		name      string
+		caption   string
+		wantField string
+	}{
+		{"empty", "", ""},
+		{"at limit", "abcde", ""},
+		{"over limit", "abcdef", "postCaption"},
+		// Uzunluk bayt değil rune olarak sayılır
+		{"turkish letters at limit", "çşğüö", ""},
+		{"emoji at limit", "🙂🙂🙂🙂🙂", ""},
+		{"emoji over limit", strings.Repeat("🙂", 6), "postCaption"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, msg := validateCaption("postCaption", tt.caption)
+			if field != tt.wantField {
+				t.Errorf("validateCaption(%q) field = %q, want %q", tt.caption, field, tt.wantField)
+			}
+			if (msg == "") != (tt.wantField == "") {
+				t.Errorf("validateCaption(%q) message = %q", tt.caption, msg)
+			}
+		})
+	}
+}
+
+func TestPostCaptionLength(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "captionuser")
+	place := createTestPlace(t, db, "captionplace")
+	pc := NewPostController(db, nil)
+
+	create := func(caption string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"postCaption": caption,
+			"mediaItems":  []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/test.jpg"}},
+			"placeId":     place.ID,
+			"latitude":    place.Latitude,
+			"longitude":   place.Longitude,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(pc.CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), user.ID)
+	}
+
+	// Varsayılan sınır 2200 karakter; emoji tek karakter sayılır
+	atLimit := strings.Repeat("🙂", 2200)
+	w := create(atLimit + "a")
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"field":"postCaption"`) {
+		t.Fatalf("over limit: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := create(atLimit); w.Code != http.StatusCreated {
+		t.Fatalf("at limit: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	var post models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+	update := func(caption string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(gin.H{"content": caption})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(pc.UpdatePost, http.MethodPut, "/posts/"+param.Value, bytes.NewReader(body), user.ID, param)
+	}
+	if w := update(strings.Repeat("ç", 2201)); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"field":"content"`) {
+		t.Errorf("update over limit: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := update("güncel"); w.Code != http.StatusOK {
+		t.Fatalf("update: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if err := db.First(&post, post.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if post.PostCaption != "güncel" {
+		t.Errorf("caption after update = %q, want güncel", post.PostCaption)
+	}
+}
+
+func TestLikedStatus(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "likedowner")
+	viewer := createTestUser(t, db, "likedviewer")
+	place := createTestPlace(t, db, "likedplace")
+	liked := createTestPost(t, db, owner, place, "liked", true)
+	unliked := createTestPost(t, db, owner, place, "unliked", true)
+	likedByOther := createTestPost(t, db, owner, place, "other", true)
+	for _, like := range []models.Like{{PostID: liked.ID, UserID: viewer.ID}, {PostID: likedByOther.ID, UserID: owner.ID}} {
+		if err := db.Create(&like).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	pc := NewPostController(db, nil)
+
+	body := fmt.Sprintf(`{"postIds":[%d,%d,%d,999999]}`, liked.ID, unliked.ID, likedByOther.ID)
+	w := callHandler(pc.LikedStatus, http.MethodPost, "/posts/liked-status", strings.NewReader(body), viewer.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data map[string]bool `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{
+		strconv.Itoa(int(liked.ID)):        true,
+		strconv.Itoa(int(unliked.ID)):      false,
+		strconv.Itoa(int(likedByOther.ID)): false,
+		"999999":                           false,
+	}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("liked status = %v, want %v", resp.Data, want)
+	}
+
+	if w := callHandler(pc.LikedStatus, http.MethodPost, "/posts/liked-status", strings.NewReader(`{"postIds":[]}`), viewer.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("empty postIds: status = %d, want 400", w.Code)
+	}
+
+	// Liste uçları isLiked alanını izleyiciye göre doldurur
+	userParam := gin.Param{Key: "userId", Value: strconv.Itoa(int(owner.ID))}
+	placeParam := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	listings := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		param   gin.Param
+	}{
+		{"user posts", pc.GetUserPosts, "/users/" + userParam.Value + "/posts", userParam},
+		{"place grid", pc.GetPlacePostsGrid, "/places/" + placeParam.Value + "/posts/grid", placeParam},
+	}
+	for _, l := range listings {
+		w := callHandler(l.handler, http.MethodGet, l.target, nil, viewer.ID, l.param)
+		var list postListResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Data) != 3 {
+			t.Fatalf("%s: %d posts, want 3: %s", l.name, len(list.Data), w.Body.String())
+		}
+		for _, post := range list.Data {
+			if post.Interaction.IsLiked != (post.ID == liked.ID) {
+				t.Errorf("%s: post %d isLiked = %v", l.name, post.ID, post.Interaction.IsLiked)
+			}
+		}
+	}
+}
+
+func TestGetMyPlaceHistory(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "historyuser")
+	other := createTestUser(t, db, "historyother")
+	place := createTestPlace(t, db, "historyplace")
+	elsewhere := createTestPlace(t, db, "historyelsewhere")
+
+	first := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	// UTC'de ocak, UTC+3'te şubat
+	monthEdge := time.Date(2026, 1, 31, 22, 30, 0, 0, time.UTC)
+	last := time.Date(2026, 3, 20, 18, 0, 0, 0, time.UTC)
+	for _, visit := range []struct {
+		user   models.User
+		place  models.Place
+		at     time.Time
+		points int64
+	}{
+		{user, place, first, 10},
+		{user, place, monthEdge, 5},
+		{user, place, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), 1},
+		{user, place, last, 1},
+		{user, elsewhere, last.Add(time.Hour), 30},
+		{other, place, last.Add(2 * time.Hour), 20},
+	} {
+		post := createTestPost(t, db, visit.user, visit.place, "", true)
+		if err := db.Model(&post).Updates(map[string]interface{}{"created_at": visit.at, "earned_points": visit.points}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	param := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	history := func(query string) (posts []PostSummary, summary PlaceVisitSummary, months []PlaceVisitMonth) {
+		t.Helper()
+		w := callHandler(NewPostController(db, nil).GetMyPlaceHistory, http.MethodGet, "/places/"+param.Value+"/my-history"+query, nil, user.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []PostSummary `json:"data"`
+			Meta struct {
+				Summary PlaceVisitSummary `json:"summary"`
+				Monthly []PlaceVisitMonth `json:"monthly"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data, resp.Meta.Summary, resp.Meta.Monthly
+	}
+
+	posts, summary, months := history("?timezone=UTC")
+	if len(posts) != 4 || summary.TotalPosts != 4 || summary.TotalPoints != 17 {
+		t.Errorf("posts = %d, summary = %+v; want 4 posts worth 17 points", len(posts), summary)
+	}
+	if summary.FirstVisitAt == nil || !summary.FirstVisitAt.Equal(first) {
+		t.Errorf("first visit = %v, want %v", summary.FirstVisitAt, first)
+	}
+	if summary.LastVisitAt == nil || !summary.LastVisitAt.Equal(last) {
+		t.Errorf("last visit = %v, want %v", summary.LastVisitAt, last)
+	}
+	want := []PlaceVisitMonth{{Month: "2026-03", Posts: 2, Points: 2}, {Month: "2026-01", Posts: 2, Points: 15}}
+	if !reflect.DeepEqual(months, want) {
+		t.Errorf("monthly in UTC = %+v, want %+v", months, want)
+	}
+
+	_, _, months = history("?tzOffset=180")
+	want = []PlaceVisitMonth{{Month: "2026-03", Posts: 2, Points: 2}, {Month: "2026-02", Posts: 1, Points: 5}, {Month: "2026-01", Posts: 1, Points: 10}}
+	if !reflect.DeepEqual(months, want) {
+		t.Errorf("monthly at UTC+3 = %+v, want %+v", months, want)
+	}
+
+	// Hiç gönderi yoksa ziyaret zamanları boştur
+	param.Value = strconv.Itoa(int(createTestPlace(t, db, "historynever").ID))
+	if _, summary, months := history(""); summary.TotalPosts != 0 || summary.FirstVisitAt != nil || len(months) != 0 {
+		t.Errorf("unvisited place: summary = %+v, months = %+v", summary, months)
+	}
+}
+
+func TestCreatePostRollsBackOnMediaFailure(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "rollbackuser")
+	place := createTestPlace(t, db, "rollbackplace")
+	if err := db.Model(&user).Update("total_points", 40).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// İkinci medya kaydında hata: gönderi ve ilk medya işlem içinde yazılmış olur
+	var mediaCalls int
+	failMedia := true
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail_post_media", func(tx *gorm.DB) {
+		if tx.Statement.Table != "post_media" || !failMedia {
+			return
+		}
+		if mediaCalls++; mediaCalls == 2 {
+			tx.AddError(errors.New("injected media failure"))
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(gin.H{
+		"mediaItems": []gin.H{
+			{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/first.jpg"},
+			{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/second.jpg"},
+		},
+		"placeId":   place.ID,
+		"latitude":  place.Latitude,
+		"longitude": place.Longitude,
+		"isPublic":  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := NewPostController(db, nil)
+	create := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/posts", bytes.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.Header.Set("Idempotency-Key", "rollback-retry")
+		c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: user.ID, Role: "user"})
+		pc.CreatePost(c)
+		return w
+	}
+
+	if w := create(); w.Code != http.StatusInternalServerError {
+		t.Fatalf("create with failing media: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	count := func(model interface{}, query string, args ...interface{}) int64 {
+		t.Helper()
+		var n int64
+		if err := db.Unscoped().Model(model).Where(query, args...).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if n := count(&models.Post{}, "user_id = ?", user.ID); n != 0 {
+		t.Errorf("posts after rollback = %d, want 0", n)
+	}
+	if n := count(&models.PostMedia{}, "media_url LIKE ?", "https://cdn.example.com/%"); n != 0 {
+		t.Errorf("media after rollback = %d, want 0", n)
+	}
+	if n := count(&models.ActivityLog{}, "user_id = ?", user.ID); n != 0 {
+		t.Errorf("activity logs after rollback = %d, want 0", n)
+	}
+	if n := count(&models.Notification{}, "user_id = ?", user.ID); n != 0 {
+		t.Errorf("notifications after rollback = %d, want 0", n)
+	}
+	var stored models.User
+	if err := db.Select("total_points, posts_count").First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.TotalPoints != 40 || stored.PostsCount != 0 {
+		t.Errorf("user after rollback: points %d, posts %d; want 40 and 0", stored.TotalPoints, stored.PostsCount)
+	}
+
+	// Anahtar serbest bırakılır; yeniden deneme gönderiyi keşif bonusuyla oluşturur
+	failMedia = false
+	if w := create(); w.Code != http.StatusCreated {
+		t.Fatalf("retry: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var post models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !post.IsDiscovery || count(&models.PostMedia{}, "post_id = ?", post.ID) != 2 {
+		t.Errorf("retried post = %+v, want a discovery with two media items", post)
+	}
+	if got := totalPoints(t, db, user); got != 40+post.EarnedPoints {
+		t.Errorf("points after retry = %d, want %d", got, 40+post.EarnedPoints)
+	}
+}
+
+func TestGetPostDetailPresignsMediaOnlyForAuthorizedViewers(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "privatemediaauthor")
+	follower := createTestUser(t, db, "privatemediafollower")
+	stranger := createTestUser(t, db, "privatemediastranger")
+	if err := db.Model(&author).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: author.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	post := createTestPost(t, db, author, createTestPlace(t, db, "privatemediaplace"), "private", true)
+	key := fmt.Sprintf("uploads/photo/%d/1_private.jpg", author.ID)
+	if err := db.Create(&models.PostMedia{PostID: post.ID, MediaType: "photo", MediaURL: "https://cdn.example.com/" + key}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CLOUDFLARE_PRIVATE_MEDIA", "true")
+	pc := NewPostController(db, newTestUploadController(t))
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(post.ID))}
+
+	w := callHandler(pc.GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, follower.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("follower: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			MediaItems []PostMediaItem `json:"mediaItems"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.MediaItems) != 1 || !strings.Contains(resp.Data.MediaItems[0].MediaURL, "X-Amz-Signature=") {
+		t.Errorf("follower media = %+v, want a presigned URL", resp.Data.MediaItems)
+	}
+
+	w = callHandler(pc.GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, stranger.ID, param)
+	if w.Code != http.StatusNotFound || strings.Contains(w.Body.String(), key) {
+		t.Errorf("stranger: status = %d, body = %s; want 404 without the media", w.Code, w.Body.String())
+	}
+}
+
+func TestPostThumbnailsArePresignedOutsidePostDetail(t *testing.T) {
+	db := openTestDB(t)
+	author := createTestUser(t, db, "thumbauthor")
+	follower := createTestUser(t, db, "thumbfollower")
+	if err := db.Model(&author).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: author.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	place := createTestPlace(t, db, "thumbplace")
+	post := createTestPost(t, db, author, place, "private", true)
+	key := fmt.Sprintf("uploads/photo/%d/1_thumb.jpg", author.ID)
+	if err := db.Create(&models.PostMedia{PostID: post.ID, MediaType: "photo", MediaURL: "https://cdn.example.com/" + key}).Error; err != nil {
+		t.Fatal(err)
+	}
+	comment := createTestComment(t, db, follower, post, nil, "nice", time.Now())
+	if err := db.Create(&models.ActivityLog{UserID: follower.ID, PostID: post.ID, PlaceID: place.ID, Activity: "post_liked"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CLOUDFLARE_PRIVATE_MEDIA", "true")
+	uploads := newTestUploadController(t)
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		param   gin.Param
+	}{
+		{"place posts", NewPlaceController(db, uploads).GetPlacePosts, gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}},
+		{"comment context", NewCommentController(db, uploads).GetCommentContext, gin.Param{Key: "commentId", Value: strconv.Itoa(int(comment.CommentID))}},
+		{"activity", NewUserController(db, uploads).GetUserActivity, gin.Param{Key: "userId", Value: strconv.Itoa(int(follower.ID))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := callHandler(tt.handler, http.MethodGet, "/", nil, follower.ID, tt.param)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			if body := w.Body.String(); !strings.Contains(body, key) || !strings.Contains(body, "X-Amz-Signature=") {
+				t.Errorf("body = %s, want a presigned thumbnail", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/media"
+	"github.com/snap-point/api-go/models"
+)
+
+// Algısal özetleri en fazla bu kadar bit farklı olan görüntüler aynı sayılır
+const nearDuplicateHashDistance = 6
+
+// findDuplicatePost returns the id of the user's post at placeID, created within
+// the duplicate window, that reuses one of mediaURLs or has an image whose
+// perceptual hash is near one of hashes. It returns 0 when there is none.
+func (pc *PostController) findDuplicatePost(userID, placeID uint, mediaURLs, hashes []string) (uint, error) {
+	var recent []struct {
+		PostID         uint
+		MediaURL       string
+		PerceptualHash string
+	}
+	if err := pc.DB.Model(&models.PostMedia{}).
+		Select("post_media.post_id, post_media.media_url, post_media.perceptual_hash").
+		Joins("JOIN posts ON posts.id = post_media.post_id AND posts.deleted_at IS NULL").
+		Where("posts.user_id = ? AND posts.place_id = ? AND posts.created_at >= ?",
+			userID, placeID, time.Now().Add(-config.GetDuplicatePostWindow())).
+		Order("post_media.post_id DESC").
+		Scan(&recent).Error; err != nil {
+		return 0, err
+	}
+
+	urls := make(map[string]bool, len(mediaURLs))
+	for _, url := range mediaURLs {
+		urls[url] = true
+	}
+	for _, item := range recent {
+		if urls[item.MediaURL] {
+			return item.PostID, nil
+		}
+		for _, hash := range hashes {
+			if distance, ok := media.HashDistance(item.PerceptualHash, hash); ok && distance <= nearDuplicateHashDistance {
+				return item.PostID, nil
+			}
+		}
+	}
+	return 0, nil
+}
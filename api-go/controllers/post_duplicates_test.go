@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestDuplicatePostPolicy(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "duplicateuser")
+	place := createTestPlace(t, db, "duplicateplace")
+	otherPlace := createTestPlace(t, db, "duplicateother")
+
+	// İkinci yükleme aynı fotoğrafın yeniden sıkıştırılmış hali: özeti bir bit farklı
+	for _, upload := range []models.MediaUpload{
+		{UserID: user.ID, MediaURL: "https://cdn.example.com/sunset.jpg", MediaType: "photo", PerceptualHash: "f0e0c0a080604020"},
+		{UserID: user.ID, MediaURL: "https://cdn.example.com/sunset-copy.jpg", MediaType: "photo", PerceptualHash: "f0e0c0a080604021"},
+		{UserID: user.ID, MediaURL: "https://cdn.example.com/beach.jpg", MediaType: "photo", PerceptualHash: "0f1f3f5f7f9fbfdf"},
+	} {
+		if err := db.Create(&upload).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	create := func(place models.Place, mediaURL string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := json.Marshal(gin.H{
+			"mediaItems": []gin.H{{"mediaType": "photo", "mediaUrl": mediaURL}},
+			"placeId":    place.ID,
+			"latitude":   place.Latitude,
+			"longitude":  place.Longitude,
+			"isPublic":   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return callHandler(NewPostController(db, nil).CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), user.ID)
+	}
+
+	if w := create(place, "https://cdn.example.com/sunset.jpg"); w.Code != http.StatusCreated {
+		t.Fatalf("first post: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var original models.Post
+	if err := db.Where("user_id = ?", user.ID).First(&original).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"https://cdn.example.com/sunset.jpg", "https://cdn.example.com/sunset-copy.jpg"} {
+		w := create(place, url)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("repeat of %s: status = %d, want 409", url, w.Code)
+		}
+		var resp struct {
+			DuplicateOf uint `json:"duplicateOf"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.DuplicateOf != original.ID {
+			t.Errorf("repeat of %s: duplicateOf = %d, want %d", url, resp.DuplicateOf, original.ID)
+		}
+	}
+
+	// Farklı görüntü ya da farklı mekan serbest
+	if w := create(place, "https://cdn.example.com/beach.jpg"); w.Code != http.StatusCreated {
+		t.Errorf("different photo: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := create(otherPlace, "https://cdn.example.com/sunset.jpg"); w.Code != http.StatusCreated {
+		t.Errorf("another place: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	// flag politikasında gönderi yayınlanır ama incelemeye düşer
+	t.Setenv("DUPLICATE_POST_POLICY", "flag")
+	if w := create(place, "https://cdn.example.com/sunset-copy.jpg"); w.Code != http.StatusCreated {
+		t.Fatalf("flagged repeat: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var flagged []models.Post
+	if err := db.Where("user_id = ? AND needs_review", user.ID).Find(&flagged).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(flagged) != 1 || flagged[0].PlaceID != place.ID {
+		t.Fatalf("posts needing review = %+v, want the one flagged repeat", flagged)
+	}
+	if flagged[0].EarnedPoints != 0 {
+		t.Errorf("flagged post earned %d points before review", flagged[0].EarnedPoints)
+	}
+
+	// İncelemedeki gönderiyi yalnızca sahibi görür
+	viewer := createTestUser(t, db, "duplicateviewer")
+	param := gin.Param{Key: "id", Value: strconv.Itoa(int(flagged[0].ID))}
+	detail := func(userID uint) int {
+		return callHandler(NewPostController(db, nil).GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, userID, param).Code
+	}
+	if code := detail(viewer.ID); code != http.StatusNotFound {
+		t.Errorf("flagged post for another user: status = %d, want 404", code)
+	}
+	if code := detail(user.ID); code != http.StatusOK {
+		t.Errorf("flagged post for its owner: status = %d, want 200", code)
+	}
+
+	totalPoints := func() int64 {
+		var stored models.User
+		if err := db.Select("total_points").First(&stored, user.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return stored.TotalPoints
+	}
+	before := totalPoints()
+	ac := NewAdminController(db, nil)
+	review := func(postID uint, action string) int {
+		param := gin.Param{Key: "postId", Value: strconv.Itoa(int(postID))}
+		return callHandler(ac.ReviewPost, http.MethodPost, "/admin/posts/"+param.Value+"/review",
+			strings.NewReader(`{"action":"`+action+`"}`), viewer.ID, param).Code
+	}
+	if code := review(flagged[0].ID, "approve"); code != http.StatusOK {
+		t.Fatalf("approve: status = %d", code)
+	}
+	if got, want := totalPoints()-before, calculateInitialPoints(place.BasePoints, "photo"); got != want {
+		t.Errorf("approval credited %d points, want %d", got, want)
+	}
+	if code := detail(viewer.ID); code != http.StatusOK {
+		t.Errorf("approved post for another user: status = %d, want 200", code)
+	}
+	if code := review(flagged[0].ID, "approve"); code != http.StatusNotFound {
+		t.Errorf("second approve: status = %d, want 404", code)
+	}
+
+	// Reddedilen gönderi silinir
+	if w := create(place, "https://cdn.example.com/sunset.jpg"); w.Code != http.StatusCreated {
+		t.Fatalf("second flagged repeat: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var rejected models.Post
+	if err := db.Where("user_id = ? AND needs_review", user.ID).First(&rejected).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code := review(rejected.ID, "reject"); code != http.StatusOK {
+		t.Fatalf("reject: status = %d", code)
+	}
+	if err := db.First(&models.Post{}, rejected.ID).Error; err == nil {
+		t.Error("rejected post still exists")
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+// Harita kümeleme ayarları
+const (
+	// postClusterCellsPerTile bir harita karosunun (256px) kenarındaki hücre sayısı
+	postClusterCellsPerTile = 4
+	// postClusterExpandMax bu sayıya kadar gönderi içeren kümeler tek tek döndürülür
+	postClusterExpandMax = 3
+	// postClusterMaxCells bir istekte döndürülen en fazla küme sayısı
+	postClusterMaxCells = 500
+)
+
+// PostMapClustersQuery; Bounds "minLat,minLng,maxLat,maxLng" biçimindedir
+type PostMapClustersQuery struct {
+	Bounds string `form:"bounds" binding:"required"`
+	Zoom   int    `form:"zoom,default=14" binding:"min=0,max=22"`
+}
+
+// PostMapCluster is one grid cell of visible posts. Posts is set only for
+// small clusters; larger ones are drawn as a single marker with Count.
+type PostMapCluster struct {
+	Latitude  float64       `json:"latitude"`  // kümenin ağırlık merkezi
+	Longitude float64       `json:"longitude"` // kümenin ağırlık merkezi
+	Count     int64         `json:"count"`
+	Posts     []PostSummary `json:"posts,omitempty"`
+}
+
+// parseMapBounds parses "minLat,minLng,maxLat,maxLng"
+func parseMapBounds(bounds string) (minLat, minLng, maxLat, maxLng float64, ok bool) {
+	parts := strings.Split(bounds, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, false
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) {
+			return 0, 0, 0, 0, false
+		}
+		values[i] = value
+	}
+	minLat, minLng, maxLat, maxLng = values[0], values[1], values[2], values[3]
+	// Tarih değiştirme çizgisini aşan görünümler desteklenmez
+	if minLat < -90 || maxLat > 90 || minLng < -180 || maxLng > 180 || minLat > maxLat || minLng > maxLng {
+		return 0, 0, 0, 0, false
+	}
+	return minLat, minLng, maxLat, maxLng, true
+}
+
+// postClusterCellSize returns the grid cell size in degrees for a zoom level,
+// a fixed fraction of one map tile so clusters keep their on-screen size.
+func postClusterCellSize(zoom int) float64 {
+	return 360 / math.Pow(2, float64(zoom)) / postClusterCellsPerTile
+}
+
+// GetPostMapClusters godoc
+// @Summary Get clustered post locations for a map view
+// @Description Groups the posts visible to the user inside the bounds into grid cells sized for the zoom level. Each cluster has its centroid and post count; clusters of up to 3 posts also list the posts. The largest clusters are returned first, at most 500.
+// @Tags posts
+// @Produce json
+// @Param bounds query string true "Visible area as minLat,minLng,maxLat,maxLng"
+// @Param zoom query integer false "Map zoom level, 0-22 (default: 14)"
+// @Success 200 {object} StandardResponse{data=[]PostMapCluster}
+// @Router /posts/map-clusters [get]
+func (pc *PostController) GetPostMapClusters(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var query PostMapClustersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	minLat, minLng, maxLat, maxLng, ok := parseMapBounds(query.Bounds)
+	if !ok {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "bounds must be minLat,minLng,maxLat,maxLng within valid coordinates",
+		})
+		return
+	}
+
+	cellSize := postClusterCellSize(query.Zoom)
+	visibility, visibilityArgs := visiblePostsCondition(currentUser.UserID)
+
+	var rows []struct {
+		Latitude  float64       `gorm:"column:latitude"`
+		Longitude float64       `gorm:"column:longitude"`
+		Count     int64         `gorm:"column:count"`
+		PostIDs   pq.Int64Array `gorm:"column:post_ids"`
+	}
+	// Hücreler mekan seçimindeki ızgarayla aynı şekilde floor(koordinat / hücre) ile bulunur
+	if err := pc.DB.Model(&models.Post{}).
+		Select(`FLOOR(posts.latitude / ?) AS cell_lat, FLOOR(posts.longitude / ?) AS cell_lng,
+			AVG(posts.latitude) AS latitude, AVG(posts.longitude) AS longitude, COUNT(*) AS count,
+			CASE WHEN COUNT(*) <= ? THEN array_agg(posts.id ORDER BY posts.created_at DESC) END AS post_ids`,
+			cellSize, cellSize, postClusterExpandMax).
+		Joins("JOIN users ON posts.user_id = users.id").
+		Where("posts.latitude BETWEEN ? AND ? AND posts.longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng).
+		Where(visibility, visibilityArgs...).
+		Group("cell_lat, cell_lng").
+		Order("count DESC, latitude, longitude").
+		Limit(postClusterMaxCells).
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching map clusters",
+		})
+		return
+	}
+
+	var postIDs []int64
+	for _, row := range rows {
+		postIDs = append(postIDs, row.PostIDs...)
+	}
+	posts := make(map[uint]PostSummary)
+	if len(postIDs) > 0 {
+		summaries, err := pc.postSummaries(currentUser.UserID, pc.DB.Model(&models.Post{}).Where("posts.id IN ?", postIDs), 0, len(postIDs))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{
+				Success: false,
+				Message: "Error fetching map clusters",
+			})
+			return
+		}
+		for _, post := range summaries {
+			posts[post.ID] = post
+		}
+	}
+
+	clusters := make([]PostMapCluster, len(rows))
+	for i, row := range rows {
+		clusters[i] = PostMapCluster{Latitude: row.Latitude, Longitude: row.Longitude, Count: row.Count}
+		for _, id := range row.PostIDs {
+			if post, ok := posts[uint(id)]; ok {
+				clusters[i].Posts = append(clusters[i].Posts, post)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    clusters,
+		Meta: gin.H{
+			"zoom":     query.Zoom,
+			"cellSize": cellSize,
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestGetPostMapClusters(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "clusterviewer")
+	author := createTestUser(t, db, "clusterauthor")
+	dense := createTestPlace(t, db, "clusterdense")
+	sparse := createTestPlace(t, db, "clustersparse")
+	// Yoğun mekandan yaklaşık 11 km kuzeyde
+	if err := db.Model(&sparse).Update("latitude", dense.Latitude+0.1).Error; err != nil {
+		t.Fatal(err)
+	}
+	sparse.Latitude += 0.1
+
+	for i := 0; i < 5; i++ {
+		createTestPost(t, db, author, dense, "crowded", true)
+	}
+	lonely := createTestPost(t, db, author, sparse, "quiet", true)
+	// Görülemeyen gönderi kümeye sayılmaz
+	createTestPost(t, db, createTestUser(t, db, "clusterprivate"), sparse, "hidden", false)
+
+	pc := NewPostController(db, nil)
+	clusters := func(target string) []PostMapCluster {
+		t.Helper()
+		w := callHandler(pc.GetPostMapClusters, http.MethodGet, target, nil, viewer.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []PostMapCluster `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+
+	got := clusters("/posts/map-clusters?bounds=40.9,28.9,41.2,29.1&zoom=14")
+	if len(got) != 2 {
+		t.Fatalf("clusters = %+v, want 2", got)
+	}
+	if got[0].Count != 5 || len(got[0].Posts) != 0 || math.Abs(got[0].Latitude-dense.Latitude) > 1e-9 {
+		t.Errorf("dense cluster = %+v, want 5 posts as a single marker", got[0])
+	}
+	if got[1].Count != 1 || len(got[1].Posts) != 1 || got[1].Posts[0].ID != lonely.ID {
+		t.Errorf("sparse cluster = %+v, want the single post listed", got[1])
+	}
+
+	// Zoom düştükçe iki bölge aynı hücreye girer
+	got = clusters("/posts/map-clusters?bounds=40.9,28.9,41.2,29.1&zoom=4")
+	if len(got) != 1 || got[0].Count != 6 {
+		t.Errorf("zoomed out clusters = %+v, want one cluster of 6", got)
+	}
+
+	// Görünüm dışındaki gönderiler sayılmaz
+	if got := clusters("/posts/map-clusters?bounds=41.05,28.9,41.2,29.1&zoom=14"); len(got) != 1 || got[0].Count != 1 {
+		t.Errorf("narrow bounds clusters = %+v, want only the sparse post", got)
+	}
+
+	for _, bounds := range []string{"41,29,40,28", "41,29", "a,b,c,d", "-91,0,0,0"} {
+		if w := callHandler(pc.GetPostMapClusters, http.MethodGet, "/posts/map-clusters?bounds="+bounds, nil, viewer.ID); w.Code != http.StatusBadRequest {
+			t.Errorf("bounds %s: status = %d, want 400", bounds, w.Code)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import "gorm.io/gorm"
+
+// PostMediaTypeQuery; MediaType verilirse yalnızca ilk medyası bu türde olan gönderiler listelenir
+type PostMediaTypeQuery struct {
+	MediaType string `form:"mediaType" binding:"omitempty,mediatype"`
+}
+
+// firstMediaTypeSQL matches posts whose first media, the one shown as the
+// thumbnail in listings, is of the given type.
+const firstMediaTypeSQL = `EXISTS (
+	SELECT 1 FROM post_media
+	WHERE post_media.id = (
+		SELECT first_media.id FROM post_media first_media
+		WHERE first_media.post_id = posts.id
+		ORDER BY first_media.order_index, first_media.id
+		LIMIT 1
+	)
+	AND post_media.media_type = ?
+)`
+
+// filterByMediaType restricts a posts query to the media type; an empty type leaves it unchanged.
+func filterByMediaType(db *gorm.DB, mediaType string) *gorm.DB {
+	if mediaType == "" {
+		return db
+	}
+	return db.Where(firstMediaTypeSQL, mediaType)
+}
+
+// mediaTypeMeta is the mediaType filter as reported in response meta; null when not filtered.
+func mediaTypeMeta(mediaType string) interface{} {
+	if mediaType == "" {
+		return nil
+	}
+	return mediaType
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/media"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestMediaTypeFilter(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "mediafilterowner")
+	viewer := createTestUser(t, db, "mediafilterviewer")
+	place := createTestPlace(t, db, "mediafilterplace")
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: owner.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Her gönderinin medyaları sırasıyla eklenir; ilk medya listede gösterilen küçük resimdir
+	postWithMedia := func(caption string, mediaTypes ...string) models.Post {
+		t.Helper()
+		post := createTestPost(t, db, owner, place, caption, true)
+		for i, mediaType := range mediaTypes {
+			if err := db.Create(&models.PostMedia{
+				PostID:     post.ID,
+				MediaType:  mediaType,
+				MediaURL:   fmt.Sprintf("https://cdn.example.com/%d/%d", post.ID, i),
+				OrderIndex: i,
+			}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+		return post
+	}
+	photo := postWithMedia("photo", media.Photo)
+	photoFirst := postWithMedia("photo then video", media.Photo, media.Video)
+	videoFirst := postWithMedia("video then photo", media.Video, media.Photo)
+	postWithMedia("no media")
+
+	pc := NewPostController(db, nil)
+	userParam := gin.Param{Key: "userId", Value: strconv.Itoa(int(owner.ID))}
+	placeParam := gin.Param{Key: "placeId", Value: strconv.Itoa(int(place.ID))}
+	listings := []struct {
+		name    string
+		handler gin.HandlerFunc
+		target  string
+		param   gin.Param
+	}{
+		{"user posts", pc.GetUserPosts, "/users/" + userParam.Value + "/posts", userParam},
+		{"place grid", pc.GetPlacePostsGrid, "/places/" + placeParam.Value + "/posts/grid", placeParam},
+	}
+	for _, listing := range listings {
+		if got := listPostIDs(t, listing.handler, listing.target+"?mediaType=photo", viewer.ID, listing.param); !sameIDs(got, []uint{photo.ID, photoFirst.ID}) {
+			t.Errorf("%s: photo filter = %v, want %v", listing.name, got, []uint{photo.ID, photoFirst.ID})
+		}
+		if got := listPostIDs(t, listing.handler, listing.target+"?mediaType=video", viewer.ID, listing.param); !sameIDs(got, []uint{videoFirst.ID}) {
+			t.Errorf("%s: video filter = %v, want %v", listing.name, got, []uint{videoFirst.ID})
+		}
+		if got := listPostIDs(t, listing.handler, listing.target, viewer.ID, listing.param); len(got) != 4 {
+			t.Errorf("%s: unfiltered = %v, want all 4 posts", listing.name, got)
+		}
+
+		w := callHandler(listing.handler, http.MethodGet, listing.target+"?mediaType=video", nil, viewer.ID, listing.param)
+		var resp struct {
+			Meta struct {
+				MediaType string `json:"mediaType"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Meta.MediaType != media.Video {
+			t.Errorf("%s: meta mediaType = %q, want video", listing.name, resp.Meta.MediaType)
+		}
+		if w := callHandler(listing.handler, http.MethodGet, listing.target+"?mediaType=hologram", nil, viewer.ID, listing.param); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: unknown media type: status = %d, want 400", listing.name, w.Code)
+		}
+	}
+
+	fc := NewFeedController(db)
+	if ids, _ := feedPostIDs(t, fc, "/feed?mediaType=photo", viewer.ID); !sameIDs(ids, []uint{photo.ID, photoFirst.ID}) {
+		t.Errorf("feed photo filter = %v, want %v", ids, []uint{photo.ID, photoFirst.ID})
+	}
+	if w := callHandler(fc.GetUserFeed, http.MethodGet, "/feed?mediaType=hologram", nil, viewer.ID); w.Code != http.StatusBadRequest {
+		t.Errorf("feed unknown media type: status = %d, want 400", w.Code)
+	}
+}
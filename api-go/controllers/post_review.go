@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostReviewRequest approves or rejects a post flagged as a duplicate
+type PostReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject"`
+}
+
+// GetPostReviewQueue godoc
+// @Summary List posts flagged as duplicates (admin)
+// @Description Returns posts the duplicate-post policy flagged for review, oldest first. Until reviewed they are visible to their author only and earn no points.
+// @Tags admin
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse
+// @Router /admin/posts/review-queue [get]
+func (ac *AdminController) GetPostReviewQueue(c *gin.Context) {
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+
+	db := ac.DB.Model(&models.Post{}).Where("posts.needs_review = true")
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch review queue"})
+		return
+	}
+
+	var posts []struct {
+		ID        uint      `json:"id"`
+		UserID    uint      `json:"userId"`
+		Username  string    `json:"username"`
+		PlaceID   uint      `json:"placeId"`
+		PlaceName string    `json:"placeName"`
+		Caption   string    `json:"caption" gorm:"column:post_caption"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	if err := db.Select("posts.id, posts.user_id, users.username, posts.place_id, places.name AS place_name, posts.post_caption, posts.created_at").
+		Joins("JOIN users ON users.id = posts.user_id").
+		Joins("JOIN places ON places.id = posts.place_id").
+		Order("posts.created_at, posts.id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to fetch review queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    posts,
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
+
+// ReviewPost godoc
+// @Summary Approve or reject a flagged post (admin)
+// @Description Approving publishes the post and credits the points it would have earned; rejecting deletes it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID"
+// @Param request body PostReviewRequest true "approve or reject"
+// @Success 200 {object} StandardResponse
+// @Router /admin/posts/{postId}/review [post]
+func (ac *AdminController) ReviewPost(c *gin.Context) {
+	var req PostReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	adminID := utils.GetUser(c).UserID
+
+	tx := ac.DB.Begin()
+	failed := func() {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review post"})
+	}
+
+	// Satır kilitlenir: aynı gönderi iki kez onaylanıp puanı iki kez verilmez
+	var post models.Post
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("needs_review = true").First(&post, c.Param("postId")).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Post not found in review queue"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review post"})
+		return
+	}
+
+	var points int64
+	var err error
+	if req.Action == "approve" {
+		points, err = approveFlaggedPost(tx, post)
+	} else {
+		err = rejectFlaggedPost(tx, post)
+	}
+	if err == nil {
+		err = recordAdminAction(tx, adminID, "post_review_"+req.Action, "post", post.ID, gin.H{
+			"userId":  post.UserID,
+			"placeId": post.PlaceID,
+			"points":  points,
+		})
+	}
+	if err != nil {
+		failed()
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to review post"})
+		return
+	}
+
+	log.Printf("Post review: admin=%d post=%d action=%s points=%d", adminID, post.ID, req.Action, points)
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data: gin.H{
+			"postId": post.ID,
+			"action": req.Action,
+			"points": points,
+		},
+		Message: "Post reviewed",
+	})
+}
+
+// approveFlaggedPost publishes a flagged post and credits the points it was
+// held back at creation: the place's base points plus the first media's bonus.
+func approveFlaggedPost(tx *gorm.DB, post models.Post) (int64, error) {
+	var place models.Place
+	if err := tx.Unscoped().Select("id, base_points").First(&place, post.PlaceID).Error; err != nil {
+		return 0, err
+	}
+	var first models.PostMedia
+	if err := tx.Where("post_id = ?", post.ID).Order("order_index").First(&first).Error; err != nil {
+		return 0, err
+	}
+	points := calculateInitialPoints(place.BasePoints, first.MediaType)
+
+	if err := tx.Model(&post).Updates(map[string]interface{}{"needs_review": false, "earned_points": points}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.ActivityLog{}).
+		Where("post_id = ? AND activity = ?", post.ID, "post_created").
+		Update("points", points).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.User{}).Where("id = ?", post.UserID).
+		Update("total_points", gorm.Expr("total_points + ?", points)).Error; err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+// rejectFlaggedPost deletes a flagged post the way DeletePost does; it never
+// earned points, so there is nothing to take back
+func rejectFlaggedPost(tx *gorm.DB, post models.Post) error {
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostMedia{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.Like{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("post_id = ?", post.ID).Delete(&models.Comment{}).Error; err != nil {
+		return err
+	}
+	if err := adjustPostsCount(tx, post.UserID, -1); err != nil {
+		return err
+	}
+	return tx.Delete(&post).Error
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// referralCodeAlphabet karıştırılabilen 0/O ve 1/I karakterlerini içermez
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeLength   = 8
+	// referralCodeAttempts çakışan kodlar için en fazla deneme sayısı
+	referralCodeAttempts = 5
+)
+
+var (
+	errInvalidReferralCode = errors.New("invalid referral code")
+	errSelfReferral        = errors.New("you cannot use your own referral code")
+)
+
+// ReferralItem is one user who signed up with the current user's code
+type ReferralItem struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	Points      int        `json:"points"` // davet edene verilen bonus
+	User        PostUser   `json:"user"`
+}
+
+// ReferralsResponse lists the current user's code and the referrals made with it
+type ReferralsResponse struct {
+	Code           string         `json:"code"`
+	ReferrerPoints int            `json:"referrerPoints"` // davet tamamlandığında davet edene verilir
+	ReferredPoints int            `json:"referredPoints"` // davet tamamlandığında davet edilene verilir
+	PointsEarned   int64          `json:"pointsEarned"`
+	Pending        []ReferralItem `json:"pending"`
+	Completed      []ReferralItem `json:"completed"`
+}
+
+// generateReferralCode returns a random code from referralCodeAlphabet
+func generateReferralCode() (string, error) {
+	code := make([]byte, referralCodeLength)
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeReferralCode trims and uppercases a code typed by a user
+func normalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ensureReferralCode returns the user's referral code, generating it on first use
+func ensureReferralCode(db *gorm.DB, userID uint) (string, error) {
+	var user models.User
+	if err := db.Select("id, referral_code").First(&user, userID).Error; err != nil {
+		return "", err
+	}
+	if user.ReferralCode != nil {
+		return *user.ReferralCode, nil
+	}
+
+	var lastErr error
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, err := generateReferralCode()
+		if err != nil {
+			return "", err
+		}
+		// Eşzamanlı bir istek kodu önce yazmışsa o kod kullanılır
+		result := db.Model(&models.User{}).Where("id = ? AND referral_code IS NULL", userID).Update("referral_code", code)
+		if result.Error != nil {
+			lastErr = result.Error
+			continue
+		}
+		if result.RowsAffected == 0 {
+			if err := db.Select("id, referral_code").First(&user, userID).Error; err != nil {
+				return "", err
+			}
+			if user.ReferralCode != nil {
+				return *user.ReferralCode, nil
+			}
+			continue
+		}
+		return code, nil
+	}
+	return "", lastErr
+}
+
+// emailIdentity reduces an address to its mailbox, dropping any +tag, so
+// aliases of the same inbox are recognised as one person.
+func emailIdentity(email string) string {
+	email = normalizeEmail(email)
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local := email[:at]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	return local + email[at:]
+}
+
+// findReferrer returns the owner of code, rejecting unknown codes and codes
+// that belong to the registering person under another address.
+func findReferrer(db *gorm.DB, code, email string) (*models.User, error) {
+	var referrer models.User
+	if err := db.Select("id, email").Where("referral_code = ?", normalizeReferralCode(code)).First(&referrer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidReferralCode
+		}
+		return nil, err
+	}
+	if emailIdentity(referrer.Email) == emailIdentity(email) {
+		return nil, errSelfReferral
+	}
+	return &referrer, nil
+}
+
+// completeReferral completes the pending referral of userID inside the post
+// transaction tx and awards the bonus points to both users. While either
+// account is still younger than the referral account-age gate the referral
+// stays pending and completes with a later post. It returns the completed
+// referral, or nil when there was nothing to complete.
+func completeReferral(tx *gorm.DB, userID, postID uint) (*models.Referral, error) {
+	var referral models.Referral
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("referred_user_id = ? AND status = ?", userID, models.ReferralPending).
+		First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []models.User
+	if err := tx.Select("id, created_at, is_verified").
+		Where("id IN ?", []uint{referral.ReferrerUserID, referral.ReferredUserID}).
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	// Silinmiş davet eden hesaba bonus verilmez
+	if len(accounts) != 2 {
+		return nil, nil
+	}
+	for _, account := range accounts {
+		if !accountAgeAllows(account, config.AccountAgeActionReferral) {
+			return nil, nil
+		}
+	}
+
+	referrerPoints, referredPoints := config.GetReferralBonusPoints()
+	now := time.Now()
+	if err := tx.Model(&referral).Updates(map[string]interface{}{
+		"status":          models.ReferralCompleted,
+		"completed_at":    now,
+		"post_id":         postID,
+		"referrer_points": referrerPoints,
+		"referred_points": referredPoints,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	for id, points := range map[uint]int{referral.ReferrerUserID: referrerPoints, referral.ReferredUserID: referredPoints} {
+		if points == 0 {
+			continue
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", id).
+			Update("total_points", gorm.Expr("total_points + ?", points)).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &referral, nil
+}
+
+// GetReferrals godoc
+// @Summary Get the current user's referral code and referrals
+// @Description Returns the user's referral code, generated on first request, and the users who signed up with it. A referral completes when the new user shares their first post and both accounts are past the minimum account age or verified; both users then receive bonus points.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} StandardResponse{data=ReferralsResponse}
+// @Router /profile/referrals [get]
+func (ac *AuthController) GetReferrals(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	code, err := ensureReferralCode(ac.DB, user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to create referral code"})
+		return
+	}
+
+	var rows []struct {
+		ID             uint       `gorm:"column:id"`
+		Status         string     `gorm:"column:status"`
+		CreatedAt      time.Time  `gorm:"column:created_at"`
+		CompletedAt    *time.Time `gorm:"column:completed_at"`
+		ReferrerPoints int        `gorm:"column:referrer_points"`
+		UserID         uint       `gorm:"column:user_id"`
+		Username       string     `gorm:"column:username"`
+		FirstName      string     `gorm:"column:first_name"`
+		LastName       string     `gorm:"column:last_name"`
+		Avatar         string     `gorm:"column:avatar"`
+	}
+	if err := ac.DB.Model(&models.Referral{}).
+		Select(`referrals.id, referrals.status, referrals.created_at, referrals.completed_at, referrals.referrer_points,
+			users.id AS user_id, users.username, users.first_name, users.last_name, users.avatar`).
+		Joins("JOIN users ON users.id = referrals.referred_user_id AND users.deleted_at IS NULL").
+		Where("referrals.referrer_user_id = ?", user.UserID).
+		Order("referrals.created_at DESC, referrals.id DESC").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching referrals"})
+		return
+	}
+
+	referrerPoints, referredPoints := config.GetReferralBonusPoints()
+	response := ReferralsResponse{
+		Code:           code,
+		ReferrerPoints: referrerPoints,
+		ReferredPoints: referredPoints,
+		Pending:        []ReferralItem{},
+		Completed:      []ReferralItem{},
+	}
+	for _, row := range rows {
+		item := ReferralItem{
+			ID:          row.ID,
+			Status:      row.Status,
+			CreatedAt:   row.CreatedAt,
+			CompletedAt: row.CompletedAt,
+			Points:      row.ReferrerPoints,
+			User:        PostUser{ID: row.UserID, Username: row.Username, FirstName: row.FirstName, LastName: row.LastName, Avatar: row.Avatar},
+		}
+		if row.Status == models.ReferralCompleted {
+			response.Completed = append(response.Completed, item)
+			response.PointsEarned += int64(row.ReferrerPoints)
+		} else {
+			response.Pending = append(response.Pending, item)
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Data: response})
+}
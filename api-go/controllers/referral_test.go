@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestEmailIdentity(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"Ali@Example.com", "ali@example.com"},
+		{" ali+invite@example.com ", "ali@example.com"},
+		{"ali+a+b@example.com", "ali@example.com"},
+		{"no-at-sign", "no-at-sign"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := emailIdentity(tt.email); got != tt.want {
+				t.Errorf("emailIdentity(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+// referralCodeOf returns userID's referral code through the referrals endpoint
+func referralCodeOf(t *testing.T, ac *AuthController, userID uint) ReferralsResponse {
+	t.Helper()
+	w := callHandler(ac.GetReferrals, http.MethodGet, "/profile/referrals", nil, userID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get referrals: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data ReferralsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data
+}
+
+// registerWithReferral registers username with code and returns the status and new user ID
+func registerWithReferral(t *testing.T, ac *AuthController, username, email, code string) (int, uint) {
+	t.Helper()
+	body := `{"username":"` + username + `","email":"` + email + `","password":"secret123","firstName":"A","lastName":"B","referralCode":"` + code + `"}`
+	w := callHandler(ac.Register, http.MethodPost, "/auth/register", strings.NewReader(body), 0)
+	var resp struct {
+		User struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp.User.ID
+}
+
+// createPostAt creates a post for userID at place through the CreatePost handler
+func createPostAt(t *testing.T, pc *PostController, userID uint, place models.Place) models.Post {
+	t.Helper()
+	body, err := json.Marshal(gin.H{
+		"mediaItems": []gin.H{{"mediaType": "photo", "mediaUrl": "https://cdn.example.com/referral.jpg"}},
+		"placeId":    place.ID,
+		"latitude":   place.Latitude,
+		"longitude":  place.Longitude,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := callHandler(pc.CreatePost, http.MethodPost, "/posts", bytes.NewReader(body), userID)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create post: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var post models.Post
+	if err := pc.DB.Where("user_id = ?", userID).Order("id DESC").First(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	return post
+}
+
+func TestReferralAwardsPointsOnFirstPost(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("REFERRAL_REFERRER_POINTS", "20")
+	t.Setenv("REFERRAL_REFERRED_POINTS", "10")
+	referrer := createTestUser(t, db, "referrer")
+	place := createTestPlace(t, db, "referralplace")
+	ac := NewAuthController(db, nil)
+	pc := NewPostController(db, nil)
+
+	code := referralCodeOf(t, ac, referrer.ID).Code
+	if len(code) != referralCodeLength {
+		t.Fatalf("code = %q, want %d characters", code, referralCodeLength)
+	}
+	if again := referralCodeOf(t, ac, referrer.ID).Code; again != code {
+		t.Errorf("second request code = %q, want the stored %q", again, code)
+	}
+
+	if status, _ := registerWithReferral(t, ac, "selfinvite", "referrer+alt@example.com", code); status != http.StatusBadRequest {
+		t.Errorf("self-referral: status = %d, want 400", status)
+	}
+	if status, _ := registerWithReferral(t, ac, "badcode", "badcode@example.com", "NOPE2345"); status != http.StatusBadRequest {
+		t.Errorf("unknown code: status = %d, want 400", status)
+	}
+
+	// Kodlar büyük/küçük harf duyarsızdır
+	status, referredID := registerWithReferral(t, ac, "newcomer", "newcomer@example.com", strings.ToLower(code))
+	if status != http.StatusCreated {
+		t.Fatalf("register with code: status = %d, want 201", status)
+	}
+	if resp := referralCodeOf(t, ac, referrer.ID); len(resp.Pending) != 1 || resp.Pending[0].User.ID != referredID {
+		t.Fatalf("pending = %+v, want the new user", resp.Pending)
+	}
+
+	// Doğrulanmış hesaplar hesap yaşı koşulundan muaftır
+	if err := db.Model(&models.User{}).Where("id = ?", referredID).Update("is_verified", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	post := createPostAt(t, pc, referredID, place)
+
+	var referred, updatedReferrer models.User
+	db.First(&referred, referredID)
+	db.First(&updatedReferrer, referrer.ID)
+	if want := post.EarnedPoints + 10; referred.TotalPoints != want {
+		t.Errorf("referred points = %d, want %d", referred.TotalPoints, want)
+	}
+	if updatedReferrer.TotalPoints != 20 {
+		t.Errorf("referrer points = %d, want 20", updatedReferrer.TotalPoints)
+	}
+
+	resp := referralCodeOf(t, ac, referrer.ID)
+	if len(resp.Pending) != 0 || len(resp.Completed) != 1 || resp.PointsEarned != 20 {
+		t.Fatalf("after first post: pending = %d, completed = %d, earned = %d; want 0, 1, 20", len(resp.Pending), len(resp.Completed), resp.PointsEarned)
+	}
+
+	// Bonus yalnızca bir kez verilir
+	createPostAt(t, pc, referredID, place)
+	db.First(&updatedReferrer, referrer.ID)
+	if updatedReferrer.TotalPoints != 20 {
+		t.Errorf("referrer points after second post = %d, want 20", updatedReferrer.TotalPoints)
+	}
+}
+
+func TestReferralWaitsForAccountAge(t *testing.T) {
+	db := openTestDB(t)
+	t.Setenv("MIN_ACCOUNT_AGE", "24h")
+	t.Setenv("MIN_ACCOUNT_AGE_ACTIONS", "referral")
+	referrer := createTestUser(t, db, "agereferrer")
+	place := createTestPlace(t, db, "ageplace")
+	ac := NewAuthController(db, nil)
+	pc := NewPostController(db, nil)
+
+	code := referralCodeOf(t, ac, referrer.ID).Code
+	status, referredID := registerWithReferral(t, ac, "agenewcomer", "agenewcomer@example.com", code)
+	if status != http.StatusCreated {
+		t.Fatalf("register: status = %d, want 201", status)
+	}
+
+	// Yeni ve doğrulanmamış hesabın ilk gönderisi daveti tamamlamaz
+	createPostAt(t, pc, referredID, place)
+	if resp := referralCodeOf(t, ac, referrer.ID); len(resp.Pending) != 1 || len(resp.Completed) != 0 {
+		t.Fatalf("young account: pending = %d, completed = %d; want 1, 0", len(resp.Pending), len(resp.Completed))
+	}
+
+	if err := db.Model(&models.User{}).Where("id = ?", referredID).
+		Update("created_at", time.Now().Add(-48*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	createPostAt(t, pc, referredID, place)
+	if resp := referralCodeOf(t, ac, referrer.ID); len(resp.Completed) != 1 {
+		t.Errorf("old enough account: completed = %d, want 1", len(resp.Completed))
+	}
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// errUsernameReserved is returned for usernames on a reserved list
+var errUsernameReserved = errors.New("this username is reserved and cannot be used")
+
+// usernameHomoglyphs harf yerine sık kullanılan rakamları harfe çevirir; l de
+// 1 ile karıştığından i'ye indirgenir
+var usernameHomoglyphs = strings.NewReplacer(
+	"0", "o", "1", "i", "l", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "_", "",
+)
+
+// canonicalUsername lowercases a username and folds the common look-alike
+// substitutions, so "Adm1n" and "a_dmin" both become "admin".
+func canonicalUsername(username string) string {
+	return usernameHomoglyphs.Replace(strings.ToLower(strings.TrimSpace(username)))
+}
+
+// isConfigReservedUsername reports whether username matches a default or
+// RESERVED_USERNAMES entry after canonicalization
+func isConfigReservedUsername(username string) bool {
+	canonical := canonicalUsername(username)
+	for _, word := range config.GetReservedUsernames() {
+		if canonicalUsername(word) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// isUsernameBlocked reports whether username matches a reserved word added
+// by an admin. validateUsernamePattern covers the configured words.
+func isUsernameBlocked(db *gorm.DB, username string) (bool, error) {
+	var count int64
+	err := db.Model(&models.ReservedUsername{}).Where("canonical = ?", canonicalUsername(username)).Count(&count).Error
+	return count > 0, err
+}
+
+// reservedWordPattern eklenebilecek kelimeler; kullanıcı adı karakterleriyle aynı
+var reservedWordPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// Ayrılmış kelimelerin kaynağı
+const (
+	ReservedUsernameDefault = "default"
+	ReservedUsernameEnv     = "env"
+	ReservedUsernameAdmin   = "admin"
+)
+
+// ReservedUsernameItem is one reserved word and where it is configured
+type ReservedUsernameItem struct {
+	Word    string     `json:"word"`
+	Source  string     `json:"source"` // default, env veya admin
+	AddedAt *time.Time `json:"addedAt,omitempty"`
+}
+
+// ReservedUsernameRequest is the body of an admin reserved word addition
+type ReservedUsernameRequest struct {
+	Word string `json:"word" binding:"required"`
+}
+
+// GetReservedUsernames godoc
+// @Summary List reserved usernames (admin)
+// @Description Returns the built-in, RESERVED_USERNAMES and admin-added reserved words. Usernames matching one case-insensitively, ignoring underscores and common digit-for-letter substitutions, are rejected.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} StandardResponse{data=[]ReservedUsernameItem}
+// @Router /admin/reserved-usernames [get]
+func (ac *AdminController) GetReservedUsernames(c *gin.Context) {
+	items := []ReservedUsernameItem{}
+	for _, word := range config.DefaultReservedUsernames {
+		items = append(items, ReservedUsernameItem{Word: word, Source: ReservedUsernameDefault})
+	}
+	for _, word := range config.GetExtraReservedUsernames() {
+		items = append(items, ReservedUsernameItem{Word: word, Source: ReservedUsernameEnv})
+	}
+
+	var added []models.ReservedUsername
+	if err := ac.DB.Order("word").Find(&added).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching reserved usernames"})
+		return
+	}
+	for i := range added {
+		items = append(items, ReservedUsernameItem{Word: added[i].Word, Source: ReservedUsernameAdmin, AddedAt: &added[i].CreatedAt})
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Data: items})
+}
+
+// AddReservedUsername godoc
+// @Summary Reserve a username (admin)
+// @Description Blocks the word as a username from now on. Existing accounts keep their username.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ReservedUsernameRequest true "Word to reserve"
+// @Success 201 {object} StandardResponse{data=ReservedUsernameItem}
+// @Router /admin/reserved-usernames [post]
+func (ac *AdminController) AddReservedUsername(c *gin.Context) {
+	adminID := utils.GetUser(c).UserID
+
+	var req ReservedUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+	word := strings.ToLower(strings.TrimSpace(req.Word))
+	if !reservedWordPattern.MatchString(word) {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Word can only contain letters, numbers and underscores"})
+		return
+	}
+
+	if isConfigReservedUsername(word) {
+		c.JSON(http.StatusConflict, StandardResponse{Success: false, Message: "Word is already reserved"})
+		return
+	}
+	if blocked, err := isUsernameBlocked(ac.DB, word); err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to reserve username"})
+		return
+	} else if blocked {
+		c.JSON(http.StatusConflict, StandardResponse{Success: false, Message: "Word is already reserved"})
+		return
+	}
+
+	reserved := models.ReservedUsername{Word: word, Canonical: canonicalUsername(word), AddedByUserID: adminID}
+	tx := ac.DB.Begin()
+	if err := tx.Create(&reserved).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to reserve username"})
+		return
+	}
+	if err := recordAdminAction(tx, adminID, "reserved_username_add", "reserved_username", reserved.ID, gin.H{"word": word}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to reserve username"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to reserve username"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, StandardResponse{
+		Success: true,
+		Data:    ReservedUsernameItem{Word: word, Source: ReservedUsernameAdmin, AddedAt: &reserved.CreatedAt},
+		Message: "Username reserved",
+	})
+}
+
+// RemoveReservedUsername godoc
+// @Summary Release a reserved username (admin)
+// @Description Removes an admin-added reserved word. Built-in and RESERVED_USERNAMES words can only be changed in the configuration.
+// @Tags admin
+// @Produce json
+// @Param word path string true "Reserved word"
+// @Success 200 {object} StandardResponse
+// @Router /admin/reserved-usernames/{word} [delete]
+func (ac *AdminController) RemoveReservedUsername(c *gin.Context) {
+	adminID := utils.GetUser(c).UserID
+	word := strings.ToLower(strings.TrimSpace(c.Param("word")))
+
+	var reserved models.ReservedUsername
+	if err := ac.DB.Where("word = ?", word).First(&reserved).Error; err != nil {
+		if isConfigReservedUsername(word) {
+			c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: "Configured reserved words can only be removed from the configuration"})
+			return
+		}
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "Reserved username not found"})
+		return
+	}
+
+	tx := ac.DB.Begin()
+	if err := tx.Delete(&reserved).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to release username"})
+		return
+	}
+	if err := recordAdminAction(tx, adminID, "reserved_username_remove", "reserved_username", reserved.ID, gin.H{"word": reserved.Word}); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to release username"})
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Failed to release username"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{Success: true, Message: "Username released"})
+}
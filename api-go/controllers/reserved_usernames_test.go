@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestValidateUsernamePatternReservedWords(t *testing.T) {
+	t.Setenv("RESERVED_USERNAMES", " AcmeBrand ,scamword")
+
+	tests := []struct {
+		username string
+		reserved bool
+	}{
+		{"acmebrand", true},
+		{"AcmeBrand", true},
+		{"acm3brand", true},
+		{"acme_brand", true},
+		{"Adm1n", true},
+		{"r00t", true},
+		{"nu11", true},
+		{"scamword", true},
+		{"acmebrands", false},
+		{"wanderer", false},
+		{"administrator", false},
+	}
+	for _, tt := range tests {
+		err := validateUsernamePattern(tt.username)
+		if reserved := errors.Is(err, errUsernameReserved); reserved != tt.reserved {
+			t.Errorf("validateUsernamePattern(%q) = %v, want reserved %v", tt.username, err, tt.reserved)
+		}
+	}
+}
+
+func TestAdminReservedUsernames(t *testing.T) {
+	t.Setenv("RESERVED_USERNAMES", "acmebrand")
+	db := openTestDB(t)
+	admin := createTestUser(t, db, "reservedadmin")
+	ac := NewAdminController(db, nil)
+	auth := NewAuthController(db, nil)
+
+	register := func(username string) int {
+		t.Helper()
+		body := `{"username":"` + username + `","email":"` + strings.ToLower(username) + `@example.com","password":"secret123","firstName":"A","lastName":"B"}`
+		return callHandler(auth.Register, http.MethodPost, "/auth/register", strings.NewReader(body), 0).Code
+	}
+	add := func(word string) int {
+		t.Helper()
+		return callHandler(ac.AddReservedUsername, http.MethodPost, "/admin/reserved-usernames", strings.NewReader(`{"word":"`+word+`"}`), admin.ID).Code
+	}
+	remove := func(word string) int {
+		t.Helper()
+		param := gin.Param{Key: "word", Value: word}
+		return callHandler(ac.RemoveReservedUsername, http.MethodDelete, "/admin/reserved-usernames/"+word, nil, admin.ID, param).Code
+	}
+
+	if code := register("AcmeBrand"); code != http.StatusBadRequest {
+		t.Errorf("register configured word: status %d, want 400", code)
+	}
+
+	if code := add("SpamCorp"); code != http.StatusCreated {
+		t.Fatalf("add: status %d, want 201", code)
+	}
+	if code := add("spamc0rp"); code != http.StatusConflict {
+		t.Errorf("add look-alike of a reserved word: status %d, want 409", code)
+	}
+	if code := add("r00t"); code != http.StatusConflict {
+		t.Errorf("add look-alike of a default word: status %d, want 409", code)
+	}
+	if code := register("Spam_C0rp"); code != http.StatusBadRequest {
+		t.Errorf("register admin-reserved look-alike: status %d, want 400", code)
+	}
+	check := callHandler(auth.RegisterUsernameCheck, http.MethodPost, "/register/check-username", strings.NewReader(`{"username":"spamcorp"}`), 0)
+	if check.Code != http.StatusBadRequest {
+		t.Errorf("username check: status %d, want 400", check.Code)
+	}
+
+	w := callHandler(ac.GetReservedUsernames, http.MethodGet, "/admin/reserved-usernames", nil, admin.ID)
+	var resp struct {
+		Data []ReservedUsernameItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]string{}
+	for _, item := range resp.Data {
+		sources[item.Word] = item.Source
+	}
+	if sources["admin"] != ReservedUsernameDefault || sources["acmebrand"] != ReservedUsernameEnv || sources["spamcorp"] != ReservedUsernameAdmin {
+		t.Errorf("sources = %v, want admin from defaults, acmebrand from env and spamcorp from admin", sources)
+	}
+
+	if code := remove("acmebrand"); code != http.StatusBadRequest {
+		t.Errorf("remove configured word: status %d, want 400", code)
+	}
+	if code := remove("spamcorp"); code != http.StatusOK {
+		t.Fatalf("remove: status %d, want 200", code)
+	}
+	if code := remove("spamcorp"); code != http.StatusNotFound {
+		t.Errorf("remove again: status %d, want 404", code)
+	}
+	if code := register("Spam_C0rp"); code != http.StatusCreated {
+		t.Errorf("register after release: status %d, want 201", code)
+	}
+
+	var audits int64
+	db.Model(&models.AdminAuditLog{}).Where("action IN ?", []string{"reserved_username_add", "reserved_username_remove"}).Count(&audits)
+	if audits != 2 {
+		t.Errorf("audit entries = %d, want 2", audits)
+	}
+}
@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+// Bir çalıştırmada varlık türü başına kalıcı silinecek en fazla kayıt; kalanlar sonraki çalıştırmaya kalır
+const purgeBatchSize = 500
+
+// PurgeResult counts the rows hard-deleted by one purge run.
+type PurgeResult struct {
+	Users      int64 `json:"users"`
+	Posts      int64 `json:"posts"`
+	Places     int64 `json:"places"`
+	MediaFiles int   `json:"mediaFiles"`
+}
+
+// purgeDeletion is one dependent-row delete in the purge cascade; every
+// placeholder in query is bound to ids, and it is skipped when ids is empty.
+type purgeDeletion struct {
+	model interface{}
+	query string
+	ids   []uint
+}
+
+// purgeSoftDeleted hard-deletes users, posts and places soft-deleted longer than
+// their retention window, together with the rows that reference them, then
+// removes their media from storage. Posts of purged users and places go too;
+// owners of live posts at a purged place lose those posts' count and points
+// as if they had deleted them. Users referenced by the admin audit log are kept. adminID > 0 records the
+// run in the audit log.
+func purgeSoftDeleted(db *gorm.DB, uploads *UploadController, now time.Time, adminID uint) (PurgeResult, error) {
+	var result PurgeResult
+
+	cutoff := func(entity string) time.Time {
+		return now.AddDate(0, 0, -config.GetRetentionDays(entity))
+	}
+
+	var userIDs, placeIDs, postIDs []uint
+	if err := db.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff("users")).
+		Where("NOT EXISTS (SELECT 1 FROM admin_audit_logs WHERE admin_audit_logs.admin_user_id = users.id)").
+		Order("deleted_at").Limit(purgeBatchSize).
+		Pluck("id", &userIDs).Error; err != nil {
+		return result, err
+	}
+	if err := db.Unscoped().Model(&models.Place{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff("places")).
+		Order("deleted_at").Limit(purgeBatchSize).
+		Pluck("id", &placeIDs).Error; err != nil {
+		return result, err
+	}
+	if err := db.Unscoped().Model(&models.Post{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff("posts")).
+		Order("deleted_at").Limit(purgeBatchSize).
+		Pluck("id", &postIDs).Error; err != nil {
+		return result, err
+	}
+	if len(userIDs) > 0 || len(placeIDs) > 0 {
+		var ownedPostIDs []uint
+		if err := db.Unscoped().Model(&models.Post{}).
+			Where("user_id IN ? OR place_id IN ?", nonEmptyIDs(userIDs), nonEmptyIDs(placeIDs)).
+			Pluck("id", &ownedPostIDs).Error; err != nil {
+			return result, err
+		}
+		postIDs = append(postIDs, ownedPostIDs...)
+	}
+
+	if len(userIDs) == 0 && len(placeIDs) == 0 && len(postIDs) == 0 {
+		return result, nil
+	}
+
+	// Depolamadan silinecek dosyalar; kayıtlar gittikten sonra URL'lere ulaşılamaz
+	var mediaURLs []string
+	var media []models.PostMedia
+	if err := db.Unscoped().Select("media_url, thumbnail_url").
+		Where("post_id IN ?", nonEmptyIDs(postIDs)).Find(&media).Error; err != nil {
+		return result, err
+	}
+	for _, item := range media {
+		mediaURLs = append(mediaURLs, item.MediaURL, item.ThumbnailURL)
+	}
+	var avatars []string
+	if err := db.Unscoped().Model(&models.User{}).Where("id IN ?", nonEmptyIDs(userIDs)).
+		Pluck("avatar", &avatars).Error; err != nil {
+		return result, err
+	}
+	mediaURLs = append(mediaURLs, avatars...)
+
+	users, places, posts := nonEmptyIDs(userIDs), nonEmptyIDs(placeIDs), nonEmptyIDs(postIDs)
+
+	// Sıra önemli: önce referans veren kayıtlar, en son kullanıcı/mekan/gönderi
+	deletions := []purgeDeletion{
+		{&models.PostMedia{}, "post_id IN ?", postIDs},
+		{&models.Like{}, "post_id IN ?", postIDs},
+		{&models.Like{}, "user_id IN ?", userIDs},
+		{&models.Notification{}, "post_id IN ?", postIDs},
+		{&models.Notification{}, "user_id IN ? OR actor_user_id IN ?", userIDs},
+		{&models.ActivityLog{}, "post_id IN ?", postIDs},
+		{&models.ActivityLog{}, "place_id IN ?", placeIDs},
+		{&models.FavoritePlace{}, "place_id IN ?", placeIDs},
+		{&models.PlaceLocationSuggestion{}, "place_id IN ?", placeIDs},
+		{&models.ActivityLog{}, "user_id IN ? OR target_user_id IN ?", userIDs},
+		{&models.Comment{}, "post_id IN ?", postIDs},
+		{&models.Comment{}, "user_id IN ?", userIDs},
+		{&models.RefreshToken{}, "user_id IN ?", userIDs},
+		{&models.Follow{}, "follower_user_id IN ? OR following_user_id IN ?", userIDs},
+		{&models.Block{}, "blocker_user_id IN ? OR blocked_user_id IN ?", userIDs},
+		{&models.Mute{}, "muter_user_id IN ? OR muted_user_id IN ?", userIDs},
+		{&models.Report{}, "reporter_user_id IN ? OR reported_user_id IN ?", userIDs},
+		{&models.NotificationPreference{}, "user_id IN ?", userIDs},
+		{&models.DeviceToken{}, "user_id IN ?", userIDs},
+		{&models.MediaUpload{}, "user_id IN ?", userIDs},
+		{&models.FeedPreference{}, "user_id IN ?", userIDs},
+		{&models.FavoritePlace{}, "user_id IN ?", userIDs},
+		{&models.SearchHistory{}, "user_id IN ?", userIDs},
+		{&models.UsernameChange{}, "user_id IN ?", userIDs},
+		{&models.PostDraft{}, "user_id IN ?", userIDs},
+		{&models.LoginAttempt{}, "user_id IN ?", userIDs},
+		{&models.DataExport{}, "user_id IN ?", userIDs},
+		{&models.IdempotencyKey{}, "post_id IN ?", postIDs},
+		{&models.IdempotencyKey{}, "user_id IN ?", userIDs},
+		{&models.PlaceLocationSuggestion{}, "user_id IN ?", userIDs},
+		{&models.Referral{}, "referrer_user_id IN ? OR referred_user_id IN ?", userIDs},
+	}
+
+	tx := db.Begin()
+
+	// Silinecek yorumlara verilen yanıtlar üst yorumsuz kalır
+	if err := tx.Model(&models.Comment{}).Unscoped().
+		Where("parent_comment_id IN (SELECT comment_id FROM comments WHERE post_id IN ? OR user_id IN ?)", posts, users).
+		Update("parent_comment_id", nil).Error; err != nil {
+		tx.Rollback()
+		return result, err
+	}
+	if err := tx.Model(&models.PostDraft{}).Where("place_id IN ?", places).Update("place_id", nil).Error; err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	// Mekanla giden canlı gönderiler DeletePost'taki gibi sahiplerinin sayacından ve puanından düşülür
+	if err := tx.Exec(`
+		UPDATE users SET
+			posts_count = GREATEST(users.posts_count - live.posts, 0),
+			total_points = users.total_points - live.points
+		FROM (
+			SELECT user_id, COUNT(*) as posts, COALESCE(SUM(earned_points), 0) as points
+			FROM posts
+			WHERE place_id IN ? AND deleted_at IS NULL
+			GROUP BY user_id
+		) live
+		WHERE users.id = live.user_id`, places).Error; err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	for _, deletion := range deletions {
+		if len(deletion.ids) == 0 {
+			continue
+		}
+		args := make([]interface{}, strings.Count(deletion.query, "?"))
+		for i := range args {
+			args[i] = deletion.ids
+		}
+		if err := tx.Unscoped().Where(deletion.query, args...).Delete(deletion.model).Error; err != nil {
+			tx.Rollback()
+			return result, err
+		}
+	}
+
+	deleted := tx.Unscoped().Where("id IN ?", posts).Delete(&models.Post{})
+	if deleted.Error != nil {
+		tx.Rollback()
+		return result, deleted.Error
+	}
+	result.Posts = deleted.RowsAffected
+
+	deleted = tx.Unscoped().Where("id IN ?", places).Delete(&models.Place{})
+	if deleted.Error != nil {
+		tx.Rollback()
+		return result, deleted.Error
+	}
+	result.Places = deleted.RowsAffected
+
+	deleted = tx.Unscoped().Where("id IN ?", users).Delete(&models.User{})
+	if deleted.Error != nil {
+		tx.Rollback()
+		return result, deleted.Error
+	}
+	result.Users = deleted.RowsAffected
+
+	if adminID > 0 {
+		if err := recordAdminAction(tx, adminID, "purge", "system", 0, result); err != nil {
+			tx.Rollback()
+			return result, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return result, err
+	}
+
+	// Dosya silme hataları temizliği geri almaz; yetim dosyalar yalnızca loglanır
+	if uploads != nil {
+		for _, mediaURL := range mediaURLs {
+			key, ok := uploads.mediaKeyFromURL(mediaURL)
+			if !ok {
+				continue
+			}
+			if err := uploads.deleteFile(key); err != nil {
+				log.Printf("Purge: failed to delete %s from storage: %v", key, err)
+				continue
+			}
+			result.MediaFiles++
+		}
+	}
+
+	return result, nil
+}
+
+// nonEmptyIDs keeps "IN ?" valid for empty lists; 0 matches no primary key
+func nonEmptyIDs(ids []uint) []uint {
+	if len(ids) == 0 {
+		return []uint{0}
+	}
+	return ids
+}
+
+// StartPurgeJob runs the soft-delete purge every interval in the background
+func (ac *AdminController) StartPurgeJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			result, err := purgeSoftDeleted(ac.DB, ac.UploadController, now, 0)
+			if err != nil {
+				log.Printf("Purge job failed: %v", err)
+				continue
+			}
+			log.Printf("Purge job: users=%d posts=%d places=%d mediaFiles=%d",
+				result.Users, result.Posts, result.Places, result.MediaFiles)
+		}
+	}()
+}
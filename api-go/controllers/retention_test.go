@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+// softDelete backdates a row's deleted_at by days
+func softDelete(t *testing.T, db *gorm.DB, model interface{}, days int) {
+	t.Helper()
+	if err := db.Unscoped().Model(model).Update("deleted_at", time.Now().AddDate(0, 0, -days)).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rowExists(t *testing.T, db *gorm.DB, model interface{}, query string, args ...interface{}) bool {
+	t.Helper()
+	var count int64
+	if err := db.Unscoped().Model(model).Where(query, args...).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func TestPurgeSoftDeletedRespectsRetention(t *testing.T) {
+	t.Setenv("RETENTION_DAYS_USERS", "")
+	t.Setenv("RETENTION_DAYS_POSTS", "10")
+	t.Setenv("RETENTION_DAYS_PLACES", "")
+	db := openTestDB(t)
+
+	live := createTestUser(t, db, "purgelive")
+	oldUser := createTestUser(t, db, "purgeolduser")
+	recentUser := createTestUser(t, db, "purgerecentuser")
+	auditor := createTestUser(t, db, "purgeauditor")
+	place := createTestPlace(t, db, "purgeplace")
+	oldPlace := createTestPlace(t, db, "purgeoldplace")
+
+	oldPost := createTestPost(t, db, live, place, "old", true)
+	recentPost := createTestPost(t, db, live, place, "recent", true)
+	livePost := createTestPost(t, db, live, place, "live", true)
+	oldUsersPost := createTestPost(t, db, oldUser, place, "owned", true)
+	oldPlacesPost := createTestPost(t, db, live, oldPlace, "at old place", true)
+	like := models.Like{PostID: livePost.ID, UserID: oldUser.ID}
+	if err := db.Create(&like).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := recordAdminAction(db, auditor.ID, "place_review_reject", "place", oldPlace.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Varsayılan saklama: kullanıcı 30, mekan 90 gün; gönderi ortamdan 10 gün
+	softDelete(t, db, &oldUser, 31)
+	softDelete(t, db, &recentUser, 29)
+	softDelete(t, db, &auditor, 365)
+	softDelete(t, db, &oldPlace, 91)
+	softDelete(t, db, &oldPost, 11)
+	softDelete(t, db, &recentPost, 9)
+
+	result, err := purgeSoftDeleted(db, nil, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Users != 1 || result.Places != 1 || result.Posts != 3 {
+		t.Errorf("purged users = %d, places = %d, posts = %d; want 1, 1, 3", result.Users, result.Places, result.Posts)
+	}
+
+	checks := []struct {
+		name  string
+		model interface{}
+		id    uint
+		want  bool
+	}{
+		{"live user", &models.User{}, live.ID, true},
+		{"old user", &models.User{}, oldUser.ID, false},
+		{"recently deleted user", &models.User{}, recentUser.ID, true},
+		{"user referenced by the audit log", &models.User{}, auditor.ID, true},
+		{"live place", &models.Place{}, place.ID, true},
+		{"old place", &models.Place{}, oldPlace.ID, false},
+		{"old post", &models.Post{}, oldPost.ID, false},
+		{"recently deleted post", &models.Post{}, recentPost.ID, true},
+		{"live post", &models.Post{}, livePost.ID, true},
+		{"old user's post", &models.Post{}, oldUsersPost.ID, false},
+		{"old place's post", &models.Post{}, oldPlacesPost.ID, false},
+	}
+	for _, c := range checks {
+		if got := rowExists(t, db, c.model, "id = ?", c.id); got != c.want {
+			t.Errorf("%s exists = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if rowExists(t, db, &models.Like{}, "like_id = ?", like.LikeID) {
+		t.Error("old user's like survived the purge")
+	}
+
+	// İkinci çalıştırma silinecek bir şey bulmaz
+	if result, err := purgeSoftDeleted(db, nil, time.Now(), 0); err != nil || result != (PurgeResult{}) {
+		t.Errorf("second purge = %+v, %v; want nothing", result, err)
+	}
+}
+
+func TestPurgeSoftDeletedPlaceTakesBackPostCounters(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "purgeplaceowner")
+	place := createTestPlace(t, db, "purgecounterplace")
+	oldPlace := createTestPlace(t, db, "purgecounteroldplace")
+
+	kept := createTestPost(t, db, owner, place, "kept", true)
+	purged := createTestPost(t, db, owner, oldPlace, "purged with the place", true)
+	deletedBefore := createTestPost(t, db, owner, oldPlace, "deleted by the owner", true)
+	for post, points := range map[*models.Post]int64{&kept: 20, &purged: 30, &deletedBefore: 5} {
+		if err := db.Model(post).Update("earned_points", points).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Sahibin kendi sildiği gönderi sayaçtan ve puandan zaten düşülmüştür
+	if err := db.Model(&owner).Updates(map[string]interface{}{"posts_count": 2, "total_points": 50}).Error; err != nil {
+		t.Fatal(err)
+	}
+	softDelete(t, db, &deletedBefore, 0)
+	softDelete(t, db, &oldPlace, 91)
+
+	result, err := purgeSoftDeleted(db, nil, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Places != 1 || result.Posts != 2 {
+		t.Errorf("purged places = %d, posts = %d; want 1, 2", result.Places, result.Posts)
+	}
+
+	var got models.User
+	if err := db.Select("posts_count, total_points").First(&got, owner.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.PostsCount != 1 || got.TotalPoints != 20 {
+		t.Errorf("owner posts_count = %d, total_points = %d; want 1, 20", got.PostsCount, got.TotalPoints)
+	}
+	if !rowExists(t, db, &models.Post{}, "id = ?", kept.ID) {
+		t.Error("post at a live place was purged")
+	}
+}
+
+func TestPurgeSoftDeletedRemovesReports(t *testing.T) {
+	db := openTestDB(t)
+	reporter := createTestUser(t, db, "purgereporter")
+	reported := createTestUser(t, db, "purgereported")
+	report := models.Report{ReporterUserID: reporter.ID, ReportedUserID: reported.ID, Reason: "spam", Status: "pending"}
+	if err := db.Create(&report).Error; err != nil {
+		t.Fatal(err)
+	}
+	softDelete(t, db, &reported, 365)
+
+	result, err := purgeSoftDeleted(db, nil, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Users != 1 {
+		t.Errorf("purged users = %d, want 1", result.Users)
+	}
+	if rowExists(t, db, &models.Report{}, "id = ?", report.ID) {
+		t.Error("report for the purged user survived")
+	}
+}
+
+func TestMediaKeyFromURL(t *testing.T) {
+	uc := newTestUploadController(t)
+	tests := []struct {
+		url    string
+		key    string
+		wantOK bool
+	}{
+		{"https://cdn.example.com/posts/1/a.jpg", "posts/1/a.jpg", true},
+		{"https://cdn.example.com.evil.test/a.jpg", "", false},
+		{"https://other.example.com/a.jpg", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		key, ok := uc.mediaKeyFromURL(tt.url)
+		if key != tt.key || ok != tt.wantOK {
+			t.Errorf("mediaKeyFromURL(%q) = %q, %v; want %q, %v", tt.url, key, ok, tt.key, tt.wantOK)
+		}
+	}
+}
+
+func TestPurgeSoftDeletedRemovesUserRows(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "purgeowner")
+	place := createTestPlace(t, db, "purgeownerplace")
+	post := createTestPost(t, db, user, place, "owned", true)
+	key := models.IdempotencyKey{UserID: user.ID, Key: "purged", RequestHash: "hash", PostID: &post.ID}
+	if err := db.Create(&key).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Silinen mekana başka kullanıcının önerisi; canlı mekana silinen kullanıcının önerisi
+	other := createTestUser(t, db, "purgeother")
+	deletedPlace := createTestPlace(t, db, "purgedplace")
+	byUser := models.PlaceLocationSuggestion{PlaceID: place.ID, UserID: user.ID, Latitude: 41, Longitude: 29}
+	onPlace := models.PlaceLocationSuggestion{PlaceID: deletedPlace.ID, UserID: other.ID, Latitude: 41, Longitude: 29}
+	kept := models.PlaceLocationSuggestion{PlaceID: place.ID, UserID: other.ID, Latitude: 41, Longitude: 29}
+	for _, suggestion := range []*models.PlaceLocationSuggestion{&byUser, &onPlace, &kept} {
+		if err := db.Create(suggestion).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Silinen kullanıcı hem davet eden hem davet edilen olabilir
+	inviter := createTestUser(t, db, "purgeinviter")
+	invited := models.Referral{ReferrerUserID: user.ID, ReferredUserID: other.ID, Status: models.ReferralPending}
+	referred := models.Referral{ReferrerUserID: inviter.ID, ReferredUserID: user.ID, Status: models.ReferralPending}
+	for _, referral := range []*models.Referral{&invited, &referred} {
+		if err := db.Create(referral).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	softDelete(t, db, &user, 365)
+	softDelete(t, db, &deletedPlace, 365)
+
+	result, err := purgeSoftDeleted(db, nil, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Users != 1 || result.Places != 1 {
+		t.Errorf("purged users = %d, places = %d; want 1 and 1", result.Users, result.Places)
+	}
+	if rowExists(t, db, &models.IdempotencyKey{}, "id = ?", key.ID) {
+		t.Error("idempotency key of the purged user survived")
+	}
+	if rowExists(t, db, &models.PlaceLocationSuggestion{}, "id = ?", byUser.ID) {
+		t.Error("location suggestion of the purged user survived")
+	}
+	if rowExists(t, db, &models.PlaceLocationSuggestion{}, "id = ?", onPlace.ID) {
+		t.Error("location suggestion for the purged place survived")
+	}
+	if !rowExists(t, db, &models.PlaceLocationSuggestion{}, "id = ?", kept.ID) {
+		t.Error("unrelated location suggestion was purged")
+	}
+	for _, referral := range []models.Referral{invited, referred} {
+		if rowExists(t, db, &models.Referral{}, "id = ?", referral.ID) {
+			t.Errorf("referral %d -> %d of the purged user survived", referral.ReferrerUserID, referral.ReferredUserID)
+		}
+	}
+}
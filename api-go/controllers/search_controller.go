@@ -0,0 +1,444 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+type SearchController struct {
+	DB *gorm.DB
+}
+
+type SearchQuery struct {
+	Q        string `form:"q" binding:"required"`
+	Type     string `form:"type,default=all" binding:"omitempty,oneof=all users places hashtags"`
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"pageSize,default=10" binding:"min=1,max=50"`
+	// MinRating yalnızca mekan sonuçlarına uygulanır
+	MinRating *float64 `form:"minRating" binding:"omitempty,min=0,max=5"`
+}
+
+// maxSearchHistoryEntries caps how many recent searches are kept per user
+const maxSearchHistoryEntries = 20
+
+type RecordSearchRequest struct {
+	Query      string `json:"query" binding:"required,max=100"`
+	ResultType string `json:"resultType" binding:"omitempty,oneof=query user place hashtag"`
+	ResultID   *uint  `json:"resultId"`
+}
+
+type SearchUserResult struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	FirstName      string `json:"firstName"`
+	LastName       string `json:"lastName"`
+	Avatar         string `json:"avatar"`
+	IsVerified     bool   `json:"isVerified"`
+	TotalPoints    int64  `json:"totalPoints"`
+	FollowersCount int64  `json:"followersCount"`
+	PostsCount     int64  `json:"postsCount"`
+}
+
+type SearchPlaceResult struct {
+	ID         uint     `json:"id"`
+	Name       string   `json:"name"`
+	Address    string   `json:"address"`
+	Image      string   `json:"image"`
+	PointValue int      `json:"pointValue"`
+	Rating     *float64 `json:"rating"`
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	IsVerified bool     `json:"isVerified"`
+}
+
+type SearchHashtagResult struct {
+	Tag        string `json:"tag"`
+	PostsCount int64  `json:"postsCount"`
+}
+
+func NewSearchController(db *gorm.DB) *SearchController {
+	return &SearchController{DB: db}
+}
+
+// Search godoc
+// @Summary Unified search over users, places and hashtags
+// @Description Searches the given type (or all types) and returns typed results with per-type pagination
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "all, users, places or hashtags (default: all)"
+// @Param page query integer false "Page number per type (default: 1)"
+// @Param pageSize query integer false "Items per type (default: 10, max: 50)"
+// @Param minRating query number false "Hide places rated below this (0-5); unrated places follow NULL_RATING_POLICY"
+// @Success 200 {object} StandardResponse
+// @Router /search [get]
+func (sc *SearchController) Search(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var query SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	term := strings.TrimSpace(query.Q)
+	if term == "" {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "Search query is required",
+		})
+		return
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	data := gin.H{}
+	pagination := gin.H{}
+	meta := gin.H{
+		"query": term,
+		"type":  query.Type,
+	}
+
+	if query.Type == "all" || query.Type == "users" {
+		users, total, err := sc.searchUsers(term, user.UserID, offset, query.PageSize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error searching users"})
+			return
+		}
+		data["users"] = users
+		pagination["users"] = searchPagination(query.Page, query.PageSize, total)
+	}
+
+	if query.Type == "all" || query.Type == "places" {
+		places, total, nullRating, err := sc.searchPlaces(term, query.MinRating, offset, query.PageSize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error searching places"})
+			return
+		}
+		if query.MinRating != nil {
+			meta["minRating"] = *query.MinRating
+			meta["nullRating"] = nullRating
+		}
+		data["places"] = places
+		pagination["places"] = searchPagination(query.Page, query.PageSize, total)
+	}
+
+	if query.Type == "all" || query.Type == "hashtags" {
+		hashtags, total, err := sc.searchHashtags(term, user.UserID, offset, query.PageSize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error searching hashtags"})
+			return
+		}
+		data["hashtags"] = hashtags
+		pagination["hashtags"] = searchPagination(query.Page, query.PageSize, total)
+	}
+
+	meta["pagination"] = pagination
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
+// RecordSearchHistory godoc
+// @Summary Record a recent search
+// @Description Stores a search query or a tapped result; repeating an entry bumps its timestamp instead of duplicating it
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body RecordSearchRequest true "Search entry"
+// @Success 200 {object} StandardResponse
+// @Router /search/history [post]
+func (sc *SearchController) RecordSearchHistory(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var req RecordSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "Search query is required",
+		})
+		return
+	}
+	if req.ResultType == "" {
+		req.ResultType = "query"
+	}
+	// Sadece kullanıcı ve mekan sonuçları bir ID'ye bağlanır
+	if req.ResultType == "query" || req.ResultType == "hashtag" {
+		req.ResultID = nil
+	} else if req.ResultID == nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{
+			Success: false,
+			Message: "resultId is required for user and place results",
+		})
+		return
+	}
+
+	var entry models.SearchHistory
+	tx := sc.DB.Begin()
+
+	existing := tx.Where("user_id = ? AND LOWER(query) = LOWER(?) AND result_type = ?", user.UserID, req.Query, req.ResultType)
+	if req.ResultID != nil {
+		existing = existing.Where("result_id = ?", *req.ResultID)
+	} else {
+		existing = existing.Where("result_id IS NULL")
+	}
+
+	err := existing.First(&entry).Error
+	switch {
+	case err == nil:
+		// Tekrarlanan arama: yeni kayıt yerine zamanı güncelle
+		if err := tx.Model(&entry).Update("searched_at", time.Now()).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving search history"})
+			return
+		}
+	case err == gorm.ErrRecordNotFound:
+		entry = models.SearchHistory{
+			UserID:     user.UserID,
+			Query:      req.Query,
+			ResultType: req.ResultType,
+			ResultID:   req.ResultID,
+			SearchedAt: time.Now(),
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving search history"})
+			return
+		}
+	default:
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving search history"})
+		return
+	}
+
+	// Kullanıcı başına en fazla maxSearchHistoryEntries kayıt tut
+	if err := tx.Exec(`DELETE FROM search_histories WHERE user_id = ? AND id NOT IN (
+			SELECT id FROM search_histories WHERE user_id = ? ORDER BY searched_at DESC, id DESC LIMIT ?)`,
+		user.UserID, user.UserID, maxSearchHistoryEntries).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving search history"})
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error saving search history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    entry,
+	})
+}
+
+// GetSearchHistory godoc
+// @Summary List recent searches
+// @Description Returns the current user's recent searches, newest first
+// @Tags search
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /search/history [get]
+func (sc *SearchController) GetSearchHistory(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	history := []models.SearchHistory{}
+	if err := sc.DB.Where("user_id = ?", user.UserID).
+		Order("searched_at DESC, id DESC").
+		Limit(maxSearchHistoryEntries).
+		Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching search history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    history,
+	})
+}
+
+// ClearSearchHistory godoc
+// @Summary Clear recent searches
+// @Description Deletes all recent searches of the current user
+// @Tags search
+// @Produce json
+// @Success 200 {object} StandardResponse
+// @Router /search/history [delete]
+func (sc *SearchController) ClearSearchHistory(c *gin.Context) {
+	user := utils.GetUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	if err := sc.DB.Where("user_id = ?", user.UserID).Delete(&models.SearchHistory{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error clearing search history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Message: "Search history cleared",
+	})
+}
+
+// searchUsers matches username and names, hiding users blocked in either direction
+func (sc *SearchController) searchUsers(term string, viewerID uint, offset, limit int) ([]SearchUserResult, int64, error) {
+	pattern := "%" + escapeLike(term) + "%"
+	db := sc.DB.Table("users").
+		Where("users.deleted_at IS NULL").
+		Where(`users.username ILIKE ? ESCAPE '\' OR users.first_name ILIKE ? ESCAPE '\' OR users.last_name ILIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern).
+		Where(`NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+			((blocks.blocker_user_id = ? AND blocks.blocked_user_id = users.id) OR
+			 (blocks.blocker_user_id = users.id AND blocks.blocked_user_id = ?)))`, viewerID, viewerID)
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	relevance, relevanceArgs := searchRelevanceCase("users.username", term)
+	users := []SearchUserResult{}
+	err := db.Select("users.id, users.username, users.first_name, users.last_name, users.avatar, users.is_verified, users.total_points, "+
+		"users.followers_count, users.posts_count, "+relevance+" as relevance", relevanceArgs...).
+		Order("relevance, users.is_verified DESC, users.total_points DESC, users.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&users).Error
+
+	return users, total, err
+}
+
+// searchPlaces matches place name and address, best name matches first
+func (sc *SearchController) searchPlaces(term string, minRating *float64, offset, limit int) ([]SearchPlaceResult, int64, string, error) {
+	pattern := "%" + escapeLike(term) + "%"
+	db := sc.DB.Table("places").
+		Where("places.deleted_at IS NULL AND places.needs_review = false").
+		Where(`places.name ILIKE ? ESCAPE '\' OR places.address ILIKE ? ESCAPE '\'`, pattern, pattern)
+
+	var nullRating string
+	if minRating != nil {
+		var condition string
+		var args []interface{}
+		condition, args, nullRating = minRatingCondition("places.rating", *minRating)
+		db = db.Where(condition, args...)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, "", err
+	}
+
+	relevance, relevanceArgs := searchRelevanceCase("places.name", term)
+	places := []SearchPlaceResult{}
+	err := db.Select(`places.id, places.name, places.address, places.place_image as image,
+			places.base_points as point_value, places.rating, places.latitude, places.longitude, places.is_verified, `+
+		relevance+` as relevance`, relevanceArgs...).
+		Order("relevance, places.is_verified DESC, places.base_points DESC, places.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&places).Error
+
+	return places, total, nullRating, err
+}
+
+// searchHashtags extracts #tags from captions of posts the viewer can see
+func (sc *SearchController) searchHashtags(term string, viewerID uint, offset, limit int) ([]SearchHashtagResult, int64, error) {
+	pattern := "%" + escapeLike(strings.ToLower(strings.TrimPrefix(term, "#"))) + "%"
+	visibility, visibilityArgs := visiblePostsCondition(viewerID)
+
+	tagsQuery := `
+		SELECT LOWER(m[1]) as tag, COUNT(DISTINCT posts.id) as posts_count
+		FROM posts
+		JOIN users ON posts.user_id = users.id
+		CROSS JOIN LATERAL regexp_matches(posts.post_caption, '#([[:alnum:]_]+)', 'g') as m
+		WHERE posts.deleted_at IS NULL AND LOWER(m[1]) LIKE ? ESCAPE '\' AND ` + visibility + `
+		GROUP BY LOWER(m[1])`
+	args := append([]interface{}{pattern}, visibilityArgs...)
+
+	var total int64
+	if err := sc.DB.Raw("SELECT COUNT(*) FROM ("+tagsQuery+") as tags", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hashtags := []SearchHashtagResult{}
+	err := sc.DB.Raw(tagsQuery+" ORDER BY posts_count DESC, tag LIMIT ? OFFSET ?", append(args, limit, offset)...).
+		Scan(&hashtags).Error
+
+	return hashtags, total, err
+}
+
+// searchRelevanceCase ranks rows by how well column matches term:
+// exact match (0), prefix match (1), substring match (2), anything else (3).
+// Lower is better; callers order by it before their points tiebreaker.
+func searchRelevanceCase(column, term string) (string, []interface{}) {
+	expr := "CASE WHEN LOWER(" + column + ") = LOWER(?) THEN 0 " +
+		"WHEN " + column + " ILIKE ? ESCAPE '\\' THEN 1 " +
+		"WHEN " + column + " ILIKE ? ESCAPE '\\' THEN 2 " +
+		"ELSE 3 END"
+	escaped := escapeLike(term)
+	return expr, []interface{}{term, escaped + "%", "%" + escaped + "%"}
+}
+
+// likeEscaper, LIKE desenlerinde joker sayılan karakterleri ESCAPE '\' ile kullanılmak üzere kaçırır
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match literally inside a LIKE/ILIKE pattern that
+// declares ESCAPE '\'.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func searchPagination(page, pageSize int, total int64) PaginationMeta {
+	return PaginationMeta{
+		CurrentPage: page,
+		PageSize:    pageSize,
+		TotalItems:  total,
+		TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+	}
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/snap-point/api-go/models"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"istanbul", "istanbul"},
+		{"100%", `100\%`},
+		{"user_name", `user\_name`},
+		{`back\slash`, `back\\slash`},
+		{`%_\`, `\%\_\\`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escapeLike(tt.in); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+type searchResponse struct {
+	Data map[string]json.RawMessage `json:"data"`
+}
+
+func TestSearchTypes(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "searchviewer")
+	createTestUser(t, db, "cafeuser")
+	blocker := createTestUser(t, db, "cafeblocker")
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	place := createTestPlace(t, db, "cafeplace")
+	poster := createTestUser(t, db, "poster")
+	createTestPost(t, db, poster, place, "morning #cafelife", true)
+
+	sc := NewSearchController(db)
+	search := func(target string) map[string][]string {
+		t.Helper()
+		w := callHandler(sc.Search, http.MethodGet, target, nil, viewer.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d body %s", target, w.Code, w.Body.String())
+		}
+		var resp searchResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+
+		got := map[string][]string{}
+		for kind, raw := range resp.Data {
+			var items []struct {
+				Username string `json:"username"`
+				Name     string `json:"name"`
+				Tag      string `json:"tag"`
+			}
+			if err := json.Unmarshal(raw, &items); err != nil {
+				t.Fatal(err)
+			}
+			got[kind] = []string{}
+			for _, item := range items {
+				got[kind] = append(got[kind], item.Username+item.Name+item.Tag)
+			}
+			sort.Strings(got[kind])
+		}
+		return got
+	}
+
+	tests := []struct {
+		target string
+		want   map[string][]string
+	}{
+		{"/search?q=cafe&type=users", map[string][]string{"users": {"cafeuser"}}},
+		{"/search?q=cafe&type=places", map[string][]string{"places": {"cafeplace"}}},
+		{"/search?q=%23cafe&type=hashtags", map[string][]string{"hashtags": {"cafelife"}}},
+		{"/search?q=cafe", map[string][]string{
+			"users":    {"cafeuser"},
+			"places":   {"cafeplace"},
+			"hashtags": {"cafelife"},
+		}},
+		// Joker karakterler harfiyen aranır
+		{"/search?q=_", map[string][]string{"users": {}, "places": {}, "hashtags": {}}},
+	}
+	for _, tt := range tests {
+		got := search(tt.target)
+		if len(got) != len(tt.want) {
+			t.Errorf("GET %s: result types %v, want %v", tt.target, got, tt.want)
+			continue
+		}
+		for kind, want := range tt.want {
+			if !reflect.DeepEqual(got[kind], want) {
+				t.Errorf("GET %s: %s = %v, want %v", tt.target, kind, got[kind], want)
+			}
+		}
+	}
+}
+
+func TestSearchUsersMatchesLiterally(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "literalviewer")
+	createTestUser(t, db, "with_underscore")
+	createTestUser(t, db, "withxunderscore")
+
+	uc := NewUserController(db, nil)
+	w := callHandler(uc.SearchUsers, http.MethodGet, "/users/search?q=with_", nil, viewer.ID)
+	var resp struct {
+		Users []struct {
+			Username string `json:"username"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Username != "with_underscore" {
+		t.Errorf("users = %+v, want only with_underscore", resp.Users)
+	}
+}
+
+func TestSearchRanksExactMatchFirst(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "rankviewer")
+
+	// Puanı en düşük olan tam eşleşme yine de ilk sırada olmalı
+	for username, points := range map[string]int{"ankara": 10, "ankaralover": 1000, "bestankara": 2000} {
+		user := createTestUser(t, db, username)
+		if err := db.Model(&user).Update("total_points", points).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, points := range map[string]int{"moda": 5, "moda sahil": 50, "eski moda": 100} {
+		place := createTestPlace(t, db, name)
+		if err := db.Model(&place).Update("base_points", points).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(raw json.RawMessage) []string {
+		t.Helper()
+		var items []struct {
+			Username string `json:"username"`
+			Name     string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			t.Fatal(err)
+		}
+		out := []string{}
+		for _, item := range items {
+			out = append(out, item.Username+item.Name)
+		}
+		return out
+	}
+
+	sc := NewSearchController(db)
+	w := callHandler(sc.Search, http.MethodGet, "/search?q=ankara&type=users", nil, viewer.ID)
+	var resp searchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(resp.Data["users"]), []string{"ankara", "ankaralover", "bestankara"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/search users = %v, want %v", got, want)
+	}
+
+	w = callHandler(sc.Search, http.MethodGet, "/search?q=moda&type=places", nil, viewer.ID)
+	resp = searchResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(resp.Data["places"]), []string{"moda", "moda sahil", "eski moda"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/search places = %v, want %v", got, want)
+	}
+
+	uc := NewUserController(db, nil)
+	w = callHandler(uc.SearchUsers, http.MethodGet, "/users/search?q=ankara", nil, viewer.ID)
+	var legacy struct {
+		Users json.RawMessage `json:"users"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(legacy.Users), []string{"ankara", "ankaralover", "bestankara"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/users/search = %v, want %v", got, want)
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	db := openTestDB(t)
+	sc := NewSearchController(db)
+
+	record := func(user models.User, body string) {
+		t.Helper()
+		w := callHandler(sc.RecordSearchHistory, http.MethodPost, "/search/history", strings.NewReader(body), user.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("record %s: status %d body %s", body, w.Code, w.Body.String())
+		}
+	}
+	history := func(user models.User) []string {
+		t.Helper()
+		w := callHandler(sc.GetSearchHistory, http.MethodGet, "/search/history", nil, user.ID)
+		var resp struct {
+			Data []models.SearchHistory `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		queries := []string{}
+		for _, entry := range resp.Data {
+			queries = append(queries, entry.Query)
+		}
+		return queries
+	}
+
+	t.Run("repeated query bumps instead of duplicating", func(t *testing.T) {
+		user := createTestUser(t, db, "historydedupe")
+		place := createTestPlace(t, db, "historyplace")
+		record(user, `{"query":"moda"}`)
+		record(user, `{"query":"kadikoy"}`)
+		record(user, `{"query":"MODA"}`)
+		// Aynı metin farklı bir sonuç türü için ayrı kayıttır
+		record(user, fmt.Sprintf(`{"query":"moda","resultType":"place","resultId":%d}`, place.ID))
+
+		if got, want := history(user), []string{"moda", "moda", "kadikoy"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("history = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("capped per user", func(t *testing.T) {
+		user := createTestUser(t, db, "historycap")
+		other := createTestUser(t, db, "historyother")
+		record(other, `{"query":"untouched"}`)
+		for i := 0; i < maxSearchHistoryEntries+3; i++ {
+			record(user, fmt.Sprintf(`{"query":"query %d"}`, i))
+		}
+
+		got := history(user)
+		if len(got) != maxSearchHistoryEntries {
+			t.Fatalf("kept %d entries, want %d", len(got), maxSearchHistoryEntries)
+		}
+		if newest, oldest := got[0], got[len(got)-1]; newest != fmt.Sprintf("query %d", maxSearchHistoryEntries+2) || oldest != "query 3" {
+			t.Errorf("kept %q..%q, want the newest entries", newest, oldest)
+		}
+		var stored int64
+		db.Model(&models.SearchHistory{}).Where("user_id = ?", user.ID).Count(&stored)
+		if stored != maxSearchHistoryEntries {
+			t.Errorf("stored %d rows, want %d", stored, maxSearchHistoryEntries)
+		}
+		if got := history(other); !reflect.DeepEqual(got, []string{"untouched"}) {
+			t.Errorf("other user's history = %v, want untouched", got)
+		}
+
+		callHandler(sc.ClearSearchHistory, http.MethodDelete, "/search/history", nil, user.ID)
+		if got := history(user); len(got) != 0 {
+			t.Errorf("history after clear = %v, want empty", got)
+		}
+		if got := history(other); len(got) != 1 {
+			t.Errorf("clear removed another user's history: %v", got)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// isSoftDeleted reports whether the row of model with id still exists but is
+// soft-deleted, telling removed content (410 Gone) apart from IDs that never
+// existed or were already purged (404).
+func isSoftDeleted(db *gorm.DB, model interface{}, id interface{}) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// respondDeleted answers 410 Gone with the deleted marker shared by every
+// entity, so clients can show "content removed" instead of "not found"
+func respondDeleted(c *gin.Context, message string) {
+	c.JSON(http.StatusGone, StandardResponse{
+		Success: false,
+		Message: message,
+		Data:    gin.H{"deleted": true},
+	})
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestDeletedEntitiesAreGone(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "goneviewer")
+	author := createTestUser(t, db, "goneauthor")
+	place := createTestPlace(t, db, "goneplace")
+	post := createTestPost(t, db, author, createTestPlace(t, db, "gonepostplace"), "", true)
+	softDelete(t, db, &post, 0)
+	softDelete(t, db, &author, 0)
+	softDelete(t, db, &place, 0)
+
+	endpoints := []struct {
+		name      string
+		handler   gin.HandlerFunc
+		key       string
+		deletedID uint
+	}{
+		{"post", NewPostController(db, nil).GetPostDetail, "id", post.ID},
+		{"user", NewUserController(db, nil).GetUserProfile, "userId", author.ID},
+		{"place", NewPlaceController(db, nil).GetPlaceProfile, "placeId", place.ID},
+	}
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.name, func(t *testing.T) {
+			param := gin.Param{Key: endpoint.key, Value: strconv.Itoa(int(endpoint.deletedID))}
+			w := callHandler(endpoint.handler, http.MethodGet, "/"+param.Value, nil, viewer.ID, param)
+			if w.Code != http.StatusGone {
+				t.Fatalf("deleted: status = %d, want 410; body = %s", w.Code, w.Body.String())
+			}
+			// Her varlık 404 ile aynı StandardResponse gövdesini döner
+			var body StandardResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if data, _ := body.Data.(map[string]interface{}); body.Success || body.Message == "" || data["deleted"] != true {
+				t.Errorf("deleted: body = %s, want success false, a message and data.deleted", w.Body.String())
+			}
+
+			missing := gin.Param{Key: endpoint.key, Value: "999999"}
+			if w := callHandler(endpoint.handler, http.MethodGet, "/999999", nil, viewer.ID, missing); w.Code != http.StatusNotFound {
+				t.Errorf("never existed: status = %d, want 404", w.Code)
+			}
+		})
+	}
+}
+
+func TestDeletedPostGoneOnlyWhenVisible(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "gonestranger")
+	follower := createTestUser(t, db, "gonefollower")
+	private := createTestUser(t, db, "goneprivate")
+	blocker := createTestUser(t, db, "goneblocker")
+	place := createTestPlace(t, db, "gonevisibility")
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: private.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	privatePost := createTestPost(t, db, private, place, "private", true)
+	blockedPost := createTestPost(t, db, blocker, place, "blocked", true)
+	softDelete(t, db, &privatePost, 0)
+	softDelete(t, db, &blockedPost, 0)
+	pc := NewPostController(db, nil)
+
+	tests := []struct {
+		name     string
+		postID   uint
+		viewerID uint
+		want     int
+	}{
+		// Görülemeyen gönderinin silindiği de söylenmez
+		{"private post, stranger", privatePost.ID, viewer.ID, http.StatusNotFound},
+		{"private post, follower", privatePost.ID, follower.ID, http.StatusGone},
+		{"private post, owner", privatePost.ID, private.ID, http.StatusGone},
+		{"blocked author", blockedPost.ID, viewer.ID, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := gin.Param{Key: "id", Value: strconv.Itoa(int(tt.postID))}
+			if w := callHandler(pc.GetPostDetail, http.MethodGet, "/posts/"+param.Value, nil, tt.viewerID, param); w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body = %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
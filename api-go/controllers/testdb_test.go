@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	// NewAuthController Google kimlik bilgileri olmadan panikler
+	if os.Getenv("GOOGLE_CLIENT_ID") == "" {
+		os.Setenv("GOOGLE_CLIENT_ID", "test-client")
+		os.Setenv("GOOGLE_CLIENT_SECRET", "test-secret")
+	}
+	os.Exit(m.Run())
+}
+
+// openTestDB connects to the Postgres database in TEST_DATABASE_URL and
+// migrates a fresh schema that is dropped when the test ends. Tests that need
+// a database are skipped when it is not set.
+func openTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	gormConfig := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	// search_path her bağlantıda uygulanır; URL ve anahtar=değer DSN'leri desteklenir
+	switch {
+	case strings.Contains(dsn, "://") && strings.Contains(dsn, "?"):
+		dsn += "&search_path=" + schema
+	case strings.Contains(dsn, "://"):
+		dsn += "?search_path=" + schema
+	default:
+		dsn += " search_path=" + schema
+	}
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		t.Fatalf("connect to schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if err := config.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// createTestUser stores a verified user with a month-old account
+func createTestUser(t testing.TB, db *gorm.DB, username string) models.User {
+	t.Helper()
+	var role models.Role
+	if err := db.Where(models.Role{Name: "user"}).FirstOrCreate(&role).Error; err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	user := models.User{
+		Username:   username,
+		Email:      username + "@example.com",
+		RoleID:     role.ID,
+		IsVerified: true,
+		CreatedAt:  time.Now().AddDate(0, -1, 0),
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+func createTestPlace(t testing.TB, db *gorm.DB, name string) models.Place {
+	t.Helper()
+	place := models.Place{
+		Name:      name,
+		Address:   name + " address",
+		Latitude:  41.0082,
+		Longitude: 28.9784,
+		PlaceType: "user_created",
+		// Benzersiz indeks boş değerleri de çakıştırır
+		GooglePlaceID: "test-" + name,
+	}
+	if err := db.Create(&place).Error; err != nil {
+		t.Fatalf("create place %s: %v", name, err)
+	}
+	return place
+}
+
+func createTestPost(t testing.TB, db *gorm.DB, user models.User, place models.Place, caption string, public bool) models.Post {
+	t.Helper()
+	post := models.Post{
+		UserID:      user.ID,
+		PlaceID:     place.ID,
+		PostCaption: caption,
+		Latitude:    place.Latitude,
+		Longitude:   place.Longitude,
+		IsPublic:    true,
+	}
+	if err := db.Create(&post).Error; err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	// default:true olan alanlara false Create ile yazılmaz
+	if !public {
+		if err := db.Model(&post).Update("is_public", false).Error; err != nil {
+			t.Fatalf("update post: %v", err)
+		}
+		post.IsPublic = false
+	}
+	return post
+}
+
+// callHandler runs handler for a request made by userID and returns the recorded response
+func callHandler(handler gin.HandlerFunc, method, target string, body io.Reader, userID uint, params ...gin.Param) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, body)
+	if body != nil {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	c.Params = params
+	c.Set(string(utils.UserContextKey), &utils.UserClaims{UserID: userID, Role: "user"})
+	handler(c)
+	return w
+}
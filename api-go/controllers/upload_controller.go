@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -14,8 +15,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/media"
+	"github.com/snap-point/api-go/models"
 	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UploadController struct {
@@ -28,7 +32,7 @@ type PresignedURLRequest struct {
 	FileName    string `json:"fileName" binding:"required"`
 	ContentType string `json:"contentType" binding:"required"`
 	FileSize    int64  `json:"fileSize" binding:"required"`
-	MediaType   string `json:"mediaType" binding:"required,oneof=photo video"`
+	MediaType   string `json:"mediaType" binding:"required,mediatype"`
 }
 
 type AvatarUploadRequest struct {
@@ -43,11 +47,13 @@ type AvatarConfirmRequest struct {
 }
 
 type PresignedURLResponse struct {
-	UploadURL    string `json:"uploadUrl"`
-	FileURL      string `json:"fileUrl"`
-	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
-	Key          string `json:"key"`
-	ExpiresIn    int    `json:"expiresIn"`
+	UploadURL         string `json:"uploadUrl"`
+	FileURL           string `json:"fileUrl"`
+	ThumbnailURL      string `json:"thumbnailUrl,omitempty"`
+	Key               string `json:"key"`
+	ExpiresIn         int    `json:"expiresIn"`
+	MaxSize           int64  `json:"maxSize"`           // enforced upper bound for this file in bytes
+	ThumbnailExpected bool   `json:"thumbnailExpected"` // client should upload a thumbnail to ThumbnailURL
 }
 
 type MultipleUploadRequest struct {
@@ -55,12 +61,17 @@ type MultipleUploadRequest struct {
 }
 
 type MultipleUploadResponse struct {
-	Files []PresignedURLResponse `json:"files"`
+	Files        []PresignedURLResponse `json:"files"`
+	TotalSize    int64                  `json:"totalSize"`
+	MaxTotalSize int64                  `json:"maxTotalSize"`
 }
 
+// presignExpiry yükleme URL'lerinin geçerlilik süresi
+const presignExpiry = time.Hour
+
 type UploadCompleteRequest struct {
 	Key       string `json:"key" binding:"required"`
-	MediaType string `json:"mediaType" binding:"required,oneof=photo video"`
+	MediaType string `json:"mediaType" binding:"required,mediatype"`
 	Width     int    `json:"width"`
 	Height    int    `json:"height"`
 	Duration  int    `json:"duration"`
@@ -98,6 +109,11 @@ func (uc *UploadController) GetPresignedURL(c *gin.Context) {
 		return
 	}
 
+	if !isMediaTypeEnabled(req.MediaType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s uploads are currently disabled", req.MediaType)})
+		return
+	}
+
 	// Validate file type
 	if !uc.isValidFileType(req.ContentType, req.MediaType) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type for media type"})
@@ -120,18 +136,7 @@ func (uc *UploadController) GetPresignedURL(c *gin.Context) {
 		return
 	}
 
-	response := PresignedURLResponse{
-		UploadURL: presignedURL,
-		FileURL:   fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, key),
-		Key:       key,
-		ExpiresIn: 3600, // 1 hour
-	}
-
-	// Generate thumbnail URL for videos
-	if req.MediaType == "video" {
-		thumbnailKey := uc.generateThumbnailKey(key)
-		response.ThumbnailURL = fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, thumbnailKey)
-	}
+	response := uc.presignedURLResponse(presignedURL, key, req.MediaType)
 
 	c.JSON(http.StatusOK, StandardResponse{
 		Success: true,
@@ -149,16 +154,23 @@ func (uc *UploadController) GetMultiplePresignedURLs(c *gin.Context) {
 		return
 	}
 
-	// Validate number of files (max 10 for Instagram-like experience)
-	if len(req.Files) > 10 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 10 files allowed per upload"})
+	// Validate number of files (same limit as media items per post)
+	maxFiles := config.GetMaxMediaItemsPerPost()
+	if len(req.Files) > maxFiles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Maximum %d files allowed per upload", maxFiles)})
 		return
 	}
 
-	var responses []PresignedURLResponse
-
+	// Validate every file and the batch total before presigning anything
+	var totalSize int64
 	for _, fileReq := range req.Files {
-		// Validate each file
+		if !isMediaTypeEnabled(fileReq.MediaType) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s uploads are currently disabled (%s)", fileReq.MediaType, fileReq.FileName),
+			})
+			return
+		}
+
 		if !uc.isValidFileType(fileReq.ContentType, fileReq.MediaType) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error": fmt.Sprintf("Invalid file type for %s", fileReq.FileName),
@@ -168,11 +180,28 @@ func (uc *UploadController) GetMultiplePresignedURLs(c *gin.Context) {
 
 		if !uc.isValidFileSize(fileReq.FileSize, fileReq.MediaType) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("File size exceeds limit for %s", fileReq.FileName),
+				"error":   fmt.Sprintf("File size exceeds limit for %s", fileReq.FileName),
+				"maxSize": media.MaxSize(fileReq.MediaType),
 			})
 			return
 		}
 
+		totalSize += fileReq.FileSize
+	}
+
+	maxTotalSize := config.GetMaxUploadBytesPerPost()
+	if totalSize > maxTotalSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":        "Total upload size exceeds the per-post limit",
+			"totalSize":    totalSize,
+			"maxTotalSize": maxTotalSize,
+		})
+		return
+	}
+
+	var responses []PresignedURLResponse
+
+	for _, fileReq := range req.Files {
 		// Generate unique key
 		key := uc.generateFileKey(user.UserID, fileReq.FileName, fileReq.MediaType)
 		
@@ -185,25 +214,15 @@ func (uc *UploadController) GetMultiplePresignedURLs(c *gin.Context) {
 			return
 		}
 
-		response := PresignedURLResponse{
-			UploadURL: presignedURL,
-			FileURL:   fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, key),
-			Key:       key,
-			ExpiresIn: 3600,
-		}
-
-		if fileReq.MediaType == "video" {
-			thumbnailKey := uc.generateThumbnailKey(key)
-			response.ThumbnailURL = fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, thumbnailKey)
-		}
-
-		responses = append(responses, response)
+		responses = append(responses, uc.presignedURLResponse(presignedURL, key, fileReq.MediaType))
 	}
 
 	c.JSON(http.StatusOK, StandardResponse{
 		Success: true,
 		Data: MultipleUploadResponse{
-			Files: responses,
+			Files:        responses,
+			TotalSize:    totalSize,
+			MaxTotalSize: maxTotalSize,
 		},
 		Message: "Multiple presigned URLs generated successfully",
 	})
@@ -237,16 +256,55 @@ func (uc *UploadController) ConfirmUpload(c *gin.Context) {
 		return
 	}
 
-	response := gin.H{
-		"key":       req.Key,
-		"fileUrl":   fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, req.Key),
-		"fileSize":  fileInfo.ContentLength,
-		"mediaType": req.MediaType,
-					"uploadedBy": user.UserID,
-		"uploadedAt": time.Now(),
+	fileURL := fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, req.Key)
+
+	// İstemcinin bildirdiği boyutlara güvenilmez; okunamazsa onlara geri dönülür
+	declared := mediaDimensions{Width: req.Width, Height: req.Height, Duration: req.Duration}
+	extracted, err := uc.extractMediaDimensions(c.Request.Context(), req.Key, req.MediaType)
+	if err != nil {
+		log.Printf("Failed to read dimensions of %s, using declared values: %v", req.Key, err)
+	}
+	dims, verified := resolveMediaDimensions(declared, extracted, err)
+	// Yalnızca kullanıcının kendi dosyası için kayıt tutulur; gönderi oluşturma bunlara güvenir
+	if verified && uc.verifyFileOwnership(req.Key, user.UserID) {
+		upload := models.MediaUpload{
+			UserID:    user.UserID,
+			MediaURL:  fileURL,
+			MediaType: req.MediaType,
+			Width:     dims.Width,
+			Height:    dims.Height,
+			Duration:  dims.Duration,
+		}
+		// Özet yinelenen gönderi tespiti içindir; hesaplanamazsa yükleme yine onaylanır
+		if media.IsHashable(req.MediaType) {
+			hash, err := uc.imagePerceptualHash(c.Request.Context(), req.Key, req.MediaType)
+			if err != nil {
+				log.Printf("Failed to hash %s: %v", req.Key, err)
+			}
+			upload.PerceptualHash = hash
+		}
+		if err := uc.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "media_url"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "media_type", "width", "height", "duration", "perceptual_hash", "updated_at"}),
+		}).Create(&upload).Error; err != nil {
+			log.Printf("Failed to store dimensions of %s: %v", req.Key, err)
+		}
 	}
 
-	if req.MediaType == "video" {
+	response := gin.H{
+		"key":                req.Key,
+		"fileUrl":            fileURL,
+		"fileSize":           fileInfo.ContentLength,
+		"mediaType":          req.MediaType,
+		"width":              dims.Width,
+		"height":             dims.Height,
+		"duration":           dims.Duration,
+		"dimensionsVerified": verified,
+		"uploadedBy":         user.UserID,
+		"uploadedAt":         time.Now(),
+	}
+
+	if media.ExpectsThumbnail(req.MediaType) {
 		thumbnailKey := uc.generateThumbnailKey(req.Key)
 		response["thumbnailUrl"] = fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, thumbnailKey)
 	}
@@ -383,41 +441,33 @@ func (uc *UploadController) CleanupTempAvatar(c *gin.Context) {
 
 // Helper functions
 func (uc *UploadController) isValidFileType(contentType, mediaType string) bool {
-	validTypes := map[string][]string{
-		"photo": {
-			"image/jpeg", "image/jpg", "image/png", "image/webp", "image/heic",
-		},
-		"video": {
-			"video/mp4", "video/quicktime", "video/avi", "video/webm", "video/mov",
-		},
-	}
-
-	allowed, exists := validTypes[mediaType]
-	if !exists {
-		return false
-	}
-
-	for _, validType := range allowed {
-		if contentType == validType {
-			return true
-		}
-	}
-	return false
+	valid, _ := media.ValidateUpload(mediaType, contentType, 0)
+	return valid
 }
 
 func (uc *UploadController) isValidFileSize(fileSize int64, mediaType string) bool {
-	// Size limits in bytes
-	limits := map[string]int64{
-		"photo": 10 * 1024 * 1024,   // 10MB
-		"video": 100 * 1024 * 1024,  // 100MB
+	_, valid := media.ValidateUpload(mediaType, "", fileSize)
+	return valid
+}
+
+// presignedURLResponse builds the per-file upload response including the
+// limits the client should enforce; videos expect a thumbnail upload.
+func (uc *UploadController) presignedURLResponse(presignedURL, key, mediaType string) PresignedURLResponse {
+	response := PresignedURLResponse{
+		UploadURL: presignedURL,
+		FileURL:   fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, key),
+		Key:       key,
+		ExpiresIn: int(presignExpiry.Seconds()),
+		MaxSize:   media.MaxSize(mediaType),
 	}
 
-	limit, exists := limits[mediaType]
-	if !exists {
-		return false
+	if media.ExpectsThumbnail(mediaType) {
+		thumbnailKey := uc.generateThumbnailKey(key)
+		response.ThumbnailURL = fmt.Sprintf("%s/%s", uc.R2Config.PublicURL, thumbnailKey)
+		response.ThumbnailExpected = true
 	}
 
-	return fileSize <= limit
+	return response
 }
 
 func (uc *UploadController) generateFileKey(userID uint, fileName, mediaType string) string {
@@ -443,7 +493,7 @@ func (uc *UploadController) createPresignedURL(key, contentType string) (string,
 
 	presigner := s3.NewPresignClient(uc.R2Client)
 	req, err := presigner.PresignPutObject(context.TODO(), input, func(opts *s3.PresignOptions) {
-		opts.Expires = time.Hour // 1 hour expiry
+		opts.Expires = presignExpiry
 	})
 
 	if err != nil {
@@ -453,6 +503,46 @@ func (uc *UploadController) createPresignedURL(key, contentType string) (string,
 	return req.URL, nil
 }
 
+// createPresignedGetURL returns a short-lived download URL for key, for
+// buckets that are not publicly readable.
+func (uc *UploadController) createPresignedGetURL(key string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(uc.R2Config.BucketName),
+		Key:    aws.String(key),
+	}
+
+	presigner := s3.NewPresignClient(uc.R2Client)
+	req, err := presigner.PresignGetObject(context.TODO(), input, func(opts *s3.PresignOptions) {
+		opts.Expires = config.GetMediaURLExpiry()
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+// mediaReadURL returns the URL a client should load stored media from. Media
+// URLs are stored under the public URL either way; with private media they
+// are swapped for presigned GET URLs on read. Callers must check that the
+// viewer may see the post first. A nil controller returns mediaURL unchanged.
+func (uc *UploadController) mediaReadURL(mediaURL string) string {
+	if uc == nil || mediaURL == "" || !uc.R2Config.PrivateMedia {
+		return mediaURL
+	}
+	key, ok := uc.mediaKeyFromURL(mediaURL)
+	if !ok {
+		return mediaURL
+	}
+	signedURL, err := uc.createPresignedGetURL(key)
+	if err != nil {
+		// Özel kovada imzasız URL açılmaz; istemci medyayı yüklenemedi olarak gösterir
+		log.Printf("Failed to presign media %s: %v", key, err)
+		return mediaURL
+	}
+	return signedURL
+}
+
 func (uc *UploadController) verifyFileExists(key string) (bool, error) {
 	input := &s3.HeadObjectInput{
 		Bucket: aws.String(uc.R2Config.BucketName),
@@ -486,6 +576,15 @@ func (uc *UploadController) deleteFile(key string) error {
 	return err
 }
 
+// mediaKeyFromURL returns the bucket key of a public media URL; false for URLs outside our storage
+func (uc *UploadController) mediaKeyFromURL(mediaURL string) (string, bool) {
+	publicURL := strings.TrimRight(uc.R2Config.PublicURL, "/")
+	if publicURL == "" || !strings.HasPrefix(mediaURL, publicURL+"/") {
+		return "", false
+	}
+	return strings.TrimPrefix(mediaURL, publicURL+"/"), true
+}
+
 func (uc *UploadController) verifyFileOwnership(key string, userID uint) bool {
 	// Extract user ID from key format: uploads/{mediaType}/{userID}/{timestamp}_{uuid}.{ext}
 	parts := strings.Split(key, "/")
@@ -497,25 +596,37 @@ func (uc *UploadController) verifyFileOwnership(key string, userID uint) bool {
 	return fmt.Sprintf("%d", userID) == keyUserID
 }
 
-func (uc *UploadController) isValidAvatarFile(contentType string, fileSize int64) bool {
-	validTypes := []string{
-		"image/jpeg", "image/jpg", "image/png", "image/webp",
+// validateMediaURL checks that a post media URL points to a file the user
+// uploaded to our bucket: it must start with the configured public URL and its
+// key must be under the user's uploads prefix.
+func (uc *UploadController) validateMediaURL(mediaURL string, userID uint) error {
+	// Public URL tanımlı değilse (ör. lokal geliştirme) doğrulama atlanır
+	publicURL := strings.TrimRight(uc.R2Config.PublicURL, "/")
+	if publicURL == "" {
+		return nil
 	}
-	
-	validType := false
-	for _, validContentType := range validTypes {
-		if contentType == validContentType {
-			validType = true
-			break
-		}
+
+	if !strings.HasPrefix(mediaURL, publicURL+"/") {
+		return fmt.Errorf("media URL must point to our storage")
 	}
-	
-	if !validType {
-		return false
+
+	key := strings.TrimPrefix(mediaURL, publicURL+"/")
+	if !strings.HasPrefix(key, "uploads/") || !uc.verifyFileOwnership(key, userID) {
+		return fmt.Errorf("media file does not belong to the current user")
 	}
-	
-	// Avatar size limit: 5MB
-	return fileSize <= 5*1024*1024
+
+	if config.ShouldVerifyMediaUploads() {
+		exists, err := uc.verifyFileExists(key)
+		if err != nil || !exists {
+			return fmt.Errorf("media file not found in storage")
+		}
+	}
+
+	return nil
+}
+
+func (uc *UploadController) isValidAvatarFile(contentType string, fileSize int64) bool {
+	return media.Avatar.Allows(contentType) && fileSize <= media.Avatar.MaxSize
 }
 
 func (uc *UploadController) generateTempAvatarKey(fileName string) string {
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/media"
+)
+
+func TestValidateMediaURL(t *testing.T) {
+	// Depolama kontrolü S3 istemcisi ister; burada yalnızca URL kuralları sınanır
+	t.Setenv("VERIFY_MEDIA_UPLOADS", "false")
+	uc := &UploadController{R2Config: &config.R2Config{PublicURL: "https://media.example.com/"}}
+
+	tests := []struct {
+		name     string
+		mediaURL string
+		wantErr  bool
+	}{
+		{"own upload", "https://media.example.com/uploads/image/7/1700000000_a.jpg", false},
+		{"own video", "https://media.example.com/uploads/video/7/1700000000_a.mp4", false},
+		{"external url", "https://cdn.other.com/uploads/image/7/1700000000_a.jpg", true},
+		{"lookalike host", "https://media.example.com.evil.com/uploads/image/7/a.jpg", true},
+		{"another user's upload", "https://media.example.com/uploads/image/8/1700000000_a.jpg", true},
+		{"outside uploads", "https://media.example.com/avatars/7/a.jpg", true},
+		{"bare public url", "https://media.example.com/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.validateMediaURL(tt.mediaURL, 7)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMediaURL(%q) error = %v, wantErr %v", tt.mediaURL, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Public URL yoksa doğrulama atlanır
+	local := &UploadController{R2Config: &config.R2Config{}}
+	if err := local.validateMediaURL("https://cdn.other.com/a.jpg", 7); err != nil {
+		t.Errorf("without a public URL: error = %v, want nil", err)
+	}
+}
+
+// newTestUploadController imzalama çevrimdışı yapıldığı için sahte R2 bilgileriyle çalışır
+func newTestUploadController(t *testing.T) *UploadController {
+	t.Helper()
+	t.Setenv("CLOUDFLARE_ACCOUNT_ID", "test-account")
+	t.Setenv("CLOUDFLARE_ACCESS_KEY_ID", "test-key")
+	t.Setenv("CLOUDFLARE_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("CLOUDFLARE_BUCKET_NAME", "test-bucket")
+	t.Setenv("CLOUDFLARE_PUBLIC_URL", "https://cdn.example.com")
+	return NewUploadController(nil)
+}
+
+func TestGetMultiplePresignedURLsMixedBatch(t *testing.T) {
+	uc := newTestUploadController(t)
+	body := `{"files":[
+		{"fileName":"a.jpg","contentType":"image/jpeg","fileSize":1048576,"mediaType":"photo"},
+		{"fileName":"b.mp4","contentType":"video/mp4","fileSize":52428800,"mediaType":"video"}
+	]}`
+	w := callHandler(uc.GetMultiplePresignedURLs, http.MethodPost, "/upload/multiple-presigned-urls", strings.NewReader(body), 1)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data MultipleUploadResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Data.Files) != 2 {
+		t.Fatalf("files = %d, want 2", len(resp.Data.Files))
+	}
+	if resp.Data.TotalSize != 1048576+52428800 {
+		t.Errorf("totalSize = %d", resp.Data.TotalSize)
+	}
+	photo, video := resp.Data.Files[0], resp.Data.Files[1]
+	if photo.MaxSize != media.MaxSize(media.Photo) || photo.ThumbnailExpected || photo.ThumbnailURL != "" {
+		t.Errorf("photo = %+v, want photo limit and no thumbnail", photo)
+	}
+	if video.MaxSize != media.MaxSize(media.Video) || !video.ThumbnailExpected || video.ThumbnailURL == "" {
+		t.Errorf("video = %+v, want video limit and a thumbnail", video)
+	}
+	for _, file := range resp.Data.Files {
+		if file.ExpiresIn != int(presignExpiry.Seconds()) || file.UploadURL == "" {
+			t.Errorf("file = %+v, want a presigned URL with expiresIn %v", file, presignExpiry.Seconds())
+		}
+	}
+}
+
+func TestGetMultiplePresignedURLsRejectsBatch(t *testing.T) {
+	uc := newTestUploadController(t)
+	t.Setenv("MAX_UPLOAD_BYTES_PER_POST", "60000000")
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"over aggregate budget", `{"files":[
+			{"fileName":"a.mp4","contentType":"video/mp4","fileSize":40000000,"mediaType":"video"},
+			{"fileName":"b.mp4","contentType":"video/mp4","fileSize":40000000,"mediaType":"video"}
+		]}`},
+		{"file over its own limit", `{"files":[
+			{"fileName":"a.jpg","contentType":"image/jpeg","fileSize":20000000,"mediaType":"photo"}
+		]}`},
+		{"negative size", `{"files":[
+			{"fileName":"a.jpg","contentType":"image/jpeg","fileSize":-1,"mediaType":"photo"}
+		]}`},
+		{"wrong content type", `{"files":[
+			{"fileName":"a.jpg","contentType":"video/mp4","fileSize":1000,"mediaType":"photo"}
+		]}`},
+	}
+	for _, tt := range tests {
+		w := callHandler(uc.GetMultiplePresignedURLs, http.MethodPost, "/upload/multiple-presigned-urls", strings.NewReader(tt.body), 1)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", tt.name, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "uploadUrl") {
+			t.Errorf("%s: response contains presigned URLs", tt.name)
+		}
+	}
+}
+
+func TestMediaTypeRegistryAppliesEverywhere(t *testing.T) {
+	// Yalnızca media paketine eklenen tür istek doğrulamasında ve yüklemede kabul edilir
+	saved := media.Types
+	t.Cleanup(func() { media.Types = saved })
+	media.Types = append(append([]media.Type{}, saved...), media.Type{
+		Name:         "sticker",
+		ContentTypes: []string{"image/apng"},
+		MaxSize:      2048,
+	})
+
+	item := CreatePostMediaItem{MediaType: "sticker", MediaURL: "https://cdn.example.com/a.png"}
+	if err := binding.Validator.ValidateStruct(item); err != nil {
+		t.Errorf("create post item with new type: %v", err)
+	}
+	item.MediaType = "hologram"
+	if err := binding.Validator.ValidateStruct(item); err == nil {
+		t.Error("create post item with unknown type passed validation")
+	}
+	complete := UploadCompleteRequest{Key: "uploads/sticker/1/a.png", MediaType: "sticker"}
+	if err := binding.Validator.ValidateStruct(complete); err != nil {
+		t.Errorf("confirm upload with new type: %v", err)
+	}
+
+	uc := newTestUploadController(t)
+	body := `{"fileName":"a.png","contentType":"image/apng","fileSize":1024,"mediaType":"sticker"}`
+	w := callHandler(uc.GetPresignedURL, http.MethodPost, "/upload/presigned-url", strings.NewReader(body), 1)
+	if w.Code != http.StatusOK {
+		t.Fatalf("presign new type: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data PresignedURLResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.MaxSize != 2048 || resp.Data.ThumbnailExpected {
+		t.Errorf("presigned = %+v, want the new type's limit and no thumbnail", resp.Data)
+	}
+
+	body = `{"fileName":"a.png","contentType":"image/apng","fileSize":4096,"mediaType":"sticker"}`
+	w = callHandler(uc.GetPresignedURL, http.MethodPost, "/upload/presigned-url", strings.NewReader(body), 1)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("presign over the new type's limit: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMediaReadURL(t *testing.T) {
+	stored := "https://cdn.example.com/uploads/photo/7/1_a.jpg"
+
+	uc := newTestUploadController(t)
+	if got := uc.mediaReadURL(stored); got != stored {
+		t.Errorf("public bucket URL = %s, want it unchanged", got)
+	}
+
+	t.Setenv("CLOUDFLARE_PRIVATE_MEDIA", "true")
+	t.Setenv("MEDIA_URL_EXPIRY", "5m")
+	uc = newTestUploadController(t)
+	signed := uc.mediaReadURL(stored)
+	if !strings.Contains(signed, "uploads/photo/7/1_a.jpg") || !strings.Contains(signed, "X-Amz-Signature=") ||
+		!strings.Contains(signed, "X-Amz-Expires=300") {
+		t.Errorf("private bucket URL = %s, want a presigned GET valid for 5 minutes", signed)
+	}
+	// Başka kaynaklardaki URL'ler imzalanmaz
+	if external := "https://example.org/a.jpg"; uc.mediaReadURL(external) != external {
+		t.Errorf("external URL was rewritten")
+	}
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+	"gorm.io/gorm"
+)
+
+// UserCommentItem is a comment on a user's activity screen with a summary of the post it is on
+type UserCommentItem struct {
+	CommentItem
+	Post CommentPostSummary `json:"post"`
+}
+
+// GetUserComments godoc
+// @Summary List the comments a user has written
+// @Description Returns the user's comments newest first, each with a summary of its post. Comments on posts the viewer cannot see are left out, and a private account's comments are shown only to approved followers; nothing is shown across a block.
+// @Tags comments
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse{data=[]UserCommentItem}
+// @Router /users/{userId}/comments [get]
+func (cc *CommentController) GetUserComments(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{Success: false, Message: "User not found in context"})
+		return
+	}
+
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+	offset := (page - 1) * pageSize
+
+	var owner models.User
+	if err := cc.DB.Select("id, username, first_name, last_name, avatar, is_private").First(&owner, c.Param("userId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{Success: false, Message: "User not found"})
+		return
+	}
+
+	visible, err := canViewUserContent(cc.DB, currentUser.UserID, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error checking comment visibility"})
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusOK, StandardResponse{
+			Success: true,
+			Data:    []UserCommentItem{},
+			Meta:    gin.H{"isPrivate": owner.IsPrivate},
+			Pagination: &PaginationMeta{
+				CurrentPage: page,
+				PageSize:    pageSize,
+				TotalItems:  0,
+				TotalPages:  0,
+			},
+		})
+		return
+	}
+
+	// users gönderi sahibidir; görünürlük koşulu bu birleştirmeyi bekler
+	visibility, visibilityArgs := visiblePostsCondition(currentUser.UserID)
+	comments := func() *gorm.DB {
+		return cc.DB.Table("comments").
+			Joins("JOIN posts ON posts.id = comments.post_id AND posts.deleted_at IS NULL").
+			Joins("JOIN users ON posts.user_id = users.id").
+			Joins("JOIN places ON posts.place_id = places.id").
+			Where("comments.user_id = ? AND comments.deleted_at IS NULL", owner.ID).
+			Where(visibility, visibilityArgs...)
+	}
+
+	var total int64
+	if err := comments().Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting comments"})
+		return
+	}
+
+	var rows []struct {
+		ID              uint      `gorm:"column:comment_id"`
+		ParentCommentID *uint     `gorm:"column:parent_comment_id"`
+		Content         string    `gorm:"column:text_content"`
+		CreatedAt       time.Time `gorm:"column:created_at"`
+		IsEdited        bool      `gorm:"column:is_edited"`
+		PostID          uint      `gorm:"column:post_id"`
+		PostCaption     string    `gorm:"column:post_caption"`
+		PostCreatedAt   time.Time `gorm:"column:post_created_at"`
+		ThumbnailURL    string    `gorm:"column:thumbnail_url"`
+		MediaType       string    `gorm:"column:media_type"`
+		UserID          uint      `gorm:"column:user_id"`
+		Username        string    `gorm:"column:username"`
+		UserFirstName   string    `gorm:"column:user_first_name"`
+		UserLastName    string    `gorm:"column:user_last_name"`
+		UserAvatar      string    `gorm:"column:user_avatar"`
+		PlaceID         uint      `gorm:"column:place_id"`
+		PlaceName       string    `gorm:"column:place_name"`
+		PlaceImage      string    `gorm:"column:place_image"`
+	}
+	if err := comments().
+		Select(`
+			comments.comment_id,
+			comments.parent_comment_id,
+			comments.text_content,
+			comments.created_at,
+			comments.is_edited,
+			posts.id as post_id,
+			posts.post_caption,
+			posts.created_at as post_created_at,
+			(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+			(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type,
+			posts.user_id,
+			users.username,
+			users.first_name as user_first_name,
+			users.last_name as user_last_name,
+			users.avatar as user_avatar,
+			posts.place_id,
+			places.name as place_name,
+			places.place_image as place_image
+		`).
+		Order("comments.created_at DESC, comments.comment_id DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching comments"})
+		return
+	}
+
+	author := PostUser{
+		ID:        owner.ID,
+		Username:  owner.Username,
+		FirstName: owner.FirstName,
+		LastName:  owner.LastName,
+		Avatar:    owner.Avatar,
+	}
+	items := make([]UserCommentItem, len(rows))
+	for i, row := range rows {
+		items[i] = UserCommentItem{
+			CommentItem: CommentItem{
+				ID:        row.ID,
+				Content:   row.Content,
+				CreatedAt: row.CreatedAt,
+				IsEdited:  row.IsEdited,
+				ParentID:  row.ParentCommentID,
+				User:      author,
+			},
+			Post: CommentPostSummary{
+				ID:           row.PostID,
+				Caption:      row.PostCaption,
+				CreatedAt:    row.PostCreatedAt,
+				ThumbnailURL: cc.UploadController.mediaReadURL(row.ThumbnailURL),
+				MediaType:    row.MediaType,
+				User: PostUser{
+					ID:        row.UserID,
+					Username:  row.Username,
+					FirstName: row.UserFirstName,
+					LastName:  row.UserLastName,
+					Avatar:    row.UserAvatar,
+				},
+				Place: PostPlace{
+					ID:    row.PlaceID,
+					Name:  row.PlaceName,
+					Image: row.PlaceImage,
+				},
+			},
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    items,
+		Meta:    gin.H{"isPrivate": owner.IsPrivate},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
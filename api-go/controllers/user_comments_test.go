@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetUserCommentsRespectsPostVisibility(t *testing.T) {
+	db := openTestDB(t)
+	commenter := createTestUser(t, db, "commenter")
+	viewer := createTestUser(t, db, "commentviewer")
+	author := createTestUser(t, db, "commentauthor")
+	place := createTestPlace(t, db, "commentplace")
+
+	publicPost := createTestPost(t, db, author, place, "public post", true)
+	hiddenPost := createTestPost(t, db, author, place, "followers only", false)
+	base := time.Now().Add(-time.Hour)
+	visible := createTestComment(t, db, commenter, publicPost, nil, "visible", base)
+	hidden := createTestComment(t, db, commenter, hiddenPost, nil, "on a hidden post", base.Add(time.Minute))
+	deleted := createTestComment(t, db, commenter, publicPost, nil, "deleted", base.Add(2*time.Minute))
+	if err := db.Delete(&deleted).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	cc := NewCommentController(db, nil)
+	list := func(viewerID uint) []uint {
+		t.Helper()
+		param := gin.Param{Key: "userId", Value: strconv.Itoa(int(commenter.ID))}
+		w := callHandler(cc.GetUserComments, http.MethodGet, "/users/"+param.Value+"/comments", nil, viewerID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []UserCommentItem `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids := []uint{}
+		for _, item := range resp.Data {
+			if item.Post.ID == 0 || item.User.ID != commenter.ID {
+				t.Errorf("item %d: post = %+v, user = %+v; want the post summary and the commenter", item.ID, item.Post, item.User)
+			}
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	if got := list(viewer.ID); len(got) != 1 || got[0] != visible.CommentID {
+		t.Errorf("stranger sees %v, want only %d", got, visible.CommentID)
+	}
+
+	// Gönderi sahibini takip eden izleyici gizli gönderideki yorumu da görür
+	if err := db.Create(&models.Follow{FollowerUserID: viewer.ID, FollowingUserID: author.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := list(viewer.ID); len(got) != 2 || got[0] != hidden.CommentID || got[1] != visible.CommentID {
+		t.Errorf("author's follower sees %v, want [%d %d]", got, hidden.CommentID, visible.CommentID)
+	}
+
+	// Gizli hesabın yorumları yalnızca onaylı takipçilere gösterilir
+	if err := db.Model(&models.User{}).Where("id = ?", commenter.ID).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := list(viewer.ID); len(got) != 0 {
+		t.Errorf("non-follower of a private account sees %v, want none", got)
+	}
+	if got := list(commenter.ID); len(got) != 1 {
+		t.Errorf("commenter sees %v, want their visible comment", got)
+	}
+
+	if err := db.Model(&models.User{}).Where("id = ?", commenter.ID).Update("is_private", false).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Block{BlockerUserID: commenter.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := list(viewer.ID); len(got) != 0 {
+		t.Errorf("blocked viewer sees %v, want none", got)
+	}
+}
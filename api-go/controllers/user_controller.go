@@ -1,22 +1,89 @@
 package controllers
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/models"
 	"github.com/snap-point/api-go/utils"
 	"gorm.io/gorm"
 )
 
 type UserController struct {
-	DB *gorm.DB
+	DB               *gorm.DB
+	UploadController *UploadController
 }
 
-func NewUserController(db *gorm.DB) *UserController {
-	return &UserController{DB: db}
+type UserActivityQuery struct {
+	ActivityType string `form:"activityType"`
+	From         string `form:"from"`
+	To           string `form:"to"`
+	Page         int    `form:"page,default=1" binding:"min=1"`
+	PageSize     int    `form:"pageSize,default=20" binding:"min=1,max=50"`
+}
+
+// UserListQuery paginates simple user lists such as blocked users
+type UserListQuery struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"pageSize,default=20" binding:"min=1,max=50"`
+}
+
+type BlockedUserItem struct {
+	PostUser
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+type MutedUserItem struct {
+	PostUser
+	MutedAt time.Time `json:"mutedAt"`
+}
+
+type ActivityPost struct {
+	ID           uint   `json:"id"`
+	Caption      string `json:"caption"`
+	EarnedPoints int64  `json:"earnedPoints"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	MediaType    string `json:"mediaType"`
+}
+
+type ActivityItem struct {
+	ID         uint          `json:"id"`
+	Activity   string        `json:"activity"`
+	Points     int           `json:"points"`
+	Latitude   float64       `json:"latitude"`
+	Longitude  float64       `json:"longitude"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	IsSeen     bool          `json:"isSeen"`
+	SeenAt     *time.Time    `json:"seenAt,omitempty"`
+	Place      *PostPlace    `json:"place,omitempty"`
+	Post       *ActivityPost `json:"post,omitempty"`
+	TargetUser *PostUser     `json:"targetUser,omitempty"`
+}
+
+// validActivityTypes lists the activity values accepted by the activity filter.
+// Yorum ve kaydetme tipleri ilgili endpointler eklendiğinde kullanılacak.
+var validActivityTypes = map[string]bool{
+	"post_created":     true,
+	"post_updated":     true,
+	"post_deleted":     true,
+	"post_liked":       true,
+	"user_followed":    true,
+	"place_visited":    true,
+	"place_discovered": true,
+	"post_commented":   true,
+	"comment_liked":    true,
+	"post_saved":       true,
+}
+
+func NewUserController(db *gorm.DB, uploadController *UploadController) *UserController {
+	return &UserController{DB: db, UploadController: uploadController}
 }
 
 func (uc *UserController) GetUserProfile(c *gin.Context) {
@@ -29,21 +96,16 @@ func (uc *UserController) GetUserProfile(c *gin.Context) {
 	userID := c.Param("userId")
 	
 	var targetUser models.User
-	if err := uc.DB.Preload("Following").Preload("Followers").First(&targetUser, userID).Error; err != nil {
+	if err := uc.DB.First(&targetUser, userID).Error; err != nil {
+		// Silinmiş hesap 410 ile ayırt edilir
+		if deleted, err := isSoftDeleted(uc.DB, &models.User{}, userID); err == nil && deleted {
+			respondDeleted(c, "User account has been deleted")
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	var stats struct {
-		PostsCount     int64 `json:"postsCount"`
-		FollowersCount int64 `json:"followersCount"`
-		FollowingCount int64 `json:"followingCount"`
-	}
-
-	uc.DB.Model(&models.Post{}).Where("user_id = ?", userID).Count(&stats.PostsCount)
-	uc.DB.Model(&models.Follow{}).Where("following_user_id = ? AND status = ?", userID, "accepted").Count(&stats.FollowersCount)
-	uc.DB.Model(&models.Follow{}).Where("follower_user_id = ? AND status = ?", userID, "accepted").Count(&stats.FollowingCount)
-
 	var isFollowing bool
 	var isFollowRequestPending bool
 	if currentUser.UserID != targetUser.ID {
@@ -79,9 +141,9 @@ func (uc *UserController) GetUserProfile(c *gin.Context) {
 			"isOwnProfile":     isOwnProfile,
 			"isFollowing":      isFollowing,
 			"isFollowPending":  isFollowRequestPending,
-			"postsCount":       stats.PostsCount,
-			"followersCount":   stats.FollowersCount,
-			"followingCount":   stats.FollowingCount,
+			"postsCount":       targetUser.PostsCount,
+			"followersCount":   targetUser.FollowersCount,
+			"followingCount":   targetUser.FollowingCount,
 		},
 	})
 }
@@ -93,8 +155,8 @@ func (uc *UserController) SearchUsers(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
 	offset := (page - 1) * pageSize
 
 	var users []struct {
@@ -108,7 +170,8 @@ func (uc *UserController) SearchUsers(c *gin.Context) {
 		PostsCount   int64  `json:"postsCount"`
 	}
 
-	searchPattern := "%" + query + "%"
+	searchPattern := "%" + escapeLike(query) + "%"
+	relevance, relevanceArgs := searchRelevanceCase("users.username", query)
 	
 	uc.DB.Table("users").
 		Select(`
@@ -119,13 +182,13 @@ func (uc *UserController) SearchUsers(c *gin.Context) {
 			users.avatar,
 			users.is_verified,
 			users.total_points,
-			COUNT(posts.id) as posts_count
-		`).
-		Joins("LEFT JOIN posts ON posts.user_id = users.id").
-		Where("users.username ILIKE ? OR users.first_name ILIKE ? OR users.last_name ILIKE ?", 
+			users.posts_count,
+			`+relevance+` as relevance
+		`, relevanceArgs...).
+		Where(`users.username ILIKE ? ESCAPE '\' OR users.first_name ILIKE ? ESCAPE '\' OR users.last_name ILIKE ? ESCAPE '\'`,
 			searchPattern, searchPattern, searchPattern).
-		Group("users.id").
-		Order("users.total_points DESC, posts_count DESC").
+		// Önce eşleşme kalitesi (tam > önek > içerir), puan sadece eşitlikte belirleyici
+		Order("relevance, users.total_points DESC, users.posts_count DESC").
 		Offset(offset).
 		Limit(pageSize).
 		Scan(&users)
@@ -146,7 +209,7 @@ func (uc *UserController) GetSuggestedUsers(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	limit := clampPageSize(c.Query("limit"), 10, config.GetMaxPageSize())
 
 	var suggestedUsers []struct {
 		ID          uint   `json:"id"`
@@ -202,7 +265,7 @@ func (uc *UserController) GetUsersByUsername(c *gin.Context) {
 
 	uc.DB.Table("users").
 		Select("id, username, first_name, last_name, avatar, is_verified, total_points").
-		Where("username ILIKE ?", "%"+username+"%").
+		Where(`username ILIKE ? ESCAPE '\'`, "%"+escapeLike(username)+"%").
 		Order("total_points DESC").
 		Limit(20).
 		Scan(&users)
@@ -213,6 +276,16 @@ func (uc *UserController) GetUsersByUsername(c *gin.Context) {
 	})
 }
 
+// GetNearbyUsers godoc
+// @Summary List nearby users who opted in to location sharing
+// @Description Proximity is based on each user's most recent public post. Only users with shareLocation enabled, public accounts and no block in either direction are included; distances are bucketed.
+// @Tags users
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius query number false "Radius in km (default: 10, max: 50)"
+// @Success 200 {object} map[string]interface{}
+// @Router /users/nearby [get]
 func (uc *UserController) GetNearbyUsers(c *gin.Context) {
 	currentUser := utils.GetUser(c)
 	if currentUser == nil {
@@ -222,60 +295,96 @@ func (uc *UserController) GetNearbyUsers(c *gin.Context) {
 
 	lat, _ := strconv.ParseFloat(c.Query("lat"), 64)
 	lng, _ := strconv.ParseFloat(c.Query("lng"), 64)
-	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64)
+	radius, err := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64)
+	if err != nil || radius <= 0 {
+		radius = 10
+	}
+	if radius > maxNearbyUsersRadius {
+		radius = maxNearbyUsersRadius
+	}
 
 	if lat == 0 || lng == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Latitude and longitude are required"})
 		return
 	}
 
-	var nearbyUsers []struct {
-		ID          uint    `json:"id"`
-		Username    string  `json:"username"`
-		FirstName   string  `json:"firstName"`
-		LastName    string  `json:"lastName"`
-		Avatar      string  `json:"avatar"`
-		IsVerified  bool    `json:"isVerified"`
-		TotalPoints int64   `json:"totalPoints"`
-		Distance    float64 `json:"distance"`
-		LastSeen    string  `json:"lastSeen"`
+	// Susturulan kullanıcılar yakındakiler listesinde gösterilmez
+	mutedCond, mutedArgs := notMutedCondition("users.id", currentUser.UserID)
+
+	var rawUsers []struct {
+		ID          uint    `gorm:"column:id"`
+		Username    string  `gorm:"column:username"`
+		FirstName   string  `gorm:"column:first_name"`
+		LastName    string  `gorm:"column:last_name"`
+		Avatar      string  `gorm:"column:avatar"`
+		IsVerified  bool    `gorm:"column:is_verified"`
+		TotalPoints int64   `gorm:"column:total_points"`
+		Distance    float64 `gorm:"column:distance"`
 	}
 
-	uc.DB.Table("users").
-		Select(`
-			DISTINCT users.id,
-			users.username,
-			users.first_name,
-			users.last_name,
-			users.avatar,
-			users.is_verified,
-			users.total_points,
-			ROUND(
-				6371 * acos(
-					cos(radians(?)) * 
-					cos(radians(posts.latitude)) * 
-					cos(radians(posts.longitude) - radians(?)) + 
-					sin(radians(?)) * 
-					sin(radians(posts.latitude))
-				)::numeric, 2
-			) AS distance,
-			MAX(posts.created_at)::text as last_seen
-		`, lat, lng, lat).
-		Joins("JOIN posts ON posts.user_id = users.id").
-		Where(`
-			users.id != ? AND
-			6371 * acos(
-				cos(radians(?)) * 
-				cos(radians(posts.latitude)) * 
-				cos(radians(posts.longitude) - radians(?)) + 
-				sin(radians(?)) * 
-				sin(radians(posts.latitude))
-			) <= ?
-		`, currentUser.UserID, lat, lng, lat, radius).
-		Group("users.id").
-		Order("distance ASC, users.total_points DESC").
+	// Konum yalnızca kullanıcının en son herkese açık gönderisinden alınır
+	latestPosts := uc.DB.Raw(`
+		SELECT DISTINCT ON (posts.user_id) posts.user_id, posts.latitude, posts.longitude
+		FROM posts
+		WHERE posts.deleted_at IS NULL AND posts.is_public = true AND posts.is_archived = false
+		ORDER BY posts.user_id, posts.created_at DESC`)
+
+	distanceExpr := `6371 * acos(LEAST(1, 
+		cos(radians(?)) * cos(radians(latest.latitude)) * 
+		cos(radians(latest.longitude) - radians(?)) + 
+		sin(radians(?)) * sin(radians(latest.latitude))))`
+
+	err = uc.DB.Table("(?) as latest", latestPosts).
+		Select(`users.id, users.username, users.first_name, users.last_name, users.avatar,
+			users.is_verified, users.total_points, `+distanceExpr+` AS distance`, lat, lng, lat).
+		Joins("JOIN users ON users.id = latest.user_id").
+		Where("users.id != ? AND users.deleted_at IS NULL", currentUser.UserID).
+		Where("users.share_location = true AND users.is_private = false").
+		Where(`NOT EXISTS(SELECT 1 FROM blocks WHERE blocks.deleted_at IS NULL AND
+			((blocks.blocker_user_id = ? AND blocks.blocked_user_id = users.id) OR
+			 (blocks.blocker_user_id = users.id AND blocks.blocked_user_id = ?)))`, currentUser.UserID, currentUser.UserID).
+		Where(mutedCond, mutedArgs...).
+		Where(distanceExpr+" <= ?", lat, lng, lat, radius).
+		Order("distance ASC").
 		Limit(50).
-		Scan(&nearbyUsers)
+		Scan(&rawUsers).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching nearby users"})
+		return
+	}
+
+	type NearbyUser struct {
+		ID          uint   `json:"id"`
+		Username    string `json:"username"`
+		FirstName   string `json:"firstName"`
+		LastName    string `json:"lastName"`
+		Avatar      string `json:"avatar"`
+		IsVerified  bool   `json:"isVerified"`
+		TotalPoints int64  `json:"totalPoints"`
+		Distance    string `json:"distance"` // "<1km", "1-5km", ...
+	}
+
+	// Kesin mesafe ve sıralama dışarı sızmasın: aynı kovadakiler puana göre sıralanır
+	nearbyUsers := make([]NearbyUser, len(rawUsers))
+	for i, raw := range rawUsers {
+		nearbyUsers[i] = NearbyUser{
+			ID:          raw.ID,
+			Username:    raw.Username,
+			FirstName:   raw.FirstName,
+			LastName:    raw.LastName,
+			Avatar:      raw.Avatar,
+			IsVerified:  raw.IsVerified,
+			TotalPoints: raw.TotalPoints,
+			Distance:    distanceBucket(raw.Distance),
+		}
+	}
+	sort.SliceStable(nearbyUsers, func(i, j int) bool {
+		bi, bj := distanceBucketIndex(nearbyUsers[i].Distance), distanceBucketIndex(nearbyUsers[j].Distance)
+		if bi != bj {
+			return bi < bj
+		}
+		return nearbyUsers[i].TotalPoints > nearbyUsers[j].TotalPoints
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
@@ -288,9 +397,42 @@ func (uc *UserController) GetNearbyUsers(c *gin.Context) {
 	})
 }
 
+// maxNearbyUsersRadius yakındaki kullanıcılar için en fazla arama yarıçapı (km)
+const maxNearbyUsersRadius = 50.0
+
+// nearbyDistanceBuckets upper bounds (km) and labels used to fuzz user distances
+var nearbyDistanceBuckets = []struct {
+	maxKm float64
+	label string
+}{
+	{1, "<1km"},
+	{5, "1-5km"},
+	{10, "5-10km"},
+	{25, "10-25km"},
+	{math.MaxFloat64, "25km+"},
+}
+
+func distanceBucket(km float64) string {
+	for _, bucket := range nearbyDistanceBuckets {
+		if km < bucket.maxKm {
+			return bucket.label
+		}
+	}
+	return nearbyDistanceBuckets[len(nearbyDistanceBuckets)-1].label
+}
+
+func distanceBucketIndex(label string) int {
+	for i, bucket := range nearbyDistanceBuckets {
+		if bucket.label == label {
+			return i
+		}
+	}
+	return len(nearbyDistanceBuckets)
+}
+
 func (uc *UserController) GetTopUsers(c *gin.Context) {
 	timeFilter := c.DefaultQuery("timeFilter", "all_time")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	limit := clampPageSize(c.Query("limit"), 50, config.GetMaxPageSize())
 
 	var topUsers []struct {
 		ID          uint   `json:"id"`
@@ -364,13 +506,32 @@ func (uc *UserController) BlockUser(c *gin.Context) {
 			BlockedUserID: targetUser.ID,
 		}
 
-		if err := uc.DB.Create(&block).Error; err != nil {
+		tx := uc.DB.Begin()
+
+		if err := tx.Create(&block).Error; err != nil {
+			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
 			return
 		}
 
-		uc.DB.Where("(follower_user_id = ? AND following_user_id = ?) OR (follower_user_id = ? AND following_user_id = ?)",
-			currentUser.UserID, targetUserID, targetUserID, currentUser.UserID).Delete(&models.Follow{})
+		// Engelleme her iki yöndeki takip ilişkisini (bekleyen istekler dahil) kaldırır
+		var follows []models.Follow
+		if err := tx.Where("(follower_user_id = ? AND following_user_id = ?) OR (follower_user_id = ? AND following_user_id = ?)",
+			currentUser.UserID, targetUser.ID, targetUser.ID, currentUser.UserID).Find(&follows).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove follow relationships"})
+			return
+		}
+		if err := removeFollows(tx, follows); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove follow relationships"})
+			return
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block user"})
+			return
+		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
@@ -391,6 +552,234 @@ func (uc *UserController) BlockUser(c *gin.Context) {
 	}
 }
 
+// GetBlockedUsers godoc
+// @Summary List users blocked by the current user
+// @Description Returns paginated blocked users, most recently blocked first. Unblock via POST /users/{userId}/block.
+// @Tags users
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20, max: 50)"
+// @Success 200 {object} StandardResponse{data=[]BlockedUserItem}
+// @Router /users/me/blocked [get]
+func (uc *UserController) GetBlockedUsers(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var query UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	db := uc.DB.Table("blocks").
+		Joins("JOIN users ON users.id = blocks.blocked_user_id AND users.deleted_at IS NULL").
+		Where("blocks.blocker_user_id = ? AND blocks.deleted_at IS NULL", currentUser.UserID)
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting blocked users"})
+		return
+	}
+
+	var rawUsers []struct {
+		ID        uint      `gorm:"column:id"`
+		Username  string    `gorm:"column:username"`
+		FirstName string    `gorm:"column:first_name"`
+		LastName  string    `gorm:"column:last_name"`
+		Avatar    string    `gorm:"column:avatar"`
+		BlockedAt time.Time `gorm:"column:blocked_at"`
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Select("users.id, users.username, users.first_name, users.last_name, users.avatar, blocks.created_at as blocked_at").
+		Order("blocks.created_at DESC, blocks.id DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Scan(&rawUsers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching blocked users"})
+		return
+	}
+
+	users := make([]BlockedUserItem, len(rawUsers))
+	for i, raw := range rawUsers {
+		users[i] = BlockedUserItem{
+			PostUser: PostUser{
+				ID:        raw.ID,
+				Username:  raw.Username,
+				FirstName: raw.FirstName,
+				LastName:  raw.LastName,
+				Avatar:    raw.Avatar,
+			},
+			BlockedAt: raw.BlockedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    users,
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		},
+	})
+}
+
+// MuteUser godoc
+// @Summary Toggle muting a user
+// @Description Hides the user's posts from the current user's feed and nearby results without blocking them. The muted user is not notified.
+// @Tags users
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /users/{userId}/mute [post]
+func (uc *UserController) MuteUser(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	targetUserID := c.Param("userId")
+
+	if strconv.Itoa(int(currentUser.UserID)) == targetUserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot mute yourself"})
+		return
+	}
+
+	var targetUser models.User
+	if err := uc.DB.First(&targetUser, targetUserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var existingMute models.Mute
+	result := uc.DB.Where("muter_user_id = ? AND muted_user_id = ?", currentUser.UserID, targetUser.ID).First(&existingMute)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		mute := models.Mute{
+			MuterUserID: currentUser.UserID,
+			MutedUserID: targetUser.ID,
+		}
+		if err := uc.DB.Create(&mute).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mute user"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "User muted successfully",
+			"muted":   true,
+		})
+	} else if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check mute status"})
+	} else {
+		if err := uc.DB.Delete(&existingMute).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unmute user"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "User unmuted successfully",
+			"muted":   false,
+		})
+	}
+}
+
+// GetMutedUsers godoc
+// @Summary List users muted by the current user
+// @Description Returns paginated muted users, most recently muted first. Unmute via POST /users/{userId}/mute.
+// @Tags users
+// @Produce json
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20, max: 50)"
+// @Success 200 {object} StandardResponse{data=[]MutedUserItem}
+// @Router /users/me/muted [get]
+func (uc *UserController) GetMutedUsers(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	var query UserListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, StandardResponse{Success: false, Message: err.Error()})
+		return
+	}
+
+	db := uc.DB.Table("mutes").
+		Joins("JOIN users ON users.id = mutes.muted_user_id AND users.deleted_at IS NULL").
+		Where("mutes.muter_user_id = ? AND mutes.deleted_at IS NULL", currentUser.UserID)
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error counting muted users"})
+		return
+	}
+
+	var rawUsers []struct {
+		ID        uint      `gorm:"column:id"`
+		Username  string    `gorm:"column:username"`
+		FirstName string    `gorm:"column:first_name"`
+		LastName  string    `gorm:"column:last_name"`
+		Avatar    string    `gorm:"column:avatar"`
+		MutedAt   time.Time `gorm:"column:muted_at"`
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := db.Select("users.id, users.username, users.first_name, users.last_name, users.avatar, mutes.created_at as muted_at").
+		Order("mutes.created_at DESC, mutes.id DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Scan(&rawUsers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{Success: false, Message: "Error fetching muted users"})
+		return
+	}
+
+	users := make([]MutedUserItem, len(rawUsers))
+	for i, raw := range rawUsers {
+		users[i] = MutedUserItem{
+			PostUser: PostUser{
+				ID:        raw.ID,
+				Username:  raw.Username,
+				FirstName: raw.FirstName,
+				LastName:  raw.LastName,
+				Avatar:    raw.Avatar,
+			},
+			MutedAt: raw.MutedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    users,
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		},
+	})
+}
+
+// notMutedCondition excludes rows whose userColumn belongs to a user the viewer has muted
+func notMutedCondition(userColumn string, viewerID uint) (string, []interface{}) {
+	return "NOT EXISTS(SELECT 1 FROM mutes WHERE mutes.deleted_at IS NULL AND mutes.muter_user_id = ? AND mutes.muted_user_id = " +
+		userColumn + ")", []interface{}{viewerID}
+}
+
 func (uc *UserController) ReportUser(c *gin.Context) {
 	currentUser := utils.GetUser(c)
 	if currentUser == nil {
@@ -415,6 +804,10 @@ func (uc *UserController) ReportUser(c *gin.Context) {
 		return
 	}
 
+	if !requireAccountAge(c, uc.DB, currentUser, config.AccountAgeActionReport) {
+		return
+	}
+
 	var targetUser models.User
 	if err := uc.DB.First(&targetUser, targetUserID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -448,26 +841,408 @@ func (uc *UserController) GetUserActivity(c *gin.Context) {
 	}
 
 	userID := c.Param("userId")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
-	offset := (page - 1) * pageSize
-
 	if strconv.Itoa(int(currentUser.UserID)) != userID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Can only view own activity"})
 		return
 	}
 
+	var query UserActivityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := uc.DB.Model(&models.ActivityLog{}).Where("user_id = ?", userID)
+
+	// Aktivite tipi filtresi (virgülle ayrılmış birden fazla tip desteklenir)
+	if query.ActivityType != "" {
+		activityTypes := strings.Split(query.ActivityType, ",")
+		for i, activityType := range activityTypes {
+			activityTypes[i] = strings.TrimSpace(activityType)
+			if !validActivityTypes[activityTypes[i]] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity type: " + activityTypes[i]})
+				return
+			}
+		}
+		db = db.Where("activity IN ?", activityTypes)
+	}
+
+	// Tarih aralığı filtresi
+	if query.From != "" {
+		from, err := parseActivityDate(query.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		db = db.Where("created_at >= ?", from)
+	}
+	if query.To != "" {
+		to, err := parseActivityDate(query.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		// Sadece tarih verildiyse o günün tamamını dahil et
+		if len(query.To) == len("2006-01-02") {
+			to = to.AddDate(0, 0, 1)
+		}
+		db = db.Where("created_at < ?", to)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error counting activities",
+		})
+		return
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+
 	var activities []models.ActivityLog
-	uc.DB.Where("user_id = ?", userID).
-		Order("created_at DESC").
+	if err := db.Order("created_at DESC").
 		Offset(offset).
-		Limit(pageSize).
-		Find(&activities)
+		Limit(query.PageSize).
+		Find(&activities).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching activities",
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"activities": activities,
-		"page":       page,
-		"pageSize":   pageSize,
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    uc.hydrateActivities(currentUser.UserID, activities),
+		Meta: gin.H{
+			"activityType": query.ActivityType,
+			"from":         query.From,
+			"to":           query.To,
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: query.Page,
+			PageSize:    query.PageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(query.PageSize))),
+		},
+	})
+}
+
+// hydrateActivities resolves the place, post and target user references of
+// the given activity rows with one query per reference type. Posts the viewer
+// may no longer see are left out.
+func (uc *UserController) hydrateActivities(viewerID uint, activities []models.ActivityLog) []ActivityItem {
+	placeIDs := make([]uint, 0)
+	postIDs := make([]uint, 0)
+	targetUserIDs := make([]uint, 0)
+	for _, activity := range activities {
+		if activity.PlaceID != 0 {
+			placeIDs = append(placeIDs, activity.PlaceID)
+		}
+		if activity.PostID != 0 {
+			postIDs = append(postIDs, activity.PostID)
+		}
+		if activity.TargetUserID != nil {
+			targetUserIDs = append(targetUserIDs, *activity.TargetUserID)
+		}
+	}
+
+	places := make(map[uint]PostPlace)
+	if len(placeIDs) > 0 {
+		var rawPlaces []PostPlace
+		uc.DB.Model(&models.Place{}).
+			Select("id, name, address, place_image as image").
+			Where("id IN ?", placeIDs).
+			Scan(&rawPlaces)
+		for _, place := range rawPlaces {
+			places[place.ID] = place
+		}
+	}
+
+	posts := make(map[uint]ActivityPost)
+	if len(postIDs) > 0 {
+		var rawPosts []ActivityPost
+		visibility, visibilityArgs := visiblePostsCondition(viewerID)
+		uc.DB.Model(&models.Post{}).
+			Select(`
+				posts.id,
+				posts.post_caption as caption,
+				posts.earned_points,
+				(SELECT media_url FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as thumbnail_url,
+				(SELECT media_type FROM post_media WHERE post_media.post_id = posts.id ORDER BY order_index LIMIT 1) as media_type
+			`).
+			Joins("JOIN users ON posts.user_id = users.id").
+			Where("posts.id IN ?", postIDs).
+			Where(visibility, visibilityArgs...).
+			Scan(&rawPosts)
+		for _, post := range rawPosts {
+			post.ThumbnailURL = uc.UploadController.mediaReadURL(post.ThumbnailURL)
+			posts[post.ID] = post
+		}
+	}
+
+	targetUsers := make(map[uint]PostUser)
+	if len(targetUserIDs) > 0 {
+		var rawUsers []PostUser
+		uc.DB.Model(&models.User{}).
+			Select("id, username, first_name, last_name, avatar").
+			Where("id IN ?", targetUserIDs).
+			Scan(&rawUsers)
+		for _, targetUser := range rawUsers {
+			targetUsers[targetUser.ID] = targetUser
+		}
+	}
+
+	items := make([]ActivityItem, len(activities))
+	for i, activity := range activities {
+		items[i] = ActivityItem{
+			ID:        activity.ID,
+			Activity:  activity.Activity,
+			Points:    activity.Points,
+			Latitude:  activity.Latitude,
+			Longitude: activity.Longitude,
+			CreatedAt: activity.CreatedAt,
+			IsSeen:    activity.IsSeen,
+			SeenAt:    activity.SeenAt,
+		}
+		if place, ok := places[activity.PlaceID]; ok {
+			items[i].Place = &place
+		}
+		if post, ok := posts[activity.PostID]; ok {
+			items[i].Post = &post
+		}
+		if activity.TargetUserID != nil {
+			if targetUser, ok := targetUsers[*activity.TargetUserID]; ok {
+				items[i].TargetUser = &targetUser
+			}
+		}
+	}
+
+	return items
+}
+
+// parseActivityDate accepts either an RFC3339 timestamp or a plain YYYY-MM-DD date.
+func parseActivityDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// PointsTimelineQuery; from/to RFC3339 veya YYYY-MM-DD kabul eder
+type PointsTimelineQuery struct {
+	Granularity string `form:"granularity,default=day" binding:"oneof=day week month"`
+	From        string `form:"from"`
+	To          string `form:"to"`
+}
+
+// PointsTimelineBucket holds the points earned in one period and the running total at its end
+type PointsTimelineBucket struct {
+	Period     time.Time `json:"period"`
+	Points     int64     `json:"points"`
+	Cumulative int64     `json:"cumulative"`
+}
+
+// Tek istekte dönebilecek en fazla dönem sayısı
+const maxTimelineBuckets = 366
+
+// GetPointsTimeline godoc
+// @Summary Get a user's earned points over time
+// @Description Buckets post and check-in points by day, week or month (UTC). Empty periods are returned as zero.
+// @Tags users
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param granularity query string false "day, week or month (default: day)"
+// @Param from query string false "Start date (default: 30 days, 12 weeks or 12 months back)"
+// @Param to query string false "End date, inclusive for plain dates (default: now)"
+// @Success 200 {object} StandardResponse
+// @Router /users/{userId}/points-timeline [get]
+func (uc *UserController) GetPointsTimeline(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+		return
+	}
+
+	var query PointsTimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var owner models.User
+	if err := uc.DB.Select("id, is_private").First(&owner, c.Param("userId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if allowed, err := canViewUserContent(uc.DB, currentUser.UserID, owner); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking access"})
+		return
+	} else if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This account is private"})
+		return
+	}
+
+	to := time.Now().UTC()
+	if query.To != "" {
+		parsed, err := parseActivityDate(query.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, use RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		// Sadece tarih verildiyse o günün tamamını dahil et
+		if len(query.To) == len("2006-01-02") {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = parsed.UTC()
+	}
+
+	var from time.Time
+	if query.From != "" {
+		parsed, err := parseActivityDate(query.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, use RFC3339 or YYYY-MM-DD"})
+			return
+		}
+		from = parsed.UTC()
+	} else {
+		switch query.Granularity {
+		case "week":
+			from = to.AddDate(0, 0, -7*12)
+		case "month":
+			from = to.AddDate(0, -12, 0)
+		default:
+			from = to.AddDate(0, 0, -30)
+		}
+	}
+
+	// Dönemler ilk dönemin başından itibaren hesaplanır
+	from = truncateToPeriod(from, query.Granularity)
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	var periods []time.Time
+	for period := from; period.Before(to); period = nextPeriod(period, query.Granularity) {
+		if len(periods) == maxTimelineBuckets {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Date range is too large, at most %d periods are allowed", maxTimelineBuckets)})
+			return
+		}
+		periods = append(periods, period)
+	}
+
+	// Gönderi puanları ve check-in puanları birlikte sayılır; silinen gönderilerin puanı düşülmüş olduğundan dahil edilmez
+	earnedPoints := `
+		SELECT created_at, earned_points AS points FROM posts
+		WHERE user_id = ? AND deleted_at IS NULL AND created_at < ?
+		UNION ALL
+		SELECT created_at, points FROM activity_logs
+		WHERE user_id = ? AND activity = 'place_visited' AND deleted_at IS NULL AND created_at < ?`
+	earnedArgs := []interface{}{owner.ID, to, owner.ID, to}
+
+	var startingPoints int64
+	if err := uc.DB.Raw("SELECT COALESCE(SUM(points), 0) FROM ("+earnedPoints+") earned WHERE created_at < ?",
+		append(earnedArgs, from)...).Scan(&startingPoints).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching points timeline"})
+		return
+	}
+
+	var rows []struct {
+		Period time.Time `gorm:"column:period"`
+		Points int64     `gorm:"column:points"`
+	}
+	if err := uc.DB.Raw(`SELECT date_trunc(?, created_at AT TIME ZONE 'UTC') AS period, SUM(points) AS points
+		FROM (`+earnedPoints+`) earned
+		WHERE created_at >= ?
+		GROUP BY period`,
+		append(append([]interface{}{query.Granularity}, earnedArgs...), from)...).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching points timeline"})
+		return
+	}
+
+	pointsByPeriod := make(map[time.Time]int64, len(rows))
+	for _, row := range rows {
+		period := row.Period
+		pointsByPeriod[time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, time.UTC)] += row.Points
+	}
+
+	buckets := make([]PointsTimelineBucket, len(periods))
+	cumulative := startingPoints
+	var totalInRange int64
+	for i, period := range periods {
+		points := pointsByPeriod[period]
+		cumulative += points
+		totalInRange += points
+		buckets[i] = PointsTimelineBucket{
+			Period:     period,
+			Points:     points,
+			Cumulative: cumulative,
+		}
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    buckets,
+		Meta: gin.H{
+			"granularity":    query.Granularity,
+			"from":           from,
+			"to":             to,
+			"startingPoints": startingPoints,
+			"totalPoints":    totalInRange,
+		},
 	})
-} 
\ No newline at end of file
+}
+
+// canViewUserContent reports whether the viewer may see a user's private data:
+// always for the owner, never across a block, and for private accounts only
+// for accepted followers.
+func canViewUserContent(db *gorm.DB, viewerID uint, owner models.User) (bool, error) {
+	if viewerID == owner.ID {
+		return true, nil
+	}
+
+	blocked, err := isBlockedBetween(db, viewerID, owner.ID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	if !owner.IsPrivate {
+		return true, nil
+	}
+
+	var followCount int64
+	err = db.Model(&models.Follow{}).
+		Where("follower_user_id = ? AND following_user_id = ? AND status = ?", viewerID, owner.ID, "accepted").
+		Count(&followCount).Error
+	return followCount > 0, err
+}
+
+// truncateToPeriod returns the UTC start of the day, ISO week (Monday) or
+// month containing t, matching PostgreSQL's date_trunc.
+func truncateToPeriod(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextPeriod returns the start of the period following period
+func nextPeriod(period time.Time, granularity string) time.Time {
+	switch granularity {
+	case "week":
+		return period.AddDate(0, 0, 7)
+	case "month":
+		return period.AddDate(0, 1, 0)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
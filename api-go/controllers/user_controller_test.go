@@ -0,0 +1,666 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+type userActivityResponse struct {
+	Data       []ActivityItem `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+func TestGetUserActivityFilters(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "activityowner")
+	other := createTestUser(t, db, "activityother")
+	place := createTestPlace(t, db, "activityfilterplace")
+	// activity_logs.post_id bir gönderiye referans vermek zorunda
+	post := createTestPost(t, db, user, place, "activity", true)
+
+	day := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	activities := []struct {
+		activity  string
+		createdAt time.Time
+	}{
+		{"post_created", day.AddDate(0, 0, -3)},
+		{"post_created", day},
+		{"post_liked", day.Add(time.Hour)},
+		{"user_followed", day.AddDate(0, 0, 2)},
+	}
+	ids := make(map[string][]uint)
+	for _, a := range activities {
+		activity := models.ActivityLog{
+			UserID:    user.ID,
+			PlaceID:   place.ID,
+			PostID:    post.ID,
+			Activity:  a.activity,
+			Latitude:  place.Latitude,
+			Longitude: place.Longitude,
+			CreatedAt: a.createdAt,
+		}
+		if a.activity == "user_followed" {
+			activity.TargetUserID = &other.ID
+		}
+		if err := db.Create(&activity).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[a.activity] = append(ids[a.activity], activity.ID)
+	}
+
+	uc := NewUserController(db, nil)
+	userParam := gin.Param{Key: "userId", Value: strconv.Itoa(int(user.ID))}
+	tests := []struct {
+		name  string
+		query string
+		want  []uint
+	}{
+		{"all", "", []uint{ids["user_followed"][0], ids["post_liked"][0], ids["post_created"][1], ids["post_created"][0]}},
+		{"single type", "?activityType=post_created", []uint{ids["post_created"][1], ids["post_created"][0]}},
+		{"several types", "?activityType=post_liked,user_followed", []uint{ids["user_followed"][0], ids["post_liked"][0]}},
+		// Sadece tarih verilen "to" o günün tamamını kapsar
+		{"date window", "?from=2024-05-10&to=2024-05-10", []uint{ids["post_liked"][0], ids["post_created"][1]}},
+		{"type and window", "?activityType=post_created&from=2024-05-01T00:00:00Z&to=2024-05-10T00:00:00Z", []uint{ids["post_created"][0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := callHandler(uc.GetUserActivity, http.MethodGet, "/users/"+userParam.Value+"/activity"+tt.query, nil, user.ID, userParam)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			var resp userActivityResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Pagination.TotalItems != int64(len(tt.want)) {
+				t.Errorf("totalItems = %d, want %d", resp.Pagination.TotalItems, len(tt.want))
+			}
+			if len(resp.Data) != len(tt.want) {
+				t.Fatalf("got %d activities, want %d", len(resp.Data), len(tt.want))
+			}
+			for i, item := range resp.Data {
+				if item.ID != tt.want[i] {
+					t.Errorf("activity %d = %d, want %d", i, item.ID, tt.want[i])
+				}
+				if item.Place == nil || item.Place.ID != place.ID {
+					t.Errorf("activity %d place = %+v, want place %d", i, item.Place, place.ID)
+				}
+				if item.Post == nil || item.Post.ID != post.ID {
+					t.Errorf("activity %d post = %+v, want post %d", i, item.Post, post.ID)
+				}
+				if item.Activity == "user_followed" && (item.TargetUser == nil || item.TargetUser.ID != other.ID) {
+					t.Errorf("activity %d target user = %+v, want user %d", i, item.TargetUser, other.ID)
+				}
+			}
+		})
+	}
+
+	w := callHandler(uc.GetUserActivity, http.MethodGet, "/users/"+userParam.Value+"/activity?activityType=bogus_type", nil, user.ID, userParam)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown activity type: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w = callHandler(uc.GetUserActivity, http.MethodGet, "/users/"+userParam.Value+"/activity", nil, other.ID, userParam)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("other user: status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestParseActivityDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-05-10", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), false},
+		{"2024-05-10T08:30:00Z", time.Date(2024, 5, 10, 8, 30, 0, 0, time.UTC), false},
+		{"2024-05-10T08:30:00+03:00", time.Date(2024, 5, 10, 5, 30, 0, 0, time.UTC), false},
+		{"10.05.2024", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseActivityDate(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseActivityDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseActivityDate(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBlockUserRemovesMutualFollow(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "blockme")
+	friend := createTestUser(t, db, "blockfriend")
+	bystander := createTestUser(t, db, "blockbystander")
+	for _, f := range []models.Follow{
+		{FollowerUserID: me.ID, FollowingUserID: friend.ID, Status: "accepted"},
+		{FollowerUserID: friend.ID, FollowingUserID: me.ID, Status: "accepted"},
+		{FollowerUserID: bystander.ID, FollowingUserID: me.ID, Status: "accepted"},
+	} {
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uc := NewUserController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(friend.ID))}
+	w := callHandler(uc.BlockUser, http.MethodPost, "/users/"+param.Value+"/block", nil, me.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	var between int64
+	db.Model(&models.Follow{}).
+		Where("(follower_user_id = ? AND following_user_id = ?) OR (follower_user_id = ? AND following_user_id = ?)", me.ID, friend.ID, friend.ID, me.ID).
+		Count(&between)
+	if between != 0 {
+		t.Errorf("follows between blocked users = %d, want 0", between)
+	}
+	var others int64
+	db.Model(&models.Follow{}).Where("follower_user_id = ?", bystander.ID).Count(&others)
+	if others != 1 {
+		t.Errorf("unrelated follows = %d, want 1", others)
+	}
+	var blocks int64
+	db.Model(&models.Block{}).Where("blocker_user_id = ? AND blocked_user_id = ?", me.ID, friend.ID).Count(&blocks)
+	if blocks != 1 {
+		t.Errorf("blocks = %d, want 1", blocks)
+	}
+}
+
+func TestGetUserProfileSkipsFollowRelations(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "profileviewer")
+	target := createTestUser(t, db, "profiletarget")
+	for i := 0; i < 3; i++ {
+		fan := createTestUser(t, db, fmt.Sprintf("profilefan%d", i))
+		if err := db.Create(&models.Follow{FollowerUserID: fan.ID, FollowingUserID: target.ID, Status: "accepted"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := reconcileUserCounts(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	if err := db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		queries = append(queries, tx.Statement.SQL.String())
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(target.ID))}
+	w := callHandler(NewUserController(db, nil).GetUserProfile, http.MethodGet, "/users/"+param.Value, nil, viewer.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			FollowersCount int64 `json:"followersCount"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.FollowersCount != 3 {
+		t.Errorf("followers count = %d, want 3", resp.Data.FollowersCount)
+	}
+	// Profil ve takip durumu tek satırlık sorgular; ilişki listeleri yüklenmez
+	for _, sql := range queries {
+		if !strings.Contains(sql, "LIMIT") {
+			t.Errorf("profile ran a multi-row query: %s", sql)
+		}
+	}
+}
+
+func TestFollowRelationsUseFollowsTable(t *testing.T) {
+	db := openTestDB(t)
+	fan := createTestUser(t, db, "relationfan")
+	star := createTestUser(t, db, "relationstar")
+	former := createTestUser(t, db, "relationformer")
+	follow := models.Follow{FollowerUserID: former.ID, FollowingUserID: star.ID, Status: "accepted"}
+	for _, f := range []*models.Follow{{FollowerUserID: fan.ID, FollowingUserID: star.ID, Status: "accepted"}, &follow} {
+		if err := db.Create(f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Silinen takip ilişkilerde görünmez
+	if err := db.Delete(&follow).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Follow{FollowerUserID: former.ID, FollowingUserID: fan.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded models.User
+	if err := db.Preload("Followers").Preload("Following").First(&loaded, fan.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Following) != 1 || loaded.Following[0].ID != star.ID {
+		t.Errorf("fan following = %+v, want the star", loaded.Following)
+	}
+	if len(loaded.Followers) != 1 || loaded.Followers[0].ID != former.ID {
+		t.Errorf("fan followers = %+v, want the former follower", loaded.Followers)
+	}
+	if err := db.Preload("Followers").First(&loaded, star.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Followers) != 1 || loaded.Followers[0].ID != fan.ID {
+		t.Errorf("star followers = %+v, want only the fan", loaded.Followers)
+	}
+
+	// Aynı çift silinen takibin ardından yeniden takip edebilir
+	if err := db.Create(&models.Follow{FollowerUserID: former.ID, FollowingUserID: star.ID, Status: "accepted"}).Error; err != nil {
+		t.Errorf("follow again after unfollowing: %v", err)
+	}
+}
+
+func TestGetBlockedUsers(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "blocklister")
+	first := createTestUser(t, db, "blockedfirst")
+	second := createTestUser(t, db, "blockedsecond")
+	other := createTestUser(t, db, "blockedbyother")
+	if err := db.Create(&models.Block{BlockerUserID: other.ID, BlockedUserID: first.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	uc := NewUserController(db, nil)
+	toggle := func(target models.User) {
+		t.Helper()
+		param := gin.Param{Key: "userId", Value: strconv.Itoa(int(target.ID))}
+		if w := callHandler(uc.BlockUser, http.MethodPost, "/users/"+param.Value+"/block", nil, me.ID, param); w.Code != http.StatusOK {
+			t.Fatalf("toggle block: status = %d, body = %s", w.Code, w.Body.String())
+		}
+	}
+	blocked := func() ([]uint, int64) {
+		t.Helper()
+		w := callHandler(uc.GetBlockedUsers, http.MethodGet, "/users/me/blocked", nil, me.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data       []BlockedUserItem `json:"data"`
+			Pagination PaginationMeta    `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids := []uint{}
+		for _, user := range resp.Data {
+			ids = append(ids, user.ID)
+		}
+		return ids, resp.Pagination.TotalItems
+	}
+
+	toggle(first)
+	toggle(second)
+	if ids, total := blocked(); !reflect.DeepEqual(ids, []uint{second.ID, first.ID}) || total != 2 {
+		t.Errorf("blocked = %v (total %d), want newest first [%d %d]", ids, total, second.ID, first.ID)
+	}
+
+	// Engeli kaldırınca listeden düşer
+	toggle(first)
+	if ids, total := blocked(); !reflect.DeepEqual(ids, []uint{second.ID}) || total != 1 {
+		t.Errorf("blocked after unblock = %v (total %d), want [%d]", ids, total, second.ID)
+	}
+}
+
+func TestMuteUser(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "muter")
+	muted := createTestUser(t, db, "mutedauthor")
+	other := createTestUser(t, db, "unmutedauthor")
+	place := createTestPlace(t, db, "muteplace")
+	mutedPost := createTestPost(t, db, muted, place, "muted", true)
+	otherPost := createTestPost(t, db, other, place, "visible", true)
+	for _, following := range []models.User{muted, other} {
+		if err := db.Create(&models.Follow{FollowerUserID: me.ID, FollowingUserID: following.ID, Status: "accepted"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uc := NewUserController(db, nil)
+	fc := NewFeedController(db)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(muted.ID))}
+	toggle := func(want bool) {
+		t.Helper()
+		w := callHandler(uc.MuteUser, http.MethodPost, "/users/"+param.Value+"/mute", nil, me.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("toggle mute: status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Muted bool `json:"muted"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Muted != want {
+			t.Fatalf("muted = %v, want %v", resp.Muted, want)
+		}
+	}
+	mutedList := func() []uint {
+		t.Helper()
+		w := callHandler(uc.GetMutedUsers, http.MethodGet, "/users/me/muted", nil, me.ID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []MutedUserItem `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		ids := []uint{}
+		for _, user := range resp.Data {
+			ids = append(ids, user.ID)
+		}
+		return ids
+	}
+	// Akış ve yakındakiler aynı koşulu kullanır
+	visiblePosts := func() []uint {
+		t.Helper()
+		cond, args := notMutedCondition("posts.user_id", me.ID)
+		var ids []uint
+		if err := db.Model(&models.Post{}).Where(cond, args...).Order("id").Pluck("id", &ids).Error; err != nil {
+			t.Fatal(err)
+		}
+		return ids
+	}
+
+	if w := callHandler(uc.MuteUser, http.MethodPost, "/users/me/mute", nil, me.ID, gin.Param{Key: "userId", Value: strconv.Itoa(int(me.ID))}); w.Code != http.StatusBadRequest {
+		t.Errorf("muting yourself: status = %d, want 400", w.Code)
+	}
+
+	toggle(true)
+	if ids := mutedList(); !reflect.DeepEqual(ids, []uint{muted.ID}) {
+		t.Errorf("muted = %v, want [%d]", ids, muted.ID)
+	}
+	if ids := visiblePosts(); !reflect.DeepEqual(ids, []uint{otherPost.ID}) {
+		t.Errorf("visible posts while muted = %v, want [%d]", ids, otherPost.ID)
+	}
+	if ids, _ := feedPostIDs(t, fc, "/feed", me.ID); !reflect.DeepEqual(ids, []uint{otherPost.ID}) {
+		t.Errorf("feed while muted = %v, want [%d]", ids, otherPost.ID)
+	}
+
+	// Profil susturmadan etkilenmez
+	w := callHandler(uc.GetUserProfile, http.MethodGet, "/users/"+param.Value+"/profile", nil, me.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("muted user's profile: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var profile struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &profile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.Data.ID != muted.ID {
+		t.Errorf("profile user = %d, want %d", profile.Data.ID, muted.ID)
+	}
+
+	// Susturma tek yönlüdür; susturulan kullanıcının görünümü değişmez
+	cond, args := notMutedCondition("posts.user_id", muted.ID)
+	var count int64
+	if err := db.Model(&models.Post{}).Where(cond, args...).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("muted user's view = %d posts, want 2", count)
+	}
+
+	toggle(false)
+	if ids := mutedList(); len(ids) != 0 {
+		t.Errorf("muted after unmute = %v, want none", ids)
+	}
+	if ids := visiblePosts(); !reflect.DeepEqual(ids, []uint{mutedPost.ID, otherPost.ID}) {
+		t.Errorf("visible posts after unmute = %v, want [%d %d]", ids, mutedPost.ID, otherPost.ID)
+	}
+	if ids, _ := feedPostIDs(t, fc, "/feed", me.ID); len(ids) != 2 {
+		t.Errorf("feed after unmute = %v, want both posts", ids)
+	}
+}
+
+func TestDistanceBucket(t *testing.T) {
+	tests := []struct {
+		km   float64
+		want string
+	}{
+		{0, "<1km"},
+		{0.99, "<1km"},
+		{1, "1-5km"},
+		{4.2, "1-5km"},
+		{7, "5-10km"},
+		{24.9, "10-25km"},
+		{25, "25km+"},
+		{49, "25km+"},
+	}
+	for _, tt := range tests {
+		if got := distanceBucket(tt.km); got != tt.want {
+			t.Errorf("distanceBucket(%v) = %q, want %q", tt.km, got, tt.want)
+		}
+	}
+}
+
+func TestGetNearbyUsersIsOptIn(t *testing.T) {
+	db := openTestDB(t)
+	viewer := createTestUser(t, db, "nearbyviewer")
+	near := createTestUser(t, db, "nearbyshared")
+	far := createTestUser(t, db, "nearbyfar")
+	hidden := createTestUser(t, db, "nearbyhidden")
+	private := createTestUser(t, db, "nearbyprivate")
+	blocker := createTestUser(t, db, "nearbyblocker")
+	moved := createTestUser(t, db, "nearbymoved")
+	place := createTestPlace(t, db, "nearbyplace")
+
+	for _, user := range []models.User{near, far, private, blocker, moved} {
+		if err := db.Model(&user).Update("share_location", true).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Block{BlockerUserID: blocker.ID, BlockedUserID: viewer.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(user models.User, lat float64, age time.Duration) {
+		t.Helper()
+		p := createTestPost(t, db, user, place, "here", true)
+		if err := db.Model(&p).Updates(map[string]interface{}{"latitude": lat, "created_at": time.Now().Add(-age)}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 0.027 derece enlem ~3 km
+	post(near, place.Latitude, time.Hour)
+	post(far, place.Latitude+0.027, time.Hour)
+	post(hidden, place.Latitude, time.Hour)
+	post(private, place.Latitude, time.Hour)
+	post(blocker, place.Latitude, time.Hour)
+	// Eski gönderi yakında, en yenisi 100 km uzakta
+	post(moved, place.Latitude, 48*time.Hour)
+	post(moved, place.Latitude+0.9, time.Hour)
+
+	target := fmt.Sprintf("/users/nearby?lat=%f&lng=%f&radius=10", place.Latitude, place.Longitude)
+	w := callHandler(NewUserController(db, nil).GetNearbyUsers, http.MethodGet, target, nil, viewer.ID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "lastSeen") {
+		t.Errorf("response exposes lastSeen: %s", w.Body.String())
+	}
+	var resp struct {
+		NearbyUsers []struct {
+			ID       uint   `json:"id"`
+			Distance string `json:"distance"`
+		} `json:"nearbyUsers"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := map[uint]string{}
+	for _, user := range resp.NearbyUsers {
+		got[user.ID] = user.Distance
+	}
+	want := map[uint]string{near.ID: "<1km", far.ID: "1-5km"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nearby users = %v, want %v (hidden %d, private %d, blocker %d, moved %d excluded)",
+			got, want, hidden.ID, private.ID, blocker.ID, moved.ID)
+	}
+}
+
+func TestGetUserActivityAcceptsCommentAndSaveTypes(t *testing.T) {
+	db := openTestDB(t)
+	user := createTestUser(t, db, "activitytypes")
+	place := createTestPlace(t, db, "activitytypesplace")
+	post := createTestPost(t, db, user, place, "activity", true)
+
+	ids := map[string]uint{}
+	for _, activity := range []string{"post_commented", "comment_liked", "post_saved", "post_created"} {
+		row := models.ActivityLog{UserID: user.ID, PlaceID: place.ID, PostID: post.ID, Activity: activity}
+		if err := db.Create(&row).Error; err != nil {
+			t.Fatal(err)
+		}
+		ids[activity] = row.ID
+	}
+
+	uc := NewUserController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(user.ID))}
+	w := callHandler(uc.GetUserActivity, http.MethodGet, "/users/"+param.Value+"/activity?activityType=post_commented,comment_liked,post_saved", nil, user.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp userActivityResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := map[uint]bool{}
+	for _, item := range resp.Data {
+		got[item.ID] = true
+	}
+	if len(got) != 3 || !got[ids["post_commented"]] || !got[ids["comment_liked"]] || !got[ids["post_saved"]] {
+		t.Errorf("activities = %v, want the comment and save rows %v", got, ids)
+	}
+}
+
+func TestTruncateToPeriod(t *testing.T) {
+	// 2024-03-14 bir perşembe
+	ts := time.Date(2024, 3, 14, 18, 30, 0, 0, time.FixedZone("TRT", 3*60*60))
+	tests := []struct {
+		granularity string
+		want        time.Time
+	}{
+		{"day", time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got := truncateToPeriod(ts, tt.granularity)
+		if !got.Equal(tt.want) {
+			t.Errorf("truncateToPeriod(%s) = %v, want %v", tt.granularity, got, tt.want)
+		}
+		if next := nextPeriod(got, tt.granularity); !next.After(ts) {
+			t.Errorf("nextPeriod(%s) = %v, want after %v", tt.granularity, next, ts)
+		}
+	}
+}
+
+func TestGetPointsTimeline(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "timelineowner")
+	stranger := createTestUser(t, db, "timelinestranger")
+	place := createTestPlace(t, db, "timelineplace")
+
+	addPost := func(points int64, at time.Time) {
+		t.Helper()
+		post := createTestPost(t, db, owner, place, "", true)
+		if err := db.Model(&post).Updates(map[string]interface{}{"earned_points": points, "created_at": at}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	addPost(7, day(1)) // aralıktan önce, başlangıç puanına eklenir
+	addPost(10, day(10))
+	addPost(5, day(10))
+	addPost(20, day(12))
+	checkIn := models.ActivityLog{UserID: owner.ID, PlaceID: place.ID, Activity: "place_visited", Points: 3, CreatedAt: day(12)}
+	if err := db.Create(&checkIn).Error; err != nil {
+		t.Fatal(err)
+	}
+	uc := NewUserController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(owner.ID))}
+
+	w := callHandler(uc.GetPointsTimeline, http.MethodGet, "/users/"+param.Value+"/points-timeline?from=2024-03-10&to=2024-03-13", nil, owner.ID, param)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []PointsTimelineBucket `json:"data"`
+		Meta struct {
+			StartingPoints int64 `json:"startingPoints"`
+			TotalPoints    int64 `json:"totalPoints"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	var sum int64
+	for _, bucket := range resp.Data {
+		got = append(got, bucket.Points)
+		sum += bucket.Points
+	}
+	if want := []int64{15, 0, 23, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("points = %v, want %v", got, want)
+	}
+	if resp.Meta.StartingPoints != 7 || sum != resp.Meta.TotalPoints {
+		t.Errorf("starting = %d, total = %d, bucket sum = %d", resp.Meta.StartingPoints, resp.Meta.TotalPoints, sum)
+	}
+	if last := resp.Data[len(resp.Data)-1].Cumulative; last != 7+sum {
+		t.Errorf("final cumulative = %d, want %d", last, 7+sum)
+	}
+
+	w = callHandler(uc.GetPointsTimeline, http.MethodGet, "/users/"+param.Value+"/points-timeline?granularity=month&from=2024-01-01&to=2024-03-31", nil, owner.ID, param)
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got = got[:0]
+	for _, bucket := range resp.Data {
+		got = append(got, bucket.Points)
+	}
+	if want := []int64{0, 0, 45}; !reflect.DeepEqual(got, want) {
+		t.Errorf("monthly points = %v, want %v", got, want)
+	}
+
+	if w := callHandler(uc.GetPointsTimeline, http.MethodGet, "/users/"+param.Value+"/points-timeline?from=2020-01-01&to=2024-01-01", nil, owner.ID, param); w.Code != http.StatusBadRequest {
+		t.Errorf("too many buckets: status = %d, want 400", w.Code)
+	}
+
+	if err := db.Model(&owner).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if w := callHandler(uc.GetPointsTimeline, http.MethodGet, "/users/"+param.Value+"/points-timeline", nil, stranger.ID, param); w.Code != http.StatusForbidden {
+		t.Errorf("private account, stranger: status = %d, want 403", w.Code)
+	}
+	follow := models.Follow{FollowerUserID: stranger.ID, FollowingUserID: owner.ID, Status: "accepted"}
+	if err := db.Create(&follow).Error; err != nil {
+		t.Fatal(err)
+	}
+	if w := callHandler(uc.GetPointsTimeline, http.MethodGet, "/users/"+param.Value+"/points-timeline", nil, stranger.ID, param); w.Code != http.StatusOK {
+		t.Errorf("private account, follower: status = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"log"
+	"time"
+
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+// adjustFollowCounts moves the cached following count of followerID and the
+// follower count of followingID by delta. Only accepted follows are counted.
+func adjustFollowCounts(tx *gorm.DB, followerID, followingID uint, delta int) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", followerID).
+		Update("following_count", gorm.Expr("GREATEST(following_count + ?, 0)", delta)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", followingID).
+		Update("followers_count", gorm.Expr("GREATEST(followers_count + ?, 0)", delta)).Error
+}
+
+// removeFollows deletes follows and takes the accepted ones out of the cached counts
+func removeFollows(tx *gorm.DB, follows []models.Follow) error {
+	for _, follow := range follows {
+		if err := tx.Delete(&follow).Error; err != nil {
+			return err
+		}
+		if follow.Status == "accepted" {
+			if err := adjustFollowCounts(tx, follow.FollowerUserID, follow.FollowingUserID, -1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// acceptFollowRequest turns a pending follow into an accepted one and counts it
+func acceptFollowRequest(tx *gorm.DB, follow *models.Follow) error {
+	if follow.Status != "pending" {
+		return nil
+	}
+	if err := tx.Model(follow).Update("status", "accepted").Error; err != nil {
+		return err
+	}
+	return adjustFollowCounts(tx, follow.FollowerUserID, follow.FollowingUserID, 1)
+}
+
+// adjustPostsCount moves the cached post count of userID by delta
+func adjustPostsCount(tx *gorm.DB, userID uint, delta int) error {
+	return tx.Model(&models.User{}).Where("id = ?", userID).
+		Update("posts_count", gorm.Expr("GREATEST(posts_count + ?, 0)", delta)).Error
+}
+
+// reconcileUserCounts recomputes the cached follower, following and post
+// counts from the follows and posts tables and returns how many users drifted.
+func reconcileUserCounts(db *gorm.DB) (int64, error) {
+	result := db.Exec(`
+		UPDATE users SET
+			followers_count = counts.followers,
+			following_count = counts.following,
+			posts_count = counts.posts
+		FROM (
+			SELECT users.id,
+				(SELECT COUNT(*) FROM follows WHERE follows.following_user_id = users.id AND follows.status = 'accepted' AND follows.deleted_at IS NULL) as followers,
+				(SELECT COUNT(*) FROM follows WHERE follows.follower_user_id = users.id AND follows.status = 'accepted' AND follows.deleted_at IS NULL) as following,
+				(SELECT COUNT(*) FROM posts WHERE posts.user_id = users.id AND posts.deleted_at IS NULL) as posts
+			FROM users
+		) counts
+		WHERE users.id = counts.id AND (
+			users.followers_count <> counts.followers OR
+			users.following_count <> counts.following OR
+			users.posts_count <> counts.posts
+		)
+	`)
+	return result.RowsAffected, result.Error
+}
+
+// StartCounterReconcileJob recomputes the cached profile counts at startup and
+// then every interval in the background
+func (ac *AdminController) StartCounterReconcileJob(interval time.Duration) {
+	reconcile := func() {
+		drifted, err := reconcileUserCounts(ac.DB)
+		if err != nil {
+			log.Printf("Counter reconcile job failed: %v", err)
+			return
+		}
+		if drifted > 0 {
+			log.Printf("Counter reconcile job: corrected %d users", drifted)
+		}
+	}
+
+	go func() {
+		// İlk çalıştırma yeni eklenen sütunları mevcut kullanıcılar için doldurur
+		reconcile()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			reconcile()
+		}
+	}()
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
+)
+
+// assertCounts compares a user's cached follow counts with the expected values
+func assertCounts(t *testing.T, db *gorm.DB, label string, user models.User, followers, following int64) {
+	t.Helper()
+	var stored models.User
+	if err := db.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.FollowersCount != followers || stored.FollowingCount != following {
+		t.Errorf("%s: %s followers/following = %d/%d, want %d/%d",
+			label, user.Username, stored.FollowersCount, stored.FollowingCount, followers, following)
+	}
+}
+
+func TestFollowCountsStayAccurate(t *testing.T) {
+	db := openTestDB(t)
+	me := createTestUser(t, db, "countme")
+	public := createTestUser(t, db, "countpublic")
+	private := createTestUser(t, db, "countprivate")
+	if err := db.Model(&private).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ic := NewInteractionController(db)
+	follow := func(follower, target models.User) {
+		t.Helper()
+		param := gin.Param{Key: "userId", Value: strconv.Itoa(int(target.ID))}
+		w := callHandler(ic.FollowUser, http.MethodPost, "/users/"+param.Value+"/follow", nil, follower.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("follow status = %d, body = %s", w.Code, w.Body.String())
+		}
+	}
+
+	follow(me, public)
+	assertCounts(t, db, "after follow", me, 0, 1)
+	assertCounts(t, db, "after follow", public, 1, 0)
+
+	// Bekleyen istek kabul edilene kadar sayılmaz
+	follow(me, private)
+	assertCounts(t, db, "after request", private, 0, 0)
+	accept := gin.Param{Key: "userId", Value: strconv.Itoa(int(me.ID))}
+	if w := callHandler(ic.AcceptFollowRequest, http.MethodPost, "/users/follow-requests/"+accept.Value+"/accept", nil, private.ID, accept); w.Code != http.StatusOK {
+		t.Fatalf("accept status = %d, body = %s", w.Code, w.Body.String())
+	}
+	// İkinci kabul bekleyen istek bulamaz ve sayaçları değiştirmez
+	if w := callHandler(ic.AcceptFollowRequest, http.MethodPost, "/users/follow-requests/"+accept.Value+"/accept", nil, private.ID, accept); w.Code != http.StatusNotFound {
+		t.Errorf("second accept status = %d, want 404", w.Code)
+	}
+	assertCounts(t, db, "after accept", me, 0, 2)
+	assertCounts(t, db, "after accept", private, 1, 0)
+
+	follow(me, public)
+	assertCounts(t, db, "after unfollow", me, 0, 1)
+	assertCounts(t, db, "after unfollow", public, 0, 0)
+
+	// Engelleme her iki yöndeki takibi sayaçlardan düşer
+	follow(private, me)
+	assertCounts(t, db, "before block", me, 1, 1)
+	uc := NewUserController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(private.ID))}
+	if w := callHandler(uc.BlockUser, http.MethodPost, "/users/"+param.Value+"/block", nil, me.ID, param); w.Code != http.StatusOK {
+		t.Fatalf("block status = %d, body = %s", w.Code, w.Body.String())
+	}
+	assertCounts(t, db, "after block", me, 0, 0)
+	assertCounts(t, db, "after block", private, 0, 0)
+}
+
+func TestReconcileUserCounts(t *testing.T) {
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "reconcileowner")
+	fan := createTestUser(t, db, "reconcilefan")
+	place := createTestPlace(t, db, "reconcileplace")
+	createTestPost(t, db, owner, place, "one", true)
+	createTestPost(t, db, owner, place, "two", true)
+	for _, f := range []models.Follow{
+		{FollowerUserID: fan.ID, FollowingUserID: owner.ID, Status: "accepted"},
+		{FollowerUserID: owner.ID, FollowingUserID: fan.ID, Status: "pending"},
+	} {
+		if err := db.Create(&f).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	drifted, err := reconcileUserCounts(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drifted != 2 {
+		t.Errorf("drifted users = %d, want 2", drifted)
+	}
+	assertCounts(t, db, "after reconcile", owner, 1, 0)
+	assertCounts(t, db, "after reconcile", fan, 0, 1)
+	var stored models.User
+	db.First(&stored, owner.ID)
+	if stored.PostsCount != 2 {
+		t.Errorf("posts count = %d, want 2", stored.PostsCount)
+	}
+
+	// Doğru sayaçlar yeniden yazılmaz
+	if drifted, err := reconcileUserCounts(db); err != nil || drifted != 0 {
+		t.Errorf("second run drifted = %d, err = %v, want 0", drifted, err)
+	}
+}
@@ -0,0 +1,338 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/models"
+	"github.com/snap-point/api-go/utils"
+)
+
+// Zaman akışı öğe tipleri
+const (
+	TimelineItemPost        = "post"
+	TimelineItemAchievement = "achievement"
+	TimelineItemMilestone   = "milestone"
+)
+
+// TimelineAchievement is an achievement unlocked by a post; currently the
+// discovery of a place, i.e. being the first to post there.
+type TimelineAchievement struct {
+	ID     uint      `json:"id"`
+	Kind   string    `json:"kind"` // activity tipi, ör. place_discovered
+	Points int       `json:"points"`
+	PostID uint      `json:"postId"`
+	Place  PostPlace `json:"place"`
+}
+
+// TimelineMilestone marks the moment the user's total points first reached a threshold
+type TimelineMilestone struct {
+	Points int64 `json:"points"`
+}
+
+// TimelineItem is one entry of a profile timeline; exactly one of Post,
+// Achievement and Milestone is set, matching Type.
+type TimelineItem struct {
+	Type        string               `json:"type"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	Post        *PostSummary         `json:"post,omitempty"`
+	Achievement *TimelineAchievement `json:"achievement,omitempty"`
+	Milestone   *TimelineMilestone   `json:"milestone,omitempty"`
+}
+
+// timelineAchievementActivities zaman akışında başarım olarak gösterilen aktivite tipleri
+var timelineAchievementActivities = []string{"place_discovered"}
+
+// timelineKey identifies a post or achievement row before it is loaded
+type timelineKey struct {
+	Type      string    `gorm:"column:type"`
+	ID        uint      `gorm:"column:id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// timelineKeysSQL unions the owner's visible posts with the achievements of
+// those posts. Both %[1]s placeholders take the visibility clause; arguments: the
+// owner ID, the owner ID, the achievement activities.
+const timelineKeysSQL = `
+	SELECT 'post' AS type, posts.id, posts.created_at
+	FROM posts
+	WHERE posts.user_id = ? AND posts.deleted_at IS NULL AND %[1]s
+	UNION ALL
+	SELECT 'achievement' AS type, activity_logs.id, activity_logs.created_at
+	FROM activity_logs
+	JOIN posts ON posts.id = activity_logs.post_id AND posts.deleted_at IS NULL
+	WHERE activity_logs.user_id = ? AND activity_logs.deleted_at IS NULL
+		AND activity_logs.activity IN ? AND %[1]s`
+
+// pointMilestonesSQL finds when the user's running point total first reached
+// each milestone. Points come from post_created and place_visited activities;
+// a deleted post takes its earned points back. Arguments: the user ID, the
+// user ID, the milestones as an array.
+const pointMilestonesSQL = `
+	WITH deltas AS (
+		SELECT activity_logs.id, activity_logs.created_at, activity_logs.points AS delta
+		FROM activity_logs
+		WHERE activity_logs.user_id = ? AND activity_logs.deleted_at IS NULL
+			AND activity_logs.activity IN ('post_created', 'place_visited')
+		UNION ALL
+		SELECT activity_logs.id, activity_logs.created_at, -posts.earned_points AS delta
+		FROM activity_logs
+		JOIN posts ON posts.id = activity_logs.post_id
+		WHERE activity_logs.user_id = ? AND activity_logs.deleted_at IS NULL
+			AND activity_logs.activity = 'post_deleted'
+	), running AS (
+		SELECT created_at, SUM(delta) OVER (ORDER BY created_at, id) AS total
+		FROM deltas
+	)
+	SELECT milestones.points, MIN(running.created_at) AS reached_at
+	FROM running
+	JOIN unnest(?::bigint[]) AS milestones(points) ON running.total >= milestones.points
+	GROUP BY milestones.points`
+
+// pointMilestones returns the milestones the user has reached, newest first
+func (pc *PostController) pointMilestones(userID uint) ([]TimelineItem, error) {
+	var rows []struct {
+		Points    int64     `gorm:"column:points"`
+		ReachedAt time.Time `gorm:"column:reached_at"`
+	}
+	if err := pc.DB.Raw(pointMilestonesSQL, userID, userID, pq.Int64Array(config.GetPointMilestones())).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]TimelineItem, len(rows))
+	for i, row := range rows {
+		items[i] = TimelineItem{
+			Type:      TimelineItemMilestone,
+			CreatedAt: row.ReachedAt,
+			Milestone: &TimelineMilestone{Points: row.Points},
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return timelineNewer(items[i], items[j]) })
+	return items, nil
+}
+
+// timelineNewer orders timeline items newest first; a milestone reached by a
+// post is listed above that post.
+func timelineNewer(a, b TimelineItem) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return timelineTypeOrder[a.Type] < timelineTypeOrder[b.Type]
+}
+
+var timelineTypeOrder = map[string]int{
+	TimelineItemMilestone:   0,
+	TimelineItemAchievement: 1,
+	TimelineItemPost:        2,
+}
+
+// loadTimelineItems turns post and achievement keys into timeline items
+func (pc *PostController) loadTimelineItems(viewerID uint, keys []timelineKey) ([]TimelineItem, error) {
+	var postIDs, achievementIDs []uint
+	for _, key := range keys {
+		if key.Type == TimelineItemPost {
+			postIDs = append(postIDs, key.ID)
+		} else {
+			achievementIDs = append(achievementIDs, key.ID)
+		}
+	}
+
+	posts := make(map[uint]PostSummary)
+	if len(postIDs) > 0 {
+		summaries, err := pc.postSummaries(viewerID, pc.DB.Model(&models.Post{}).Where("posts.id IN ?", postIDs), 0, len(postIDs))
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range summaries {
+			posts[post.ID] = post
+		}
+	}
+
+	achievements := make(map[uint]TimelineAchievement)
+	if len(achievementIDs) > 0 {
+		var rows []struct {
+			ID           uint   `gorm:"column:id"`
+			Activity     string `gorm:"column:activity"`
+			Points       int    `gorm:"column:points"`
+			PostID       uint   `gorm:"column:post_id"`
+			PlaceID      uint   `gorm:"column:place_id"`
+			PlaceName    string `gorm:"column:place_name"`
+			PlaceAddress string `gorm:"column:place_address"`
+			PlaceImage   string `gorm:"column:place_image"`
+		}
+		if err := pc.DB.Model(&models.ActivityLog{}).
+			Select(`activity_logs.id, activity_logs.activity, activity_logs.points, activity_logs.post_id,
+				places.id AS place_id, places.name AS place_name, places.address AS place_address, places.place_image`).
+			Joins("JOIN places ON places.id = activity_logs.place_id").
+			Where("activity_logs.id IN ?", achievementIDs).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			achievements[row.ID] = TimelineAchievement{
+				ID:     row.ID,
+				Kind:   row.Activity,
+				Points: row.Points,
+				PostID: row.PostID,
+				Place:  PostPlace{ID: row.PlaceID, Name: row.PlaceName, Address: row.PlaceAddress, Image: row.PlaceImage},
+			}
+		}
+	}
+
+	items := make([]TimelineItem, 0, len(keys))
+	for _, key := range keys {
+		item := TimelineItem{Type: key.Type, CreatedAt: key.CreatedAt}
+		if key.Type == TimelineItemPost {
+			post, ok := posts[key.ID]
+			if !ok {
+				continue
+			}
+			item.Post = &post
+		} else {
+			achievement, ok := achievements[key.ID]
+			if !ok {
+				continue
+			}
+			item.Achievement = &achievement
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// GetUserTimeline godoc
+// @Summary Get a user's profile timeline
+// @Description Returns the user's posts, achievements and point milestones merged into one stream, newest first. Each item has a type (post, achievement or milestone) and the matching object. Posts and their achievements follow the same privacy rules as the user's post list.
+// @Tags users
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param page query integer false "Page number (default: 1)"
+// @Param pageSize query integer false "Items per page (default: 20)"
+// @Success 200 {object} StandardResponse{data=[]TimelineItem}
+// @Router /users/{userId}/timeline [get]
+func (pc *PostController) GetUserTimeline(c *gin.Context) {
+	currentUser := utils.GetUser(c)
+	if currentUser == nil {
+		c.JSON(http.StatusUnauthorized, StandardResponse{
+			Success: false,
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	page := clampPage(c.Query("page"))
+	pageSize := clampPageSize(c.Query("pageSize"), 20, config.GetMaxPageSize())
+	offset := (page - 1) * pageSize
+
+	var owner models.User
+	if err := pc.DB.Select("id, is_private").First(&owner, c.Param("userId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, StandardResponse{
+			Success: false,
+			Message: "User not found",
+		})
+		return
+	}
+
+	visibilityClause, visible, err := pc.userPostsVisibility(currentUser.UserID, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error checking post visibility",
+		})
+		return
+	}
+	if !visible {
+		// Gönderileri görülemeyen profilin başarım ve kilometre taşları da gösterilmez
+		c.JSON(http.StatusOK, StandardResponse{
+			Success: true,
+			Data:    []TimelineItem{},
+			Meta: gin.H{
+				"isPrivate": owner.IsPrivate,
+			},
+			Pagination: &PaginationMeta{
+				CurrentPage: page,
+				PageSize:    pageSize,
+				TotalItems:  0,
+				TotalPages:  0,
+			},
+		})
+		return
+	}
+
+	milestones, err := pc.pointMilestones(owner.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching timeline",
+		})
+		return
+	}
+
+	keysSQL := fmt.Sprintf(timelineKeysSQL, visibilityClause)
+	keyArgs := []interface{}{owner.ID, owner.ID, timelineAchievementActivities}
+
+	var total int64
+	if err := pc.DB.Raw("SELECT COUNT(*) FROM ("+keysSQL+") timeline", keyArgs...).Scan(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error counting timeline",
+		})
+		return
+	}
+	total += int64(len(milestones))
+
+	// Kilometre taşları SQL dışında hesaplandığından sayfa birleştirilerek bulunur:
+	// her kaynağın ilk offset+pageSize öğesi sayfanın tamamını içerir
+	var keys []timelineKey
+	if err := pc.DB.Raw(keysSQL+`
+		ORDER BY created_at DESC, type, id DESC
+		LIMIT ?`, append(keyArgs, offset+pageSize)...).
+		Scan(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching timeline",
+		})
+		return
+	}
+
+	items, err := pc.loadTimelineItems(currentUser.UserID, keys)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, StandardResponse{
+			Success: false,
+			Message: "Error fetching timeline",
+		})
+		return
+	}
+	items = append(items, milestones...)
+	sort.SliceStable(items, func(i, j int) bool { return timelineNewer(items[i], items[j]) })
+
+	timeline := []TimelineItem{}
+	if offset < len(items) {
+		end := offset + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		timeline = items[offset:end]
+	}
+
+	c.JSON(http.StatusOK, StandardResponse{
+		Success: true,
+		Data:    timeline,
+		Meta: gin.H{
+			"isPrivate": owner.IsPrivate,
+		},
+		Pagination: &PaginationMeta{
+			CurrentPage: page,
+			PageSize:    pageSize,
+			TotalItems:  total,
+			TotalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+)
+
+func TestGetUserTimeline(t *testing.T) {
+	t.Setenv("POINT_MILESTONES", "100,250,1000")
+	db := openTestDB(t)
+	owner := createTestUser(t, db, "timelineowner")
+	follower := createTestUser(t, db, "timelinefollower")
+	stranger := createTestUser(t, db, "timelinestranger")
+	place := createTestPlace(t, db, "timelineplace")
+	if err := db.Create(&models.Follow{FollowerUserID: follower.ID, FollowingUserID: owner.ID, Status: "accepted"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	// Gönderi ve puan kaydı ayrı anlarda yazılır; kilometre taşı puanın yazıldığı andadır
+	post := func(caption string, public bool, at time.Duration, points int, discovery bool) models.Post {
+		t.Helper()
+		p := createTestPost(t, db, owner, place, caption, public)
+		if err := db.Model(&p).Updates(map[string]interface{}{"created_at": base.Add(at), "earned_points": points}).Error; err != nil {
+			t.Fatal(err)
+		}
+		logs := []models.ActivityLog{{UserID: owner.ID, PlaceID: place.ID, PostID: p.ID, Activity: "post_created", Points: points, CreatedAt: base.Add(at + time.Second)}}
+		if discovery {
+			logs = append(logs, models.ActivityLog{UserID: owner.ID, PlaceID: place.ID, PostID: p.ID, Activity: "place_discovered", Points: 10, CreatedAt: base.Add(at + 2*time.Second)})
+		}
+		if err := db.Create(&logs).Error; err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	first := post("first", true, 0, 60, true)
+	second := post("second", true, 10*time.Minute, 50, false)      // toplam 110: 100 eşiği
+	hidden := post("followers", false, 20*time.Minute, 200, false) // toplam 310: 250 eşiği
+
+	pc := NewPostController(db, nil)
+	param := gin.Param{Key: "userId", Value: strconv.Itoa(int(owner.ID))}
+	timeline := func(viewer models.User, query string) (entries []string, total int64) {
+		t.Helper()
+		w := callHandler(pc.GetUserTimeline, http.MethodGet, "/users/"+param.Value+"/timeline"+query, nil, viewer.ID, param)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", query, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data       []TimelineItem `json:"data"`
+			Pagination PaginationMeta `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		entries = []string{}
+		for _, item := range resp.Data {
+			switch {
+			case item.Post != nil:
+				entries = append(entries, fmt.Sprintf("post:%d", item.Post.ID))
+			case item.Achievement != nil:
+				entries = append(entries, fmt.Sprintf("achievement:%s:%d", item.Achievement.Kind, item.Achievement.PostID))
+			case item.Milestone != nil:
+				entries = append(entries, fmt.Sprintf("milestone:%d", item.Milestone.Points))
+			}
+		}
+		return entries, resp.Pagination.TotalItems
+	}
+
+	postEntry := func(p models.Post) string { return fmt.Sprintf("post:%d", p.ID) }
+	discovered := fmt.Sprintf("achievement:place_discovered:%d", first.ID)
+	want := []string{"milestone:250", postEntry(hidden), "milestone:100", postEntry(second), discovered, postEntry(first)}
+	for _, viewer := range []models.User{owner, follower} {
+		if got, total := timeline(viewer, ""); !reflect.DeepEqual(got, want) || total != int64(len(want)) {
+			t.Errorf("%s sees %v (total %d), want %v", viewer.Username, got, total, want)
+		}
+	}
+
+	// Sayfalar birleştirilmiş akış üzerinden kesilir
+	if got, _ := timeline(owner, "?page=2&pageSize=2"); !reflect.DeepEqual(got, want[2:4]) {
+		t.Errorf("page 2 = %v, want %v", got, want[2:4])
+	}
+	if got, _ := timeline(owner, "?page=4&pageSize=2"); len(got) != 0 {
+		t.Errorf("page past the end = %v, want empty", got)
+	}
+
+	// Takipçilere özel gönderi yabancıya gösterilmez
+	wantStranger := []string{"milestone:250", "milestone:100", postEntry(second), discovered, postEntry(first)}
+	if got, _ := timeline(stranger, ""); !reflect.DeepEqual(got, wantStranger) {
+		t.Errorf("stranger sees %v, want %v", got, wantStranger)
+	}
+
+	// Silinen gönderi puanını geri alır; kilometre taşı ilk ulaşıldığı anda kalır
+	if err := db.Delete(&first).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.ActivityLog{UserID: owner.ID, PlaceID: place.ID, PostID: first.ID, Activity: "post_deleted", CreatedAt: base.Add(30 * time.Minute)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"milestone:250", postEntry(hidden), "milestone:100", postEntry(second)}
+	if got, _ := timeline(owner, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("after deleting the first post: %v, want %v", got, want)
+	}
+
+	if err := db.Model(&owner).Update("is_private", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got, total := timeline(stranger, ""); len(got) != 0 || total != 0 {
+		t.Errorf("stranger sees %v on a private account, want nothing", got)
+	}
+}
@@ -20,7 +20,7 @@ func (vc *ValidationController) ValidateUsername(c *gin.Context) {
 	username := c.Param("username")
 
 	var user models.User
-	result := vc.DB.Where("username = ?", username).First(&user)
+	result := vc.DB.Where("LOWER(username) = LOWER(?)", username).First(&user)
 
 	if result.Error == nil {
 		// Username exists
@@ -38,7 +38,7 @@ func (vc *ValidationController) ValidateEmail(c *gin.Context) {
 	email := c.Param("email")
 
 	var user models.User
-	result := vc.DB.Where("email = ?", email).First(&user)
+	result := vc.DB.Where("LOWER(email) = ?", normalizeEmail(email)).First(&user)
 
 	if result.Error == nil {
 		// Email exists
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/middleware"
 	"github.com/snap-point/api-go/routes"
 )
 
@@ -23,10 +24,14 @@ func main() {
 	db := config.InitDB()
 
 	// Create a new Gin router
-	r := gin.Default()
+	// Tek erişim günlüğü (?token= değerleri gizlenir) ve istek metrikleri
+	r := gin.New()
+	r.Use(middleware.RequestLogger(os.Stdout), middleware.Metrics(), gin.Recovery())
 
-	// Add logging middleware
-	r.Use(gin.LoggerWithWriter(os.Stdout))
+	// Yalnızca yapılandırılmış proxy'lerin X-Forwarded-For başlığına güven
+	if err := r.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 
 	// Initialize routes
 	routes.SetupRoutes(r, db)
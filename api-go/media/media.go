@@ -0,0 +1,149 @@
+// Package media defines the media types posts and uploads accept, with the
+// content types and size limits of each. Request validation, presigning and
+// point calculation all read from here, so a type is added in one place.
+package media
+
+const (
+	Photo     = "photo"
+	Video     = "video"
+	GIF       = "gif"
+	LivePhoto = "live_photo"
+)
+
+// ValidationTag is the binding tag that checks a field holds a known media type
+const ValidationTag = "mediatype"
+
+// Type describes one accepted media type
+type Type struct {
+	Name         string
+	ContentTypes []string
+	MaxSize      int64 // bytes
+	// Thumbnail istemcinin dosyayla birlikte bir küçük resim yüklemesi beklendiğini belirtir
+	Thumbnail bool
+	// Animated istemciye medyanın hareketli oynatılacağını bildirir
+	Animated bool
+	// PointsBonus gönderinin ilk medyası bu türdeyse yer puanına eklenir
+	PointsBonus int
+	// Hashable yinelenen gönderi tespiti için görüntünün algısal özeti çıkarılabilir
+	Hashable bool
+}
+
+// Types lists the accepted media types in display order; replaced in tests
+var Types = []Type{
+	{
+		Name:         Photo,
+		ContentTypes: []string{"image/jpeg", "image/jpg", "image/png", "image/webp", "image/heic"},
+		MaxSize:      10 * 1024 * 1024, // 10MB
+		PointsBonus:  2,
+		Hashable:     true,
+	},
+	{
+		Name:         Video,
+		ContentTypes: []string{"video/mp4", "video/quicktime", "video/avi", "video/webm", "video/mov"},
+		MaxSize:      100 * 1024 * 1024, // 100MB
+		Thumbnail:    true,
+		PointsBonus:  5,
+	},
+	{
+		Name:         GIF,
+		ContentTypes: []string{"image/gif"},
+		MaxSize:      15 * 1024 * 1024, // 15MB
+		Animated:     true,
+		PointsBonus:  2,
+		Hashable:     true,
+	},
+	{
+		// Canlı fotoğraf: hareketli kısmı tek dosyada (HEIC/JPEG + MOV) yüklenen fotoğraf
+		Name:         LivePhoto,
+		ContentTypes: []string{"image/heic", "image/jpeg", "video/quicktime"},
+		MaxSize:      30 * 1024 * 1024, // 30MB
+		Animated:     true,
+		PointsBonus:  3,
+	},
+}
+
+// Avatar profil fotoğrafı yüklemelerinin kısıtları; gönderi medya türü değildir
+var Avatar = Type{
+	Name:         "avatar",
+	ContentTypes: []string{"image/jpeg", "image/jpg", "image/png", "image/webp"},
+	MaxSize:      5 * 1024 * 1024, // 5MB
+}
+
+// Lookup returns the media type called name
+func Lookup(name string) (Type, bool) {
+	for _, t := range Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// IsValid reports whether name is an accepted media type
+func IsValid(name string) bool {
+	_, ok := Lookup(name)
+	return ok
+}
+
+// Names returns the accepted media type names in display order
+func Names() []string {
+	names := make([]string, len(Types))
+	for i, t := range Types {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// MaxSize returns the size limit of a media type in bytes, 0 if it is unknown
+func MaxSize(name string) int64 {
+	t, _ := Lookup(name)
+	return t.MaxSize
+}
+
+// ExpectsThumbnail reports whether uploads of the media type come with a thumbnail
+func ExpectsThumbnail(name string) bool {
+	t, _ := Lookup(name)
+	return t.Thumbnail
+}
+
+// IsHashable reports whether uploads of the media type get a perceptual hash
+func IsHashable(name string) bool {
+	t, _ := Lookup(name)
+	return t.Hashable
+}
+
+// IsAnimated reports whether clients should play the media type as motion
+func IsAnimated(name string) bool {
+	t, _ := Lookup(name)
+	return t.Animated
+}
+
+// PointsBonus returns the extra points a post earns when its first media is of the type
+func PointsBonus(name string) int {
+	t, _ := Lookup(name)
+	return t.PointsBonus
+}
+
+// ValidateUpload reports whether a file of contentType and size is allowed for the media type
+func ValidateUpload(name, contentType string, size int64) (contentTypeOK, sizeOK bool) {
+	t, ok := Lookup(name)
+	if !ok {
+		return false, false
+	}
+	return t.Allows(contentType), t.Fits(size)
+}
+
+// Allows reports whether contentType may be uploaded as this type
+func (t Type) Allows(contentType string) bool {
+	for _, allowed := range t.ContentTypes {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Fits reports whether a file of size bytes is within the limit of this type
+func (t Type) Fits(size int64) bool {
+	return size > 0 && size <= t.MaxSize
+}
@@ -0,0 +1,54 @@
+package media
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		mediaType   string
+		contentType string
+		size        int64
+		wantType    bool
+		wantSize    bool
+	}{
+		{"photo", Photo, "image/jpeg", 1024, true, true},
+		{"photo over limit", Photo, "image/png", 10*1024*1024 + 1, true, false},
+		{"video content type for photo", Photo, "video/mp4", 1024, false, true},
+		{"video at limit", Video, "video/mp4", 100 * 1024 * 1024, true, true},
+		{"empty file", Video, "video/webm", 0, true, false},
+		{"gif", GIF, "image/gif", 1024, true, true},
+		{"jpeg as gif", GIF, "image/jpeg", 1024, false, true},
+		{"live photo movie", LivePhoto, "video/quicktime", 20 * 1024 * 1024, true, true},
+		{"unknown type", "audio", "audio/mpeg", 1024, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotSize := ValidateUpload(tt.mediaType, tt.contentType, tt.size)
+			if gotType != tt.wantType || gotSize != tt.wantSize {
+				t.Errorf("ValidateUpload(%q, %q, %d) = %v, %v, want %v, %v",
+					tt.mediaType, tt.contentType, tt.size, gotType, gotSize, tt.wantType, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestTypesLookup(t *testing.T) {
+	if got, want := Names(), []string{Photo, Video, GIF, LivePhoto}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if !IsValid(Photo) || IsValid("avatar") || IsValid("") {
+		t.Error("only registered post media types should be valid")
+	}
+	if ExpectsThumbnail(Photo) || !ExpectsThumbnail(Video) {
+		t.Error("only videos should expect a thumbnail")
+	}
+	if IsAnimated(Photo) || !IsAnimated(GIF) || !IsAnimated(LivePhoto) {
+		t.Error("only GIFs and live photos should be animated")
+	}
+	if MaxSize("unknown") != 0 || PointsBonus("unknown") != 0 {
+		t.Error("unknown types should have no limit and no bonus")
+	}
+}
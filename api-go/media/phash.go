@@ -0,0 +1,68 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+)
+
+// dHash için görüntünün küçültüldüğü ızgara: her satırda 9 örnek, 8 karşılaştırma
+const (
+	hashWidth  = 9
+	hashHeight = 8
+)
+
+// DifferenceHash returns a 64-bit difference hash (dHash) of img as 16 hex
+// digits. Re-encoded, resized or recompressed copies of the same picture hash
+// a few bits apart, so hashes are compared with HashDistance.
+func DifferenceHash(img image.Image) string {
+	bounds := img.Bounds()
+	var grid [hashHeight][hashWidth]float64
+	for y := 0; y < hashHeight; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/hashHeight
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/hashHeight
+		for x := 0; x < hashWidth; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/hashWidth
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/hashWidth
+			grid[y][x] = averageLuma(img, x0, y0, max(x1, x0+1), max(y1, y0+1))
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if grid[y][x] < grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// HashDistance returns how many bits two hashes from DifferenceHash differ in;
+// ok is false when either is empty or malformed.
+func HashDistance(a, b string) (distance int, ok bool) {
+	if len(a) != 16 || len(b) != 16 {
+		return 0, false
+	}
+	x, errA := strconv.ParseUint(a, 16, 64)
+	y, errB := strconv.ParseUint(b, 16, 64)
+	if errA != nil || errB != nil {
+		return 0, false
+	}
+	return bits.OnesCount64(x ^ y), true
+}
+
+// averageLuma is the mean brightness of the pixels in [x0,x1)x[y0,y1)
+func averageLuma(img image.Image, x0, y0, x1, y1 int) float64 {
+	var sum float64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+		}
+	}
+	return sum / float64((x1-x0)*(y1-y0))
+}
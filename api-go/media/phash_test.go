@@ -0,0 +1,57 @@
+package media
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient draws a w x h picture whose brightness follows shade
+func gradient(w, h int, shade func(x, y, w, h int) uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: shade(x, y, w, h)})
+		}
+	}
+	return img
+}
+
+func TestDifferenceHash(t *testing.T) {
+	// Yatay dalgalı desen; küçültülmüş kopyası aynı özeti vermeli
+	waves := func(x, y, w, h int) uint8 { return uint8((x*7/w*40 + y*5/h*30) % 256) }
+	original := DifferenceHash(gradient(640, 480, waves))
+	resized := DifferenceHash(gradient(160, 120, waves))
+	other := DifferenceHash(gradient(640, 480, func(x, y, w, h int) uint8 { return uint8(255 - x*255/w) }))
+
+	if len(original) != 16 {
+		t.Fatalf("hash = %q, want 16 hex digits", original)
+	}
+	if d, ok := HashDistance(original, resized); !ok || d > 6 {
+		t.Errorf("resized copy distance = %d (ok %v), want <= 6", d, ok)
+	}
+	if d, ok := HashDistance(original, other); !ok || d <= 6 {
+		t.Errorf("different image distance = %d (ok %v), want > 6", d, ok)
+	}
+}
+
+func TestHashDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+		ok       bool
+	}{
+		{"0000000000000000", "0000000000000000", 0, true},
+		{"0000000000000000", "000000000000000f", 4, true},
+		{"ffffffffffffffff", "0000000000000000", 64, true},
+		{"", "0000000000000000", 0, false},
+		{"zzzzzzzzzzzzzzzz", "0000000000000000", 0, false},
+		{"00", "00", 0, false},
+	}
+	for _, tt := range tests {
+		distance, ok := HashDistance(tt.a, tt.b)
+		if distance != tt.distance || ok != tt.ok {
+			t.Errorf("HashDistance(%q, %q) = %d, %v; want %d, %v", tt.a, tt.b, distance, ok, tt.distance, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package metrics defines the application's Prometheus metrics; they are
+// registered with the default registry and served by Handler.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Uygulamanın metrikleri; gecikme kovaları Prometheus varsayılanlarıdır (saniye)
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query duration by operation and table.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "table"})
+	GoogleAPIRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "google_api_requests_total",
+		Help: "Google API calls by API and result (HTTP status, or error when no response arrived).",
+	}, []string{"api", "result"})
+	GoogleAPIDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "google_api_request_duration_seconds",
+		Help:    "Google API call latency by API.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"api"})
+)
+
+// Handler serves the registered metrics for a Prometheus scrape
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerExposesHistogram(t *testing.T) {
+	GoogleAPIDuration.WithLabelValues("handler_test").Observe(0.3)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`google_api_request_duration_seconds_bucket{api="handler_test",le="0.25"} 0`,
+		`google_api_request_duration_seconds_bucket{api="handler_test",le="0.5"} 1`,
+		`google_api_request_duration_seconds_count{api="handler_test"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/utils"
+)
+
+// AdminMiddleware must run after AuthMiddleware; it rejects non-admin tokens with 403.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !utils.GetUser(c).IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/utils"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		claims *utils.UserClaims
+		want   int
+	}{
+		{"admin", &utils.UserClaims{UserID: 1, Role: utils.AdminRoleName}, http.StatusOK},
+		{"regular user", &utils.UserClaims{UserID: 2, Role: "user"}, http.StatusForbidden},
+		{"no user in context", nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if tt.claims != nil {
+				c.Set(string(utils.UserContextKey), tt.claims)
+			}
+		}, AdminMiddleware())
+		r.PUT("/admin/places/1/post-radius", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/places/1/post-radius", nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
@@ -1,8 +1,8 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/snap-point/api-go/utils"
@@ -29,9 +29,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		token := bearerToken[1]
 		claims := jwt.MapClaims{}
-		parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
+		parsedToken, err := utils.ParseToken(token, claims)
 
 		if err != nil || !parsedToken.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -39,7 +37,21 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		userID := uint(claims["user_id"].(float64))
+		// exp zorunlu: MapClaims.Valid yalnızca claim varsa süreyi kontrol eder
+		if _, ok := claims["exp"].(float64); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			c.Abort()
+			return
+		}
+
+		rawUserID, ok := claims["user_id"].(float64)
+		if !ok || rawUserID < 1 || rawUserID != math.Trunc(rawUserID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			c.Abort()
+			return
+		}
+		userID := uint(rawUserID)
+
 		role, ok := claims["role"].(string)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
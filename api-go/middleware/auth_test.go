@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/utils"
+)
+
+func TestAuthMiddlewareRejectsBadTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_KID", "")
+	t.Setenv("JWT_PREVIOUS_SECRET", "")
+
+	exp := time.Now().Add(time.Hour).Unix()
+	sign := func(claims jwt.MapClaims) string {
+		t.Helper()
+		token, err := utils.SignToken(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	valid := sign(jwt.MapClaims{"user_id": 7, "role": "user", "exp": exp})
+	parts := strings.Split(valid, ".")
+	// Gövdeyi değiştir, imzayı koru
+	tampered := parts[0] + "." + jwt.EncodeSegment([]byte(`{"user_id":1,"role":"admin","exp":9999999999}`)) + "." + parts[2]
+
+	noneToken, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"user_id": 7, "role": "user", "exp": exp}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid", valid, http.StatusOK},
+		{"tampered payload", tampered, http.StatusUnauthorized},
+		{"wrong secret", func() string {
+			token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 7, "role": "user", "exp": exp}).SignedString([]byte("other"))
+			return token
+		}(), http.StatusUnauthorized},
+		{"alg none", noneToken, http.StatusUnauthorized},
+		{"expired", sign(jwt.MapClaims{"user_id": 7, "role": "user", "exp": time.Now().Add(-time.Minute).Unix()}), http.StatusUnauthorized},
+		{"missing exp", sign(jwt.MapClaims{"user_id": 7, "role": "user"}), http.StatusUnauthorized},
+		{"missing user_id", sign(jwt.MapClaims{"role": "user", "exp": exp}), http.StatusUnauthorized},
+		{"string user_id", sign(jwt.MapClaims{"user_id": "7", "role": "user", "exp": exp}), http.StatusUnauthorized},
+		{"fractional user_id", sign(jwt.MapClaims{"user_id": 7.5, "role": "user", "exp": exp}), http.StatusUnauthorized},
+		{"zero user_id", sign(jwt.MapClaims{"user_id": 0, "role": "user", "exp": exp}), http.StatusUnauthorized},
+		{"missing role", sign(jwt.MapClaims{"user_id": 7, "exp": exp}), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/", AuthMiddleware(), func(c *gin.Context) {
+				if user := utils.GetUser(c); user == nil || user.UserID != 7 {
+					t.Errorf("user in context = %+v, want id 7", user)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
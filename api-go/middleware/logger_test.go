@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactToken(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"/notifications/stream?token=abc.def", "/notifications/stream?token=REDACTED"},
+		{"/notifications/stream?x=1&token=abc&y=2", "/notifications/stream?x=1&token=REDACTED&y=2"},
+		{"/posts?mytoken=keep", "/posts?mytoken=keep"},
+		{"/posts", "/posts"},
+	}
+	for _, tt := range tests {
+		if got := redactToken(tt.in); got != tt.want {
+			t.Errorf("redactToken(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRequestLoggerRedactsToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(&logs), QueryTokenAuth())
+	r.GET("/notifications/stream", func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer secret-token" {
+			t.Errorf("Authorization = %q", c.GetHeader("Authorization"))
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notifications/stream?token=secret-token", nil))
+	if strings.Contains(logs.String(), "secret-token") {
+		t.Errorf("token leaked into log: %s", logs.String())
+	}
+	if n := strings.Count(logs.String(), "token=REDACTED"); n != 1 {
+		t.Errorf("log lines with the redacted path = %d, want 1: %s", n, logs.String())
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/metrics"
+)
+
+// Metrics records the count and latency of every request by method, route
+// pattern and status. Requests matching no route share one label so unknown
+// paths cannot grow the series without bound.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuth limits the metrics endpoint to internal scrapers: with
+// METRICS_TOKEN set the request must carry it as a bearer token, otherwise
+// the client must be on a loopback or private network address.
+func MetricsAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := config.GetMetricsToken(); token != "" {
+			if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid metrics token"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Metrics are only available internally"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/metrics"
+)
+
+func TestMetricsExposesRequestCounter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("METRICS_TOKEN", "scrape-secret")
+
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/metrics", MetricsAuth(), gin.WrapH(metrics.Handler()))
+	r.GET("/api/places/:placeId/profile", func(c *gin.Context) {
+		c.Status(http.StatusTeapot)
+	})
+
+	for _, path := range []string{"/api/places/1/profile", "/api/places/2/profile"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	scrape := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := scrape(""); w.Code != http.StatusUnauthorized {
+		t.Errorf("scrape without token: status = %d, want 401", w.Code)
+	}
+	w := scrape("Bearer scrape-secret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape: status = %d", w.Code)
+	}
+	// Yol parametreleri değil rota kalıbı etiketlenir
+	body := w.Body.String()
+	if want := `http_requests_total{method="GET",route="/api/places/:placeId/profile",status="418"} 2`; !strings.Contains(body, want) {
+		t.Errorf("metrics missing %q:\n%s", want, body)
+	}
+	if want := `http_request_duration_seconds_count{method="GET",route="/api/places/:placeId/profile",status="418"} 2`; !strings.Contains(body, want) {
+		t.Errorf("metrics missing %q", want)
+	}
+}
+
+func TestMetricsAuthWithoutTokenIsInternalOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("METRICS_TOKEN", "")
+
+	r := gin.New()
+	r.GET("/metrics", MetricsAuth(), gin.WrapH(metrics.Handler()))
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"127.0.0.1:9000", http.StatusOK},
+		{"10.1.2.3:9000", http.StatusOK},
+		{"203.0.113.7:9000", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = tt.remoteAddr
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.remoteAddr, w.Code, tt.want)
+		}
+	}
+}
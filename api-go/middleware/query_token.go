@@ -0,0 +1,19 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// QueryTokenAuth copies a ?token= access token into the Authorization header
+// for clients that cannot set headers, such as browser EventSource. Use it
+// only in front of AuthMiddleware on streaming routes: tokens in URLs tend to
+// end up in proxy logs. RequestLogger redacts them from our own access log.
+func QueryTokenAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		c.Next()
+	}
+}
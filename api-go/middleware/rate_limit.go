@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+	"github.com/snap-point/api-go/utils"
+)
+
+// tokenBucket dolum hızı Requests/Window olan, kapasitesi Requests kadar kova
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	capacity  float64
+	perSecond float64
+	window    time.Duration
+	lastSweep time.Time
+}
+
+// allow consumes a token for key. When the bucket is empty it returns false
+// and how long until the next token is available.
+func (rl *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	// Uzun süredir kullanılmayan kovaları temizle
+	if now.Sub(rl.lastSweep) > rl.window {
+		for k, b := range rl.buckets {
+			if now.Sub(b.lastSeen) > rl.window {
+				delete(rl.buckets, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	bucket, exists := rl.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: rl.capacity, lastSeen: now}
+		rl.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(rl.capacity, bucket.tokens+elapsed*rl.perSecond)
+	bucket.lastSeen = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / rl.perSecond * float64(time.Second))
+	return false, wait
+}
+
+func newRateLimiter(limit config.RateLimit, now time.Time) *rateLimiter {
+	return &rateLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		capacity:  float64(limit.Requests),
+		perSecond: float64(limit.Requests) / limit.Window.Seconds(),
+		window:    limit.Window,
+		lastSweep: now,
+	}
+}
+
+// Aynı adla kaydedilen route'lar kovaları paylaşır
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*rateLimiter)
+)
+
+// namedLimiter returns the limiter registered under name, creating it with
+// limit on first use.
+func namedLimiter(name string, limit config.RateLimit) *rateLimiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	limiter, exists := limiters[name]
+	if !exists {
+		limiter = newRateLimiter(limit, time.Now())
+		limiters[name] = limiter
+	}
+	return limiter
+}
+
+// RateLimit throttles requests with a token bucket per client. Clients are
+// keyed by user ID when the route is authenticated, otherwise by IP. name
+// selects the RATE_LIMIT_<NAME> override, and every route using the same name
+// draws from the same buckets; the defaults of the first call for a name win.
+func RateLimit(name string, defaultRequests int, defaultWindow time.Duration) gin.HandlerFunc {
+	limiter := namedLimiter(name, config.GetRateLimit(name, defaultRequests, defaultWindow))
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if user := utils.GetUser(c); user != nil {
+			key = fmt.Sprintf("user:%d", user.UserID)
+		}
+
+		allowed, wait := limiter.allow(key, time.Now())
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":      "Too many requests, please try again later",
+				"retryAfter": retryAfter,
+				"success":    false,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	start := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	type step struct {
+		key     string
+		at      time.Duration
+		allowed bool
+		wait    time.Duration
+	}
+	tests := []struct {
+		name  string
+		limit config.RateLimit
+		steps []step
+	}{
+		{"burst up to capacity", config.RateLimit{Requests: 2, Window: time.Minute}, []step{
+			{"a", 0, true, 0},
+			{"a", 0, true, 0},
+			{"a", 0, false, 30 * time.Second},
+		}},
+		{"refills over the window", config.RateLimit{Requests: 2, Window: time.Minute}, []step{
+			{"a", 0, true, 0},
+			{"a", 0, true, 0},
+			{"a", 20 * time.Second, false, 10 * time.Second},
+			{"a", 30 * time.Second, true, 0},
+		}},
+		{"keys are independent", config.RateLimit{Requests: 1, Window: time.Minute}, []step{
+			{"a", 0, true, 0},
+			{"a", 0, false, time.Minute},
+			{"b", 0, true, 0},
+		}},
+		{"swept bucket starts full", config.RateLimit{Requests: 1, Window: time.Minute}, []step{
+			{"a", 0, true, 0},
+			{"a", 3 * time.Minute, true, 0},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rl := newRateLimiter(tt.limit, start)
+			for i, s := range tt.steps {
+				allowed, wait := rl.allow(s.key, start.Add(s.at))
+				if allowed != s.allowed || (wait-s.wait).Abs() > time.Millisecond {
+					t.Errorf("step %d: allow(%q) = %v, %v; want %v, %v", i, s.key, allowed, wait, s.allowed, s.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitSharesBucketsByName(t *testing.T) {
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/first", RateLimit("test_shared", 1, time.Hour), ok)
+	r.GET("/second", RateLimit("test_shared", 1, time.Hour), ok)
+	r.GET("/other", RateLimit("test_other", 1, time.Hour), ok)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/first", http.StatusOK},
+		{"/second", http.StatusTooManyRequests},
+		{"/first", http.StatusTooManyRequests},
+		{"/other", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = "203.0.113.5:4000"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddrs    []string
+		forwardedFor   []string
+		want           []int
+	}{
+		// Her istek farklı bir X-Forwarded-For gönderse de aynı istemcidir
+		{"no trusted proxies", "", []string{"203.0.113.5:4000", "203.0.113.5:4001"}, []string{"198.51.100.1", "198.51.100.2"},
+			[]int{http.StatusOK, http.StatusTooManyRequests}},
+		{"untrusted peer", "10.0.0.1", []string{"203.0.113.5:4000", "203.0.113.5:4001"}, []string{"198.51.100.1", "198.51.100.2"},
+			[]int{http.StatusOK, http.StatusTooManyRequests}},
+		// Güvenilen proxy arkasındaki farklı istemciler ayrı kovalar kullanır
+		{"trusted proxy", "10.0.0.1", []string{"10.0.0.1:4000", "10.0.0.1:4001"}, []string{"198.51.100.1", "198.51.100.2"},
+			[]int{http.StatusOK, http.StatusOK}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.trustedProxies)
+			r := gin.New()
+			if err := r.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+				t.Fatal(err)
+			}
+			r.GET("/login", RateLimit(fmt.Sprintf("test_spoof_%d", i), 1, time.Hour), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for j := range tt.want {
+				req := httptest.NewRequest(http.MethodGet, "/login", nil)
+				req.RemoteAddr = tt.remoteAddrs[j]
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor[j])
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if w.Code != tt.want[j] {
+					t.Errorf("request %d: status %d, want %d", j, w.Code, tt.want[j])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// AdminAuditLog bir yöneticinin yaptığı değişikliğin kaydıdır.
+// Kayıt, işlemin kendisiyle aynı transaction içinde yazılır.
+type AdminAuditLog struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+	AdminUserID uint      `gorm:"not null;index" json:"admin_user_id"`
+	AdminUser   User      `gorm:"foreignKey:AdminUserID" json:"-"`
+	Action      string    `gorm:"not null;type:varchar(50);index" json:"action"` // place_post_radius_set, place_review_approve, ...
+	TargetType  string    `gorm:"not null;type:varchar(30)" json:"target_type"`  // place, user, report
+	TargetID    uint      `gorm:"not null" json:"target_id"`
+	Detail      *string   `gorm:"type:jsonb" json:"-"`
+}
@@ -3,6 +3,7 @@ package models
 import (
     "time"
 
+    "gorm.io/gorm"
 )
 
 type Comment struct {
@@ -14,6 +15,7 @@ type Comment struct {
     CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime"`
     IsEdited        bool      `gorm:"column:is_edited;default:false"`
     LikeCount       int       `gorm:"column:like_count;default:0"`
+    DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at;index"` // yönetici tarafından kaldırılan yorumlar
 
     // İlişkiler
     ParentComment *Comment `gorm:"foreignKey:ParentCommentID"`
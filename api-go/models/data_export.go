@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Dışa aktarım durumları
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportCompleted  = "completed"
+	DataExportFailed     = "failed"
+)
+
+// DataExport büyük hesaplar için arka planda hazırlanan kişisel veri
+// arşividir. Arşiv ExpiresAt'e kadar indirilebilir; süresi dolanlar kullanıcı
+// yeni bir dışa aktarım istediğinde silinir.
+type DataExport struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Status      string     `gorm:"not null;type:varchar(20);default:'pending'" json:"status"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	Archive     []byte     `gorm:"type:bytea" json:"-"` // JSON arşiv
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// DeviceToken push bildirimi için kayıtlı bir cihazı temsil eder.
+// Aynı token tek bir kullanıcıya aittir; başka hesapla kaydedilirse taşınır.
+type DeviceToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	Token     string    `gorm:"not null;size:512;uniqueIndex" json:"token"`
+	Platform  string    `gorm:"not null;type:varchar(10)" json:"platform"` // ios, android, web
+	LastSeen  time.Time `gorm:"not null;index" json:"last_seen"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// FavoritePlace bir kullanıcının daha sonra ziyaret etmek üzere kaydettiği
+// mekandır; gönderi paylaşmaktan bağımsızdır. Kaldırıldığında satır silinir.
+type FavoritePlace struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	UserID  uint `gorm:"not null;uniqueIndex:idx_favorite_places_user_place" json:"user_id"`
+	PlaceID uint `gorm:"not null;uniqueIndex:idx_favorite_places_user_place;index" json:"place_id"`
+
+	User  User  `gorm:"foreignKey:UserID" json:"-"`
+	Place Place `gorm:"foreignKey:PlaceID" json:"-"`
+}
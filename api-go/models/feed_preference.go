@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// FeedPreference kullanıcının varsayılan akış filtrelerini tutar.
+// İstekte açıkça verilen parametreler bu değerleri ezer.
+type FeedPreference struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	UserID      uint           `gorm:"not null;uniqueIndex" json:"user_id"`
+	User        User           `gorm:"foreignKey:UserID" json:"-"`
+	SortBy      string         `json:"sort_by"`
+	Radius      float64        `json:"radius"`
+	Categories  pq.StringArray `gorm:"type:text[]" json:"categories"`
+	OnlyFriends bool           `gorm:"default:false" json:"only_friends"`
+}
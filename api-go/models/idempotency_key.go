@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyKey istemcinin Idempotency-Key başlığıyla gönderdiği bir isteğin kaydıdır.
+// PostID boşsa istek hâlâ işleniyordur.
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	User         User      `gorm:"foreignKey:UserID" json:"-"`
+	Key          string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key" json:"key"`
+	RequestHash  string    `gorm:"not null;size:64" json:"-"`
+	PostID       *uint     `json:"post_id"`
+	PointsEarned int64     `gorm:"not null;default:0" json:"points_earned"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt e-posta ile yapılan her giriş denemesini tutar (hesap kilitleme için)
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	Email     string    `gorm:"not null;index" json:"email"`
+	UserID    *uint     `gorm:"index" json:"user_id"` // Bilinmeyen e-postalar için boş
+	IPAddress string    `gorm:"type:varchar(64)" json:"ip_address"`
+	Success   bool      `gorm:"not null;default:false" json:"success"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// MediaUpload yüklenen bir dosyanın sunucuda okunan gerçek boyutlarını tutar.
+// Yükleme onaylanırken yazılır; gönderi oluşturulurken istemcinin bildirdiği
+// değerler yerine bunlar kullanılır.
+type MediaUpload struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	MediaURL  string    `gorm:"not null;uniqueIndex" json:"media_url"`
+	MediaType string    `gorm:"size:50;not null" json:"media_type"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Duration  int       `json:"duration"` // saniye, yalnızca video
+	// Fotoğraf ve GIF'lerin algısal özeti; okunamazsa boş
+	PerceptualHash string `gorm:"size:16" json:"perceptual_hash"`
+}
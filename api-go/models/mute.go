@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Mute sessiz engelleme kaydıdır: susturulan kullanıcının gönderileri
+// susturan kullanıcının akışından gizlenir, takip ilişkileri ve profiller
+// etkilenmez. Susturulan taraf bundan haberdar edilmez.
+type Mute struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	MuterUserID uint `gorm:"not null;index" json:"muter_user_id"`
+	MutedUserID uint `gorm:"not null;index" json:"muted_user_id"`
+
+	MuterUser User `gorm:"foreignKey:MuterUserID" json:"-"`
+	MutedUser User `gorm:"foreignKey:MutedUserID" json:"-"`
+}
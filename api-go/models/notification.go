@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification kullanıcıya uygulama içinde gösterilen bildirimdir.
+// Type "like", "follow", "follow_request", "follow_accepted", "comment_removed",
+// "place_discovered" veya "referral_completed" olabilir; ActorUserID bildirimi tetikleyen kullanıcıdır,
+// sistem bildirimlerinde boştur.
+type Notification struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	UserID      uint       `gorm:"not null;index:idx_notifications_user_read" json:"user_id"`
+	IsRead      bool       `gorm:"not null;default:false;index:idx_notifications_user_read" json:"is_read"`
+	ReadAt      *time.Time `json:"read_at"`
+	Type        string     `gorm:"not null;type:varchar(30)" json:"type"`
+	ActorUserID *uint      `gorm:"index" json:"actor_user_id"`
+	PostID      *uint      `json:"post_id"`
+
+	User      User  `gorm:"foreignKey:UserID" json:"-"`
+	ActorUser *User `gorm:"foreignKey:ActorUserID" json:"-"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// NotificationPreference hangi bildirim tiplerinin e-posta ve push ile
+// gönderileceğini tutar. Kayıt yoksa tüm tipler push ile gönderilir,
+// e-posta gönderilmez.
+type NotificationPreference struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	UserID     uint           `gorm:"not null;uniqueIndex" json:"user_id"`
+	User       User           `gorm:"foreignKey:UserID" json:"-"`
+	EmailTypes pq.StringArray `gorm:"type:text[]" json:"email_types"`
+	PushTypes  pq.StringArray `gorm:"type:text[]" json:"push_types"`
+}
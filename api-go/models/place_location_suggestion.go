@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// PlaceLocationSuggestion bir kullanıcının yanlış konumlu bir mekan için
+// önerdiği düzeltilmiş koordinattır. Kullanıcı başına mekanda bir bekleyen
+// öneri tutulur; yönetici kabul ederse mekanın koordinatları güncellenir.
+type PlaceLocationSuggestion struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	PlaceID   uint    `gorm:"not null;index" json:"place_id"`
+	UserID    uint    `gorm:"not null;index" json:"user_id"`
+	Latitude  float64 `gorm:"not null;type:decimal(10,8)" json:"latitude"`
+	Longitude float64 `gorm:"not null;type:decimal(11,8)" json:"longitude"`
+	// Önerildiği sırada kullanıcı önerdiği noktanın yakınındaydı
+	OnSite bool   `gorm:"default:false" json:"on_site"`
+	Status string `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // pending, accepted, rejected, superseded
+
+	ReviewedByUserID *uint      `json:"reviewed_by_user_id"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+
+	Place Place `gorm:"foreignKey:PlaceID" json:"-"`
+	User  User  `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -8,11 +8,11 @@ import (
 
 type Post struct {
 	ID            uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	CreatedAt     time.Time      `json:"created_at"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"index:idx_posts_user_created,priority:2"` // akış adayları kullanıcı başına en yeni gönderilerden seçilir
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 	PostCaption   string         `json:"post_caption" gorm:"type:text"`
-	UserID        uint           `json:"user_id" gorm:"not null"`
+	UserID        uint           `json:"user_id" gorm:"not null;index:idx_posts_user_created,priority:1"`
 	PlaceID       uint           `json:"place_id" gorm:"not null"`
 	EarnedPoints  int64          `json:"earned_points" gorm:"not null;default:0"`
 	User          User           `json:"user" gorm:"foreignKey:UserID"`
@@ -22,6 +22,9 @@ type Post struct {
 	IsArchived    bool           `json:"is_archived" gorm:"default:false"`
 	AllowComments bool           `json:"allow_comments" gorm:"default:true"`
 	IsPublic      bool           `json:"is_public" gorm:"default:true"`
+	IsDiscovery   bool           `json:"is_discovery" gorm:"not null;default:false"` // mekandaki ilk gönderi, keşif bonusu aldı
+	Language      string         `json:"language" gorm:"size:2;index"` // ISO 639-1, boş ise bilinmiyor
+	NeedsReview   bool           `json:"needs_review" gorm:"default:false;index"` // yinelenen gönderi olarak işaretlendi
 	PostMedia     []PostMedia    `json:"post_media" gorm:"foreignKey:PostID"`
 	Comments      []Comment      `json:"comments" gorm:"foreignKey:PostID"`
 	Likes         []Like         `json:"likes" gorm:"foreignKey:PostID"`
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// PostDraft sunucuda saklanan yayınlanmamış gönderi taslağıdır.
+// Taslaklar puan kazandırmaz ve sadece sahibine görünür; yayınlanırken
+// normal gönderi doğrulamasından (konum kontrolü dahil) geçer.
+type PostDraft struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	User          User      `gorm:"foreignKey:UserID" json:"-"`
+	PostCaption   string    `gorm:"type:text" json:"post_caption"`
+	PlaceID       *uint     `json:"place_id"`
+	Latitude      *float64  `gorm:"type:decimal(10,8)" json:"latitude"`
+	Longitude     *float64  `gorm:"type:decimal(11,8)" json:"longitude"`
+	IsPublic      bool      `gorm:"default:true" json:"is_public"`
+	AllowComments bool      `gorm:"default:true" json:"allow_comments"`
+	Language      string    `gorm:"size:2" json:"language"`
+	MediaItems    string    `gorm:"type:jsonb;not null;default:'[]'" json:"-"` // []CreatePostMediaItem
+	Data          *string   `gorm:"type:jsonb" json:"-"`                       // istemciye ait serbest JSON
+}
@@ -23,4 +23,6 @@ type PostMedia struct {
 	Width        int            `json:"width"`                    // Genişlik
 	Height       int            `json:"height"`                   // Yükseklik
 	Duration     int            `json:"duration"`                 // Süre (video/ses için, saniye cinsinden)
+	// Yüklemede hesaplanan algısal özet (dHash, 16 hex); yinelenen gönderileri yakalar
+	PerceptualHash string `gorm:"size:16" json:"-"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Davet durumları
+const (
+	ReferralPending   = "pending"
+	ReferralCompleted = "completed"
+)
+
+// Referral bir kullanıcının başka bir kullanıcının davet koduyla kaydolduğunu
+// kaydeder. Davet edilen ilk gönderisini paylaştığında tamamlanır ve iki
+// tarafa da bonus puan verilir; bir kullanıcı yalnızca bir kez davet edilebilir.
+type Referral struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ReferrerUserID uint       `gorm:"not null;index" json:"referrer_user_id"`
+	ReferredUserID uint       `gorm:"not null;uniqueIndex" json:"referred_user_id"`
+	Status         string     `gorm:"not null;type:varchar(20);default:'pending'" json:"status"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	PostID         *uint      `json:"post_id"` // daveti tamamlayan gönderi
+	ReferrerPoints int        `gorm:"not null;default:0" json:"referrer_points"`
+	ReferredPoints int        `gorm:"not null;default:0" json:"referred_points"`
+
+	ReferrerUser User `gorm:"foreignKey:ReferrerUserID" json:"-"`
+	ReferredUser User `gorm:"foreignKey:ReferredUserID" json:"-"`
+}
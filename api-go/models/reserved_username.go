@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ReservedUsername yöneticilerin çalışma anında eklediği, kullanılamayan bir
+// kullanıcı adıdır. Varsayılan ve ortamdan gelen liste config'dedir; eşleşme
+// Canonical (küçük harf, benzer karakterler sadeleştirilmiş) üzerinden yapılır.
+type ReservedUsername struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Word          string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"word"`
+	Canonical     string    `gorm:"type:varchar(50);not null;index" json:"-"`
+	AddedByUserID uint      `gorm:"not null" json:"added_by_user_id"`
+}
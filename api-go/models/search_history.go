@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// SearchHistory kullanıcının son aramalarını tutar.
+// ResultType "query" ise sadece arama metni kaydedilir; "user", "place" veya
+// "hashtag" ise kullanıcının dokunduğu sonuç da saklanır.
+type SearchHistory struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	SearchedAt time.Time `gorm:"not null;index" json:"searched_at"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	User       User      `gorm:"foreignKey:UserID" json:"-"`
+	Query      string    `gorm:"not null;size:100" json:"query"`
+	ResultType string    `gorm:"not null;default:'query'" json:"result_type"`
+	ResultID   *uint     `json:"result_id"`
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -25,6 +26,7 @@ type User struct {
 	GoogleID   *string `gorm:"unique" json:"google_id"`
 	Provider   string `gorm:"default:'email'" json:"provider"` // email, google, apple, etc.
 	ProviderID string `json:"provider_id"`
+	LinkedProviders pq.StringArray `gorm:"type:text[]" json:"linked_providers"` // Hesaba bağlı tüm giriş yöntemleri (email, google)
 	Posts         []Post         `json:"posts" gorm:"foreignKey:UserID"`
 	Comments      []Comment      `json:"comments" gorm:"foreignKey:UserID"`
 	Likes         []Like         `json:"likes" gorm:"foreignKey:UserID"`
@@ -38,4 +40,13 @@ type User struct {
 	EmailVerified bool           `json:"email_verified"`
 	PhoneVerified bool           `json:"phone_verified"`
 	TotalPoints   int64          `gorm:"default:0" json:"total_points"`
+	// Profil sayaçları takip/gönderi işlemlerinde artımlı güncellenir, periyodik olarak uzlaştırılır
+	FollowersCount int64 `gorm:"default:0" json:"followers_count"`
+	FollowingCount int64 `gorm:"default:0" json:"following_count"`
+	PostsCount     int64 `gorm:"default:0" json:"posts_count"`
+	IsPrivate     bool           `gorm:"default:false" json:"is_private"` // Gizli hesap: gönderiler yalnızca onaylı takipçilere görünür
+	// Yakındaki kullanıcılar listesinde görünmeye açık rıza (varsayılan kapalı)
+	ShareLocation bool `gorm:"default:false" json:"share_location"`
+	// Davet kodu ilk istendiğinde üretilir
+	ReferralCode *string `gorm:"uniqueIndex;size:16" json:"-"`
 }
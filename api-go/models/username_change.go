@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// UsernameChange kullanıcı adı değişiklik geçmişini tutar.
+// ReservedUntil tarihine kadar eski kullanıcı adı başka biri tarafından alınamaz.
+type UsernameChange struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	User          User      `gorm:"foreignKey:UserID" json:"-"`
+	OldUsername   string    `gorm:"not null;index" json:"old_username"`
+	NewUsername   string    `gorm:"not null" json:"new_username"`
+	ReservedUntil time.Time `gorm:"not null" json:"reserved_until"`
+}
@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// FCMNotifier sends push notifications through the FCM HTTP v1 API using a
+// service account key file.
+type FCMNotifier struct {
+	ProjectID string
+	client    *http.Client
+}
+
+// NewFCMNotifier loads the service account JSON at credentialsFile
+func NewFCMNotifier(ctx context.Context, credentialsFile string) (*FCMNotifier, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read FCM credentials: %w", err)
+	}
+
+	credentials, err := google.CredentialsFromJSON(ctx, data, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("parse FCM credentials: %w", err)
+	}
+	if credentials.ProjectID == "" {
+		return nil, fmt.Errorf("FCM credentials have no project_id")
+	}
+
+	client := oauth2.NewClient(ctx, credentials.TokenSource)
+	client.Timeout = 10 * time.Second
+
+	return &FCMNotifier{ProjectID: credentials.ProjectID, client: client}, nil
+}
+
+func (f *FCMNotifier) SendEmail(ctx context.Context, to string, msg Message) error {
+	return ErrChannelNotConfigured
+}
+
+func (f *FCMNotifier) SendPush(ctx context.Context, deviceToken string, msg Message) error {
+	payload := map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": deviceToken,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", f.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var fcmError struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&fcmError)
+
+	// Uygulama kaldırıldığında veya token yenilendiğinde FCM UNREGISTERED döner
+	if fcmError.Error.Status == "UNREGISTERED" ||
+		(fcmError.Error.Status == "INVALID_ARGUMENT" && strings.Contains(fcmError.Error.Message, "registration token")) {
+		return ErrInvalidDeviceToken
+	}
+	return fmt.Errorf("fcm send: status %d %s", resp.StatusCode, fcmError.Error.Status)
+}
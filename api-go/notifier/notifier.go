@@ -0,0 +1,172 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrChannelNotConfigured kanal için ortam değişkenleri tanımlı değil
+	ErrChannelNotConfigured = errors.New("notification channel not configured")
+	// ErrInvalidDeviceToken push sağlayıcısı cihaz token'ını artık geçersiz sayıyor
+	ErrInvalidDeviceToken = errors.New("invalid device token")
+)
+
+// Message is the channel-independent content of an outbound notification
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Notifier delivers notifications outside the app. Implementations may block
+// on the network, so callers should invoke them asynchronously.
+type Notifier interface {
+	SendEmail(ctx context.Context, to string, msg Message) error
+	SendPush(ctx context.Context, deviceToken string, msg Message) error
+}
+
+// NewFromEnv builds a Notifier from SMTP_* and FCM_* variables.
+// Unconfigured channels return ErrChannelNotConfigured.
+func NewFromEnv() Notifier {
+	n := &channelNotifier{}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		n.email = &SMTPNotifier{
+			Addr:     host + ":" + port,
+			Host:     host,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+	}
+
+	if credentialsFile := os.Getenv("FCM_CREDENTIALS_FILE"); credentialsFile != "" {
+		fcm, err := NewFCMNotifier(context.Background(), credentialsFile)
+		if err != nil {
+			log.Printf("Push notifications disabled: %v", err)
+		} else {
+			n.push = fcm
+		}
+	}
+
+	return n
+}
+
+// channelNotifier routes each channel to its own implementation
+type channelNotifier struct {
+	email Notifier
+	push  Notifier
+}
+
+func (n *channelNotifier) SendEmail(ctx context.Context, to string, msg Message) error {
+	if n.email == nil {
+		return ErrChannelNotConfigured
+	}
+	return n.email.SendEmail(ctx, to, msg)
+}
+
+func (n *channelNotifier) SendPush(ctx context.Context, deviceToken string, msg Message) error {
+	if n.push == nil {
+		return ErrChannelNotConfigured
+	}
+	return n.push.SendPush(ctx, deviceToken, msg)
+}
+
+// SMTPNotifier sends plain-text email through an SMTP relay
+type SMTPNotifier struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPNotifier) SendEmail(ctx context.Context, to string, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Başlık enjeksiyonunu önlemek için satır sonları temizlenir
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Title)
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, to, subject, msg.Body)
+
+	if err := s.send(ctx, to, []byte(body)); err != nil {
+		// Bağlantı ctx yüzünden kapandıysa asıl neden ctx hatasıdır
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return fmt.Errorf("smtp send: %w", err)
+	}
+	return nil
+}
+
+// send does what smtp.SendMail does over a connection bound to ctx: the dial
+// honours ctx, its deadline applies to the whole exchange and cancelling it
+// closes the connection.
+func (s *SMTPNotifier) send(ctx context.Context, to string, body []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *SMTPNotifier) SendPush(ctx context.Context, deviceToken string, msg Message) error {
+	return ErrChannelNotConfigured
+}
@@ -0,0 +1,90 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts one connection and runs serve on it
+func fakeSMTPServer(t *testing.T, serve func(conn *textproto.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(textproto.NewConn(conn))
+	}()
+	return ln.Addr().String()
+}
+
+func TestSMTPNotifierSendEmail(t *testing.T) {
+	received := make(chan string, 1)
+	addr := fakeSMTPServer(t, func(conn *textproto.Conn) {
+		conn.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := conn.ReadLine()
+			if err != nil {
+				return
+			}
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				conn.PrintfLine("250 OK")
+			case "DATA":
+				conn.PrintfLine("354 go ahead")
+				data, _ := conn.ReadDotLines()
+				received <- strings.Join(data, "\n")
+				conn.PrintfLine("250 OK")
+			case "QUIT":
+				conn.PrintfLine("221 bye")
+				return
+			default:
+				conn.PrintfLine("502 unsupported")
+			}
+		}
+	})
+
+	notifier := &SMTPNotifier{Addr: addr, Host: "127.0.0.1", From: "noreply@example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msg := Message{Title: "Hello\r\nBcc: x@example.com", Body: "body text"}
+	if err := notifier.SendEmail(ctx, "user@example.com", msg); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+
+	data := <-received
+	if !strings.Contains(data, "Subject: Hello  Bcc: x@example.com") || !strings.Contains(data, "body text") {
+		t.Errorf("message = %q, want sanitized subject and body", data)
+	}
+}
+
+func TestSMTPNotifierSendEmailHonoursContext(t *testing.T) {
+	// Sunucu bağlantıyı kabul eder ama hiç selamlamaz
+	hold := make(chan struct{})
+	t.Cleanup(func() { close(hold) })
+	addr := fakeSMTPServer(t, func(conn *textproto.Conn) { <-hold })
+
+	notifier := &SMTPNotifier{Addr: addr, Host: "127.0.0.1", From: "noreply@example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := notifier.SendEmail(ctx, "user@example.com", Message{Title: "t", Body: "b"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendEmail error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SendEmail returned after %v, want it to stop at the context deadline", elapsed)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+	"github.com/snap-point/api-go/middleware"
+)
+
+func SetupAdminRoutes(protected *gin.RouterGroup, adminController *controllers.AdminController, placeController *controllers.PlaceController) {
+	admin := protected.Group("/admin", middleware.AdminMiddleware())
+	{
+		admin.GET("/stats", adminController.GetStats)
+		admin.GET("/audit-log", adminController.GetAuditLog)
+		admin.POST("/purge", adminController.PurgeDeleted)
+		admin.DELETE("/comments/:commentId", adminController.DeleteComment)
+		admin.POST("/comments/:commentId/restore", adminController.RestoreComment)
+		admin.GET("/posts/review-queue", adminController.GetPostReviewQueue)
+		admin.POST("/posts/:postId/review", adminController.ReviewPost)
+		admin.PUT("/places/:placeId/post-radius", placeController.SetPostRadiusOverride)
+		admin.GET("/places/review-queue", placeController.GetPlaceReviewQueue)
+		admin.POST("/places/:placeId/review", placeController.ReviewPlace)
+		admin.POST("/places/backfill-images", placeController.BackfillPlaceImages)
+		admin.POST("/places/recompute-points", placeController.RecomputePlacePoints)
+		admin.POST("/places/normalize-categories", placeController.NormalizePlaceCategories)
+		admin.GET("/places/location-suggestions", placeController.GetLocationSuggestionQueue)
+		admin.POST("/places/location-suggestions/:suggestionId/review", placeController.ReviewLocationSuggestion)
+		admin.GET("/reserved-usernames", adminController.GetReservedUsernames)
+		admin.POST("/reserved-usernames", adminController.AddReservedUsername)
+		admin.DELETE("/reserved-usernames/:word", adminController.RemoveReservedUsername)
+	}
+}
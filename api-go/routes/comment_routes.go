@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+)
+
+func SetupCommentRoutes(protected *gin.RouterGroup, commentController *controllers.CommentController) {
+	comments := protected.Group("/comments")
+	{
+		comments.GET("/:commentId", commentController.GetCommentContext)
+	}
+
+	// Kullanıcının aktivite ekranı için yazdığı yorumlar
+	protected.GET("/users/:userId/comments", commentController.GetUserComments)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+)
+
+func SetupConfigRoutes(protected *gin.RouterGroup, configController *controllers.ConfigController) {
+	config := protected.Group("/config")
+	{
+		config.GET("/categories", configController.GetCategories)
+		config.GET("/features", configController.GetFeatures)
+		config.GET("/trending", configController.GetTrending)
+	}
+}
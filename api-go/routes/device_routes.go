@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+)
+
+func SetupDeviceRoutes(protected *gin.RouterGroup, deviceController *controllers.DeviceController) {
+	devices := protected.Group("/devices")
+	{
+		devices.POST("/register", deviceController.RegisterDevice)
+		devices.DELETE("/:token", deviceController.UnregisterDevice)
+	}
+}
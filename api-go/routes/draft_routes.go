@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+)
+
+func SetupDraftRoutes(protected *gin.RouterGroup, draftController *controllers.DraftController) {
+	drafts := protected.Group("/posts/drafts")
+	{
+		drafts.POST("", draftController.CreateDraft)
+		drafts.GET("", draftController.GetDrafts)
+		drafts.PUT("/:id", draftController.UpdateDraft)
+		drafts.DELETE("/:id", draftController.DeleteDraft)
+		drafts.POST("/:id/publish", draftController.PublishDraft)
+	}
+}
@@ -9,5 +9,7 @@ func SetupFeedRoutes(protected *gin.RouterGroup, feedController *controllers.Fee
 	feed := protected.Group("/feed")
 	{
 		feed.GET("", feedController.GetUserFeed)
+		feed.GET("/preferences", feedController.GetFeedPreferences)
+		feed.PUT("/preferences", feedController.UpdateFeedPreferences)
 	}
 }
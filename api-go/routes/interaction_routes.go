@@ -16,6 +16,10 @@ func SetupInteractionRoutes(protected *gin.RouterGroup, interactionController *c
 	users := protected.Group("/users")
 	{
 		users.POST("/:userId/follow", interactionController.FollowUser)
+		users.POST("/follow/batch", interactionController.BatchFollowUsers)
+		users.POST("/follow-requests/accept-all", interactionController.AcceptAllFollowRequests)
+		users.POST("/follow-requests/:userId/accept", interactionController.AcceptFollowRequest)
+		users.POST("/follow-requests/decline-all", interactionController.DeclineAllFollowRequests)
 		users.GET("/:userId/followers", interactionController.GetUserFollowers)
 		users.GET("/:userId/following", interactionController.GetUserFollowing)
 	}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+)
+
+func SetupNotificationRoutes(protected *gin.RouterGroup, notificationController *controllers.NotificationController) {
+	notifications := protected.Group("/notifications")
+	{
+		notifications.GET("", notificationController.GetNotifications)
+		notifications.GET("/unread-count", notificationController.GetUnreadCount)
+		notifications.PUT("/:id/read", notificationController.MarkNotificationRead)
+		notifications.POST("/read-all", notificationController.MarkAllNotificationsRead)
+		notifications.POST("/read", notificationController.MarkNotificationsRead)
+		notifications.GET("/preferences", notificationController.GetNotificationPreferences)
+		notifications.PUT("/preferences", notificationController.UpdateNotificationPreferences)
+	}
+}
@@ -9,8 +9,17 @@ func SetupPlaceRoutes(protected *gin.RouterGroup, placeController *controllers.P
 	places := protected.Group("/places")
 	{
 		places.GET("/nearby", placeController.GetNearbyPlaces)
+		places.GET("/photos/:reference", placeController.GetPlacePhoto)
 		places.GET("/:placeId/profile", placeController.GetPlaceProfile)
 		places.GET("/:placeId/posts", placeController.GetPlacePosts)
+		places.GET("/:placeId/points-breakdown", placeController.GetPlacePointsBreakdown)
 		places.GET("/:placeId/validate-location", placeController.ValidatePostLocation)
+		places.POST("/validate-locations", placeController.ValidatePostLocations)
+		places.POST("/:placeId/check-in", placeController.CheckIn)
+		places.POST("/:placeId/favorite", placeController.ToggleFavoritePlace)
+		places.GET("/:placeId/similar", placeController.GetSimilarPlaces)
+		places.GET("/:placeId/recent-activity", placeController.GetPlaceRecentActivity)
+		places.POST("/:placeId/suggest-location", placeController.SuggestPlaceLocation)
 	}
+	protected.GET("/users/me/favorite-places", placeController.GetFavoritePlaces)
 }
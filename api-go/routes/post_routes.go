@@ -9,6 +9,8 @@ func SetupPostRoutes(protected *gin.RouterGroup, postController *controllers.Pos
 	posts := protected.Group("/posts")
 	{
 		posts.POST("", postController.CreatePost)
+		posts.POST("/liked-status", postController.LikedStatus)
+		posts.GET("/map-clusters", postController.GetPostMapClusters)
 		posts.GET("/:id", postController.GetPostDetail)
 		posts.PUT("/:id", postController.UpdatePost)
 		posts.DELETE("/:id", postController.DeletePost)
@@ -17,13 +19,20 @@ func SetupPostRoutes(protected *gin.RouterGroup, postController *controllers.Pos
 	// User posts routes
 	users := protected.Group("/users")
 	{
+		users.GET("/me/liked-posts", postController.GetLikedPosts)
 		users.GET("/:userId/posts", postController.GetUserPosts)
 		users.GET("/:userId/places/:placeId/posts", postController.GetUserPostsAtPlace)
+		users.GET("/:userId/timeline", postController.GetUserTimeline)
 	}
 
 	// Place posts routes
 	places := protected.Group("/places")
 	{
 		places.GET("/:placeId/posts/grid", postController.GetPlacePostsGrid)
+		places.GET("/:placeId/media", postController.GetPlaceMedia)
+		places.GET("/:placeId/my-history", postController.GetMyPlaceHistory)
+		places.POST("/posts/grid", postController.GetMultiPlacePostsGrid)
 	}
+
+	protected.GET("/hashtags/:tag", postController.GetHashtagStats)
 }
@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/config"
 	"github.com/snap-point/api-go/controllers"
+	"github.com/snap-point/api-go/metrics"
 	"github.com/snap-point/api-go/middleware"
 	"gorm.io/gorm"
 )
@@ -11,23 +15,33 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	// Initialize controllers
 	uploadController := controllers.NewUploadController(db)
 	authController := controllers.NewAuthController(db, uploadController)
-	userController := controllers.NewUserController(db)
-	postController := controllers.NewPostController(db)
-	placeController := controllers.NewPlaceController(db)
+	userController := controllers.NewUserController(db, uploadController)
+	postController := controllers.NewPostController(db, uploadController)
+	placeController := controllers.NewPlaceController(db, uploadController)
 	interactionController := controllers.NewInteractionController(db)
 	feedController := controllers.NewFeedController(db)
 	validationController := controllers.NewValidationController(db)
 	leaderboardController := controllers.NewLeaderboardController(db)
+	searchController := controllers.NewSearchController(db)
+	draftController := controllers.NewDraftController(db, postController)
+	notificationController := controllers.NewNotificationController(db)
+	deviceController := controllers.NewDeviceController(db)
+	adminController := controllers.NewAdminController(db, uploadController)
+	commentController := controllers.NewCommentController(db, uploadController)
+	configController := controllers.NewConfigController()
+
+	// Prometheus metrics; yalnızca iç ağdan veya METRICS_TOKEN ile
+	r.GET("/metrics", middleware.MetricsAuth(), gin.WrapH(metrics.Handler()))
 
 	// Public routes
 	public := r.Group("/api")
 	{
-		public.POST("/register", authController.Register)
-		public.POST("/register/check-email", authController.RegisterEmailCheck)
-		public.POST("/register/check-username", authController.RegisterUsernameCheck)
-		public.POST("/verify-email", authController.VerifyEmail)
-		public.POST("/login", authController.Login)
-		public.POST("/google-login", authController.GoogleLogin)
+		public.POST("/register", middleware.RateLimit("register", 5, time.Hour), authController.Register)
+		public.POST("/register/check-email", middleware.RateLimit("register_check", 30, time.Minute), authController.RegisterEmailCheck)
+		public.POST("/register/check-username", middleware.RateLimit("register_check", 30, time.Minute), authController.RegisterUsernameCheck)
+		public.POST("/verify-email", middleware.RateLimit("verify_email", 10, time.Minute), authController.VerifyEmail)
+		public.POST("/login", middleware.RateLimit("login", 10, time.Minute), authController.Login)
+		public.POST("/google-login", middleware.RateLimit("login", 10, time.Minute), authController.GoogleLogin)
 	}
 
 	// Public upload routes (no auth required for avatar during registration)
@@ -37,6 +51,14 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		publicUpload.DELETE("/upload/avatar/temp/:tempKey", uploadController.CleanupTempAvatar)
 	}
 
+	// Notification and place activity streams (EventSource header gönderemediği için token sorgu parametresiyle de kabul edilir)
+	stream := r.Group("/api")
+	stream.Use(middleware.QueryTokenAuth(), middleware.AuthMiddleware())
+	{
+		stream.GET("/notifications/stream", notificationController.StreamNotifications)
+		stream.GET("/places/:placeId/recent-activity/stream", placeController.StreamPlaceRecentActivity)
+	}
+
 	// Protected routes
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware())
@@ -46,17 +68,41 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		// User routes
 		protected.GET("/profile", authController.GetProfile)
 		protected.PUT("/profile", authController.UpdateProfile)
+		protected.GET("/profile/referrals", authController.GetReferrals)
+		protected.GET("/profile/export", middleware.RateLimit("data_export", 3, 24*time.Hour), authController.ExportData)
+		protected.GET("/profile/export/:exportId", authController.GetDataExport)
+		protected.GET("/profile/export/:exportId/download", authController.DownloadDataExport)
+		protected.POST("/profile/link-google", authController.LinkGoogle)
+		protected.DELETE("/profile/link-google", authController.UnlinkGoogle)
 
 		//Leaderboard routes
 		protected.GET("/leaderboard", leaderboardController.GetLeaderboard)
+		protected.GET("/leaderboard/around-me", leaderboardController.GetLeaderboardAroundMe)
 
 		// Setup other routes within the protected group
 		SetupUserRoutes(protected, userController)
 		SetupPostRoutes(protected, postController)
+		SetupDraftRoutes(protected, draftController)
 		SetupPlaceRoutes(protected, placeController)
 		SetupInteractionRoutes(protected, interactionController)
 		SetupFeedRoutes(protected, feedController)
 		SetupValidationRoutes(protected, validationController)
 		SetupUploadRoutes(protected, uploadController)
+		SetupSearchRoutes(protected, searchController)
+		SetupNotificationRoutes(protected, notificationController)
+		SetupDeviceRoutes(protected, deviceController)
+		SetupCommentRoutes(protected, commentController)
+		SetupConfigRoutes(protected, configController)
+		SetupAdminRoutes(protected, adminController, placeController)
+	}
+
+	// Silinmiş kayıtların zamanlanmış temizliği (PURGE_INTERVAL ile açılır)
+	if interval := config.GetPurgeInterval(); interval > 0 {
+		adminController.StartPurgeJob(interval)
+	}
+
+	// Önbelleğe alınmış profil sayaçlarının uzlaştırılması (COUNTER_RECONCILE_INTERVAL=0 kapatır)
+	if interval := config.GetCounterReconcileInterval(); interval > 0 {
+		adminController.StartCounterReconcileJob(interval)
 	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/controllers"
+	"github.com/snap-point/api-go/middleware"
+)
+
+func SetupSearchRoutes(protected *gin.RouterGroup, searchController *controllers.SearchController) {
+	search := protected.Group("/search")
+	{
+		search.GET("", middleware.RateLimit("search", 60, time.Minute), searchController.Search)
+		search.GET("/history", searchController.GetSearchHistory)
+		search.POST("/history", searchController.RecordSearchHistory)
+		search.DELETE("/history", searchController.ClearSearchHistory)
+	}
+}
@@ -1,8 +1,11 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/snap-point/api-go/controllers"
+	"github.com/snap-point/api-go/middleware"
 )
 
 func SetupUserRoutes(protected *gin.RouterGroup, userController *controllers.UserController) {
@@ -10,17 +13,28 @@ func SetupUserRoutes(protected *gin.RouterGroup, userController *controllers.Use
 	{
 		// User profile endpoints
 		users.GET("/:userId/profile", userController.GetUserProfile)
-		users.GET("/search", userController.SearchUsers)
+		users.GET("/search", middleware.RateLimit("search", 60, time.Minute), userController.SearchUsers)
 		users.GET("/suggested", userController.GetSuggestedUsers)
 		users.GET("/top", userController.GetTopUsers)
 		users.GET("/nearby", userController.GetNearbyUsers)
-		users.GET("/username/:username", userController.GetUsersByUsername)
+		users.GET("/username/:username", middleware.RateLimit("search", 60, time.Minute), userController.GetUsersByUsername)
 		
 		// User actions
+		users.GET("/me/blocked", userController.GetBlockedUsers)
 		users.POST("/:userId/block", userController.BlockUser)
+		users.GET("/me/muted", userController.GetMutedUsers)
+		users.POST("/:userId/mute", userController.MuteUser)
 		users.POST("/:userId/report", userController.ReportUser)
 		
 		// User activity
 		users.GET("/:userId/activity", userController.GetUserActivity)
+		users.GET("/:userId/points-timeline", userController.GetPointsTimeline)
+	}
+
+	// Activity tab read state
+	activity := protected.Group("/activity")
+	{
+		activity.GET("/unseen-count", userController.GetUnseenActivityCount)
+		activity.POST("/seen", userController.MarkActivitySeen)
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,194 @@
+package types
+
+import "strings"
+
+// placeCategoryMap Google Places tiplerini uygulamanın kategori taksonomisine eşler.
+// Haritada olmayan tipler (point_of_interest, establishment, food, political vb.) kategori
+// olarak saklanmaz. Uygulama kategorileri kendilerine eşlenir; puan ve yarıçap
+// tablolarındaki her kategori burada kendine eşlenmelidir, aksi halde o kayıt hiç kullanılmaz.
+var placeCategoryMap = map[string]string{
+	// Tarih ve kültür
+	"castle":              "castle",
+	"palace":              "palace",
+	"historical_site":     "historical_site",
+	"archaeological_site": "archaeological_site",
+	"ruins":               "ruins",
+	"monument":            "monument",
+	"memorial":            "memorial",
+	"museum":              "museum",
+	"art_gallery":         "art_gallery",
+	"tourist_attraction":  "tourist_attraction",
+	"city_hall":           "tourist_attraction",
+	"cathedral":           "cathedral",
+	"shrine":              "shrine",
+	"science_museum":      "science_museum",
+	"history_museum":      "history_museum",
+	"planetarium":         "planetarium",
+	"concert_hall":        "concert_hall",
+	"opera_house":         "opera_house",
+	"convention_center":   "convention_center",
+	"exhibition_center":   "exhibition_center",
+	"fairground":          "fairground",
+
+	// Doğa
+	"natural_feature": "natural_feature",
+	"national_park":   "national_park",
+	"state_park":      "state_park",
+	"regional_park":   "regional_park",
+	"country_park":    "country_park",
+	"park":            "park",
+	"rv_park":         "campground",
+	"campground":      "campground",
+	"beach":           "beach",
+	"mountain":        "mountain",
+	"lake":            "lake",
+	"forest":          "forest",
+	"island":          "island",
+	"waterfall":       "waterfall",
+	"cave":            "cave",
+	"valley":          "valley",
+	"desert":          "desert",
+
+	// Eğlence
+	"zoo":              "zoo",
+	"aquarium":         "aquarium",
+	"amusement_park":   "amusement_park",
+	"theme_park":       "theme_park",
+	"water_park":       "water_park",
+	"safari_park":      "safari_park",
+	"botanical_garden": "botanical_garden",
+	"stadium":          "stadium",
+	"race_track":       "race_track",
+	"movie_theater":    "movie_theater",
+	"theater":          "theater",
+	"casino":           "casino",
+	"night_club":       "night_club",
+	"bar":              "bar",
+	"pub":              "pub",
+	"brewery":          "brewery",
+	"winery":           "winery",
+	"bowling_alley":    "bowling_alley",
+	"gym":              "gym",
+	"spa":              "spa",
+
+	// Spor tesisleri
+	"sports_complex":   "sports_complex",
+	"golf_course":      "golf_course",
+	"swimming_pool":    "swimming_pool",
+	"tennis_court":     "tennis_court",
+	"football_field":   "football_field",
+	"basketball_court": "basketball_court",
+	"baseball_field":   "baseball_field",
+
+	// Dini yapılar
+	"place_of_worship": "place_of_worship",
+	"mosque":           "mosque",
+	"church":           "church",
+	"synagogue":        "synagogue",
+	"hindu_temple":     "temple",
+	"temple":           "temple",
+	"cemetery":         "cemetery",
+
+	// Yeme içme
+	"restaurant":    "restaurant",
+	"meal_takeaway": "meal_takeaway",
+	"meal_delivery": "meal_delivery",
+	"fast_food":     "fast_food",
+	"food_court":    "food_court",
+	"cafe":          "cafe",
+	"bakery":        "bakery",
+
+	// Konaklama
+	"lodging":           "hotel",
+	"hotel":             "hotel",
+	"resort":            "resort",
+	"hostel":            "hostel",
+	"motel":             "motel",
+	"bed_and_breakfast": "bed_and_breakfast",
+
+	// Alışveriş
+	"shopping_mall":          "shopping_mall",
+	"shopping_center":        "shopping_center",
+	"market":                 "market",
+	"bazaar":                 "bazaar",
+	"hardware_store":         "hardware_store",
+	"department_store":       "shopping_mall",
+	"store":                  "store",
+	"liquor_store":           "store",
+	"clothing_store":         "clothing_store",
+	"shoe_store":             "clothing_store",
+	"book_store":             "book_store",
+	"jewelry_store":          "jewelry_store",
+	"electronics_store":      "electronics_store",
+	"furniture_store":        "furniture_store",
+	"supermarket":            "supermarket",
+	"grocery_or_supermarket": "supermarket",
+
+	// Eğitim ve hizmetler
+	"university":       "university",
+	"library":          "library",
+	"school":           "school",
+	"primary_school":   "school",
+	"secondary_school": "school",
+	"kindergarten":     "kindergarten",
+	"hospital":         "hospital",
+	"pharmacy":         "pharmacy",
+	"bank":             "bank",
+	"atm":              "atm",
+	"post_office":      "post_office",
+	"police":           "police",
+	"fire_station":     "fire_station",
+
+	// Ulaşım
+	"airport":            "airport",
+	"train_station":      "train_station",
+	"subway_station":     "subway_station",
+	"light_rail_station": "subway_station",
+	"bus_station":        "bus_station",
+	"taxi_stand":         "taxi_stand",
+	"parking":            "parking",
+	"gas_station":        "gas_station",
+}
+
+// NormalizeCategories converts Google place types into app categories, dropping
+// generic and unknown types and duplicates while keeping the original order.
+func NormalizeCategories(googleTypes []string) []string {
+	categories := make([]string, 0, len(googleTypes))
+	seen := make(map[string]bool, len(googleTypes))
+	for _, googleType := range googleTypes {
+		category, ok := placeCategoryMap[strings.ToLower(strings.TrimSpace(googleType))]
+		if !ok || seen[category] {
+			continue
+		}
+		seen[category] = true
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+// NormalizeCategory maps a single category filter value to its app category.
+// Unknown values are only lowercased so a filter on them matches nothing
+// instead of being dropped and widening the results.
+func NormalizeCategory(category string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if normalized, ok := placeCategoryMap[category]; ok {
+		return normalized
+	}
+	return category
+}
+
+// NormalizeCategoryFilter applies NormalizeCategory to each filter value and
+// removes duplicates.
+func NormalizeCategoryFilter(values []string) []string {
+	categories := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		category := NormalizeCategory(value)
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		categories = append(categories, category)
+	}
+	return categories
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCategories(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		want  []string
+	}{
+		{"generic types dropped", []string{"cafe", "food", "point_of_interest", "establishment"}, []string{"cafe"}},
+		{"synonyms collapsed once", []string{"lodging", "hotel", "light_rail_station", "subway_station"}, []string{"hotel", "subway_station"}},
+		{"scored service types kept", []string{"gas_station", "parking", "pharmacy"}, []string{"gas_station", "parking", "pharmacy"}},
+		{"case and spaces ignored", []string{" Museum ", "ART_GALLERY"}, []string{"museum", "art_gallery"}},
+		{"only noise", []string{"political", "locality", "premise"}, []string{}},
+		{"app categories kept", []string{"castle", "beach"}, []string{"castle", "beach"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeCategories(tt.types); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeCategories(%v) = %v, want %v", tt.types, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCategoryFilter(t *testing.T) {
+	got := NormalizeCategoryFilter([]string{"Lodging", "hotel", "point_of_interest", " "})
+	// Bilinmeyen değerler düşürülmez, aksi halde filtre genişlerdi
+	if want := []string{"hotel", "point_of_interest"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeCategoryFilter = %v, want %v", got, want)
+	}
+}
+
+func TestScoredCategoriesAreAppCategories(t *testing.T) {
+	// Puan veya yarıçap tablosundaki bir kategori hiçbir yerde saklanmazsa o kayıt ölü kalır
+	tables := map[string]map[string]int{
+		"CategoryPoints": GetPlaceScoring().CategoryPoints,
+		"CategoryRadius": GetPlaceRadius().CategoryRadius,
+	}
+	for table, categories := range tables {
+		for category := range categories {
+			if got := placeCategoryMap[category]; got != category {
+				t.Errorf("%s has %q, which normalizes to %q", table, category, got)
+			}
+		}
+	}
+
+	// Kaydedilmiş kategoriler yeniden normalize edildiğinde değişmez
+	for googleType, category := range placeCategoryMap {
+		if placeCategoryMap[category] != category {
+			t.Errorf("%q maps to %q, which is not an app category", googleType, category)
+		}
+	}
+}
@@ -18,12 +18,17 @@ type Marker struct {
 	IsVerified bool    `json:"isVerified" gorm:"column:is_verified"`
 }
 
+// NearbyPlacesFilters echoes the filters applied to a nearby places request
+type NearbyPlacesFilters struct {
+	Radius      float64  `json:"radius"`
+	ZoomLevel   int      `json:"zoomLevel"`
+	HideVisited bool     `json:"hideVisited"`
+	Category    string   `json:"category"`
+	MinRating   *float64 `json:"minRating"`
+	NullRating  string   `json:"nullRating,omitempty"` // minRating verildiğinde puansız mekanlar için uygulanan kural
+}
+
 type NearbyPlacesResponse struct {
-	Markers []PlaceWithRadius `json:"markers"`
-	Filters struct {
-		Radius      float64 `json:"radius"`
-		ZoomLevel   int     `json:"zoomLevel"`
-		HideVisited bool    `json:"hideVisited"`
-		Category    string  `json:"category"`
-	} `json:"filters"`
+	Markers []PlaceWithRadius   `json:"markers"`
+	Filters NearbyPlacesFilters `json:"filters"`
 }
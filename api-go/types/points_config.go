@@ -9,12 +9,14 @@ const (
 	DEFAULT_PLACE_POINTS = 5
 	USER_VISITED_POINTS = 1
 	NO_POSTS_BONUS_POINTS = 3
+	CHECK_IN_POINTS = 1
 )
 
 type PointsConfig struct {
 	DefaultPlacePoints  int
 	UserVisitedPoints   int
 	NoPostsBonusPoints  int
+	CheckInPoints       int // gönderisiz ziyaret (check-in) puanı, yer başına günde bir kez
 }
 
 type PlaceScoring struct {
@@ -53,6 +55,7 @@ type PlaceWithRadius struct {
 	CoverageArea        float64        `json:"coverage_area"`        // Yerin kapladığı alan (m²)
 	RadiusType          string         `json:"radius_type"`          // Programatik key (small, medium, large, etc.)
 	RadiusDescription   string         `json:"radius_description"`   // İnsan dostu açıklama
+	IsFavorite          bool           `json:"is_favorite"`          // Kullanıcı mekanı kaydetmiş mi
 }
 
 func GetPointsConfig() PointsConfig {
@@ -60,6 +63,7 @@ func GetPointsConfig() PointsConfig {
 		DefaultPlacePoints:  DEFAULT_PLACE_POINTS,
 		UserVisitedPoints:   USER_VISITED_POINTS,
 		NoPostsBonusPoints:  NO_POSTS_BONUS_POINTS,
+		CheckInPoints:       CHECK_IN_POINTS,
 	}
 }
 
@@ -195,45 +199,106 @@ func GetPlaceRadius() PlaceRadius {
 			
 			// Genel kategoriler
 			"tourist_attraction": 100, // 100m - genel turist yeri
-			"point_of_interest": 50,   // 50m
-			"establishment": 30,       // 30m
 		},
 		DefaultRadius: 25, // Varsayılan 25 metre
 	}
 }
 
 func GetPlacePostRadius(categories []string) (int, string, string, float64) {
+	return GetPlacePostRadiusWithOverride(categories, nil)
+}
+
+// RadiusTier is a named radius band; a radius belongs to the first tier whose
+// MinRadius it reaches.
+type RadiusTier struct {
+	Type      string `json:"type"`
+	MinRadius int    `json:"minRadius"` // metre
+}
+
+// radiusTiers büyükten küçüğe sıralıdır
+var radiusTiers = []RadiusTier{
+	{Type: "very_large", MinRadius: 500},
+	{Type: "large", MinRadius: 200},
+	{Type: "medium", MinRadius: 100},
+	{Type: "small_medium", MinRadius: 50},
+	{Type: "small", MinRadius: 0},
+}
+
+// DefaultRadiusLanguage is the language radius descriptions fall back to
+const DefaultRadiusLanguage = "tr"
+
+// radiusDescriptions yarıçap tiplerinin dillere göre insan dostu açıklamalarıdır
+var radiusDescriptions = map[string]map[string]string{
+	"tr": {
+		"very_large":   "Çok Geniş Alan",
+		"large":        "Geniş Alan",
+		"medium":       "Orta Alan",
+		"small_medium": "Küçük-Orta Alan",
+		"small":        "Küçük Alan",
+	},
+	"en": {
+		"very_large":   "Very Large Area",
+		"large":        "Large Area",
+		"medium":       "Medium Area",
+		"small_medium": "Small-Medium Area",
+		"small":        "Small Area",
+	},
+}
+
+// GetRadiusTiers returns the radius bands from the largest to the smallest.
+func GetRadiusTiers() []RadiusTier {
+	tiers := make([]RadiusTier, len(radiusTiers))
+	copy(tiers, radiusTiers)
+	return tiers
+}
+
+// GetRadiusType returns the tier key (small, medium, large, ...) of a radius in metres.
+func GetRadiusType(radius int) string {
+	for _, tier := range radiusTiers {
+		if radius >= tier.MinRadius {
+			return tier.Type
+		}
+	}
+	return radiusTiers[len(radiusTiers)-1].Type
+}
+
+// HasRadiusDescriptions reports whether radius descriptions exist for the
+// ISO 639-1 language code.
+func HasRadiusDescriptions(language string) bool {
+	_, ok := radiusDescriptions[language]
+	return ok
+}
+
+// GetRadiusDescription returns the description of a radius type in the given
+// language, falling back to DefaultRadiusLanguage.
+func GetRadiusDescription(radiusType, language string) string {
+	if descriptions, ok := radiusDescriptions[language]; ok {
+		return descriptions[radiusType]
+	}
+	return radiusDescriptions[DefaultRadiusLanguage][radiusType]
+}
+
+// GetPlacePostRadiusWithOverride returns the place's post radius; a non-nil
+// override (set per place by an admin) wins over the category-derived radius.
+func GetPlacePostRadiusWithOverride(categories []string, override *int) (int, string, string, float64) {
 	radiusConfig := GetPlaceRadius()
 	maxRadius := radiusConfig.DefaultRadius
-	radiusType := "small"
-	radiusDescription := "Küçük Alan"
 	
-	// En büyük yarıçapı bul
-	for _, category := range categories {
-		categoryLower := strings.ToLower(category)
-		if radius, exists := radiusConfig.CategoryRadius[categoryLower]; exists && radius > maxRadius {
-			maxRadius = radius
+	if override != nil {
+		maxRadius = *override
+	} else {
+		// En büyük yarıçapı bul
+		for _, category := range categories {
+			categoryLower := strings.ToLower(category)
+			if radius, exists := radiusConfig.CategoryRadius[categoryLower]; exists && radius > maxRadius {
+				maxRadius = radius
+			}
 		}
 	}
 	
 	// Yarıçap tipini belirle (hem key hem description)
-	switch {
-	case maxRadius >= 500:
-		radiusType = "very_large"
-		radiusDescription = "Çok Geniş Alan"
-	case maxRadius >= 200:
-		radiusType = "large"
-		radiusDescription = "Geniş Alan"
-	case maxRadius >= 100:
-		radiusType = "medium"
-		radiusDescription = "Orta Alan"
-	case maxRadius >= 50:
-		radiusType = "small_medium"
-		radiusDescription = "Küçük-Orta Alan"
-	default:
-		radiusType = "small"
-		radiusDescription = "Küçük Alan"
-	}
+	radiusType := GetRadiusType(maxRadius)
+	radiusDescription := GetRadiusDescription(radiusType, DefaultRadiusLanguage)
 	
 	// Kapladığı alanı hesapla (π * r²)
 	coverageArea := math.Pi * float64(maxRadius) * float64(maxRadius)
@@ -399,13 +464,11 @@ func GetPlaceScoring() PlaceScoring {
 			"casino": 15,
 			"bar": 15,
 			"hotel": 15,
-			"lodging": 15,
 			
 			// Düşük puan yerler (10 puan)
 			"gym": 10,
 			"bowling_alley": 10,
 			"bakery": 10,
-			"food": 10,
 			"meal_takeaway": 10,
 			"meal_delivery": 10,
 			
@@ -447,10 +510,6 @@ func GetPlaceScoring() PlaceScoring {
 			"parking": 10,
 			"taxi_stand": 10,
 			"atm": 10,
-			
-			// Genel kategoriler
-			"establishment": 10,
-			"point_of_interest": 15,
 		},
 		
 		RarityMultiplier: map[string]float64{
@@ -610,23 +669,46 @@ type PlaceForClustering struct {
 	Name       string
 }
 
+// SpecialBonus bir kategori kombinasyonu için verilen ek puan
+type SpecialBonus struct {
+	Categories []string `json:"categories"`
+	Points     int      `json:"points"`
+}
+
+// PlacePointsBreakdown CalculatePlacePoints hesabının adım adım dökümü
+type PlacePointsBreakdown struct {
+	MatchedCategory string         `json:"matchedCategory,omitempty"` // En yüksek puanlı kategori (yoksa boş)
+	CategoryPoints  int            `json:"categoryPoints"`
+	RatingBonus     int            `json:"ratingBonus"`
+	PopularityBonus int            `json:"popularityBonus"`
+	SpecialBonuses  []SpecialBonus `json:"specialBonuses"`
+	RawTotal        int            `json:"rawTotal"`    // Sınırlama ve yuvarlama öncesi toplam
+	FinalPoints     int            `json:"finalPoints"` // 10-60 aralığına sınırlanmış, 5'in katı
+}
+
 func CalculatePlacePoints(categories []string, rating *float64, userRatingsTotal *int) int {
+	return CalculatePlacePointsBreakdown(categories, rating, userRatingsTotal).FinalPoints
+}
+
+func CalculatePlacePointsBreakdown(categories []string, rating *float64, userRatingsTotal *int) PlacePointsBreakdown {
 	scoring := GetPlaceScoring()
 	basePoints := 15 // Varsayılan 15 puan (10-60 aralığında)
 	maxCategoryPoints := 0
-	
+	matchedCategory := ""
+
 	// En yüksek kategori puanını bul
 	for _, category := range categories {
 		categoryLower := strings.ToLower(category)
 		if points, exists := scoring.CategoryPoints[categoryLower]; exists && points > maxCategoryPoints {
 			maxCategoryPoints = points
+			matchedCategory = categoryLower
 		}
 	}
-	
+
 	if maxCategoryPoints > 0 {
 		basePoints = maxCategoryPoints
 	}
-	
+
 	// Rating bonusu
 	ratingBonus := 0
 	if rating != nil {
@@ -643,7 +725,7 @@ func CalculatePlacePoints(categories []string, rating *float64, userRatingsTotal
 			ratingBonus = -10 // Düşük rating için ceza
 		}
 	}
-	
+
 	// Popülerlik bonusu - çok popüler yerleri önceliklendirme
 	popularityBonus := 0
 	if userRatingsTotal != nil {
@@ -662,41 +744,54 @@ func CalculatePlacePoints(categories []string, rating *float64, userRatingsTotal
 			popularityBonus = -5 // Az bilinen yerler için hafif ceza
 		}
 	}
-	
+
 	// Özel kategori kombinasyonları için bonus
 	specialBonus := 0
+	specialBonuses := []SpecialBonus{}
 	categorySet := make(map[string]bool)
 	for _, cat := range categories {
 		categorySet[strings.ToLower(cat)] = true
 	}
-	
+
 	// UNESCO veya tarihi önem taşıyan yerler
 	if categorySet["historical_site"] && categorySet["tourist_attraction"] {
 		specialBonus += 15
+		specialBonuses = append(specialBonuses, SpecialBonus{Categories: []string{"historical_site", "tourist_attraction"}, Points: 15})
 	}
-	
+
 	// Doğal güzellik + turizm
 	if categorySet["natural_feature"] && categorySet["tourist_attraction"] {
 		specialBonus += 10
+		specialBonuses = append(specialBonuses, SpecialBonus{Categories: []string{"natural_feature", "tourist_attraction"}, Points: 10})
 	}
-	
+
 	// Kültür + sanat
 	if categorySet["museum"] && categorySet["art_gallery"] {
 		specialBonus += 5
+		specialBonuses = append(specialBonuses, SpecialBonus{Categories: []string{"museum", "art_gallery"}, Points: 5})
 	}
-	
+
 	// Toplam puanı hesapla
 	totalPoints := basePoints + ratingBonus + popularityBonus + specialBonus
-	
+	rawTotal := totalPoints
+
 	// 10-60 aralığına sınırla ve 5'in katları yap
 	if totalPoints < 10 {
 		totalPoints = 10
 	} else if totalPoints > 60 {
 		totalPoints = 60
 	}
-	
+
 	// En yakın 5'in katına yuvarla
 	finalPoints := ((totalPoints + 2) / 5) * 5
-	
-	return finalPoints
-} 
\ No newline at end of file
+
+	return PlacePointsBreakdown{
+		MatchedCategory: matchedCategory,
+		CategoryPoints:  basePoints,
+		RatingBonus:     ratingBonus,
+		PopularityBonus: popularityBonus,
+		SpecialBonuses:  specialBonuses,
+		RawTotal:        rawTotal,
+		FinalPoints:     finalPoints,
+	}
+}
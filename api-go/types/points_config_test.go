@@ -0,0 +1,75 @@
+package types
+
+import "testing"
+
+func TestCalculatePlacePointsBreakdown(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+	count := func(v int) *int { return &v }
+
+	tests := []struct {
+		name             string
+		categories       []string
+		rating           *float64
+		userRatingsTotal *int
+		matched          string
+		storedBasePoints int
+	}{
+		{"neighbourhood cafe", []string{"cafe", "food"}, float(4.2), count(120), "cafe", 30},
+		{"famous historical site", []string{"historical_site", "Tourist_Attraction"}, float(4.7), count(2000), "historical_site", 60},
+		{"unknown and badly rated", []string{"car_wash"}, float(2.5), count(3), "", 10},
+		{"museum with gallery, no ratings", []string{"museum", "art_gallery"}, nil, nil, "museum", 60},
+		{"restaurant", []string{"restaurant"}, float(3.6), count(60), "restaurant", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := CalculatePlacePointsBreakdown(tt.categories, tt.rating, tt.userRatingsTotal)
+
+			if breakdown.FinalPoints != tt.storedBasePoints {
+				t.Errorf("FinalPoints = %d, want stored base points %d", breakdown.FinalPoints, tt.storedBasePoints)
+			}
+			if got := CalculatePlacePoints(tt.categories, tt.rating, tt.userRatingsTotal); got != breakdown.FinalPoints {
+				t.Errorf("CalculatePlacePoints = %d, breakdown says %d", got, breakdown.FinalPoints)
+			}
+			if breakdown.MatchedCategory != tt.matched {
+				t.Errorf("MatchedCategory = %q, want %q", breakdown.MatchedCategory, tt.matched)
+			}
+
+			// Bileşenler ham toplamı vermeli
+			sum := breakdown.CategoryPoints + breakdown.RatingBonus + breakdown.PopularityBonus
+			for _, bonus := range breakdown.SpecialBonuses {
+				sum += bonus.Points
+			}
+			if sum != breakdown.RawTotal {
+				t.Errorf("components sum to %d, RawTotal = %d", sum, breakdown.RawTotal)
+			}
+		})
+	}
+}
+
+func TestGetPlacePostRadiusWithOverride(t *testing.T) {
+	override := func(v int) *int { return &v }
+
+	tests := []struct {
+		name       string
+		categories []string
+		override   *int
+		radius     int
+		radiusType string
+	}{
+		{"no categories", nil, nil, 25, "small"},
+		{"largest category wins", []string{"cafe", "national_park"}, nil, 1000, "very_large"},
+		{"override wins over category", []string{"national_park"}, override(150), 150, "medium"},
+		{"override can go below default", []string{"cafe"}, override(10), 10, "small"},
+	}
+	for _, tt := range tests {
+		radius, radiusType, _, _ := GetPlacePostRadiusWithOverride(tt.categories, tt.override)
+		if radius != tt.radius || radiusType != tt.radiusType {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, radius, radiusType, tt.radius, tt.radiusType)
+		}
+		if tt.override == nil {
+			if legacy, _, _, _ := GetPlacePostRadius(tt.categories); legacy != radius {
+				t.Errorf("%s: GetPlacePostRadius = %d, want %d", tt.name, legacy, radius)
+			}
+		}
+	}
+}
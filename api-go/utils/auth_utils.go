@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
+	"github.com/snap-point/api-go/models"
+	"gorm.io/gorm"
 )
 
 type UserClaims struct {
@@ -9,10 +13,24 @@ type UserClaims struct {
 	Role   string   `json:"role"`
 }
 
+// AdminRoleName yönetici rolünün adı (roles.name)
+const AdminRoleName = "admin"
+
+// IsAdmin reports whether the token carries the admin role
+func (u *UserClaims) IsAdmin() bool {
+	return u != nil && u.Role == AdminRoleName
+}
+
 type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// CurrentUserContextKey istek boyunca önbelleğe alınan kullanıcı satırının anahtarı
+const CurrentUserContextKey contextKey = "currentUser"
+
+// ErrNoUserInContext is returned by GetCurrentUser for unauthenticated requests
+var ErrNoUserInContext = errors.New("user not found in context")
+
 func GetUser(c *gin.Context) *UserClaims {
 	user, exists := c.Get(string(UserContextKey))
 	if !exists {
@@ -23,3 +41,26 @@ func GetUser(c *gin.Context) *UserClaims {
 	}
 	return nil
 }
+
+// GetCurrentUser returns the full users row of the authenticated user. The
+// row is loaded on the first call and kept in the gin context, so later calls
+// within the same request do not query the database again. A missing row is
+// reported as gorm.ErrRecordNotFound.
+func GetCurrentUser(c *gin.Context, db *gorm.DB) (*models.User, error) {
+	claims := GetUser(c)
+	if claims == nil {
+		return nil, ErrNoUserInContext
+	}
+	if cached, exists := c.Get(string(CurrentUserContextKey)); exists {
+		if user, ok := cached.(*models.User); ok && user.ID == claims.UserID {
+			return user, nil
+		}
+	}
+
+	var user models.User
+	if err := db.First(&user, claims.UserID).Error; err != nil {
+		return nil, err
+	}
+	c.Set(string(CurrentUserContextKey), &user)
+	return &user, nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/snap-point/api-go/config"
+)
+
+// SignToken signs the claims with the current key and sets its kid header
+func SignToken(claims jwt.MapClaims) (string, error) {
+	keySet := config.GetJWTKeySet()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if keySet.Current.ID != "" {
+		token.Header["kid"] = keySet.Current.ID
+	}
+
+	return token.SignedString(keySet.Current.Secret)
+}
+
+// ParseToken verifies the token against the key selected by its kid header
+func ParseToken(tokenString string, claims jwt.MapClaims) (*jwt.Token, error) {
+	keySet := config.GetJWTKeySet()
+
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Yalnızca HMAC kabul edilir (alg: none veya asimetrik algoritma karışıklığına karşı)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := keySet.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %q", kid)
+		}
+		return secret, nil
+	})
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func setJWTEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{"JWT_SECRET", "JWT_KID", "JWT_PREVIOUS_SECRET", "JWT_PREVIOUS_KID", "JWT_PREVIOUS_VALID_UNTIL"} {
+		t.Setenv(key, env[key])
+	}
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": float64(7), "role": "user", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestSignTokenUsesCurrentKey(t *testing.T) {
+	setJWTEnv(t, map[string]string{"JWT_SECRET": "new-secret", "JWT_KID": "2024-06", "JWT_PREVIOUS_SECRET": "old-secret", "JWT_PREVIOUS_KID": "2024-01"})
+
+	signed, err := SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := ParseToken(signed, jwt.MapClaims{})
+	if err != nil || !token.Valid {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	if kid := token.Header["kid"]; kid != "2024-06" {
+		t.Errorf("kid = %v, want current kid 2024-06", kid)
+	}
+}
+
+func TestParseTokenAcceptsPreviousKey(t *testing.T) {
+	// Eski anahtarla imzala, ardından anahtarı döndür
+	setJWTEnv(t, map[string]string{"JWT_SECRET": "old-secret", "JWT_KID": "2024-01"})
+	oldToken, err := SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		valid bool
+	}{
+		{"previous key configured", map[string]string{
+			"JWT_SECRET": "new-secret", "JWT_KID": "2024-06",
+			"JWT_PREVIOUS_SECRET": "old-secret", "JWT_PREVIOUS_KID": "2024-01",
+		}, true},
+		{"inside grace period", map[string]string{
+			"JWT_SECRET": "new-secret", "JWT_KID": "2024-06",
+			"JWT_PREVIOUS_SECRET": "old-secret", "JWT_PREVIOUS_KID": "2024-01",
+			"JWT_PREVIOUS_VALID_UNTIL": time.Now().Add(time.Hour).Format(time.RFC3339),
+		}, true},
+		{"grace period over", map[string]string{
+			"JWT_SECRET": "new-secret", "JWT_KID": "2024-06",
+			"JWT_PREVIOUS_SECRET": "old-secret", "JWT_PREVIOUS_KID": "2024-01",
+			"JWT_PREVIOUS_VALID_UNTIL": time.Now().Add(-time.Hour).Format(time.RFC3339),
+		}, false},
+		{"previous key removed", map[string]string{"JWT_SECRET": "new-secret", "JWT_KID": "2024-06"}, false},
+		{"kid points at wrong secret", map[string]string{
+			"JWT_SECRET": "new-secret", "JWT_KID": "2024-06",
+			"JWT_PREVIOUS_SECRET": "other-secret", "JWT_PREVIOUS_KID": "2024-01",
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setJWTEnv(t, tt.env)
+			token, err := ParseToken(oldToken, jwt.MapClaims{})
+			if valid := err == nil && token.Valid; valid != tt.valid {
+				t.Errorf("valid = %v (err %v), want %v", valid, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestParseTokenAcceptsLegacyTokenWithoutKid(t *testing.T) {
+	setJWTEnv(t, map[string]string{"JWT_SECRET": "legacy-secret"})
+	legacyToken, err := SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setJWTEnv(t, map[string]string{"JWT_SECRET": "new-secret", "JWT_KID": "2024-06", "JWT_PREVIOUS_SECRET": "legacy-secret"})
+	token, err := ParseToken(legacyToken, jwt.MapClaims{})
+	if err != nil || !token.Valid {
+		t.Fatalf("legacy token rejected: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package utils
+
+import "strings"
+
+// iso6391Codes ISO 639-1 iki harfli dil kodlarının listesidir
+var iso6391Codes = buildLanguageSet(`aa ab ae af ak am an ar as av ay az ba be bg bi bm bn bo br bs ca ce ch co cr
+cs cu cv cy da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht hu hy hz
+ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo lt lu lv mg
+mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa
+sc sd se sg si sk sl sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty ug uk ur uz ve vi
+vo wa wo xh yi yo za zh zu`)
+
+func buildLanguageSet(codes string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// NormalizeLanguageCode converts a client supplied language tag such as
+// "EN", "en-US" or "pt_BR" to its lowercase ISO 639-1 code. The second
+// return value is false when the code is not a known ISO 639-1 language.
+func NormalizeLanguageCode(code string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if !iso6391Codes[code] {
+		return "", false
+	}
+	return code, true
+}
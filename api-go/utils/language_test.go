@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+func TestNormalizeLanguageCode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"tr", "tr", true},
+		{"EN", "en", true},
+		{" de ", "de", true},
+		{"en-US", "en", true},
+		{"pt_BR", "pt", true},
+		{"xx", "", false},
+		{"eng", "", false},
+		{"", "", false},
+		{"-US", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeLanguageCode(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeLanguageCode(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/snap-point/api-go/config"
+)
+
+// FindBannedWord returns the first banned word or phrase that appears in text
+// as whole words, ignoring case and punctuation.
+func FindBannedWord(text string) (string, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", false
+	}
+	normalized := " " + strings.Join(words, " ") + " "
+
+	for _, banned := range config.GetBannedWords() {
+		if strings.Contains(normalized, " "+banned+" ") {
+			return banned, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,33 @@
+package utils
+
+import "testing"
+
+func TestFindBannedWord(t *testing.T) {
+	t.Setenv("BANNED_WORDS", "spam, bad phrase ,piç")
+
+	tests := []struct {
+		text      string
+		wantWord  string
+		wantFound bool
+	}{
+		{"a nice place", "", false},
+		{"", "", false},
+		{"!!!", "", false},
+		{"this is spam", "spam", true},
+		{"SPAM!", "spam", true},
+		{"spammer here", "", false},
+		{"a bad phrase indeed", "bad phrase", true},
+		{"bad, phrase", "bad phrase", true},
+		{"badphrase", "", false},
+		{"Piç Kebap", "piç", true},
+		{"spam and bad phrase", "spam", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			word, found := FindBannedWord(tt.text)
+			if word != tt.wantWord || found != tt.wantFound {
+				t.Errorf("FindBannedWord(%q) = %q, %v; want %q, %v", tt.text, word, found, tt.wantWord, tt.wantFound)
+			}
+		})
+	}
+}
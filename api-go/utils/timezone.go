@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveLocation returns the caller's timezone from the `timezone` (IANA name,
+// e.g. "Europe/Istanbul") or `tzOffset` (minutes east of UTC, e.g. 180) query
+// params. Falls back to the server's local timezone when neither is given.
+func ResolveLocation(c *gin.Context) (*time.Location, error) {
+	if name := c.Query("timezone"); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", name)
+		}
+		return loc, nil
+	}
+
+	if offset := c.Query("tzOffset"); offset != "" {
+		minutes, err := strconv.Atoi(offset)
+		if err != nil || minutes < -12*60 || minutes > 14*60 {
+			return nil, fmt.Errorf("invalid tzOffset: must be minutes east of UTC between -720 and 840")
+		}
+		return time.FixedZone(fmt.Sprintf("UTC%+d", minutes), minutes*60), nil
+	}
+
+	return time.Local, nil
+}
+
+// PeriodStart returns the start of the given period (today, this_week,
+// this_month or the monthly alias) in loc. this_week starts on Monday, matching
+// Postgres DATE_TRUNC('week'); use StartOfWeek for a different first weekday.
+// ok is false for all_time or unknown periods.
+func PeriodStart(period string, now time.Time, loc *time.Location) (start time.Time, ok bool) {
+	now = now.In(loc)
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch period {
+	case "today":
+		return startOfDay, true
+	case "this_week":
+		return StartOfWeek(now, loc, time.Monday), true
+	case "this_month", "monthly":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc), true
+	}
+
+	return time.Time{}, false
+}
+
+// StartOfWeek returns midnight in loc of the most recent day, today included,
+// that falls on first.
+func StartOfWeek(now time.Time, loc *time.Location, first time.Weekday) time.Time {
+	now = now.In(loc)
+	daysSinceFirst := (int(now.Weekday()) - int(first) + 7) % 7
+	return time.Date(now.Year(), now.Month(), now.Day()-daysSinceFirst, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPeriodStart(t *testing.T) {
+	istanbul := time.FixedZone("UTC+3", 3*60*60)
+	// Çarşamba 2024-05-15 01:30 İstanbul, UTC'de hâlâ 14 Mayıs
+	now := time.Date(2024, 5, 14, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period string
+		loc    *time.Location
+		want   time.Time
+		ok     bool
+	}{
+		{"today", istanbul, time.Date(2024, 5, 15, 0, 0, 0, 0, istanbul), true},
+		{"today", time.UTC, time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), true},
+		{"this_week", istanbul, time.Date(2024, 5, 13, 0, 0, 0, 0, istanbul), true},
+		{"this_month", istanbul, time.Date(2024, 5, 1, 0, 0, 0, 0, istanbul), true},
+		{"monthly", time.UTC, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"all_time", istanbul, time.Time{}, false},
+		{"weekly", istanbul, time.Time{}, false},
+		{"", istanbul, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period+"/"+tt.loc.String(), func(t *testing.T) {
+			got, ok := PeriodStart(tt.period, now, tt.loc)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("PeriodStart(%q) = %v, %v; want %v, %v", tt.period, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   time.Time
+		first time.Weekday
+		want  time.Time
+	}{
+		{"sunday week midweek", time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC), time.Sunday, time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{"sunday week on sunday", time.Date(2024, 5, 12, 23, 59, 0, 0, time.UTC), time.Sunday, time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{"sunday week on saturday", time.Date(2024, 5, 18, 8, 0, 0, 0, time.UTC), time.Sunday, time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{"monday week on sunday", time.Date(2024, 5, 12, 8, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{"monday week on monday", time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)},
+		{"across month boundary", time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), time.Monday, time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StartOfWeek(tt.now, time.UTC, tt.first); !got.Equal(tt.want) {
+				t.Errorf("StartOfWeek(%v, %v) = %v, want %v", tt.now, tt.first, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLateLocalPostIsToday(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	// New York yaz saati, UTC-4
+	c.Request = httptest.NewRequest("GET", "/?tzOffset=-240", nil)
+	loc, err := ResolveLocation(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Yerel 23:30'da UTC çoktan ertesi güne geçmiştir
+	now := time.Date(2024, 5, 14, 23, 30, 0, 0, loc)
+	start, ok := PeriodStart("today", now, loc)
+	if !ok {
+		t.Fatal("PeriodStart(today) not ok")
+	}
+	utcStart, _ := PeriodStart("today", now, time.UTC)
+
+	for _, posted := range []time.Time{
+		time.Date(2024, 5, 14, 23, 0, 0, 0, loc),
+		time.Date(2024, 5, 14, 10, 0, 0, 0, loc),
+	} {
+		if posted.Before(start) {
+			t.Errorf("post at %v is before the local day starting %v", posted, start)
+		}
+	}
+	// Sunucu saatiyle (UTC) sabah gönderisi "bugün" dışında kalırdı
+	if morning := time.Date(2024, 5, 14, 10, 0, 0, 0, loc); !morning.Before(utcStart) {
+		t.Errorf("test setup: post at %v should fall outside the UTC day starting %v", morning, utcStart)
+	}
+	if previous := time.Date(2024, 5, 13, 23, 0, 0, 0, loc); !previous.Before(start) {
+		t.Errorf("post from the previous local day at %v counted as today", previous)
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query      string
+		wantOffset int
+		wantErr    bool
+	}{
+		{"?tzOffset=180", 3 * 60 * 60, false},
+		{"?tzOffset=-720", -12 * 60 * 60, false},
+		{"?tzOffset=900", 0, true},
+		{"?tzOffset=abc", 0, true},
+		{"?timezone=Not/AZone", 0, true},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/"+tt.query, nil)
+		loc, err := ResolveLocation(c)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveLocation(%s) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if err == nil {
+			if _, offset := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Zone(); offset != tt.wantOffset {
+				t.Errorf("ResolveLocation(%s) offset = %d, want %d", tt.query, offset, tt.wantOffset)
+			}
+		}
+	}
+}